@@ -0,0 +1,50 @@
+// Command audit-verify walks the audit log and checks its hash chain.
+// It exits with status 1 if the chain is broken, and 2 if the audit log can't be read
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	audit_repository "github.com/defryheryanto/mini-wallet/internal/audit/repository/gorm"
+	gorm_storage_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	dsn := fmt.Sprintf(
+		"host=%s port=%s dbname=mini_wallet user=%s password=%s sslmode=disable",
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error connecting to database:", err)
+		os.Exit(2)
+	}
+
+	service := audit.NewAuditService(audit_repository.NewEventRepository(db), gorm_storage_manager.NewGormStorageManager(db))
+	verification, err := service.VerifyChain(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error verifying audit log:", err)
+		os.Exit(2)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(verification); err != nil {
+		fmt.Fprintln(os.Stderr, "error writing verification:", err)
+		os.Exit(2)
+	}
+
+	if !verification.Valid {
+		os.Exit(1)
+	}
+}
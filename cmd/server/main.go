@@ -2,43 +2,93 @@ package main
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/defryheryanto/mini-wallet/internal/httpserver"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
 )
 
-func main() {
-	var appServer *http.Server
+const (
+	shutdownTimeout     = 30 * time.Second
+	readinessDrainDelay = 5 * time.Second
+)
 
+func main() {
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		gormClient := setupGormClient()
-		appContainer := buildApp(gormClient)
-		appServer = &http.Server{
-			Addr:    ":8080",
-			Handler: httpserver.HandleRoutes(appContainer),
-		}
+	logger := setupLogger()
+	tracingProvider := setupTracing()
+	gormClient := setupGormClient()
+	appContainer := buildApp(gormClient, logger)
+	appServer := &http.Server{
+		Addr:    ":8080",
+		Handler: httpserver.HandleRoutes(appContainer),
+	}
+	// Event streams never end on their own, close them so they don't hold the shutdown
+	appServer.RegisterOnShutdown(appContainer.ActivityBroker.Close)
+
+	startupCtx := logging.Inject(context.Background(), logger)
+	rehashedTokens, err := appContainer.ClientService.RehashUnhashedTokens(startupCtx)
+	if err != nil {
+		panic(err)
+	}
+	if rehashedTokens > 0 {
+		logger.Info("hashed plaintext tokens", "count", rehashedTokens)
+	}
+
+	if err := appContainer.FxService.SeedRates(startupCtx, getFxRates()); err != nil {
+		panic(err)
+	}
 
-		log.Println("starting server on port 8080")
+	if err := appContainer.TransactionService.ResumePendingSettlements(startupCtx); err != nil {
+		logger.Error("error resuming pending settlements", logging.KEY_ERROR, err)
+	}
+
+	appContainer.StatementScheduler.Start(startupCtx)
+	appContainer.BalanceScheduler.Start(startupCtx)
+	appContainer.TransferScheduler.Start(startupCtx)
+	appContainer.PayoutProcessor.Start(startupCtx)
+	appContainer.WebhookDispatcher.Start(startupCtx)
+	appContainer.EventRelay.Start(startupCtx)
+
+	go func() {
+		logger.Info("starting server on port 8080")
 		if err := appServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("error starting server: %v\n", err)
+			logger.Error("error starting server", logging.KEY_ERROR, err)
 		}
 	}()
 
 	<-done
 
-	log.Println("shutting down server")
+	logger.Info("marking server as not ready")
+	appContainer.Lifecycle.StartShutdown()
+	time.Sleep(readinessDrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	ctx = logging.Inject(ctx, logger)
+
+	logger.Info("shutting down server")
 	if err := appServer.Shutdown(ctx); err != nil {
-		log.Printf("error shutting down server: %v\n", err)
+		logger.Error("error shutting down server", logging.KEY_ERROR, err)
+	}
+
+	logger.Info("draining background workers")
+	if err := appContainer.Lifecycle.Shutdown(ctx); err != nil {
+		logger.Error("error draining background workers", logging.KEY_ERROR, err)
+	}
+
+	if tracingProvider != nil {
+		logger.Info("flushing traces")
+		if err := tracingProvider.Shutdown(ctx); err != nil {
+			logger.Error("error flushing traces", logging.KEY_ERROR, err)
+		}
 	}
-	cancel()
 
-	log.Println("server shutdown gracefully")
+	logger.Info("server shutdown gracefully")
 }
@@ -1,46 +1,231 @@
 package main
 
 import (
+	"log/slog"
+
+	"github.com/defryheryanto/mini-wallet/db"
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/defryheryanto/mini-wallet/internal/admin"
 	"github.com/defryheryanto/mini-wallet/internal/app"
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	audit_repository "github.com/defryheryanto/mini-wallet/internal/audit/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/balance"
+	balance_repository "github.com/defryheryanto/mini-wallet/internal/balance/repository/gorm"
 	"github.com/defryheryanto/mini-wallet/internal/client"
 	client_repository "github.com/defryheryanto/mini-wallet/internal/client/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	events_repository "github.com/defryheryanto/mini-wallet/internal/events/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	fx_repository "github.com/defryheryanto/mini-wallet/internal/fx/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/health"
+	health_gorm "github.com/defryheryanto/mini-wallet/internal/health/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/metrics"
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	paymentrequest_repository "github.com/defryheryanto/mini-wallet/internal/paymentrequest/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	payout_repository "github.com/defryheryanto/mini-wallet/internal/payout/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	schedule_repository "github.com/defryheryanto/mini-wallet/internal/schedule/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+	statement_repository "github.com/defryheryanto/mini-wallet/internal/statement/repository/gorm"
 	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	gorm_storage_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/tracing"
 	"github.com/defryheryanto/mini-wallet/internal/transaction"
 	transaction_repository "github.com/defryheryanto/mini-wallet/internal/transaction/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	twofactor_repository "github.com/defryheryanto/mini-wallet/internal/twofactor/repository/gorm"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
 	wallet_repository "github.com/defryheryanto/mini-wallet/internal/wallet/repository/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	webhook_repository "github.com/defryheryanto/mini-wallet/internal/webhook/repository/gorm"
 	"gorm.io/gorm"
 )
 
-func buildApp(db *gorm.DB) *app.Application {
-	gormManager := setupGormStorageManager(db)
-	walletService := setupWallet(db)
-	clientService := setupClient(db, walletService, gormManager)
-	transactionService := setupTransaction(db, walletService, gormManager)
+func buildApp(db *gorm.DB, logger *slog.Logger) *app.Application {
+	lifecycleManager := lifecycle.NewManager()
+	appMetrics := metrics.NewMetrics()
+	settlementWorker := setupSettlementWorker(lifecycleManager, appMetrics)
+	gormManager := setupGormStorageManager(db, appMetrics)
+	auditService := audit.NewAuditService(audit_repository.NewEventRepository(db), gormManager)
+	webhookService := webhook.NewWebhookService(webhook_repository.NewEndpointRepository(db), webhook_repository.NewOutboxRepository(db), gormManager, webhook.NewDeliveryClient())
+	webhookDispatcher := setupWebhookDispatcher(lifecycleManager, webhookService)
+	eventPublisher := getEventPublisher()
+	eventService := events.NewEventService(events_repository.NewOutboxRepository(db), gormManager, eventPublisher)
+	eventRelay := setupEventRelay(lifecycleManager, eventService)
+	walletService := setupWallet(db, auditService, webhookService, eventService, gormManager, appMetrics)
+	tokenHasher := client.NewTokenHasher(getTokenPepper())
+	clientService := setupClient(db, walletService, auditService, gormManager, tokenHasher)
+	signatureVerifier := client.NewSignatureVerifier(tokenHasher, client.NewMemoryNonceStore(), getSignatureMaxSkew())
+	fxService := fx.NewFxService(fx_repository.NewQuoteRepository(db), fx_repository.NewRateRepository(db), gormManager, getFxQuoteTTL())
+	activityBroker := activity.NewBroker(activity.HISTORY_SIZE)
+	transactionService := setupTransaction(db, walletService, fxService, webhookService, eventService, gormManager, settlementWorker, activityBroker)
+	statementService := statement.NewStatementService(statement_repository.NewStatementRepository(db), walletService, transactionService)
+	statementScheduler := setupStatementScheduler(lifecycleManager, statementService)
+	balanceService := balance.NewBalanceService(balance_repository.NewSnapshotRepository(db), walletService, transactionService)
+	balanceScheduler := setupBalanceScheduler(lifecycleManager, balanceService)
+	twoFactorService := twofactor.NewTwoFactorService(twofactor_repository.NewEnrollmentRepository(db), gormManager)
+	withdrawalConfirmationService := setupWithdrawalConfirmation(db, transactionService, twoFactorService, gormManager)
+	scheduleService := schedule.NewScheduleService(schedule_repository.NewScheduleRepository(db), walletService, transactionService, withdrawalConfirmationService, gormManager)
+	transferScheduler := setupTransferScheduler(lifecycleManager, scheduleService)
+	payoutService := payout.NewPayoutService(payout_repository.NewPayoutRepository(db), walletService, transactionService, twoFactorService, gormManager, getWithdrawalConfirmationThresholds())
+	payoutProcessor := setupPayoutProcessor(lifecycleManager, payoutService)
+	paymentRequestService := paymentrequest.NewPaymentRequestService(paymentrequest_repository.NewPaymentRequestRepository(db), clientService, walletService, transactionService, withdrawalConfirmationService, twoFactorService, gormManager)
+	healthService := setupHealth(db, lifecycleManager, settlementWorker)
+	adminService := admin.NewAdminService(clientService, walletService, transactionService, fxService, auditService, gormManager)
 
 	return &app.Application{
-		WalletService:      walletService,
-		ClientService:      clientService,
-		TransactionService: transactionService,
+		WalletService:                 walletService,
+		ClientService:                 clientService,
+		SignatureVerifier:             signatureVerifier,
+		TransactionService:            transactionService,
+		FxService:                     fxService,
+		StatementService:              statementService,
+		StatementScheduler:            statementScheduler,
+		BalanceService:                balanceService,
+		BalanceScheduler:              balanceScheduler,
+		ScheduleService:               scheduleService,
+		TransferScheduler:             transferScheduler,
+		PayoutService:                 payoutService,
+		PayoutProcessor:               payoutProcessor,
+		PaymentRequestService:         paymentRequestService,
+		WebhookService:                webhookService,
+		WebhookDispatcher:             webhookDispatcher,
+		EventService:                  eventService,
+		EventPublisher:                eventPublisher,
+		EventRelay:                    eventRelay,
+		ActivityBroker:                activityBroker,
+		TwoFactorService:              twoFactorService,
+		WithdrawalConfirmationService: withdrawalConfirmationService,
+		HealthService:                 healthService,
+		AuditService:                  auditService,
+		AdminService:                  adminService,
+		AdminAuthenticator:            admin.NewAuthenticator(getAdminApiKeys()),
+		RateLimiter:                   ratelimit.NewLimiter(ratelimit.NewMemoryStore(), getRateLimits()),
+		Lifecycle:                     lifecycleManager,
+		Metrics:                       appMetrics,
+		Logger:                        logger,
 	}
 }
 
-func setupGormStorageManager(db *gorm.DB) manager.StorageManager {
-	return gorm_storage_manager.NewGormStorageManager(db)
+func setupGormStorageManager(db *gorm.DB, appMetrics *metrics.Metrics) manager.StorageManager {
+	storageManager := gorm_storage_manager.NewGormStorageManager(db)
+	return appMetrics.InstrumentStorageManager(storageManager)
 }
 
-func setupWallet(db *gorm.DB) wallet.WalletIService {
+func setupWallet(
+	db *gorm.DB,
+	auditService audit.AuditIService,
+	webhookService webhook.WebhookIService,
+	eventService events.EventIService,
+	storageManager manager.StorageManager,
+	appMetrics *metrics.Metrics,
+) wallet.WalletIService {
 	repository := wallet_repository.NewWalletRepository(db)
-	return wallet.NewWalletService(repository)
+	service := tracing.WalletService(wallet.NewWalletService(repository, auditService, webhookService, eventService, storageManager))
+	appMetrics.RegisterWalletStatistics(service)
+
+	return service
 }
 
-func setupClient(db *gorm.DB, walletService wallet.WalletIService, storageManager manager.StorageManager) client.ClientIService {
+func setupClient(
+	db *gorm.DB,
+	walletService wallet.WalletIService,
+	auditService audit.AuditIService,
+	storageManager manager.StorageManager,
+	tokenHasher *client.TokenHasher,
+) client.ClientIService {
 	repository := client_repository.NewClientRepository(db)
-	return client.NewClientService(repository, walletService, storageManager)
+	tokenRepository := client_repository.NewTokenRepository(db)
+	return client.NewClientService(repository, tokenRepository, walletService, auditService, storageManager, tokenHasher, getTokenTTL())
 }
 
-func setupTransaction(db *gorm.DB, walletService wallet.WalletIService, storageManager manager.StorageManager) transaction.TransactionIService {
+func setupSettlementWorker(lifecycleManager *lifecycle.Manager, appMetrics *metrics.Metrics) *transaction.SettlementWorker {
+	settlementWorker := transaction.NewSettlementWorker(transaction.SETTLEMENT_DELAY)
+	lifecycleManager.Register(settlementWorker)
+	appMetrics.RegisterSettlementWorker(settlementWorker)
+
+	return settlementWorker
+}
+
+func setupTransaction(
+	db *gorm.DB,
+	walletService wallet.WalletIService,
+	fxService fx.FxIService,
+	webhookService webhook.WebhookIService,
+	eventService events.EventIService,
+	storageManager manager.StorageManager,
+	settlementWorker *transaction.SettlementWorker,
+	activityBroker *activity.Broker,
+) transaction.TransactionIService {
 	repository := transaction_repository.NewTransactionRepository(db)
-	return transaction.NewTransactionService(repository, walletService, storageManager)
+	service := transaction.NewTransactionService(repository, walletService, fxService, webhookService, eventService, storageManager, settlementWorker, activityBroker)
+	return tracing.TransactionService(service)
+}
+
+func setupStatementScheduler(lifecycleManager *lifecycle.Manager, statementService statement.StatementIService) *statement.Scheduler {
+	scheduler := statement.NewScheduler(statementService)
+	lifecycleManager.Register(scheduler)
+
+	return scheduler
+}
+
+func setupBalanceScheduler(lifecycleManager *lifecycle.Manager, balanceService balance.BalanceIService) *balance.Scheduler {
+	scheduler := balance.NewScheduler(balanceService)
+	lifecycleManager.Register(scheduler)
+
+	return scheduler
+}
+
+func setupTransferScheduler(lifecycleManager *lifecycle.Manager, scheduleService schedule.ScheduleIService) *schedule.Scheduler {
+	scheduler := schedule.NewScheduler(scheduleService)
+	lifecycleManager.Register(scheduler)
+
+	return scheduler
+}
+
+func setupPayoutProcessor(lifecycleManager *lifecycle.Manager, payoutService payout.PayoutIService) *payout.Processor {
+	processor := payout.NewProcessor(payoutService)
+	lifecycleManager.Register(processor)
+
+	return processor
+}
+
+func setupWebhookDispatcher(lifecycleManager *lifecycle.Manager, webhookService webhook.WebhookIService) *webhook.Dispatcher {
+	dispatcher := webhook.NewDispatcher(webhookService)
+	lifecycleManager.Register(dispatcher)
+
+	return dispatcher
+}
+
+func setupEventRelay(lifecycleManager *lifecycle.Manager, eventService events.EventIService) *events.Relay {
+	relay := events.NewRelay(eventService)
+	lifecycleManager.Register(relay)
+
+	return relay
+}
+
+func setupWithdrawalConfirmation(
+	db *gorm.DB,
+	transactionService transaction.TransactionIService,
+	twoFactorService twofactor.TwoFactorIService,
+	storageManager manager.StorageManager,
+) transaction.WithdrawalConfirmationIService {
+	challengeRepository := transaction_repository.NewChallengeRepository(db)
+	return transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, storageManager, getWithdrawalConfirmationThresholds())
+}
+
+func setupHealth(
+	gormDB *gorm.DB,
+	lifecycleManager *lifecycle.Manager,
+	settlementWorker *transaction.SettlementWorker,
+) health.HealthIService {
+	service := health.NewHealthService(lifecycleManager, health.CHECK_TIMEOUT)
+	service.Register("database", health_gorm.DatabaseCheck(gormDB))
+	service.Register("migrations", health_gorm.MigrationCheck(gormDB, db.Migrations, "migrations"))
+	service.Register("settlement", settlementWorker.Health)
+
+	return service
 }
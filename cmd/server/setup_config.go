@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+)
+
+// Return the lifetime of the issued tokens from TOKEN_TTL, e.g. "720h".
+// Zero duration issues tokens that never expire
+func getTokenTTL() time.Duration {
+	value := os.Getenv("TOKEN_TTL")
+	if value == "" {
+		return client.DEFAULT_TOKEN_TTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return ttl
+}
+
+// Return the secret keying the hash of the stored tokens from TOKEN_PEPPER.
+// Changing the pepper invalidates every issued token
+func getTokenPepper() string {
+	pepper := os.Getenv("TOKEN_PEPPER")
+	if pepper == "" {
+		panic(fmt.Errorf("TOKEN_PEPPER is required"))
+	}
+
+	return pepper
+}
+
+// Return the maximum difference between the timestamp of a signed request and the server clock
+// from SIGNATURE_MAX_SKEW, e.g. "5m"
+func getSignatureMaxSkew() time.Duration {
+	value := os.Getenv("SIGNATURE_MAX_SKEW")
+	if value == "" {
+		return client.DEFAULT_SIGNATURE_MAX_SKEW
+	}
+
+	maxSkew, err := time.ParseDuration(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return maxSkew
+}
+
+// Return the amount above which the withdrawals of the clients enrolled to TOTP have to be confirmed,
+// by currency, from WITHDRAWAL_CONFIRMATION_THRESHOLD formatted as {currency}:{amount} separated by comma,
+// e.g. WITHDRAWAL_CONFIRMATION_THRESHOLD="IDR:1000000,USD:100".
+// Every withdrawal in a currency without a threshold has to be confirmed, so it defaults to confirming every withdrawal
+func getWithdrawalConfirmationThresholds() transaction.ConfirmationThresholds {
+	thresholds, err := transaction.ParseConfirmationThresholds(os.Getenv("WITHDRAWAL_CONFIRMATION_THRESHOLD"))
+	if err != nil {
+		panic(fmt.Errorf("WITHDRAWAL_CONFIRMATION_THRESHOLD: %w", err))
+	}
+
+	return thresholds
+}
+
+// Return the limit of every rate limit group, overridden by RATE_LIMIT_{GROUP}
+// formatted as {requests}/{period}, e.g. RATE_LIMIT_WRITE="60/1m"
+func getRateLimits() map[string]ratelimit.Limit {
+	limits := map[string]ratelimit.Limit{}
+	for group, limit := range ratelimit.DEFAULT_LIMITS {
+		limits[group] = limit
+
+		value := os.Getenv("RATE_LIMIT_" + strings.ToUpper(group))
+		if value == "" {
+			continue
+		}
+
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			panic(fmt.Errorf("RATE_LIMIT_%s: %w", strings.ToUpper(group), err))
+		}
+		limits[group] = limit
+	}
+
+	return limits
+}
+
+// Return the API key of each operator from ADMIN_API_KEYS formatted as {operator}:{key} separated by comma,
+// e.g. ADMIN_API_KEYS="alice:secret1,bob:secret2". The admin API rejects every request if it's empty
+func getAdminApiKeys() map[string]string {
+	keys, err := admin.ParseApiKeys(os.Getenv("ADMIN_API_KEYS"))
+	if err != nil {
+		panic(fmt.Errorf("ADMIN_API_KEYS: %w", err))
+	}
+
+	return keys
+}
+
+// Return the initial FX rates read from the JSON file at FX_RATES_FILE.
+// The rate table starts empty if it's not set
+func getFxRates() []*fx.Rate {
+	path := os.Getenv("FX_RATES_FILE")
+	if path == "" {
+		return nil
+	}
+
+	rates, err := fx.LoadRates(path)
+	if err != nil {
+		panic(fmt.Errorf("FX_RATES_FILE: %w", err))
+	}
+
+	return rates
+}
+
+// Return how long the FX quotes lock their rate from FX_QUOTE_TTL, e.g. "30s"
+func getFxQuoteTTL() time.Duration {
+	value := os.Getenv("FX_QUOTE_TTL")
+	if value == "" {
+		return fx.DEFAULT_QUOTE_TTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return ttl
+}
+
+// Return the publisher of the relayed events from EVENTS_PUBLISHER, one of "inprocess", "stdout" and "file".
+// The file publisher appends the events to EVENTS_FILE_PATH
+func getEventPublisher() events.Publisher {
+	publisher, err := events.NewPublisher(&events.PublisherConfig{
+		Type:     os.Getenv("EVENTS_PUBLISHER"),
+		FilePath: os.Getenv("EVENTS_FILE_PATH"),
+	})
+	if err != nil {
+		panic(fmt.Errorf("EVENTS_PUBLISHER: %w", err))
+	}
+
+	return publisher
+}
@@ -2,11 +2,12 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	otel_gorm "gorm.io/plugin/opentelemetry/tracing"
 )
 
 func setupGormClient() *gorm.DB {
@@ -21,7 +22,12 @@ func setupGormClient() *gorm.DB {
 	if err != nil {
 		panic(err)
 	}
-	log.Println("gorm client setup finished")
+
+	err = db.Use(otel_gorm.NewPlugin(otel_gorm.WithoutMetrics(), otel_gorm.WithoutQueryVariables()))
+	if err != nil {
+		panic(err)
+	}
+	slog.Info("gorm client setup finished")
 
 	return db
 }
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+func setupLogger() *slog.Logger {
+	logger := logging.NewLogger(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL")))
+	slog.SetDefault(logger)
+
+	return logger
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/defryheryanto/mini-wallet/internal/tracing"
+)
+
+func setupTracing() *tracing.Provider {
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "mini-wallet"
+	}
+
+	provider, err := tracing.Setup(context.Background(), &tracing.Config{
+		Exporter:    os.Getenv("TRACES_EXPORTER"),
+		FilePath:    os.Getenv("TRACES_FILE_PATH"),
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return provider
+}
@@ -0,0 +1,8 @@
+package db
+
+import "embed"
+
+// Migrations holds the SQL migration files so the application can tell which schema version it expects
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
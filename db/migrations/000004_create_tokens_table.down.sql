@@ -0,0 +1,10 @@
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS token VARCHAR(255);
+
+UPDATE clients SET token = (
+    SELECT t.token FROM tokens t
+    WHERE t.client_xid = clients.xid AND t.revoked_at IS NULL
+    ORDER BY t.created_at DESC
+    LIMIT 1
+);
+
+DROP TABLE IF EXISTS tokens;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tokens (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS tokens_client_xid_idx ON tokens (client_xid);
+
+INSERT INTO tokens (id, client_xid, token, created_at)
+SELECT gen_random_uuid()::VARCHAR, xid, token, CURRENT_TIMESTAMP FROM clients;
+
+ALTER TABLE clients DROP COLUMN IF EXISTS token;
@@ -0,0 +1,9 @@
+-- Hashed tokens can't be turned back into plaintext, they are dropped
+DELETE FROM tokens WHERE token IS NULL;
+
+DROP INDEX IF EXISTS tokens_token_hash_idx;
+DROP INDEX IF EXISTS tokens_token_prefix_idx;
+
+ALTER TABLE tokens ALTER COLUMN token SET NOT NULL;
+ALTER TABLE tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE tokens DROP COLUMN IF EXISTS token_prefix;
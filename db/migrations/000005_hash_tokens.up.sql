@@ -0,0 +1,6 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_prefix VARCHAR(16);
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+ALTER TABLE tokens ALTER COLUMN token DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS tokens_token_prefix_idx ON tokens (token_prefix);
+CREATE UNIQUE INDEX IF NOT EXISTS tokens_token_hash_idx ON tokens (token_hash);
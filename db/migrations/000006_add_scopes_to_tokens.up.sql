@@ -0,0 +1,3 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS scopes TEXT NOT NULL DEFAULT '';
+
+UPDATE tokens SET scopes = 'wallet:read wallet:write transactions:read deposits:create withdrawals:create tokens:manage';
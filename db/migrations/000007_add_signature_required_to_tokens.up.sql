@@ -0,0 +1 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS signature_required BOOLEAN NOT NULL DEFAULT FALSE;
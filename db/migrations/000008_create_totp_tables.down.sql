@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS withdrawal_challenges;
+DROP TABLE IF EXISTS totp_enrollments;
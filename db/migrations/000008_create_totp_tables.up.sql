@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS totp_enrollments (
+    client_xid VARCHAR(100) PRIMARY KEY NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS withdrawal_challenges (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    customer_xid VARCHAR(100) NOT NULL,
+    reference_id VARCHAR(100) NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    transaction_id VARCHAR(100),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL DEFAULT '',
+    target_id VARCHAR(100) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target_type, target_id);
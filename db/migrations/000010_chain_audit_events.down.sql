@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_no_update_delete ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+DROP INDEX IF EXISTS audit_events_request_id_idx;
+DROP INDEX IF EXISTS audit_events_actor_idx;
+DROP INDEX IF EXISTS audit_events_sequence_idx;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS ip;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS after_snapshot;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS before_snapshot;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS sequence;
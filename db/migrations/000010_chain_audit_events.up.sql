@@ -0,0 +1,30 @@
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS sequence BIGINT;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS before_snapshot TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS after_snapshot TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Events recorded before the chain are numbered in order and left unchained
+UPDATE audit_events SET sequence = numbered.sequence
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS sequence FROM audit_events) AS numbered
+WHERE audit_events.id = numbered.id;
+
+ALTER TABLE audit_events ALTER COLUMN sequence SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS audit_events_sequence_idx ON audit_events (sequence);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor);
+CREATE INDEX IF NOT EXISTS audit_events_request_id_idx ON audit_events (request_id);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
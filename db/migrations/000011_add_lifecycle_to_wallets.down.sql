@@ -0,0 +1,4 @@
+ALTER TABLE wallets DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE wallets DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE wallets DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE wallets DROP COLUMN IF EXISTS frozen_at;
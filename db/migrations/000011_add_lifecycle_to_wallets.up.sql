@@ -0,0 +1,6 @@
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE wallets SET frozen_at = disabled_at WHERE status = 'frozen' AND frozen_at IS NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS wallet_id;
+
+DROP INDEX IF EXISTS wallets_owned_by_name_idx;
+DROP INDEX IF EXISTS wallets_owned_by_default_idx;
+
+ALTER TABLE wallets DROP COLUMN IF EXISTS is_default;
+ALTER TABLE wallets DROP COLUMN IF EXISTS name;
@@ -0,0 +1,10 @@
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS name VARCHAR(50) NOT NULL DEFAULT 'main';
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Every existing client owns a single wallet, which becomes its default wallet
+UPDATE wallets SET is_default = TRUE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS wallets_owned_by_default_idx ON wallets (owned_by) WHERE is_default;
+CREATE UNIQUE INDEX IF NOT EXISTS wallets_owned_by_name_idx ON wallets (owned_by, LOWER(name));
+
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS wallet_id VARCHAR(100) NOT NULL DEFAULT '';
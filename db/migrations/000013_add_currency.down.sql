@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS fx_quotes;
+
+ALTER TABLE withdrawal_challenges ALTER COLUMN amount TYPE DECIMAL(18, 2);
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(18, 2);
+ALTER TABLE wallets ALTER COLUMN balance TYPE DECIMAL(18, 2);
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS counterparty_wallet_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+ALTER TABLE wallets DROP COLUMN IF EXISTS currency;
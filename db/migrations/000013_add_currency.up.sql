@@ -0,0 +1,22 @@
+-- Every existing wallet and transaction is in IDR
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS counterparty_wallet_id VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Currencies with 3 minor units, e.g. KWD, need one more decimal
+ALTER TABLE wallets ALTER COLUMN balance TYPE DECIMAL(19, 3);
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(19, 3);
+ALTER TABLE withdrawal_challenges ALTER COLUMN amount TYPE DECIMAL(19, 3);
+
+CREATE TABLE IF NOT EXISTS fx_quotes (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    source_amount DECIMAL(19, 3) NOT NULL,
+    target_amount DECIMAL(19, 3) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS transactions_wallet_id_transacted_at_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS transactions_wallet_id_transacted_at_idx ON transactions (wallet_id, transacted_at);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS statements_no_update_delete ON statements;
+DROP FUNCTION IF EXISTS statements_immutable();
+
+DROP TABLE IF EXISTS statements;
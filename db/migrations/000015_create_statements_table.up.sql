@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS statements (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    wallet_id VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    opening_balance DECIMAL(19, 3) NOT NULL,
+    total_credits DECIMAL(19, 3) NOT NULL,
+    total_debits DECIMAL(19, 3) NOT NULL,
+    total_fees DECIMAL(19, 3) NOT NULL,
+    closing_balance DECIMAL(19, 3) NOT NULL,
+    transaction_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS statements_wallet_id_period_start_idx ON statements (wallet_id, period_start);
+
+CREATE OR REPLACE FUNCTION statements_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'statements are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER statements_no_update_delete
+    BEFORE UPDATE OR DELETE ON statements
+    FOR EACH ROW EXECUTE FUNCTION statements_immutable();
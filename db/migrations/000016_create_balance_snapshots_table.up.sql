@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    wallet_id VARCHAR(100) NOT NULL,
+    as_of TIMESTAMP WITH TIME ZONE NOT NULL,
+    balance DECIMAL(19, 3) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (wallet_id, as_of)
+);
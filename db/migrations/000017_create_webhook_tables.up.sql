@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_endpoints_client_xid_idx ON webhook_endpoints (client_xid);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    event_id VARCHAR(100) NOT NULL REFERENCES webhook_events (id),
+    event_type VARCHAR(100) NOT NULL,
+    endpoint_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_idx ON webhook_deliveries (endpoint_id, created_at);
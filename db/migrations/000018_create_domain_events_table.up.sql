@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS domain_events (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    sequence BIGSERIAL NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS domain_events_unpublished_idx ON domain_events (sequence) WHERE published_at IS NULL;
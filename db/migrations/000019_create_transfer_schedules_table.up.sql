@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS transfer_schedules (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    wallet_id VARCHAR(100) NOT NULL,
+    target_wallet_id VARCHAR(100) NOT NULL,
+    amount DECIMAL(19, 3) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rule VARCHAR(255) NOT NULL DEFAULT '',
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    run_count INTEGER NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_status VARCHAR(20) NOT NULL DEFAULT '',
+    last_run_error TEXT NOT NULL DEFAULT '',
+    last_transaction_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS transfer_schedules_due_idx ON transfer_schedules (next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS transfer_schedules_wallet_id_idx ON transfer_schedules (wallet_id, created_at);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    client_xid VARCHAR(100) NOT NULL,
+    wallet_id VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    item_count INTEGER NOT NULL,
+    total_amount DECIMAL(19, 3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS payout_batches_unfinished_idx ON payout_batches (created_at) WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS payout_items (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    batch_id VARCHAR(100) NOT NULL REFERENCES payout_batches (id),
+    line INTEGER NOT NULL,
+    reference_id VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    target_wallet_id VARCHAR(100) NOT NULL DEFAULT '',
+    amount DECIMAL(19, 3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    transaction_id VARCHAR(100) NOT NULL DEFAULT '',
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payout_items_batch_id_reference_id_idx ON payout_items (batch_id, reference_id);
+CREATE INDEX IF NOT EXISTS payout_items_batch_id_line_idx ON payout_items (batch_id, line);
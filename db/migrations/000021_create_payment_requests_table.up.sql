@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id VARCHAR(100) PRIMARY KEY NOT NULL,
+    requester_xid VARCHAR(100) NOT NULL,
+    requester_wallet_id VARCHAR(100) NOT NULL,
+    payer_xid VARCHAR(100) NOT NULL,
+    amount DECIMAL(19, 3) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    memo VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    payer_wallet_id VARCHAR(100) NOT NULL DEFAULT '',
+    transaction_id VARCHAR(100) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS payment_requests_payer_xid_idx ON payment_requests (payer_xid, created_at);
+CREATE INDEX IF NOT EXISTS payment_requests_requester_xid_idx ON payment_requests (requester_xid, created_at);
@@ -0,0 +1,3 @@
+DROP SEQUENCE IF EXISTS audit_events_sequence_seq;
+DROP INDEX IF EXISTS audit_events_chain_key_sequence_idx;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS chain_key;
@@ -0,0 +1,8 @@
+-- Events are chained per target instead of in one chain, so appends to different targets don't wait for each other.
+-- The events recorded before keep an empty chain key and are verified as the former single chain
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS chain_key VARCHAR(160) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS audit_events_chain_key_sequence_idx ON audit_events (chain_key, sequence);
+
+-- Sequences are allocated without locking the whole log, a rolled back append leaves a gap
+CREATE SEQUENCE IF NOT EXISTS audit_events_sequence_seq OWNED BY audit_events.sequence;
+SELECT setval('audit_events_sequence_seq', COALESCE((SELECT MAX(sequence) FROM audit_events), 0) + 1, false);
@@ -0,0 +1,4 @@
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS currency;
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS quote_id;
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS target_wallet_id;
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS type;
@@ -0,0 +1,5 @@
+-- Challenges also hold transfers to wallets of other clients, the existing ones hold withdrawals
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'withdrawal';
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS target_wallet_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS quote_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT '';
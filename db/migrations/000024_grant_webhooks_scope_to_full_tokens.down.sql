@@ -0,0 +1,3 @@
+-- Full access tokens issued since can't be told apart from the granted ones, they lose the webhooks scope too
+UPDATE tokens SET scopes = array_to_string(array_remove(string_to_array(scopes, ' '), 'webhooks:manage'), ' ')
+WHERE string_to_array(scopes, ' ') @> ARRAY['wallet:read', 'wallet:write', 'transactions:read', 'deposits:create', 'withdrawals:create', 'tokens:manage', 'webhooks:manage'];
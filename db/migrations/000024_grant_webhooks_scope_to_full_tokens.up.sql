@@ -0,0 +1,5 @@
+-- Tokens granted every scope before webhooks were introduced were issued as full access tokens,
+-- grant them the webhooks scope too. Otherwise neither they nor the tokens they issue or rotate into could manage webhooks
+UPDATE tokens SET scopes = scopes || ' webhooks:manage'
+WHERE string_to_array(scopes, ' ') @> ARRAY['wallet:read', 'wallet:write', 'transactions:read', 'deposits:create', 'withdrawals:create', 'tokens:manage']
+    AND NOT string_to_array(scopes, ' ') @> ARRAY['webhooks:manage'];
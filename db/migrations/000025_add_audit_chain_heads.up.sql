@@ -0,0 +1,20 @@
+-- The head of every chain is updated along with the appends to the chain,
+-- so the removal of the last events of a chain, or of a whole chain, is found by the verification.
+-- The single chain of the events recorded before they were chained per target has an empty chain key
+CREATE TABLE IF NOT EXISTS audit_chain_heads (
+    chain_key VARCHAR(160) PRIMARY KEY NOT NULL,
+    length BIGINT NOT NULL,
+    last_sequence BIGINT NOT NULL,
+    last_hash VARCHAR(64) NOT NULL
+);
+
+INSERT INTO audit_chain_heads (chain_key, length, last_sequence, last_hash)
+SELECT chained.chain_key, chained.length, chained.last_sequence, audit_events.hash
+FROM (
+    SELECT chain_key, COUNT(*) AS length, MAX(sequence) AS last_sequence
+    FROM audit_events
+    WHERE hash <> ''
+    GROUP BY chain_key
+) AS chained
+JOIN audit_events ON audit_events.sequence = chained.last_sequence
+ON CONFLICT (chain_key) DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE withdrawal_challenges DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,3 @@
+-- Confirmations claim the challenge for a limited time, so a confirmation that never finished doesn't hold it forever
+ALTER TABLE withdrawal_challenges ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE;
+UPDATE withdrawal_challenges SET claimed_at = CURRENT_TIMESTAMP WHERE status = 'confirming';
@@ -0,0 +1,8 @@
+-- The rates are shared by every instance, so the rates set by the operators apply everywhere and survive restarts
+CREATE TABLE IF NOT EXISTS fx_rates (
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (from_currency, to_currency)
+);
@@ -0,0 +1,175 @@
+package activity
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event is a change of a wallet pushed to the activity streams of the wallet
+type Event struct {
+	Id        int64           `json:"id"`
+	WalletId  string          `json:"wallet_id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Balance is the data of the balance events, sent by the streams after every successful transaction
+type Balance struct {
+	WalletId string  `json:"wallet_id"`
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+}
+
+// Subscription receives the events of a wallet published after it is made
+type Subscription struct {
+	WalletId string
+	// Closed when the subscriber falls too far behind or the broker is closed
+	Events <-chan *Event
+	// Events of the wallet published after the last event id given on subscribe
+	Missed []*Event
+	// Whether Missed holds every event published since the given last event id.
+	// False if no last event id is given, or if it is too old to resume from
+	Resumed bool
+
+	events chan *Event
+	closed bool
+}
+
+// Broker is an in-process publish/subscribe of the wallet events.
+// The latest events are kept in memory so a subscriber can resume from the last event it received,
+// events are not kept across restarts
+type Broker struct {
+	mu          sync.Mutex
+	historySize int
+	history     []*Event
+	lastId      int64
+	// Id of the latest event removed from the history, events after it can be resumed from
+	evictedId   int64
+	subscribers map[string]map[*Subscription]bool
+	closed      bool
+}
+
+func NewBroker(historySize int) *Broker {
+	// Ids continue from the current time so they keep increasing across restarts,
+	// and an id of a previous run is never mistaken for one of this run
+	firstId := time.Now().UnixMicro()
+	return &Broker{
+		historySize: historySize,
+		lastId:      firstId,
+		evictedId:   firstId,
+		subscribers: map[string]map[*Subscription]bool{},
+	}
+}
+
+// Push the event to the subscribers of the wallet. The data is marshalled to JSON right away.
+// A subscriber whose buffer is full is disconnected rather than blocking the publisher
+func (b *Broker) Publish(walletId, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.lastId++
+	event := &Event{
+		Id:        b.lastId,
+		WalletId:  walletId,
+		Type:      eventType,
+		Data:      payload,
+		CreatedAt: time.Now(),
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.evictedId = b.history[0].Id
+		b.history = b.history[1:]
+	}
+
+	for subscription := range b.subscribers[walletId] {
+		select {
+		case subscription.events <- event:
+		default:
+			b.remove(subscription)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe to the events of the wallet.
+// The events published after lastEventId, the id of the last event received by a previous subscription, are returned as missed
+func (b *Broker) Subscribe(walletId, lastEventId string) *Subscription {
+	events := make(chan *Event, SUBSCRIBER_BUFFER_SIZE)
+	subscription := &Subscription{
+		WalletId: walletId,
+		Events:   events,
+		events:   events,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		subscription.closed = true
+		close(events)
+		return subscription
+	}
+
+	if lastId, err := strconv.ParseInt(lastEventId, 10, 64); err == nil && lastId >= b.evictedId && lastId <= b.lastId {
+		subscription.Resumed = true
+		for _, event := range b.history {
+			if event.Id > lastId && event.WalletId == walletId {
+				subscription.Missed = append(subscription.Missed, event)
+			}
+		}
+	}
+
+	if b.subscribers[walletId] == nil {
+		b.subscribers[walletId] = map[*Subscription]bool{}
+	}
+	b.subscribers[walletId][subscription] = true
+
+	return subscription
+}
+
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(subscription)
+}
+
+// Disconnect every subscriber, e.g. so the streams don't hold the shutdown of the server
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subscriptions := range b.subscribers {
+		for subscription := range subscriptions {
+			b.remove(subscription)
+		}
+	}
+}
+
+func (b *Broker) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.events)
+
+	delete(b.subscribers[subscription.WalletId], subscription)
+	if len(b.subscribers[subscription.WalletId]) == 0 {
+		delete(b.subscribers, subscription.WalletId)
+	}
+}
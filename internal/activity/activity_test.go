@@ -0,0 +1,133 @@
+package activity_test
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	t.Run("should push the event to the subscribers of the wallet only", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		subscription := broker.Subscribe("wallet-id", "")
+		other := broker.Subscribe("other-id", "")
+
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, map[string]string{"id": "trx-id"}))
+
+		event := <-subscription.Events
+		assert.Equal(t, "wallet-id", event.WalletId)
+		assert.Equal(t, activity.EVENT_TRANSACTION_UPDATED, event.Type)
+		assert.JSONEq(t, `{"id":"trx-id"}`, string(event.Data))
+		assert.Len(t, other.Events, 0)
+	})
+
+	t.Run("should disconnect the subscriber falling behind", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		subscription := broker.Subscribe("wallet-id", "")
+
+		for i := 0; i <= activity.SUBSCRIBER_BUFFER_SIZE; i++ {
+			assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, i))
+		}
+
+		received := 0
+		for range subscription.Events {
+			received++
+		}
+		assert.Equal(t, activity.SUBSCRIBER_BUFFER_SIZE, received)
+	})
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	t.Run("should not resume without last event id", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, 1))
+
+		subscription := broker.Subscribe("wallet-id", "")
+		assert.False(t, subscription.Resumed)
+		assert.Empty(t, subscription.Missed)
+	})
+
+	t.Run("should return the events of the wallet missed since the last event id", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		first := broker.Subscribe("wallet-id", "")
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, 1))
+		assert.Nil(t, broker.Publish("other-id", activity.EVENT_TRANSACTION_UPDATED, 2))
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, 3))
+		lastEvent := <-first.Events
+		broker.Unsubscribe(first)
+
+		subscription := broker.Subscribe("wallet-id", strconv.FormatInt(lastEvent.Id, 10))
+		assert.True(t, subscription.Resumed)
+		assert.Len(t, subscription.Missed, 1)
+		assert.Equal(t, "3", string(subscription.Missed[0].Data))
+	})
+
+	t.Run("should not resume from an event no longer kept", func(t *testing.T) {
+		broker := activity.NewBroker(2)
+		first := broker.Subscribe("wallet-id", "")
+		for i := 0; i < 4; i++ {
+			assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, i))
+		}
+		lastEvent := <-first.Events
+
+		subscription := broker.Subscribe("wallet-id", strconv.FormatInt(lastEvent.Id, 10))
+		assert.False(t, subscription.Resumed)
+		assert.Empty(t, subscription.Missed)
+	})
+
+	t.Run("should not resume from an event of a previous run", func(t *testing.T) {
+		previousId := time.Now().UnixMicro()
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, 1))
+
+		subscription := broker.Subscribe("wallet-id", fmt.Sprint(previousId-1))
+		assert.False(t, subscription.Resumed)
+	})
+
+	t.Run("should resume without missed events if nothing was published since", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		first := broker.Subscribe("wallet-id", "")
+		assert.Nil(t, broker.Publish("wallet-id", activity.EVENT_TRANSACTION_UPDATED, 1))
+		lastEvent := <-first.Events
+
+		subscription := broker.Subscribe("wallet-id", strconv.FormatInt(lastEvent.Id, 10))
+		assert.True(t, subscription.Resumed)
+		assert.Empty(t, subscription.Missed)
+	})
+}
+
+func TestBroker_Close(t *testing.T) {
+	t.Run("should disconnect every subscriber", func(t *testing.T) {
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		subscription := broker.Subscribe("wallet-id", "")
+
+		broker.Close()
+		_, ok := <-subscription.Events
+		assert.False(t, ok)
+
+		closedSubscription := broker.Subscribe("wallet-id", "")
+		_, ok = <-closedSubscription.Events
+		assert.False(t, ok)
+	})
+}
+
+func TestWriteEvent(t *testing.T) {
+	t.Run("should write the event in the event stream format", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		assert.Nil(t, activity.WriteRetry(buffer, 3*time.Second))
+		assert.Nil(t, activity.WriteEvent(buffer, "12", activity.EVENT_TRANSACTION_UPDATED, []byte(`{"id":"trx-id"}`)))
+		assert.Nil(t, activity.WriteEvent(buffer, "", activity.EVENT_BALANCE_UPDATED, []byte(`{"balance":10}`)))
+		assert.Nil(t, activity.WriteComment(buffer, "heartbeat"))
+
+		assert.Equal(t, "retry: 3000\n\n"+
+			"id: 12\nevent: transaction.updated\ndata: {\"id\":\"trx-id\"}\n\n"+
+			"event: balance.updated\ndata: {\"balance\":10}\n\n"+
+			": heartbeat\n\n", buffer.String())
+	})
+}
@@ -0,0 +1,20 @@
+package activity
+
+import "time"
+
+const (
+	EVENT_TRANSACTION_UPDATED = "transaction.updated"
+	EVENT_BALANCE_UPDATED     = "balance.updated"
+)
+
+// Events kept in memory across every wallet to resume the streams reconnecting with Last-Event-ID
+const HISTORY_SIZE = 1000
+
+// Events buffered for a subscriber, a subscriber falling further behind is disconnected
+const SUBSCRIBER_BUFFER_SIZE = 64
+
+const (
+	HEARTBEAT_INTERVAL = 15 * time.Second
+	// Delay before the client reconnects a dropped stream, sent as the retry field of the stream
+	RECONNECT_DELAY = 3 * time.Second
+)
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/go-chi/chi/v5"
+)
+
+// Stream the events of the wallet as Server-Sent Events until the client disconnects.
+// A stream resumed with Last-Event-ID starts with the events missed since, any other stream starts with the current balance
+func HandleStreamEvents(broker *activity.Broker, walletService wallet.WalletIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		targetWallet, err := walletService.GetWallet(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if targetWallet == nil {
+			response.Failed(w, wallet.ErrWalletNotFound)
+			return
+		}
+		if targetWallet.Status == wallet.STATUS_DISABLED {
+			response.Failed(w, wallet.ErrWalletDisabled)
+			return
+		}
+
+		subscription := broker.Subscribe(targetWallet.Id, r.Header.Get("Last-Event-ID"))
+		defer broker.Unsubscribe(subscription)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		stream := &eventStream{
+			w:             w,
+			controller:    http.NewResponseController(w),
+			walletService: walletService,
+			walletId:      targetWallet.Id,
+		}
+		err = stream.start(r.Context(), subscription, targetWallet)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("event stream closed", logging.KEY_ERROR, err)
+		}
+	}
+}
+
+type eventStream struct {
+	w             http.ResponseWriter
+	controller    *http.ResponseController
+	walletService wallet.WalletIService
+	walletId      string
+}
+
+// Write the events of the subscription until the request is done or the subscription is closed
+func (s *eventStream) start(ctx context.Context, subscription *activity.Subscription, targetWallet *wallet.Wallet) error {
+	err := activity.WriteRetry(s.w, activity.RECONNECT_DELAY)
+	if err != nil {
+		return err
+	}
+
+	balanceChanged := !subscription.Resumed
+	for _, event := range subscription.Missed {
+		err = s.writeEvent(event)
+		if err != nil {
+			return err
+		}
+		balanceChanged = balanceChanged || isSuccessfulTransaction(event)
+	}
+	if balanceChanged {
+		if len(subscription.Missed) > 0 {
+			targetWallet, err = s.walletService.GetWalletById(ctx, s.walletId)
+			if err != nil {
+				return err
+			}
+		}
+		err = s.writeBalance(targetWallet)
+		if err != nil {
+			return err
+		}
+	}
+	err = s.controller.Flush()
+	if err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(activity.HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return nil
+			}
+
+			err = s.writeEvent(event)
+			if err != nil {
+				return err
+			}
+			if isSuccessfulTransaction(event) {
+				currentWallet, err := s.walletService.GetWalletById(ctx, s.walletId)
+				if err != nil {
+					return err
+				}
+				err = s.writeBalance(currentWallet)
+				if err != nil {
+					return err
+				}
+			}
+		case <-heartbeat.C:
+			err = activity.WriteComment(s.w, "heartbeat")
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.controller.Flush()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *eventStream) writeEvent(event *activity.Event) error {
+	return activity.WriteEvent(s.w, strconv.FormatInt(event.Id, 10), event.Type, event.Data)
+}
+
+// Write the balance without an id, so a resumed stream continues from the event preceding it
+func (s *eventStream) writeBalance(target *wallet.Wallet) error {
+	data, err := json.Marshal(&activity.Balance{
+		WalletId: target.Id,
+		Balance:  target.Balance,
+		Currency: target.Currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	return activity.WriteEvent(s.w, "", activity.EVENT_BALANCE_UPDATED, data)
+}
+
+func isSuccessfulTransaction(event *activity.Event) bool {
+	if event.Type != activity.EVENT_TRANSACTION_UPDATED {
+		return false
+	}
+
+	trx := &transaction.Transaction{}
+	err := json.Unmarshal(event.Data, trx)
+	return err == nil && trx.Status == transaction.STATUS_SUCCESS
+}
@@ -0,0 +1,32 @@
+package activity
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Write an event of a text/event-stream. The id is omitted if empty,
+// the data is written on a single line so it can't contain new lines, e.g. compact JSON
+func WriteEvent(w io.Writer, id, eventType string, data []byte) error {
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+	return err
+}
+
+// Write a comment line of a text/event-stream, ignored by the clients but keeping the connection alive
+func WriteComment(w io.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
+
+// Write the delay the client waits before reconnecting a dropped stream
+func WriteRetry(w io.Writer, delay time.Duration) error {
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", delay.Milliseconds())
+	return err
+}
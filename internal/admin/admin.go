@@ -0,0 +1,340 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+)
+
+// Operator is a support staff member using the admin API
+type Operator struct {
+	Name string `json:"name"`
+}
+
+type AdminIService interface {
+	SearchClients(ctx context.Context, params *client.SearchClientsParams) ([]*client.Client, error)
+	SearchWallets(ctx context.Context, params *wallet.SearchWalletsParams) ([]*wallet.Wallet, error)
+	GetWallet(ctx context.Context, walletId string) (*wallet.Wallet, error)
+	GetWalletTransactions(ctx context.Context, walletId string) ([]*transaction.Transaction, error)
+	DisableWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error)
+	FreezeWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error)
+	UnfreezeWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error)
+	CloseWallet(ctx context.Context, params *CloseWalletParams) (*wallet.Wallet, *transaction.Transaction, error)
+	AdjustBalance(ctx context.Context, params *AdjustBalanceParams) (*transaction.Transaction, error)
+	RetryTransaction(ctx context.Context, transactionId, reason string) (*transaction.Transaction, error)
+	FailTransaction(ctx context.Context, transactionId, reason string) (*transaction.Transaction, error)
+	SetFxRates(ctx context.Context, params *SetFxRatesParams) ([]*fx.Rate, error)
+}
+
+// AdminService runs the operator actions and records each of them to the audit log
+type AdminService struct {
+	clientService      client.ClientIService
+	walletService      wallet.WalletIService
+	transactionService transaction.TransactionIService
+	fxService          fx.FxIService
+	auditService       audit.AuditIService
+	storageManager     manager.StorageManager
+}
+
+func NewAdminService(
+	clientService client.ClientIService,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+	fxService fx.FxIService,
+	auditService audit.AuditIService,
+	storageManager manager.StorageManager,
+) *AdminService {
+	return &AdminService{clientService, walletService, transactionService, fxService, auditService, storageManager}
+}
+
+func (s *AdminService) SearchClients(ctx context.Context, params *client.SearchClientsParams) ([]*client.Client, error) {
+	clients, err := s.clientService.SearchClients(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(ctx, &audit.Event{
+		Action:     ACTION_SEARCH_CLIENTS,
+		TargetType: audit.TARGET_CLIENT,
+		TargetId:   params.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (s *AdminService) SearchWallets(ctx context.Context, params *wallet.SearchWalletsParams) ([]*wallet.Wallet, error) {
+	wallets, err := s.walletService.SearchWallets(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(ctx, &audit.Event{
+		Action:     ACTION_SEARCH_WALLETS,
+		TargetType: audit.TARGET_WALLET,
+		TargetId:   params.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+func (s *AdminService) GetWallet(ctx context.Context, walletId string) (*wallet.Wallet, error) {
+	targetWallet, err := s.walletService.GetWalletById(ctx, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(ctx, &audit.Event{
+		Action:     ACTION_VIEW_WALLET,
+		TargetType: audit.TARGET_WALLET,
+		TargetId:   walletId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return targetWallet, nil
+}
+
+func (s *AdminService) GetWalletTransactions(ctx context.Context, walletId string) ([]*transaction.Transaction, error) {
+	_, err := s.walletService.GetWalletById(ctx, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionService.GetTransactionsByWalletId(ctx, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(ctx, &audit.Event{
+		Action:     ACTION_VIEW_WALLET_TRANSACTION,
+		TargetType: audit.TARGET_WALLET,
+		TargetId:   walletId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// Disable the wallet regardless of its current status
+func (s *AdminService) DisableWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error) {
+	return s.setWalletStatus(ctx, walletId, wallet.STATUS_DISABLED, ACTION_DISABLE_WALLET, reason)
+}
+
+// Freeze the wallet, it still receives deposits but its balance can't be withdrawn until it is unfrozen
+func (s *AdminService) FreezeWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error) {
+	return s.setWalletStatus(ctx, walletId, wallet.STATUS_FROZEN, ACTION_FREEZE_WALLET, reason)
+}
+
+// Enable the frozen wallet again
+func (s *AdminService) UnfreezeWallet(ctx context.Context, walletId, reason string) (*wallet.Wallet, error) {
+	if reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, walletId)
+	if err != nil {
+		return nil, err
+	}
+	if targetWallet.Status != wallet.STATUS_FROZEN {
+		return nil, ErrWalletNotFrozen
+	}
+
+	return s.setWalletStatus(ctx, walletId, wallet.STATUS_ENABLED, ACTION_UNFREEZE_WALLET, reason)
+}
+
+// Close the wallet for good, paying out the remaining balance if requested
+func (s *AdminService) CloseWallet(ctx context.Context, params *CloseWalletParams) (*wallet.Wallet, *transaction.Transaction, error) {
+	if params.Reason == "" {
+		return nil, nil, ErrEmptyReason
+	}
+
+	var closedWallet *wallet.Wallet
+	var payout *transaction.Transaction
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		closedWallet, payout, err = s.transactionService.CloseWallet(ctx, &transaction.CloseWalletParams{
+			WalletId:        params.WalletId,
+			Reason:          params.Reason,
+			PayoutRemainder: params.PayoutRemainder,
+			ReferenceId:     params.ReferenceId,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, &audit.Event{
+			Action:     ACTION_CLOSE_WALLET,
+			TargetType: audit.TARGET_WALLET,
+			TargetId:   params.WalletId,
+			Reason:     params.Reason,
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return closedWallet, payout, nil
+}
+
+// Credit or debit the wallet with a successful adjustment transaction
+func (s *AdminService) AdjustBalance(ctx context.Context, params *AdjustBalanceParams) (*transaction.Transaction, error) {
+	if params.Reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	var trx *transaction.Transaction
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		trx, err = s.transactionService.CreateAdjustment(ctx, &transaction.CreateAdjustmentParams{
+			WalletId:    params.WalletId,
+			ReferenceId: params.ReferenceId,
+			Amount:      params.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, &audit.Event{
+			Action:     ACTION_ADJUST_BALANCE,
+			TargetType: audit.TARGET_TRANSACTION,
+			TargetId:   trx.Id,
+			Reason:     params.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trx, nil
+}
+
+// Queue the settlement of the stuck pending transaction again.
+// The settlement is queued before the event is recorded since it runs outside of the database transaction
+func (s *AdminService) RetryTransaction(ctx context.Context, transactionId, reason string) (*transaction.Transaction, error) {
+	if reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	trx, err := s.transactionService.RetrySettlement(ctx, transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(ctx, &audit.Event{
+		Action:     ACTION_RETRY_TRANSACTION,
+		TargetType: audit.TARGET_TRANSACTION,
+		TargetId:   trx.Id,
+		Reason:     reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trx, nil
+}
+
+// Mark the stuck pending transaction as failed without moving the balance
+func (s *AdminService) FailTransaction(ctx context.Context, transactionId, reason string) (*transaction.Transaction, error) {
+	if reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	var trx *transaction.Transaction
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		trx, err = s.transactionService.FailTransaction(ctx, transactionId)
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, &audit.Event{
+			Action:     ACTION_FAIL_TRANSACTION,
+			TargetType: audit.TARGET_TRANSACTION,
+			TargetId:   trx.Id,
+			Reason:     reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trx, nil
+}
+
+// Replace the FX rates and record the action, the rates aren't replaced if the action can't be recorded
+func (s *AdminService) SetFxRates(ctx context.Context, params *SetFxRatesParams) ([]*fx.Rate, error) {
+	if params.Reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	var rates []*fx.Rate
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.fxService.GetRates(ctx)
+		if err != nil {
+			return err
+		}
+
+		rates, err = s.fxService.SetRates(ctx, params.Rates)
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, &audit.Event{
+			Action:     ACTION_SET_FX_RATES,
+			TargetType: audit.TARGET_FX_RATES,
+			Reason:     params.Reason,
+			Before:     audit.Snapshot(before),
+			After:      audit.Snapshot(rates),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (s *AdminService) setWalletStatus(ctx context.Context, walletId, status, action, reason string) (*wallet.Wallet, error) {
+	if reason == "" {
+		return nil, ErrEmptyReason
+	}
+
+	var targetWallet *wallet.Wallet
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		targetWallet, err = s.walletService.Transition(ctx, &wallet.TransitionParams{
+			WalletId: walletId,
+			Status:   status,
+			Reason:   reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, &audit.Event{
+			Action:     action,
+			TargetType: audit.TARGET_WALLET,
+			TargetId:   walletId,
+			Reason:     reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return targetWallet, nil
+}
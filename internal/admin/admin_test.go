@@ -0,0 +1,219 @@
+package admin_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	audit_mock "github.com/defryheryanto/mini-wallet/internal/audit/mocks"
+	client_mock "github.com/defryheryanto/mini-wallet/internal/client/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	fx_mock "github.com/defryheryanto/mini-wallet/internal/fx/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type serviceMocks struct {
+	walletService      *wallet_mock.WalletIService
+	transactionService *transaction_mock.TransactionIService
+	fxService          *fx_mock.FxIService
+	auditService       *audit_mock.AuditIService
+}
+
+func newService(t *testing.T) (*admin.AdminService, *serviceMocks) {
+	mocks := &serviceMocks{
+		walletService:      wallet_mock.NewWalletIService(t),
+		transactionService: transaction_mock.NewTransactionIService(t),
+		fxService:          fx_mock.NewFxIService(t),
+		auditService:       audit_mock.NewAuditIService(t),
+	}
+	service := admin.NewAdminService(
+		client_mock.NewClientIService(t),
+		mocks.walletService,
+		mocks.transactionService,
+		mocks.fxService,
+		mocks.auditService,
+		&manager.MockStorageManager{},
+	)
+
+	return service, mocks
+}
+
+func TestAdminService_FreezeWallet(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+
+	t.Run("should return error if reason is empty", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.FreezeWallet(context.TODO(), "wallet-id", "")
+		assert.Equal(t, admin.ErrEmptyReason, err)
+	})
+
+	t.Run("should return error if failed to set status", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("Transition", mock.Anything, &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "fraud report"}).Return(nil, wallet.ErrWalletAlreadyInStatus)
+
+		_, err := service.FreezeWallet(context.TODO(), "wallet-id", "fraud report")
+		assert.Equal(t, wallet.ErrWalletAlreadyInStatus, err)
+	})
+
+	t.Run("should return error if failed to record the action", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("Transition", mock.Anything, &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "fraud report"}).Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Return(mockedErr)
+
+		_, err := service.FreezeWallet(context.TODO(), "wallet-id", "fraud report")
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should freeze the wallet and record the action", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("Transition", mock.Anything, &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "fraud report"}).Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_FROZEN}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event, ok := args.Get(1).(*audit.Event)
+			assert.True(t, ok, "params should be *Event")
+			assert.Equal(t, admin.ACTION_FREEZE_WALLET, event.Action)
+			assert.Equal(t, audit.TARGET_WALLET, event.TargetType)
+			assert.Equal(t, "wallet-id", event.TargetId)
+			assert.Equal(t, "fraud report", event.Reason)
+		}).Return(nil)
+
+		frozenWallet, err := service.FreezeWallet(context.TODO(), "wallet-id", "fraud report")
+		assert.Nil(t, err)
+		assert.Equal(t, wallet.STATUS_FROZEN, frozenWallet.Status)
+	})
+}
+
+func TestAdminService_UnfreezeWallet(t *testing.T) {
+	t.Run("should return error if wallet is not frozen", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_DISABLED}, nil)
+
+		_, err := service.UnfreezeWallet(context.TODO(), "wallet-id", "resolved")
+		assert.Equal(t, admin.ErrWalletNotFrozen, err)
+	})
+
+	t.Run("should enable the frozen wallet", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_FROZEN}, nil)
+		mocks.walletService.On("Transition", mock.Anything, &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_ENABLED, Reason: "resolved"}).Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_ENABLED}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+		unfrozenWallet, err := service.UnfreezeWallet(context.TODO(), "wallet-id", "resolved")
+		assert.Nil(t, err)
+		assert.Equal(t, wallet.STATUS_ENABLED, unfrozenWallet.Status)
+	})
+}
+
+func TestAdminService_AdjustBalance(t *testing.T) {
+	t.Run("should return error if reason is empty", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.AdjustBalance(context.TODO(), &admin.AdjustBalanceParams{WalletId: "wallet-id", Amount: 100})
+		assert.Equal(t, admin.ErrEmptyReason, err)
+	})
+
+	t.Run("should create the adjustment and record the action", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.transactionService.On("CreateAdjustment", mock.Anything, &transaction.CreateAdjustmentParams{WalletId: "wallet-id", Amount: -100}).
+			Return(&transaction.Transaction{Id: "trx-id", Type: transaction.TYPE_ADJUSTMENT_DEBIT, Amount: 100}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*audit.Event)
+			assert.Equal(t, admin.ACTION_ADJUST_BALANCE, event.Action)
+			assert.Equal(t, "trx-id", event.TargetId)
+			assert.Equal(t, "duplicate charge", event.Reason)
+		}).Return(nil)
+
+		trx, err := service.AdjustBalance(context.TODO(), &admin.AdjustBalanceParams{WalletId: "wallet-id", Amount: -100, Reason: "duplicate charge"})
+		assert.Nil(t, err)
+		assert.Equal(t, "trx-id", trx.Id)
+	})
+}
+
+func TestAdminService_FailTransaction(t *testing.T) {
+	t.Run("should return error if transaction not pending", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.transactionService.On("FailTransaction", mock.Anything, "trx-id").Return(nil, transaction.ErrTransactionNotPending)
+
+		_, err := service.FailTransaction(context.TODO(), "trx-id", "stuck")
+		assert.Equal(t, transaction.ErrTransactionNotPending, err)
+	})
+
+	t.Run("should fail the transaction and record the action", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.transactionService.On("FailTransaction", mock.Anything, "trx-id").Return(&transaction.Transaction{Id: "trx-id", Status: transaction.STATUS_FAILED}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*audit.Event)
+			assert.Equal(t, admin.ACTION_FAIL_TRANSACTION, event.Action)
+			assert.Equal(t, audit.TARGET_TRANSACTION, event.TargetType)
+		}).Return(nil)
+
+		trx, err := service.FailTransaction(context.TODO(), "trx-id", "stuck")
+		assert.Nil(t, err)
+		assert.Equal(t, transaction.STATUS_FAILED, trx.Status)
+	})
+}
+
+func TestAdminService_GetWalletTransactions(t *testing.T) {
+	t.Run("should return error if wallet not found", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(nil, wallet.ErrWalletNotFound)
+
+		_, err := service.GetWalletTransactions(context.TODO(), "wallet-id")
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+	})
+
+	t.Run("should return transactions and record the view", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.transactionService.On("GetTransactionsByWalletId", mock.Anything, "wallet-id").Return([]*transaction.Transaction{{Id: "trx-id"}}, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+		transactions, err := service.GetWalletTransactions(context.TODO(), "wallet-id")
+		assert.Nil(t, err)
+		assert.Len(t, transactions, 1)
+	})
+}
+
+func TestAdminService_SetFxRates(t *testing.T) {
+	rates := []*fx.Rate{{From: "USD", To: "IDR", Rate: 15000}}
+
+	t.Run("should return error if reason is empty", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.SetFxRates(context.TODO(), &admin.SetFxRatesParams{Rates: rates})
+		assert.Equal(t, admin.ErrEmptyReason, err)
+	})
+
+	t.Run("should return error if rates are invalid", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.fxService.On("GetRates", mock.Anything).Return([]*fx.Rate{}, nil)
+		mocks.fxService.On("SetRates", mock.Anything, rates).Return(nil, fx.ErrInvalidRate)
+
+		_, err := service.SetFxRates(context.TODO(), &admin.SetFxRatesParams{Rates: rates, Reason: "daily update"})
+		assert.Equal(t, fx.ErrInvalidRate, err)
+	})
+
+	t.Run("should replace the rates and record the action", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.fxService.On("GetRates", mock.Anything).Return([]*fx.Rate{}, nil)
+		mocks.fxService.On("SetRates", mock.Anything, rates).Return(rates, nil)
+		mocks.auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*audit.Event)
+			assert.Equal(t, admin.ACTION_SET_FX_RATES, event.Action)
+			assert.Equal(t, audit.TARGET_FX_RATES, event.TargetType)
+			assert.Equal(t, "daily update", event.Reason)
+		}).Return(nil)
+
+		result, err := service.SetFxRates(context.TODO(), &admin.SetFxRatesParams{Rates: rates, Reason: "daily update"})
+		assert.Nil(t, err)
+		assert.Equal(t, rates, result)
+	})
+}
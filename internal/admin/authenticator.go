@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+type apiKey struct {
+	operator string
+	hash     [sha256.Size]byte
+}
+
+// Authenticator matches the API keys of the operators
+type Authenticator struct {
+	keys []apiKey
+}
+
+// Create the authenticator from the API key of each operator name.
+// The authenticator rejects every key if none is given
+func NewAuthenticator(keys map[string]string) *Authenticator {
+	authenticator := &Authenticator{}
+	for operator, key := range keys {
+		authenticator.keys = append(authenticator.keys, apiKey{operator, sha256.Sum256([]byte(key))})
+	}
+
+	return authenticator
+}
+
+// Return the operator owning the given API key.
+// Every key is compared in constant time so the response time doesn't leak which one matched
+func (a *Authenticator) Authenticate(key string) (*Operator, error) {
+	if key == "" {
+		return nil, ErrInvalidApiKey
+	}
+
+	hash := sha256.Sum256([]byte(key))
+	var matched *Operator
+	for _, candidate := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], candidate.hash[:]) == 1 {
+			matched = &Operator{Name: candidate.operator}
+		}
+	}
+	if matched == nil {
+		return nil, ErrInvalidApiKey
+	}
+
+	return matched, nil
+}
+
+// Parse the API keys formatted as {operator}:{key} separated by comma, e.g. "alice:secret1,bob:secret2"
+func ParseApiKeys(value string) (map[string]string, error) {
+	keys := map[string]string{}
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		operator, key, found := strings.Cut(entry, ":")
+		if !found || operator == "" || key == "" {
+			return nil, fmt.Errorf("invalid admin api key entry #%d, expected {operator}:{key}", i+1)
+		}
+		if _, exists := keys[operator]; exists {
+			return nil, fmt.Errorf("duplicate admin api key for operator %q", operator)
+		}
+		keys[operator] = key
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,55 @@
+package admin_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	authenticator := admin.NewAuthenticator(map[string]string{
+		"alice": "secret-1",
+		"bob":   "secret-2",
+	})
+
+	t.Run("should return the operator owning the key", func(t *testing.T) {
+		operator, err := authenticator.Authenticate("secret-2")
+		assert.Nil(t, err)
+		assert.Equal(t, "bob", operator.Name)
+	})
+
+	t.Run("should return error if key unknown", func(t *testing.T) {
+		_, err := authenticator.Authenticate("secret-3")
+		assert.Equal(t, admin.ErrInvalidApiKey, err)
+	})
+
+	t.Run("should return error if key empty", func(t *testing.T) {
+		_, err := admin.NewAuthenticator(map[string]string{}).Authenticate("")
+		assert.Equal(t, admin.ErrInvalidApiKey, err)
+	})
+}
+
+func TestParseApiKeys(t *testing.T) {
+	t.Run("should parse operator keys", func(t *testing.T) {
+		keys, err := admin.ParseApiKeys("alice:secret-1, bob:secret:2")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"alice": "secret-1", "bob": "secret:2"}, keys)
+	})
+
+	t.Run("should return empty keys if value empty", func(t *testing.T) {
+		keys, err := admin.ParseApiKeys("")
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("should return error if entry invalid", func(t *testing.T) {
+		_, err := admin.ParseApiKeys("alice")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return error if operator duplicated", func(t *testing.T) {
+		_, err := admin.ParseApiKeys("alice:secret-1,alice:secret-2")
+		assert.NotNil(t, err)
+	})
+}
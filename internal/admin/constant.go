@@ -0,0 +1,19 @@
+package admin
+
+// Prefix of the audit actor of the actions taken by an operator, followed by the operator name
+const ACTOR_PREFIX = "operator:"
+
+const (
+	ACTION_SEARCH_CLIENTS          = "clients.search"
+	ACTION_SEARCH_WALLETS          = "wallets.search"
+	ACTION_VIEW_WALLET             = "wallet.view"
+	ACTION_VIEW_WALLET_TRANSACTION = "wallet.transactions.view"
+	ACTION_DISABLE_WALLET          = "wallet.disable"
+	ACTION_FREEZE_WALLET           = "wallet.freeze"
+	ACTION_UNFREEZE_WALLET         = "wallet.unfreeze"
+	ACTION_CLOSE_WALLET            = "wallet.close"
+	ACTION_ADJUST_BALANCE          = "wallet.adjust_balance"
+	ACTION_RETRY_TRANSACTION       = "transaction.retry"
+	ACTION_FAIL_TRANSACTION        = "transaction.fail"
+	ACTION_SET_FX_RATES            = "fx.rates.set"
+)
@@ -0,0 +1,24 @@
+package admin
+
+import "context"
+
+type key string
+
+var operatorKey = key("operator_context")
+
+// Inject the operator into the current context
+func Inject(ctx context.Context, data *Operator) context.Context {
+	return context.WithValue(ctx, operatorKey, data)
+}
+
+// Extract the operator from the specified context
+//
+// Return error if operator is not exists in the context
+func FromContext(ctx context.Context) (*Operator, error) {
+	currentOperator, ok := ctx.Value(operatorKey).(*Operator)
+	if !ok || currentOperator == nil || currentOperator.Name == "" {
+		return nil, ErrInvalidOperator
+	}
+
+	return currentOperator, nil
+}
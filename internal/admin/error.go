@@ -0,0 +1,10 @@
+package admin
+
+import (
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrInvalidApiKey = errors.NewUnauthorizedError("admin api key invalid")
+var ErrInvalidOperator = errors.NewUnauthorizedError("operator invalid")
+var ErrEmptyReason = errors.NewValidationError("reason is required")
+var ErrWalletNotFrozen = errors.NewValidationError("wallet is not frozen")
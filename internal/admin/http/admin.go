@@ -0,0 +1,310 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/go-chi/chi/v5"
+)
+
+type ClientResponse struct {
+	Xid string `json:"xid"`
+}
+
+type ReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+type CloseWalletRequest struct {
+	Reason          string `json:"reason"`
+	PayoutRemainder bool   `json:"payout_remainder"`
+	// Reference of the payout of the remaining balance
+	ReferenceId string `json:"reference_id"`
+}
+
+type SetFxRatesRequest struct {
+	Rates  []*fx.Rate `json:"rates"`
+	Reason string     `json:"reason"`
+}
+
+type AdjustBalanceRequest struct {
+	// Positive amount credits the wallet, negative amount debits it
+	Amount      float64 `json:"amount"`
+	ReferenceId string  `json:"reference_id"`
+	Reason      string  `json:"reason"`
+}
+
+func HandleSearchClients(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := decodePagination(r)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		clients, err := service.SearchClients(r.Context(), &client.SearchClientsParams{
+			Query:  r.URL.Query().Get("query"),
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		res := []*ClientResponse{}
+		for _, c := range clients {
+			res = append(res, &ClientResponse{Xid: c.Xid})
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"clients": res,
+		})
+	}
+}
+
+func HandleSearchWallets(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := decodePagination(r)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		wallets, err := service.SearchWallets(r.Context(), &wallet.SearchWalletsParams{
+			Query:  r.URL.Query().Get("query"),
+			Status: r.URL.Query().Get("status"),
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallets": wallets,
+		})
+	}
+}
+
+func HandleViewWallet(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targetWallet, err := service.GetWallet(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallet": targetWallet,
+		})
+	}
+}
+
+func HandleGetWalletTransactions(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transactions, err := service.GetWalletTransactions(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if transactions == nil {
+			transactions = []*transaction.Transaction{}
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"transactions": transactions,
+		})
+	}
+}
+
+func HandleDisableWallet(service admin.AdminIService) http.HandlerFunc {
+	return handleWalletAction(service.DisableWallet)
+}
+
+func HandleFreezeWallet(service admin.AdminIService) http.HandlerFunc {
+	return handleWalletAction(service.FreezeWallet)
+}
+
+func HandleUnfreezeWallet(service admin.AdminIService) http.HandlerFunc {
+	return handleWalletAction(service.UnfreezeWallet)
+}
+
+func HandleCloseWallet(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CloseWalletRequest{}
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, admin.ErrEmptyReason)
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		closedWallet, payout, err := service.CloseWallet(r.Context(), &admin.CloseWalletParams{
+			WalletId:        chi.URLParam(r, "id"),
+			Reason:          requestBody.Reason,
+			PayoutRemainder: requestBody.PayoutRemainder,
+			ReferenceId:     requestBody.ReferenceId,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallet": closedWallet,
+			"payout": payout,
+		})
+	}
+}
+
+func HandleAdjustBalance(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &AdjustBalanceRequest{}
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, admin.ErrEmptyReason)
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		trx, err := service.AdjustBalance(r.Context(), &admin.AdjustBalanceParams{
+			WalletId:    chi.URLParam(r, "id"),
+			Amount:      requestBody.Amount,
+			ReferenceId: requestBody.ReferenceId,
+			Reason:      requestBody.Reason,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"transaction": trx,
+		})
+	}
+}
+
+func HandleRetryTransaction(service admin.AdminIService) http.HandlerFunc {
+	return handleTransactionAction(service.RetryTransaction)
+}
+
+func HandleFailTransaction(service admin.AdminIService) http.HandlerFunc {
+	return handleTransactionAction(service.FailTransaction)
+}
+
+func handleWalletAction(action func(ctx context.Context, walletId, reason string) (*wallet.Wallet, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reason, err := decodeReason(r)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		targetWallet, err := action(r.Context(), chi.URLParam(r, "id"), reason)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallet": targetWallet,
+		})
+	}
+}
+
+func handleTransactionAction(action func(ctx context.Context, transactionId, reason string) (*transaction.Transaction, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reason, err := decodeReason(r)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		trx, err := action(r.Context(), chi.URLParam(r, "id"), reason)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"transaction": trx,
+		})
+	}
+}
+
+// Replace the FX rate table with the rates of the body
+func HandleSetFxRates(service admin.AdminIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &SetFxRatesRequest{}
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, admin.ErrEmptyReason)
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		rates, err := service.SetFxRates(r.Context(), &admin.SetFxRatesParams{
+			Rates:  requestBody.Rates,
+			Reason: requestBody.Reason,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"rates": rates,
+		})
+	}
+}
+
+func decodeReason(r *http.Request) (string, error) {
+	requestBody := &ReasonRequest{}
+	err := request.DecodeBody(r, &requestBody)
+	if err != nil {
+		if err == io.EOF {
+			return "", admin.ErrEmptyReason
+		}
+		return "", err
+	}
+
+	return requestBody.Reason, nil
+}
+
+func decodePagination(r *http.Request) (limit, offset int, err error) {
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, errors.NewValidationError("limit must be a number")
+		}
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, errors.NewValidationError("offset must be a number")
+		}
+	}
+
+	return limit, offset, nil
+}
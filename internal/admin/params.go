@@ -0,0 +1,25 @@
+package admin
+
+import "github.com/defryheryanto/mini-wallet/internal/fx"
+
+type AdjustBalanceParams struct {
+	WalletId string `json:"wallet_id"`
+	// Positive amount credits the wallet, negative amount debits it
+	Amount      float64 `json:"amount"`
+	ReferenceId string  `json:"reference_id"`
+	Reason      string  `json:"reason"`
+}
+
+type SetFxRatesParams struct {
+	// Replace every rate of the table
+	Rates  []*fx.Rate `json:"rates"`
+	Reason string     `json:"reason"`
+}
+
+type CloseWalletParams struct {
+	WalletId string `json:"wallet_id"`
+	Reason   string `json:"reason"`
+	// Pay out the remaining balance instead of rejecting to close a wallet with balance
+	PayoutRemainder bool   `json:"payout_remainder"`
+	ReferenceId     string `json:"reference_id"`
+}
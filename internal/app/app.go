@@ -1,13 +1,58 @@
 package app
 
 import (
+	"log/slog"
+
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/balance"
 	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/health"
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/metrics"
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	"github.com/defryheryanto/mini-wallet/internal/statement"
 	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
 )
 
 type Application struct {
-	WalletService      wallet.WalletIService
-	ClientService      client.ClientIService
-	TransactionService transaction.TransactionIService
+	WalletService                 wallet.WalletIService
+	ClientService                 client.ClientIService
+	SignatureVerifier             *client.SignatureVerifier
+	TransactionService            transaction.TransactionIService
+	FxService                     fx.FxIService
+	StatementService              statement.StatementIService
+	StatementScheduler            *statement.Scheduler
+	BalanceService                balance.BalanceIService
+	BalanceScheduler              *balance.Scheduler
+	ScheduleService               schedule.ScheduleIService
+	TransferScheduler             *schedule.Scheduler
+	PayoutService                 payout.PayoutIService
+	PayoutProcessor               *payout.Processor
+	PaymentRequestService         paymentrequest.PaymentRequestIService
+	WebhookService                webhook.WebhookIService
+	WebhookDispatcher             *webhook.Dispatcher
+	EventService                  events.EventIService
+	EventPublisher                events.Publisher
+	EventRelay                    *events.Relay
+	ActivityBroker                *activity.Broker
+	TwoFactorService              twofactor.TwoFactorIService
+	WithdrawalConfirmationService transaction.WithdrawalConfirmationIService
+	HealthService                 health.HealthIService
+	AuditService                  audit.AuditIService
+	AdminService                  admin.AdminIService
+	AdminAuthenticator            *admin.Authenticator
+	RateLimiter                   *ratelimit.Limiter
+	Lifecycle                     *lifecycle.Manager
+	Metrics                       *metrics.Metrics
+	Logger                        *slog.Logger
 }
@@ -0,0 +1,227 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/google/uuid"
+)
+
+// Event records an action taken by an actor on a target.
+// Events of the same target are chained by including the hash of the previous event of the target in their own hash
+type Event struct {
+	Id string `json:"id"`
+	// Position of the event in the whole log, allocated without a lock so it may have gaps
+	Sequence int64 `json:"sequence"`
+	// Chain of the event, the target type and id. Empty for the events chained in a single chain before
+	ChainKey   string          `json:"chain_key"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   string          `json:"target_id"`
+	Reason     string          `json:"reason"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestId  string          `json:"request_id"`
+	Ip         string          `json:"ip"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ChainHead is the last event and the length of a chain, kept apart from the events
+// so the removal of the last events of a chain, or of the whole chain, is found by the verification
+type ChainHead struct {
+	ChainKey     string `json:"chain_key"`
+	Length       int64  `json:"length"`
+	LastSequence int64  `json:"last_sequence"`
+	LastHash     string `json:"last_hash"`
+}
+
+type EventRepository interface {
+	// Lock the chain until the end of the database transaction of the context,
+	// so only one event is appended to the chain at a time
+	LockChain(ctx context.Context, chainKey string) error
+	// Return the head of the chain, nil if the chain is empty
+	FindChainHead(ctx context.Context, chainKey string) (*ChainHead, error)
+	// Insert or replace the head of the chain
+	SaveChainHead(ctx context.Context, data *ChainHead) error
+	// Return up to limit heads with a chain key greater than the given one, in chain key order
+	FindChainHeads(ctx context.Context, chainKey string, limit int) ([]*ChainHead, error)
+	// Allocate the sequence of the next event of the log
+	NextSequence(ctx context.Context) (int64, error)
+	Insert(ctx context.Context, data *Event) error
+	Search(ctx context.Context, params *SearchEventsParams) ([]*Event, error)
+	// Return up to limit events with a sequence greater than the given one, in sequence order
+	FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*Event, error)
+}
+
+type AuditIService interface {
+	Record(ctx context.Context, event *Event) error
+	SearchEvents(ctx context.Context, params *SearchEventsParams) ([]*Event, error)
+	VerifyChain(ctx context.Context) (*Verification, error)
+}
+
+type AuditService struct {
+	repository     EventRepository
+	storageManager manager.StorageManager
+}
+
+func NewAuditService(repository EventRepository, storageManager manager.StorageManager) *AuditService {
+	return &AuditService{repository, storageManager}
+}
+
+// Append the event to the audit log, chained to the previous event of its target.
+// Only the chain of the target is locked, so changes of different wallets are recorded concurrently.
+// The actor, the request id and the IP address are taken from the context unless set on the event.
+// Record within the database transaction of the change so the event is only kept if the change is
+func (s *AuditService) Record(ctx context.Context, event *Event) error {
+	if event.Action == "" {
+		return ErrEmptyAction
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	event.Id = uuidRandom.String()
+	if event.Actor == "" {
+		event.Actor = ActorFromContext(ctx)
+	}
+	if event.RequestId == "" {
+		event.RequestId = logging.RequestIdFromContext(ctx)
+	}
+	if event.Ip == "" {
+		event.Ip = IpFromContext(ctx)
+	}
+	// The database keeps microseconds, the hash has to match the stored time
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.ChainKey = ChainKey(event.TargetType, event.TargetId)
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := s.repository.LockChain(ctx, event.ChainKey)
+		if err != nil {
+			return err
+		}
+
+		head, err := s.repository.FindChainHead(ctx, event.ChainKey)
+		if err != nil {
+			return err
+		}
+
+		// Allocated under the lock of the chain, so the sequences of a chain are increasing
+		event.Sequence, err = s.repository.NextSequence(ctx)
+		if err != nil {
+			return err
+		}
+
+		event.PrevHash = ""
+		length := int64(0)
+		if head != nil {
+			event.PrevHash = head.LastHash
+			length = head.Length
+		}
+		event.Hash = event.ComputeHash()
+
+		err = s.repository.Insert(ctx, event)
+		if err != nil {
+			return err
+		}
+
+		return s.repository.SaveChainHead(ctx, &ChainHead{
+			ChainKey:     event.ChainKey,
+			Length:       length + 1,
+			LastSequence: event.Sequence,
+			LastHash:     event.Hash,
+		})
+	})
+}
+
+func (s *AuditService) SearchEvents(ctx context.Context, params *SearchEventsParams) ([]*Event, error) {
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return nil, ErrInvalidTimeRange
+	}
+	if params.Limit <= 0 {
+		params.Limit = DEFAULT_SEARCH_LIMIT
+	}
+	if params.Limit > MAX_SEARCH_LIMIT {
+		params.Limit = MAX_SEARCH_LIMIT
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	events, err := s.repository.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Walk the whole audit log in sequence order and check every event against the previous one of its chain
+// and every chain against its head. Stop at the first event breaking a chain.
+//
+// The heads are read before the events, so the events appended meanwhile only extend the chains past their heads
+func (s *AuditService) VerifyChain(ctx context.Context) (*Verification, error) {
+	heads, err := s.findChainHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	verifier := newChainVerifier(heads)
+
+	var lastSequence int64
+	for {
+		events, err := s.repository.FindAfterSequence(ctx, lastSequence, VERIFY_BATCH_SIZE)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if !verifier.check(event) {
+				return verifier.result, nil
+			}
+		}
+
+		if len(events) < VERIFY_BATCH_SIZE {
+			verifier.checkHeadsReached()
+			return verifier.result, nil
+		}
+		lastSequence = events[len(events)-1].Sequence
+	}
+}
+
+// Return the heads of every chain by their chain key
+func (s *AuditService) findChainHeads(ctx context.Context) (map[string]*ChainHead, error) {
+	heads := map[string]*ChainHead{}
+
+	// The single chain the events were recorded in before they were chained per target has an empty chain key
+	head, err := s.repository.FindChainHead(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if head != nil {
+		heads[head.ChainKey] = head
+	}
+
+	var lastChainKey string
+	for {
+		page, err := s.repository.FindChainHeads(ctx, lastChainKey, VERIFY_BATCH_SIZE)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, head := range page {
+			heads[head.ChainKey] = head
+		}
+
+		if len(page) < VERIFY_BATCH_SIZE {
+			return heads, nil
+		}
+		lastChainKey = page[len(page)-1].ChainKey
+	}
+}
@@ -0,0 +1,380 @@
+package audit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/audit/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+
+	t.Run("should return error if action is empty", func(t *testing.T) {
+		service := audit.NewAuditService(mocks.NewEventRepository(t), &manager.MockStorageManager{})
+
+		err := service.Record(context.TODO(), &audit.Event{})
+		assert.Equal(t, audit.ErrEmptyAction, err)
+	})
+
+	t.Run("should return error if failed to lock the chain", func(t *testing.T) {
+		repository := mocks.NewEventRepository(t)
+		repository.On("LockChain", mock.Anything, "wallet:wallet-id").Return(mockedErr)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		err := service.Record(context.TODO(), &audit.Event{Action: "wallet.freeze", TargetType: audit.TARGET_WALLET, TargetId: "wallet-id"})
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should return error if failed to allocate the sequence", func(t *testing.T) {
+		repository := mocks.NewEventRepository(t)
+		repository.On("LockChain", mock.Anything, "wallet:wallet-id").Return(nil)
+		repository.On("FindChainHead", mock.Anything, "wallet:wallet-id").Return(nil, nil)
+		repository.On("NextSequence", mock.Anything).Return(int64(0), mockedErr)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		err := service.Record(context.TODO(), &audit.Event{Action: "wallet.freeze", TargetType: audit.TARGET_WALLET, TargetId: "wallet-id"})
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should start the chain of the target if it has no event", func(t *testing.T) {
+		repository := mocks.NewEventRepository(t)
+		repository.On("LockChain", mock.Anything, "wallet:wallet-id").Return(nil)
+		repository.On("FindChainHead", mock.Anything, "wallet:wallet-id").Return(nil, nil)
+		repository.On("NextSequence", mock.Anything).Return(int64(7), nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event, ok := args.Get(1).(*audit.Event)
+			assert.True(t, ok, "params should be *Event")
+			assert.Equal(t, int64(7), event.Sequence)
+			assert.Equal(t, "wallet:wallet-id", event.ChainKey)
+			assert.Equal(t, "", event.PrevHash)
+			assert.Equal(t, event.ComputeHash(), event.Hash)
+			assert.Equal(t, audit.ACTOR_SYSTEM, event.Actor)
+		}).Return(nil)
+		repository.On("SaveChainHead", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			head := args.Get(1).(*audit.ChainHead)
+			assert.Equal(t, "wallet:wallet-id", head.ChainKey)
+			assert.Equal(t, int64(1), head.Length)
+			assert.Equal(t, int64(7), head.LastSequence)
+		}).Return(nil)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		err := service.Record(context.TODO(), &audit.Event{Action: "wallet.freeze", TargetType: audit.TARGET_WALLET, TargetId: "wallet-id"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should chain the event to the last one of the target with the context of the request", func(t *testing.T) {
+		repository := mocks.NewEventRepository(t)
+		repository.On("LockChain", mock.Anything, "wallet:wallet-id").Return(nil)
+		repository.On("FindChainHead", mock.Anything, "wallet:wallet-id").Return(&audit.ChainHead{ChainKey: "wallet:wallet-id", Length: 3, LastSequence: 41, LastHash: "last-hash"}, nil)
+		repository.On("NextSequence", mock.Anything).Return(int64(42), nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*audit.Event)
+			assert.NotEmpty(t, event.Id)
+			assert.Equal(t, int64(42), event.Sequence)
+			assert.Equal(t, "last-hash", event.PrevHash)
+			assert.Equal(t, event.ComputeHash(), event.Hash)
+			assert.Equal(t, "operator:alice", event.Actor)
+			assert.Equal(t, "10.0.0.1", event.Ip)
+			assert.Equal(t, event.CreatedAt, event.CreatedAt.Truncate(time.Microsecond))
+		}).Return(nil)
+		repository.On("SaveChainHead", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			head := args.Get(1).(*audit.ChainHead)
+			assert.Equal(t, int64(4), head.Length)
+			assert.Equal(t, int64(42), head.LastSequence)
+			assert.NotEqual(t, "last-hash", head.LastHash)
+		}).Return(nil)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		ctx := audit.InjectActor(context.TODO(), "operator:alice")
+		ctx = audit.InjectIp(ctx, "10.0.0.1")
+		err := service.Record(ctx, &audit.Event{Action: "wallet.freeze", TargetType: audit.TARGET_WALLET, TargetId: "wallet-id"})
+		assert.Nil(t, err)
+	})
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	buildChain := func(length int) []*audit.Event {
+		events := []*audit.Event{}
+		prevHash := ""
+		for i := 1; i <= length; i++ {
+			event := &audit.Event{
+				Id:        fmt.Sprintf("event-%d", i),
+				Sequence:  int64(i),
+				Actor:     audit.ACTOR_SYSTEM,
+				Action:    "wallet.balance_added",
+				Before:    audit.Snapshot(map[string]int{"balance": i - 1}),
+				After:     audit.Snapshot(map[string]int{"balance": i}),
+				PrevHash:  prevHash,
+				CreatedAt: time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			}
+			event.Hash = event.ComputeHash()
+			prevHash = event.Hash
+			events = append(events, event)
+		}
+
+		return events
+	}
+
+	// Return the heads of the chains of the given events
+	headsOf := func(events []*audit.Event) []*audit.ChainHead {
+		heads := []*audit.ChainHead{}
+		byKey := map[string]*audit.ChainHead{}
+		for _, event := range events {
+			if event.Hash == "" {
+				continue
+			}
+			head := byKey[event.ChainKey]
+			if head == nil {
+				head = &audit.ChainHead{ChainKey: event.ChainKey}
+				byKey[event.ChainKey] = head
+				heads = append(heads, head)
+			}
+			head.Length++
+			head.LastSequence = event.Sequence
+			head.LastHash = event.Hash
+		}
+
+		return heads
+	}
+	newRepository := func(t *testing.T, events []*audit.Event, heads []*audit.ChainHead) *mocks.EventRepository {
+		var singleChainHead *audit.ChainHead
+		targetHeads := []*audit.ChainHead{}
+		for _, head := range heads {
+			if head.ChainKey == "" {
+				singleChainHead = head
+				continue
+			}
+			targetHeads = append(targetHeads, head)
+		}
+
+		repository := mocks.NewEventRepository(t)
+		repository.On("FindChainHead", mock.Anything, "").Return(singleChainHead, nil)
+		repository.On("FindChainHeads", mock.Anything, "", audit.VERIFY_BATCH_SIZE).Return(targetHeads, nil)
+		repository.On("FindAfterSequence", mock.Anything, int64(0), audit.VERIFY_BATCH_SIZE).Return(events, nil)
+		return repository
+	}
+
+	t.Run("should return valid for an intact chain", func(t *testing.T) {
+		repository := newRepository(t, buildChain(3), headsOf(buildChain(3)))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Equal(t, int64(3), verification.Checked)
+		assert.Equal(t, int64(3), verification.LastSequence)
+	})
+
+	t.Run("should detect a modified event", func(t *testing.T) {
+		events := buildChain(3)
+		events[1].After = audit.Snapshot(map[string]int{"balance": 1_000_000})
+
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(2), verification.BrokenSequence)
+	})
+
+	t.Run("should detect a removed event", func(t *testing.T) {
+		events := buildChain(3)
+		events = append(events[:1], events[2:]...)
+
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(3), verification.BrokenSequence)
+	})
+
+	t.Run("should detect a rehashed event by the next one", func(t *testing.T) {
+		events := buildChain(3)
+		events[0].Reason = "tampered"
+		events[0].Hash = events[0].ComputeHash()
+
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(2), verification.BrokenSequence)
+	})
+
+	t.Run("should skip the events recorded before the chain", func(t *testing.T) {
+		legacy := &audit.Event{Id: "legacy", Sequence: 1, Action: "wallet.freeze"}
+		chained := &audit.Event{Id: "chained", Sequence: 2, Action: "wallet.freeze"}
+		chained.Hash = chained.ComputeHash()
+
+		events := []*audit.Event{legacy, chained}
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Equal(t, int64(1), verification.Unchained)
+		assert.Equal(t, int64(1), verification.Checked)
+	})
+
+	// Events alternating between two wallets, with a sequence gap left by a rolled back append
+	buildTargetChains := func(singleChainLength int) []*audit.Event {
+		events := buildChain(singleChainLength)
+		prevHashes := map[string]string{}
+		sequence := int64(singleChainLength)
+		for i := 1; i <= 4; i++ {
+			sequence++
+			if i == 3 {
+				sequence++
+			}
+			targetId := fmt.Sprintf("wallet-%d", i%2)
+			event := &audit.Event{
+				Id:         fmt.Sprintf("target-event-%d", i),
+				Sequence:   sequence,
+				ChainKey:   audit.ChainKey(audit.TARGET_WALLET, targetId),
+				Actor:      audit.ACTOR_SYSTEM,
+				Action:     "wallet.balance_added",
+				TargetType: audit.TARGET_WALLET,
+				TargetId:   targetId,
+				PrevHash:   prevHashes[targetId],
+				CreatedAt:  time.Date(2024, 1, 2, 0, 0, i, 0, time.UTC),
+			}
+			event.Hash = event.ComputeHash()
+			prevHashes[targetId] = event.Hash
+			events = append(events, event)
+		}
+
+		return events
+	}
+
+	t.Run("should return valid for intact chains of the targets after the single chain", func(t *testing.T) {
+		repository := newRepository(t, buildTargetChains(2), headsOf(buildTargetChains(2)))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Equal(t, int64(6), verification.Checked)
+		assert.Equal(t, int64(7), verification.LastSequence)
+	})
+
+	t.Run("should detect an event removed from the chain of a target", func(t *testing.T) {
+		events := buildTargetChains(2)
+		// Remove the first event of wallet-1, the next event of wallet-1 no longer matches
+		events = append(events[:2], events[3:]...)
+
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(6), verification.BrokenSequence)
+	})
+
+	t.Run("should detect an event moved to another target", func(t *testing.T) {
+		events := buildTargetChains(0)
+		events[1].TargetId = "wallet-1"
+
+		repository := newRepository(t, events, headsOf(events))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(2), verification.BrokenSequence)
+	})
+
+	t.Run("should detect an event moved to another target along with its chain key", func(t *testing.T) {
+		events := buildTargetChains(0)
+		events[0].TargetId = "wallet-0"
+		events[0].ChainKey = audit.ChainKey(audit.TARGET_WALLET, "wallet-0")
+
+		repository := newRepository(t, events, headsOf(buildTargetChains(0)))
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(1), verification.BrokenSequence)
+	})
+
+	t.Run("should detect the last event removed from the chain of a target", func(t *testing.T) {
+		events := buildTargetChains(2)
+		heads := headsOf(events)
+		events = events[:len(events)-1]
+
+		repository := newRepository(t, events, heads)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(7), verification.BrokenSequence)
+	})
+
+	t.Run("should detect the last event removed from the single chain", func(t *testing.T) {
+		events := buildChain(3)
+		heads := headsOf(events)
+		events = events[:2]
+
+		repository := newRepository(t, events, heads)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, int64(3), verification.BrokenSequence)
+	})
+
+	t.Run("should detect the whole chain of a target removed", func(t *testing.T) {
+		events := buildTargetChains(0)
+		heads := headsOf(events)
+		// Keep the events of wallet-0 only
+		events = []*audit.Event{events[1], events[3]}
+
+		repository := newRepository(t, events, heads)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.Equal(t, "no event of the chain found, the events of the target were removed", verification.Reason)
+	})
+
+	t.Run("should detect an event of a target removed and replaced by a later one", func(t *testing.T) {
+		events := buildTargetChains(0)
+		heads := headsOf(events)
+		// The last event of wallet-0 is removed and the chain grows again from the one before
+		events = events[:3]
+		extra := &audit.Event{
+			Id:         "extra",
+			Sequence:   9,
+			ChainKey:   events[1].ChainKey,
+			Action:     "wallet.balance_added",
+			TargetType: audit.TARGET_WALLET,
+			TargetId:   events[1].TargetId,
+			PrevHash:   events[1].Hash,
+		}
+		extra.Hash = extra.ComputeHash()
+		events = append(events, extra)
+
+		repository := newRepository(t, events, heads)
+		service := audit.NewAuditService(repository, &manager.MockStorageManager{})
+
+		verification, err := service.VerifyChain(context.TODO())
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+	})
+}
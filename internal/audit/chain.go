@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Verification is the outcome of checking the hash chain of the audit log
+type Verification struct {
+	Valid bool `json:"valid"`
+	// Number of chained events checked
+	Checked int64 `json:"checked"`
+	// Number of events recorded before the log was chained, they can't be verified
+	Unchained    int64 `json:"unchained"`
+	LastSequence int64 `json:"last_sequence"`
+	// Sequence of the first event failing the verification and why, set if the chain is invalid
+	BrokenSequence int64  `json:"broken_sequence,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+// Return the key of the chain of the events of the target
+func ChainKey(targetType, targetId string) string {
+	return targetType + ":" + targetId
+}
+
+// Return the hex encoded SHA-256 of the event fields, its chain and the hash of the previous event.
+// The fields are encoded as a JSON array so their boundaries are unambiguous
+func (e *Event) ComputeHash() string {
+	fields := []interface{}{
+		e.Sequence,
+		e.Id,
+		e.Actor,
+		e.Action,
+		e.TargetType,
+		e.TargetId,
+		e.Reason,
+		string(e.Before),
+		string(e.After),
+		e.RequestId,
+		e.Ip,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.PrevHash,
+	}
+	// The events of the single chain were hashed before they had a chain key
+	if e.ChainKey != "" {
+		fields = append(fields, e.ChainKey)
+	}
+
+	canonical, _ := json.Marshal(fields)
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Encode the state of the target for the before and after snapshots of an event
+//
+// Return nil if the state is nil or can't be encoded
+func Snapshot(state interface{}) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+
+	return encoded
+}
+
+// chainVerifier checks the events one by one in sequence order.
+// It keeps the hash and the length of every chain seen, one per audited target,
+// and checks the chains against their heads recorded along with the events
+type chainVerifier struct {
+	result *Verification
+	// Last event of the single chain the events were recorded in before they were chained per target
+	previous   *Event
+	lastHashes map[string]string
+	lengths    map[string]int64
+	// Heads of the chains whose last event is not reached yet
+	heads map[string]*ChainHead
+}
+
+func newChainVerifier(heads map[string]*ChainHead) *chainVerifier {
+	return &chainVerifier{
+		result:     &Verification{Valid: true},
+		lastHashes: map[string]string{},
+		lengths:    map[string]int64{},
+		heads:      heads,
+	}
+}
+
+// Check the event against the previous one of its chain.
+// Return false once a chain is broken
+func (v *chainVerifier) check(event *Event) bool {
+	defer func() {
+		v.result.LastSequence = event.Sequence
+	}()
+
+	if event.Hash == "" {
+		if v.result.Checked > 0 {
+			return v.fail(event, "event is not chained")
+		}
+		v.result.Unchained++
+		v.previous = event
+		return true
+	}
+	if event.ChainKey == "" {
+		if !v.checkSingleChain(event) {
+			return false
+		}
+		return v.checkHead(event)
+	}
+
+	if event.ChainKey != ChainKey(event.TargetType, event.TargetId) {
+		return v.fail(event, "chain mismatch, the event was moved to another target")
+	}
+	if event.PrevHash != v.lastHashes[event.ChainKey] {
+		return v.fail(event, "previous hash mismatch, an earlier event of the target was modified or removed")
+	}
+	if event.ComputeHash() != event.Hash {
+		return v.fail(event, "hash mismatch, the event was modified")
+	}
+
+	v.lastHashes[event.ChainKey] = event.Hash
+	v.result.Checked++
+	return v.checkHead(event)
+}
+
+// Count the event in its chain and check it against the head of the chain if it is the last event of the head
+func (v *chainVerifier) checkHead(event *Event) bool {
+	v.lengths[event.ChainKey]++
+
+	head := v.heads[event.ChainKey]
+	if head == nil || head.LastSequence != event.Sequence {
+		return true
+	}
+	if head.LastHash != event.Hash || head.Length != v.lengths[event.ChainKey] {
+		return v.fail(event, "chain head mismatch, an event of the target was removed")
+	}
+
+	delete(v.heads, event.ChainKey)
+	return true
+}
+
+// Fail on the head whose last event is not found, the earliest first
+func (v *chainVerifier) checkHeadsReached() {
+	var missing *ChainHead
+	for _, head := range v.heads {
+		if missing == nil || head.LastSequence < missing.LastSequence {
+			missing = head
+		}
+	}
+	if missing == nil {
+		return
+	}
+
+	reason := "last event of the chain not found, the last events of the target were removed"
+	if v.lengths[missing.ChainKey] == 0 {
+		reason = "no event of the chain found, the events of the target were removed"
+	}
+	v.failAt(missing.LastSequence, reason)
+}
+
+// Check the event recorded in the single chain against the previous one
+func (v *chainVerifier) checkSingleChain(event *Event) bool {
+	defer func() {
+		v.previous = event
+	}()
+
+	expectedSequence := int64(1)
+	expectedPrevHash := ""
+	if v.previous != nil {
+		expectedSequence = v.previous.Sequence + 1
+		expectedPrevHash = v.previous.Hash
+	}
+
+	if event.Sequence != expectedSequence {
+		return v.fail(event, "sequence gap, an event was removed")
+	}
+	if event.PrevHash != expectedPrevHash {
+		return v.fail(event, "previous hash mismatch, an earlier event was modified")
+	}
+	if event.ComputeHash() != event.Hash {
+		return v.fail(event, "hash mismatch, the event was modified")
+	}
+
+	v.result.Checked++
+	return true
+}
+
+func (v *chainVerifier) fail(event *Event, reason string) bool {
+	return v.failAt(event.Sequence, reason)
+}
+
+func (v *chainVerifier) failAt(sequence int64, reason string) bool {
+	v.result.Valid = false
+	v.result.BrokenSequence = sequence
+	v.result.Reason = reason
+	return false
+}
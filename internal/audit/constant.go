@@ -0,0 +1,18 @@
+package audit
+
+const ACTOR_SYSTEM = "system"
+
+const (
+	TARGET_CLIENT      = "client"
+	TARGET_WALLET      = "wallet"
+	TARGET_TRANSACTION = "transaction"
+	TARGET_FX_RATES    = "fx_rates"
+)
+
+const (
+	DEFAULT_SEARCH_LIMIT = 50
+	MAX_SEARCH_LIMIT     = 500
+)
+
+// Number of events read at once while verifying the chain
+const VERIFY_BATCH_SIZE = 1000
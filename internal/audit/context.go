@@ -0,0 +1,38 @@
+package audit
+
+import "context"
+
+type key string
+
+var actorKey = key("audit_actor_context")
+var ipKey = key("audit_ip_context")
+
+// Inject the actor recorded on the audit events of the current context, e.g. "operator:alice"
+func InjectActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Extract the actor from the specified context
+//
+// Return ACTOR_SYSTEM if actor is not exists in the context
+func ActorFromContext(ctx context.Context) string {
+	actor, ok := ctx.Value(actorKey).(string)
+	if !ok || actor == "" {
+		return ACTOR_SYSTEM
+	}
+
+	return actor
+}
+
+// Inject the IP address of the request recorded on the audit events of the current context
+func InjectIp(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ipKey, ip)
+}
+
+// Extract the IP address from the specified context
+//
+// Return empty string if IP address is not exists in the context
+func IpFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ipKey).(string)
+	return ip
+}
@@ -0,0 +1,8 @@
+package audit
+
+import (
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyAction = errors.NewValidationError("audit action is required")
+var ErrInvalidTimeRange = errors.NewValidationError("from must be before to")
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+)
+
+func HandleSearchEvents(service audit.AuditIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		params := &audit.SearchEventsParams{
+			Actor:      query.Get("actor"),
+			Action:     query.Get("action"),
+			TargetType: query.Get("target_type"),
+			TargetId:   query.Get("target_id"),
+			RequestId:  query.Get("request_id"),
+		}
+
+		var err error
+		if params.From, err = parseTime(query.Get("from"), "from"); err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if params.To, err = parseTime(query.Get("to"), "to"); err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if params.Limit, err = parseInt(query.Get("limit"), "limit"); err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if params.Offset, err = parseInt(query.Get("offset"), "offset"); err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		events, err := service.SearchEvents(r.Context(), params)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"events": events,
+		})
+	}
+}
+
+func HandleVerifyChain(service audit.AuditIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		verification, err := service.VerifyChain(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"verification": verification,
+		})
+	}
+}
+
+func parseTime(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.NewValidationError(field + " must be RFC 3339 time")
+	}
+
+	return &parsed, nil
+}
+
+func parseInt(value, field string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.NewValidationError(field + " must be a number")
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	audit "github.com/defryheryanto/mini-wallet/internal/audit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditIService is an autogenerated mock type for the AuditIService type
+type AuditIService struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: ctx, event
+func (_m *AuditIService) Record(ctx context.Context, event *audit.Event) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Event) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchEvents provides a mock function with given fields: ctx, params
+func (_m *AuditIService) SearchEvents(ctx context.Context, params *audit.SearchEventsParams) ([]*audit.Event, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*audit.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.SearchEventsParams) ([]*audit.Event, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.SearchEventsParams) []*audit.Event); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *audit.SearchEventsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyChain provides a mock function with given fields: ctx
+func (_m *AuditIService) VerifyChain(ctx context.Context) (*audit.Verification, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *audit.Verification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*audit.Verification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *audit.Verification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.Verification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditIService creates a new instance of AuditIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditIService(t mockConstructorTestingTNewAuditIService) *AuditIService {
+	mock := &AuditIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,201 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	audit "github.com/defryheryanto/mini-wallet/internal/audit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventRepository is an autogenerated mock type for the EventRepository type
+type EventRepository struct {
+	mock.Mock
+}
+
+// FindAfterSequence provides a mock function with given fields: ctx, sequence, limit
+func (_m *EventRepository) FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*audit.Event, error) {
+	ret := _m.Called(ctx, sequence, limit)
+
+	var r0 []*audit.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*audit.Event, error)); ok {
+		return rf(ctx, sequence, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*audit.Event); ok {
+		r0 = rf(ctx, sequence, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, sequence, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindChainHead provides a mock function with given fields: ctx, chainKey
+func (_m *EventRepository) FindChainHead(ctx context.Context, chainKey string) (*audit.ChainHead, error) {
+	ret := _m.Called(ctx, chainKey)
+
+	var r0 *audit.ChainHead
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*audit.ChainHead, error)); ok {
+		return rf(ctx, chainKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *audit.ChainHead); ok {
+		r0 = rf(ctx, chainKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.ChainHead)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, chainKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindChainHeads provides a mock function with given fields: ctx, chainKey, limit
+func (_m *EventRepository) FindChainHeads(ctx context.Context, chainKey string, limit int) ([]*audit.ChainHead, error) {
+	ret := _m.Called(ctx, chainKey, limit)
+
+	var r0 []*audit.ChainHead
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*audit.ChainHead, error)); ok {
+		return rf(ctx, chainKey, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*audit.ChainHead); ok {
+		r0 = rf(ctx, chainKey, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.ChainHead)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, chainKey, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *EventRepository) Insert(ctx context.Context, data *audit.Event) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Event) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockChain provides a mock function with given fields: ctx, chainKey
+func (_m *EventRepository) LockChain(ctx context.Context, chainKey string) error {
+	ret := _m.Called(ctx, chainKey)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, chainKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NextSequence provides a mock function with given fields: ctx
+func (_m *EventRepository) NextSequence(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveChainHead provides a mock function with given fields: ctx, data
+func (_m *EventRepository) SaveChainHead(ctx context.Context, data *audit.ChainHead) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.ChainHead) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, params
+func (_m *EventRepository) Search(ctx context.Context, params *audit.SearchEventsParams) ([]*audit.Event, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*audit.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.SearchEventsParams) ([]*audit.Event, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.SearchEventsParams) []*audit.Event); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *audit.SearchEventsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEventRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventRepository creates a new instance of EventRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventRepository(t mockConstructorTestingTNewEventRepository) *EventRepository {
+	mock := &EventRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,15 @@
+package audit
+
+import "time"
+
+type SearchEventsParams struct {
+	Actor      string     `json:"actor"`
+	Action     string     `json:"action"`
+	TargetType string     `json:"target_type"`
+	TargetId   string     `json:"target_id"`
+	RequestId  string     `json:"request_id"`
+	From       *time.Time `json:"from"`
+	To         *time.Time `json:"to"`
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+}
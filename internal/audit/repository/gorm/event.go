@@ -0,0 +1,150 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Seed of the hash of the chain key giving the key of the advisory lock serializing the appends to the chain
+const chainLockKey = 7_265_432_901
+
+type EventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) *EventRepository {
+	return &EventRepository{db}
+}
+
+func (r *EventRepository) LockChain(ctx context.Context, chainKey string) error {
+	db := r.getGormClient(ctx)
+	return db.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, ?))", chainKey, chainLockKey).Error
+}
+
+func (r *EventRepository) FindChainHead(ctx context.Context, chainKey string) (*audit.ChainHead, error) {
+	result := &ChainHead{}
+
+	db := r.getGormClient(ctx)
+	err := db.Where("chain_key = ?", chainKey).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *EventRepository) SaveChainHead(ctx context.Context, data *audit.ChainHead) error {
+	payload := ChainHead{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"length", "last_sequence", "last_hash"}),
+	}).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EventRepository) FindChainHeads(ctx context.Context, chainKey string, limit int) ([]*audit.ChainHead, error) {
+	heads := []*ChainHead{}
+
+	err := r.db.WithContext(ctx).Where("chain_key > ?", chainKey).Order("chain_key").Limit(limit).Find(&heads).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*audit.ChainHead{}
+	for _, head := range heads {
+		res = append(res, head.ToServiceModel())
+	}
+
+	return res, nil
+}
+
+func (r *EventRepository) NextSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+
+	db := r.getGormClient(ctx)
+	err := db.Raw("SELECT nextval('audit_events_sequence_seq')").Scan(&sequence).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return sequence, nil
+}
+
+func (r *EventRepository) Insert(ctx context.Context, data *audit.Event) error {
+	payload := Event{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EventRepository) Search(ctx context.Context, params *audit.SearchEventsParams) ([]*audit.Event, error) {
+	events := []*Event{}
+
+	query := r.db.WithContext(ctx)
+	if params.Actor != "" {
+		query = query.Where("actor = ?", params.Actor)
+	}
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+	if params.TargetType != "" {
+		query = query.Where("target_type = ?", params.TargetType)
+	}
+	if params.TargetId != "" {
+		query = query.Where("target_id = ?", params.TargetId)
+	}
+	if params.RequestId != "" {
+		query = query.Where("request_id = ?", params.RequestId)
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at < ?", *params.To)
+	}
+
+	err := query.Order("sequence DESC").Limit(params.Limit).Offset(params.Offset).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(events), nil
+}
+
+func (r *EventRepository) FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*audit.Event, error) {
+	events := []*Event{}
+
+	err := r.db.WithContext(ctx).Where("sequence > ?", sequence).Order("sequence").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(events), nil
+}
+
+func (r *EventRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
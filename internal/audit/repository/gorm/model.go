@@ -0,0 +1,124 @@
+package gorm
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+)
+
+type Event struct {
+	Id             string    `gorm:"primaryKey;column:id"`
+	Sequence       int64     `gorm:"column:sequence"`
+	ChainKey       string    `gorm:"column:chain_key"`
+	Actor          string    `gorm:"column:actor"`
+	Action         string    `gorm:"column:action"`
+	TargetType     string    `gorm:"column:target_type"`
+	TargetId       string    `gorm:"column:target_id"`
+	Reason         string    `gorm:"column:reason"`
+	BeforeSnapshot string    `gorm:"column:before_snapshot"`
+	AfterSnapshot  string    `gorm:"column:after_snapshot"`
+	RequestId      string    `gorm:"column:request_id"`
+	Ip             string    `gorm:"column:ip"`
+	PrevHash       string    `gorm:"column:prev_hash"`
+	Hash           string    `gorm:"column:hash"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+func (Event) FromServiceModel(data *audit.Event) *Event {
+	if data == nil {
+		return nil
+	}
+
+	return &Event{
+		Id:             data.Id,
+		Sequence:       data.Sequence,
+		ChainKey:       data.ChainKey,
+		Actor:          data.Actor,
+		Action:         data.Action,
+		TargetType:     data.TargetType,
+		TargetId:       data.TargetId,
+		Reason:         data.Reason,
+		BeforeSnapshot: string(data.Before),
+		AfterSnapshot:  string(data.After),
+		RequestId:      data.RequestId,
+		Ip:             data.Ip,
+		PrevHash:       data.PrevHash,
+		Hash:           data.Hash,
+		CreatedAt:      data.CreatedAt,
+	}
+}
+
+func (e *Event) ToServiceModel() *audit.Event {
+	return &audit.Event{
+		Id:         e.Id,
+		Sequence:   e.Sequence,
+		ChainKey:   e.ChainKey,
+		Actor:      e.Actor,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetId:   e.TargetId,
+		Reason:     e.Reason,
+		Before:     toRawMessage(e.BeforeSnapshot),
+		After:      toRawMessage(e.AfterSnapshot),
+		RequestId:  e.RequestId,
+		Ip:         e.Ip,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+type ChainHead struct {
+	ChainKey     string `gorm:"primaryKey;column:chain_key"`
+	Length       int64  `gorm:"column:length"`
+	LastSequence int64  `gorm:"column:last_sequence"`
+	LastHash     string `gorm:"column:last_hash"`
+}
+
+func (ChainHead) TableName() string {
+	return "audit_chain_heads"
+}
+
+func (ChainHead) FromServiceModel(data *audit.ChainHead) *ChainHead {
+	if data == nil {
+		return nil
+	}
+
+	return &ChainHead{
+		ChainKey:     data.ChainKey,
+		Length:       data.Length,
+		LastSequence: data.LastSequence,
+		LastHash:     data.LastHash,
+	}
+}
+
+func (h *ChainHead) ToServiceModel() *audit.ChainHead {
+	return &audit.ChainHead{
+		ChainKey:     h.ChainKey,
+		Length:       h.Length,
+		LastSequence: h.LastSequence,
+		LastHash:     h.LastHash,
+	}
+}
+
+func SliceToServiceModel(data []*Event) []*audit.Event {
+	res := []*audit.Event{}
+	for _, d := range data {
+		res = append(res, d.ToServiceModel())
+	}
+
+	return res
+}
+
+func toRawMessage(snapshot string) json.RawMessage {
+	if snapshot == "" {
+		return nil
+	}
+
+	return json.RawMessage(snapshot)
+}
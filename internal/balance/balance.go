@@ -0,0 +1,193 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+)
+
+// Snapshot is the balance of a wallet at the end of a day, computed from its successful transactions
+type Snapshot struct {
+	WalletId string `json:"wallet_id"`
+	// End of the day, the balance includes the transactions made before this time
+	AsOf      time.Time `json:"as_of"`
+	Balance   float64   `json:"balance"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointInTimeBalance is the balance of a wallet at a past time
+type PointInTimeBalance struct {
+	WalletId string    `json:"wallet_id"`
+	At       time.Time `json:"at"`
+	Balance  float64   `json:"balance"`
+	Currency string    `json:"currency"`
+}
+
+type SnapshotRepository interface {
+	// Insert the snapshot unless the wallet already has a snapshot of the same time
+	Insert(ctx context.Context, data *Snapshot) error
+	// Return the latest snapshot of the wallet taken at or before the given time
+	FindLatest(ctx context.Context, walletId string, at time.Time) (*Snapshot, error)
+}
+
+type BalanceIService interface {
+	GetBalanceAt(ctx context.Context, customerXid, walletId string, at time.Time) (*PointInTimeBalance, error)
+	SnapshotDay(ctx context.Context, day time.Time) (int, error)
+}
+
+type BalanceService struct {
+	repository         SnapshotRepository
+	walletService      wallet.WalletIService
+	transactionService transaction.TransactionIService
+}
+
+func NewBalanceService(
+	repository SnapshotRepository,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+) *BalanceService {
+	return &BalanceService{repository, walletService, transactionService}
+}
+
+// Return the balance of the given wallet of the customer at the given time, or of its default wallet if the wallet id is empty.
+// Zero time returns the balance now
+func (s *BalanceService) GetBalanceAt(ctx context.Context, customerXid, walletId string, at time.Time) (*PointInTimeBalance, error) {
+	now := time.Now()
+	if at.IsZero() {
+		at = now
+	}
+	if at.After(now) {
+		return nil, ErrFutureTime
+	}
+
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+	if targetWallet.Status == wallet.STATUS_DISABLED {
+		return nil, wallet.ErrWalletDisabled
+	}
+
+	walletCurrency, err := currency.Find(targetWallet.Currency)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.balanceAt(ctx, targetWallet.Id, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PointInTimeBalance{
+		WalletId: targetWallet.Id,
+		At:       at,
+		Balance:  walletCurrency.Round(balance),
+		Currency: walletCurrency.Code,
+	}, nil
+}
+
+// Record the balance at the end of the day of the given time for every wallet whose balance changed during the day.
+// The wallets skipped keep their previous snapshot, which still holds. A wallet failing doesn't stop the others.
+//
+// Return the number of recorded snapshots
+func (s *BalanceService) SnapshotDay(ctx context.Context, day time.Time) (int, error) {
+	dayStart := DayStart(day)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	if dayEnd.After(time.Now()) {
+		return 0, ErrDayNotEnded
+	}
+
+	logger := logging.FromContext(ctx)
+	recorded := 0
+
+	for offset := 0; ; offset += wallet.MAX_SEARCH_LIMIT {
+		if err := ctx.Err(); err != nil {
+			return recorded, err
+		}
+
+		wallets, err := s.walletService.SearchWallets(ctx, &wallet.SearchWalletsParams{Limit: wallet.MAX_SEARCH_LIMIT, Offset: offset})
+		if err != nil {
+			return recorded, err
+		}
+
+		for _, targetWallet := range wallets {
+			snapshot, err := s.snapshot(ctx, targetWallet, dayStart, dayEnd)
+			if err != nil {
+				logger.Error("error recording balance snapshot", logging.KEY_WALLET_ID, targetWallet.Id, logging.KEY_ERROR, err)
+				continue
+			}
+			if snapshot != nil {
+				recorded++
+			}
+		}
+
+		if len(wallets) < wallet.MAX_SEARCH_LIMIT {
+			return recorded, nil
+		}
+	}
+}
+
+// Return nil snapshot if the balance didn't change during the day
+func (s *BalanceService) snapshot(ctx context.Context, targetWallet *wallet.Wallet, dayStart, dayEnd time.Time) (*Snapshot, error) {
+	walletCurrency, err := currency.Find(targetWallet.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.transactionService.GetBalanceChange(ctx, targetWallet.Id, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	if walletCurrency.Round(change) == 0 {
+		return nil, nil
+	}
+
+	balance, err := s.balanceAt(ctx, targetWallet.Id, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		WalletId:  targetWallet.Id,
+		AsOf:      dayEnd,
+		Balance:   walletCurrency.Round(balance),
+		Currency:  walletCurrency.Code,
+		CreatedAt: time.Now(),
+	}
+	err = s.repository.Insert(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Return the balance from the latest snapshot before the given time plus the transactions made since,
+// or from every transaction before the given time if the wallet has no snapshot yet
+func (s *BalanceService) balanceAt(ctx context.Context, walletId string, at time.Time) (float64, error) {
+	snapshot, err := s.repository.FindLatest(ctx, walletId, at)
+	if err != nil {
+		return 0, err
+	}
+
+	from := time.Time{}
+	balance := float64(0)
+	if snapshot != nil {
+		if !snapshot.AsOf.Before(at) {
+			return snapshot.Balance, nil
+		}
+		from = snapshot.AsOf
+		balance = snapshot.Balance
+	}
+
+	change, err := s.transactionService.GetBalanceChange(ctx, walletId, from, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance + change, nil
+}
@@ -0,0 +1,129 @@
+package balance_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/balance"
+	balance_mock "github.com/defryheryanto/mini-wallet/internal/balance/mocks"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type serviceMocks struct {
+	repository         *balance_mock.SnapshotRepository
+	walletService      *wallet_mock.WalletIService
+	transactionService *transaction_mock.TransactionIService
+}
+
+func newService(t *testing.T) (*balance.BalanceService, *serviceMocks) {
+	mocks := &serviceMocks{
+		repository:         balance_mock.NewSnapshotRepository(t),
+		walletService:      wallet_mock.NewWalletIService(t),
+		transactionService: transaction_mock.NewTransactionIService(t),
+	}
+
+	return balance.NewBalanceService(mocks.repository, mocks.walletService, mocks.transactionService), mocks
+}
+
+func TestBalanceService_GetBalanceAt(t *testing.T) {
+	at := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	targetWallet := &wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_ENABLED, Currency: "IDR"}
+
+	t.Run("should return error if time is in the future", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.GetBalanceAt(context.TODO(), "test", "", time.Now().Add(time.Hour))
+		assert.Equal(t, balance.ErrFutureTime, err)
+	})
+
+	t.Run("should return error if wallet is disabled", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_DISABLED}, nil)
+
+		_, err := service.GetBalanceAt(context.TODO(), "test", "", at)
+		assert.Equal(t, wallet.ErrWalletDisabled, err)
+	})
+
+	t.Run("should sum every transaction if wallet has no snapshot", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "").Return(targetWallet, nil)
+		mocks.repository.On("FindLatest", mock.Anything, "wallet-id", at).Return(nil, nil)
+		mocks.transactionService.On("GetBalanceChange", mock.Anything, "wallet-id", time.Time{}, at).Return(float64(150), nil)
+
+		result, err := service.GetBalanceAt(context.TODO(), "test", "", at)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(150), result.Balance)
+		assert.Equal(t, "IDR", result.Currency)
+	})
+
+	t.Run("should add the transactions made since the latest snapshot", func(t *testing.T) {
+		asOf := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "wallet-id").Return(targetWallet, nil)
+		mocks.repository.On("FindLatest", mock.Anything, "wallet-id", at).Return(&balance.Snapshot{WalletId: "wallet-id", AsOf: asOf, Balance: 1000}, nil)
+		mocks.transactionService.On("GetBalanceChange", mock.Anything, "wallet-id", asOf, at).Return(-0.1, nil)
+
+		result, err := service.GetBalanceAt(context.TODO(), "test", "wallet-id", at)
+		assert.Nil(t, err)
+		assert.Equal(t, 999.9, result.Balance)
+	})
+
+	t.Run("should return the snapshot taken at the given time", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "").Return(targetWallet, nil)
+		mocks.repository.On("FindLatest", mock.Anything, "wallet-id", at).Return(&balance.Snapshot{WalletId: "wallet-id", AsOf: at, Balance: 500}, nil)
+
+		result, err := service.GetBalanceAt(context.TODO(), "test", "", at)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(500), result.Balance)
+	})
+}
+
+func TestBalanceService_SnapshotDay(t *testing.T) {
+	day := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	dayStart := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	dayEnd := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should return error if the day has not ended", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.SnapshotDay(context.TODO(), time.Now())
+		assert.Equal(t, balance.ErrDayNotEnded, err)
+	})
+
+	t.Run("should return error if failed to list the wallets", func(t *testing.T) {
+		mockedErr := fmt.Errorf("mocked")
+		service, mocks := newService(t)
+		mocks.walletService.On("SearchWallets", mock.Anything, mock.Anything).Return(nil, mockedErr)
+
+		_, err := service.SnapshotDay(context.TODO(), day)
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should record the wallets whose balance changed during the day", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("SearchWallets", mock.Anything, &wallet.SearchWalletsParams{Limit: wallet.MAX_SEARCH_LIMIT}).Return([]*wallet.Wallet{
+			{Id: "idle-id", Currency: "IDR"},
+			{Id: "active-id", Currency: "IDR"},
+		}, nil)
+		mocks.transactionService.On("GetBalanceChange", mock.Anything, "idle-id", dayStart, dayEnd).Return(float64(0), nil)
+		mocks.transactionService.On("GetBalanceChange", mock.Anything, "active-id", dayStart, dayEnd).Return(float64(100), nil)
+		mocks.repository.On("FindLatest", mock.Anything, "active-id", dayEnd).Return(&balance.Snapshot{WalletId: "active-id", AsOf: dayStart, Balance: 400}, nil)
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			snapshot := args.Get(1).(*balance.Snapshot)
+			assert.Equal(t, "active-id", snapshot.WalletId)
+			assert.Equal(t, dayEnd, snapshot.AsOf)
+			assert.Equal(t, float64(500), snapshot.Balance)
+		}).Return(nil)
+
+		recorded, err := service.SnapshotDay(context.TODO(), day)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, recorded)
+	})
+}
@@ -0,0 +1,10 @@
+package balance
+
+import "time"
+
+// Delay after the end of the day before the snapshots are taken,
+// so transactions made right before the end of the day are settled
+const SNAPSHOT_DELAY = time.Hour
+
+// Delay before taking the snapshots again after listing the wallets failed
+const SNAPSHOT_RETRY_DELAY = 5 * time.Minute
@@ -0,0 +1,9 @@
+package balance
+
+import "time"
+
+// Return the start of the day of the given time in UTC
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
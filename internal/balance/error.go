@@ -0,0 +1,6 @@
+package balance
+
+import "github.com/defryheryanto/mini-wallet/internal/errors"
+
+var ErrFutureTime = errors.NewValidationError("at must not be in the future")
+var ErrDayNotEnded = errors.NewValidationError("snapshot day has not ended yet")
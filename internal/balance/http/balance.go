@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/balance"
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// Respond the balance of the wallet at `at`, an RFC 3339 time or a date meaning the end of that day (UTC).
+// The current balance is responded if `at` is empty
+func HandleGetBalance(service balance.BalanceIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		at, err := parseAt(r.URL.Query().Get("at"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.GetBalanceAt(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), at)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"balance": result,
+		})
+	}
+}
+
+func parseAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.NewValidationError("at must be RFC 3339 time or date")
+	}
+
+	// The end of today is not known yet, so today means now
+	endOfDay := parsed.AddDate(0, 0, 1)
+	if now := time.Now(); endOfDay.After(now) && !parsed.After(now) {
+		return now, nil
+	}
+
+	return endOfDay, nil
+}
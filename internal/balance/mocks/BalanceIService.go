@@ -0,0 +1,83 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	balance "github.com/defryheryanto/mini-wallet/internal/balance"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BalanceIService is an autogenerated mock type for the BalanceIService type
+type BalanceIService struct {
+	mock.Mock
+}
+
+// GetBalanceAt provides a mock function with given fields: ctx, customerXid, walletId, at
+func (_m *BalanceIService) GetBalanceAt(ctx context.Context, customerXid string, walletId string, at time.Time) (*balance.PointInTimeBalance, error) {
+	ret := _m.Called(ctx, customerXid, walletId, at)
+
+	var r0 *balance.PointInTimeBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*balance.PointInTimeBalance, error)); ok {
+		return rf(ctx, customerXid, walletId, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *balance.PointInTimeBalance); ok {
+		r0 = rf(ctx, customerXid, walletId, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*balance.PointInTimeBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, customerXid, walletId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SnapshotDay provides a mock function with given fields: ctx, day
+func (_m *BalanceIService) SnapshotDay(ctx context.Context, day time.Time) (int, error) {
+	ret := _m.Called(ctx, day)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, day)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, day)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, day)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewBalanceIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewBalanceIService creates a new instance of BalanceIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewBalanceIService(t mockConstructorTestingTNewBalanceIService) *BalanceIService {
+	mock := &BalanceIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,73 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	balance "github.com/defryheryanto/mini-wallet/internal/balance"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SnapshotRepository is an autogenerated mock type for the SnapshotRepository type
+type SnapshotRepository struct {
+	mock.Mock
+}
+
+// FindLatest provides a mock function with given fields: ctx, walletId, at
+func (_m *SnapshotRepository) FindLatest(ctx context.Context, walletId string, at time.Time) (*balance.Snapshot, error) {
+	ret := _m.Called(ctx, walletId, at)
+
+	var r0 *balance.Snapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*balance.Snapshot, error)); ok {
+		return rf(ctx, walletId, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *balance.Snapshot); ok {
+		r0 = rf(ctx, walletId, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*balance.Snapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, walletId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *SnapshotRepository) Insert(ctx context.Context, data *balance.Snapshot) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *balance.Snapshot) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSnapshotRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSnapshotRepository creates a new instance of SnapshotRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSnapshotRepository(t mockConstructorTestingTNewSnapshotRepository) *SnapshotRepository {
+	mock := &SnapshotRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
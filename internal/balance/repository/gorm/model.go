@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/balance"
+)
+
+type Snapshot struct {
+	WalletId  string    `gorm:"primaryKey;column:wallet_id"`
+	AsOf      time.Time `gorm:"primaryKey;column:as_of"`
+	Balance   float64   `gorm:"column:balance"`
+	Currency  string    `gorm:"column:currency"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (Snapshot) TableName() string {
+	return "balance_snapshots"
+}
+
+func (Snapshot) FromServiceModel(data *balance.Snapshot) *Snapshot {
+	if data == nil {
+		return nil
+	}
+
+	return &Snapshot{
+		WalletId:  data.WalletId,
+		AsOf:      data.AsOf,
+		Balance:   data.Balance,
+		Currency:  data.Currency,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func (s *Snapshot) ToServiceModel() *balance.Snapshot {
+	return &balance.Snapshot{
+		WalletId:  s.WalletId,
+		AsOf:      s.AsOf.UTC(),
+		Balance:   s.Balance,
+		Currency:  s.Currency,
+		CreatedAt: s.CreatedAt,
+	}
+}
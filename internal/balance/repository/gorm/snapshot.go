@@ -0,0 +1,54 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/balance"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) *SnapshotRepository {
+	return &SnapshotRepository{db}
+}
+
+func (r *SnapshotRepository) Insert(ctx context.Context, data *balance.Snapshot) error {
+	snapshot := Snapshot{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&snapshot).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *SnapshotRepository) FindLatest(ctx context.Context, walletId string, at time.Time) (*balance.Snapshot, error) {
+	snapshot := &Snapshot{}
+
+	err := r.getGormClient(ctx).Where("wallet_id = ? AND as_of <= ?", walletId, at).Order("as_of DESC").First(&snapshot).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return snapshot.ToServiceModel(), nil
+}
+
+func (r *SnapshotRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
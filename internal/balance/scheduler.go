@@ -0,0 +1,51 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Scheduler records the balance snapshots once a day has ended.
+// On start it records the snapshots of the last ended day, days missed before that are still answered from the transactions.
+// Snapshots are idempotent, so every instance can run its own scheduler.
+// Shutdown stops the running snapshot
+type Scheduler struct {
+	*lifecycle.Loop
+	service BalanceIService
+	// Day recorded next
+	day time.Time
+}
+
+func NewScheduler(service BalanceIService) *Scheduler {
+	s := &Scheduler{
+		service: service,
+		day:     DayStart(time.Now()).AddDate(0, 0, -1),
+	}
+	s.Loop = lifecycle.NewInterruptibleLoop("balance_snapshot", s.snapshot)
+	return s
+}
+
+// Record the snapshots of the day once it has ended and move on to the next day.
+// Return how long to wait for the end of the day, or before retrying the failed snapshot
+func (s *Scheduler) snapshot(ctx context.Context) time.Duration {
+	due := s.day.AddDate(0, 0, 1).Add(SNAPSHOT_DELAY)
+	if time.Now().Before(due) {
+		return time.Until(due)
+	}
+
+	logger := logging.FromContext(ctx)
+	day := s.day.Format(time.DateOnly)
+	logger.Info("recording balance snapshots", "day", day)
+	recorded, err := s.service.SnapshotDay(ctx, s.day)
+	if err != nil {
+		logger.Error("error recording balance snapshots", "day", day, logging.KEY_ERROR, err)
+		return SNAPSHOT_RETRY_DELAY
+	}
+	logger.Info("balance snapshots recorded", "day", day, "count", recorded)
+
+	s.day = s.day.AddDate(0, 0, 1)
+	return time.Until(s.day.AddDate(0, 0, 1).Add(SNAPSHOT_DELAY))
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/audit"
 	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/google/uuid"
 )
 
 type Client struct {
@@ -14,31 +17,56 @@ type Client struct {
 	Token string `json:"token"`
 }
 
+type SearchClientsParams struct {
+	// Match the xid by prefix
+	Query  string `json:"query"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
 type ClientRepository interface {
 	Insert(ctx context.Context, data *Client) error
 	FindByXid(ctx context.Context, xid string) (*Client, error)
-	FindByToken(ctx context.Context, token string) (*Client, error)
+	Search(ctx context.Context, params *SearchClientsParams) ([]*Client, error)
 }
 
 type ClientIService interface {
 	Create(ctx context.Context, xid string) (*Client, error)
-	GetByToken(ctx context.Context, token string) (*Client, error)
+	GetByToken(ctx context.Context, token string) (*Client, *Token, error)
+	GetTokens(ctx context.Context, xid string) ([]*Token, error)
+	CreateToken(ctx context.Context, xid, currentTokenId string, scopes []string) (*Token, error)
+	RotateToken(ctx context.Context, xid, currentTokenId string) (*Token, error)
+	RevokeToken(ctx context.Context, xid, tokenId string) error
+	SetSignatureRequired(ctx context.Context, xid, tokenId string, required bool) (*Token, error)
+	RehashUnhashedTokens(ctx context.Context) (int, error)
+	SearchClients(ctx context.Context, params *SearchClientsParams) ([]*Client, error)
+	GetByXid(ctx context.Context, xid string) (*Client, error)
 }
 
 type ClientService struct {
-	repository     ClientRepository
-	walletService  wallet.WalletIService
-	storageManager manager.StorageManager
+	repository      ClientRepository
+	tokenRepository TokenRepository
+	walletService   wallet.WalletIService
+	auditService    audit.AuditIService
+	storageManager  manager.StorageManager
+	tokenHasher     *TokenHasher
+	tokenTTL        time.Duration
 }
 
 func NewClientService(
 	repository ClientRepository,
+	tokenRepository TokenRepository,
 	walletService wallet.WalletIService,
+	auditService audit.AuditIService,
 	storageManager manager.StorageManager,
+	tokenHasher *TokenHasher,
+	tokenTTL time.Duration,
 ) ClientIService {
-	return &ClientService{repository, walletService, storageManager}
+	return &ClientService{repository, tokenRepository, walletService, auditService, storageManager, tokenHasher, tokenTTL}
 }
 
+// Create the client along with its wallet and first token.
+// The returned client holds the issued token
 func (s *ClientService) Create(ctx context.Context, xid string) (*Client, error) {
 	existingClient, err := s.repository.FindByXid(ctx, xid)
 	if err != nil {
@@ -48,28 +76,32 @@ func (s *ClientService) Create(ctx context.Context, xid string) (*Client, error)
 		return nil, ErrXidAlreadyTaken
 	}
 
-	var token string
-	for {
-		token = s.generateToken()
-		clientByToken, err := s.repository.FindByToken(ctx, token)
-		if err != nil {
-			return nil, err
+	var issuedToken *Token
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		createdClient := &Client{
+			Xid: xid,
 		}
-		if clientByToken == nil {
-			break
+		err = s.repository.Insert(ctx, createdClient)
+		if err != nil {
+			return err
 		}
-	}
 
-	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
-		err = s.repository.Insert(ctx, &Client{
-			Xid:   xid,
-			Token: token,
+		err = s.auditService.Record(ctx, &audit.Event{
+			Action:     AUDIT_ACTION_CREATED,
+			TargetType: audit.TARGET_CLIENT,
+			TargetId:   xid,
+			After:      audit.Snapshot(createdClient),
 		})
 		if err != nil {
 			return err
 		}
 
-		err = s.walletService.Create(ctx, &wallet.CreateWalletParams{
+		issuedToken, err = s.issueToken(ctx, xid, ALL_SCOPES, false)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.walletService.Create(ctx, &wallet.CreateWalletParams{
 			OwnedBy: xid,
 		})
 		if err != nil {
@@ -86,23 +118,293 @@ func (s *ClientService) Create(ctx context.Context, xid string) (*Client, error)
 	if err != nil {
 		return nil, err
 	}
+	if existingClient == nil {
+		return nil, ErrInvalidClient
+	}
+	existingClient.Token = issuedToken.Token
+
+	return existingClient, nil
+}
+
+// Return the client owning the given token along with the token.
+//
+// Return error if the token is unknown, expired or revoked
+func (s *ClientService) GetByToken(ctx context.Context, token string) (*Client, *Token, error) {
+	currentToken, err := s.findToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if currentToken == nil {
+		return nil, nil, ErrInvalidToken
+	}
+	if currentToken.IsRevoked() {
+		return nil, nil, ErrTokenRevoked
+	}
+	if currentToken.IsExpired(time.Now()) {
+		return nil, nil, ErrTokenExpired
+	}
+
+	currentClient, err := s.repository.FindByXid(ctx, currentToken.ClientXid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if currentClient == nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	return currentClient, currentToken, nil
+}
+
+func (s *ClientService) GetTokens(ctx context.Context, xid string) ([]*Token, error) {
+	tokens, err := s.tokenRepository.FindByClientXid(ctx, xid)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Issue an additional token for the client limited to the given scopes, with the signature requirement of the current token.
+// The scopes must be granted to the current token, so a token can't issue one with more permissions
+// and a token required to sign its requests can't issue one that isn't
+func (s *ClientService) CreateToken(ctx context.Context, xid, currentTokenId string, scopes []string) (*Token, error) {
+	if len(scopes) == 0 {
+		return nil, ErrEmptyScopes
+	}
+
+	currentToken, err := s.getOwnedToken(ctx, xid, currentTokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+		if !currentToken.HasScope(scope) {
+			return nil, ErrScopeNotGranted
+		}
+	}
+
+	issuedToken, err := s.issueToken(ctx, xid, scopes, currentToken.SignatureRequired)
+	if err != nil {
+		return nil, err
+	}
+
+	return issuedToken, nil
+}
+
+// Issue a new token with the scopes and signature requirement of the current one and revoke the current one
+func (s *ClientService) RotateToken(ctx context.Context, xid, currentTokenId string) (*Token, error) {
+	currentToken, err := s.getOwnedToken(ctx, xid, currentTokenId)
+	if err != nil {
+		return nil, err
+	}
+	if currentToken.IsRevoked() {
+		return nil, ErrTokenAlreadyRevoked
+	}
+
+	var issuedToken *Token
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		issuedToken, err = s.issueToken(ctx, xid, currentToken.Scopes, currentToken.SignatureRequired)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		currentToken.RevokedAt = &now
+		err = s.tokenRepository.Update(ctx, currentToken)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issuedToken, nil
+}
+
+func (s *ClientService) RevokeToken(ctx context.Context, xid, tokenId string) error {
+	targetToken, err := s.getOwnedToken(ctx, xid, tokenId)
+	if err != nil {
+		return err
+	}
+	if targetToken.IsRevoked() {
+		return ErrTokenAlreadyRevoked
+	}
+
+	now := time.Now()
+	targetToken.RevokedAt = &now
+	err = s.tokenRepository.Update(ctx, targetToken)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Require the requests made with the token to be signed.
+// The requirement is permanent, so a leaked token with tokens:manage can't lift it from the other tokens
+func (s *ClientService) SetSignatureRequired(ctx context.Context, xid, tokenId string, required bool) (*Token, error) {
+	targetToken, err := s.getOwnedToken(ctx, xid, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	if targetToken.IsRevoked() {
+		return nil, ErrTokenAlreadyRevoked
+	}
+	if targetToken.SignatureRequired && !required {
+		return nil, ErrSignatureRequirementPermanent
+	}
+
+	targetToken.SignatureRequired = required
+	err = s.tokenRepository.Update(ctx, targetToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetToken, nil
+}
+
+// Hash every token that is still stored in plaintext, then erase the plaintext.
+// Return the number of hashed tokens
+func (s *ClientService) RehashUnhashedTokens(ctx context.Context) (int, error) {
+	tokens, err := s.tokenRepository.FindUnhashed(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, token := range tokens {
+		token.Prefix = TokenPrefix(token.Token)
+		token.Hash = s.tokenHasher.Hash(token.Token)
+		token.Token = ""
+
+		err = s.tokenRepository.Update(ctx, token)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(tokens), nil
+}
+
+func (s *ClientService) SearchClients(ctx context.Context, params *SearchClientsParams) ([]*Client, error) {
+	if params.Limit <= 0 {
+		params.Limit = DEFAULT_SEARCH_LIMIT
+	}
+	if params.Limit > MAX_SEARCH_LIMIT {
+		params.Limit = MAX_SEARCH_LIMIT
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	clients, err := s.repository.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// Return the client of the xid, without token
+func (s *ClientService) GetByXid(ctx context.Context, xid string) (*Client, error) {
+	existingClient, err := s.repository.FindByXid(ctx, xid)
+	if err != nil {
+		return nil, err
+	}
+	if existingClient == nil {
+		return nil, ErrClientNotFound
+	}
 
 	return existingClient, nil
 }
 
-func (s *ClientService) GetByToken(ctx context.Context, token string) (*Client, error) {
-	currentClient, err := s.repository.FindByToken(ctx, token)
+// Look up the stored token by its prefix and compare the hashes
+//
+// Return nil if no stored token matches
+func (s *ClientService) findToken(ctx context.Context, token string) (*Token, error) {
+	candidates, err := s.tokenRepository.FindByPrefix(ctx, TokenPrefix(token))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if s.tokenHasher.Matches(token, candidate.Hash) {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *ClientService) getOwnedToken(ctx context.Context, xid, tokenId string) (*Token, error) {
+	targetToken, err := s.tokenRepository.FindById(ctx, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	if targetToken == nil || targetToken.ClientXid != xid {
+		return nil, ErrTokenNotFound
+	}
+
+	return targetToken, nil
+}
+
+func (s *ClientService) issueToken(ctx context.Context, xid string, scopes []string, signatureRequired bool) (*Token, error) {
+	var token string
+	for {
+		var err error
+		token, err = s.generateToken()
+		if err != nil {
+			return nil, err
+		}
+		existingToken, err := s.findToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if existingToken == nil {
+			break
+		}
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	hash := s.tokenHasher.Hash(token)
+	issuedToken := &Token{
+		Id:                uuidRandom.String(),
+		ClientXid:         xid,
+		Token:             token,
+		Prefix:            TokenPrefix(token),
+		Hash:              hash,
+		Scopes:            scopes,
+		SignatureRequired: signatureRequired,
+		SigningSecret:     s.tokenHasher.SigningSecret(hash),
+		CreatedAt:         now,
+	}
+	if s.tokenTTL > 0 {
+		expiresAt := now.Add(s.tokenTTL)
+		issuedToken.ExpiresAt = &expiresAt
+	}
+
+	err = s.tokenRepository.Insert(ctx, issuedToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return currentClient, nil
+	return issuedToken, nil
 }
 
-func (s *ClientService) generateToken() string {
-	b := make([]byte, 10)
+func (s *ClientService) generateToken() (string, error) {
+	b := make([]byte, TOKEN_BYTES)
 	if _, err := rand.Read(b); err != nil {
-		return ""
+		return "", err
 	}
-	return hex.EncodeToString(b)
+	return hex.EncodeToString(b), nil
 }
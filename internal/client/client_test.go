@@ -4,15 +4,27 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	audit_mock "github.com/defryheryanto/mini-wallet/internal/audit/mocks"
 	"github.com/defryheryanto/mini-wallet/internal/client"
 	client_mock "github.com/defryheryanto/mini-wallet/internal/client/mocks"
 	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
 	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// Return the audit service accepting any event, for the tests not asserting the recorded events
+func newAuditService(t *testing.T) *audit_mock.AuditIService {
+	auditService := audit_mock.NewAuditIService(t)
+	auditService.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return auditService
+}
+
 func TestClientService_Create(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 	xid := "random-string-xid"
@@ -21,9 +33,10 @@ func TestClientService_Create(t *testing.T) {
 
 		repository := client_mock.NewClientRepository(t)
 		repository.On("FindByXid", mock.Anything, xid).Return(nil, mockedErr)
+		tokenRepository := client_mock.NewTokenRepository(t)
 
 		storageManager := &manager.MockStorageManager{}
-		service := client.NewClientService(repository, walletService, storageManager)
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
 
 		res, err := service.Create(context.TODO(), xid)
 		assert.Equal(t, mockedErr, err)
@@ -35,40 +48,61 @@ func TestClientService_Create(t *testing.T) {
 
 		repository := client_mock.NewClientRepository(t)
 		repository.On("FindByXid", mock.Anything, xid).Return(&client.Client{}, nil)
+		tokenRepository := client_mock.NewTokenRepository(t)
 
 		storageManager := &manager.MockStorageManager{}
-		service := client.NewClientService(repository, walletService, storageManager)
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
 
 		res, err := service.Create(context.TODO(), xid)
 		assert.Equal(t, client.ErrXidAlreadyTaken, err)
 		assert.Nil(t, res)
 	})
 
+	t.Run("should return error if failed to insert client data", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+
+		repository := client_mock.NewClientRepository(t)
+		repository.On("FindByXid", mock.Anything, xid).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
+		tokenRepository := client_mock.NewTokenRepository(t)
+
+		storageManager := &manager.MockStorageManager{}
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.Create(context.TODO(), xid)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, res)
+	})
+
 	t.Run("should return error failed to find by token", func(t *testing.T) {
 		walletService := wallet_mock.NewWalletIService(t)
 
 		repository := client_mock.NewClientRepository(t)
 		repository.On("FindByXid", mock.Anything, xid).Return(nil, nil)
-		repository.On("FindByToken", mock.Anything, mock.Anything).Return(nil, mockedErr)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, mockedErr)
 
 		storageManager := &manager.MockStorageManager{}
-		service := client.NewClientService(repository, walletService, storageManager)
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
 
 		res, err := service.Create(context.TODO(), xid)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, res)
 	})
 
-	t.Run("should return error if failed to insert client data", func(t *testing.T) {
+	t.Run("should return error if failed to insert token", func(t *testing.T) {
 		walletService := wallet_mock.NewWalletIService(t)
 
 		repository := client_mock.NewClientRepository(t)
 		repository.On("FindByXid", mock.Anything, xid).Return(nil, nil)
-		repository.On("FindByToken", mock.Anything, mock.Anything).Return(nil, nil)
-		repository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
 
 		storageManager := &manager.MockStorageManager{}
-		service := client.NewClientService(repository, walletService, storageManager)
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
 
 		res, err := service.Create(context.TODO(), xid)
 		assert.Equal(t, mockedErr, err)
@@ -78,17 +112,377 @@ func TestClientService_Create(t *testing.T) {
 	t.Run("should return error if failed to create wallet", func(t *testing.T) {
 		repository := client_mock.NewClientRepository(t)
 		repository.On("FindByXid", mock.Anything, xid).Return(nil, nil)
-		repository.On("FindByToken", mock.Anything, mock.Anything).Return(nil, nil)
 		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("Create", mock.Anything, mock.Anything).Return(mockedErr)
+		walletService.On("Create", mock.Anything, mock.Anything).Return(nil, mockedErr)
 
 		storageManager := &manager.MockStorageManager{}
-		service := client.NewClientService(repository, walletService, storageManager)
+		service := client.NewClientService(repository, tokenRepository, walletService, newAuditService(t), storageManager, client.NewTokenHasher("pepper"), time.Hour)
 
 		res, err := service.Create(context.TODO(), xid)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, res)
 	})
+
+	t.Run("should return client with issued token if operation success", func(t *testing.T) {
+		repository := client_mock.NewClientRepository(t)
+		repository.On("FindByXid", mock.Anything, xid).Return(nil, nil).Once()
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		repository.On("FindByXid", mock.Anything, xid).Return(&client.Client{Xid: xid}, nil).Once()
+
+		var issuedToken *client.Token
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			insertParams, ok := args.Get(1).(*client.Token)
+			assert.True(t, ok, "params should be *Token")
+			assert.Equal(t, xid, insertParams.ClientXid)
+			assert.Len(t, insertParams.Token, client.TOKEN_BYTES*2)
+			assert.Equal(t, client.TokenPrefix(insertParams.Token), insertParams.Prefix)
+			assert.Equal(t, client.NewTokenHasher("pepper").Hash(insertParams.Token), insertParams.Hash)
+			assert.NotNil(t, insertParams.ExpiresAt)
+			issuedToken = insertParams
+		}).Return(nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Create", mock.Anything, mock.Anything).Return(&wallet.Wallet{}, nil)
+
+		auditService := audit_mock.NewAuditIService(t)
+		auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event, ok := args.Get(1).(*audit.Event)
+			assert.True(t, ok, "params should be *Event")
+			assert.Equal(t, client.AUDIT_ACTION_CREATED, event.Action)
+			assert.Equal(t, xid, event.TargetId)
+			assert.JSONEq(t, `{"xid": "random-string-xid", "token": ""}`, string(event.After))
+		}).Return(nil)
+
+		storageManager := &manager.MockStorageManager{}
+		service := client.NewClientService(repository, tokenRepository, walletService, auditService, storageManager, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.Create(context.TODO(), xid)
+		assert.Nil(t, err)
+		assert.Equal(t, xid, res.Xid)
+		assert.Equal(t, issuedToken.Token, res.Token)
+	})
+}
+
+func TestClientService_GetByToken(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	hasher := client.NewTokenHasher("pepper")
+	token := "test-token-value"
+	prefix := client.TokenPrefix(token)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	t.Run("should return error if failed to find token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return(nil, mockedErr)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		res, resToken, err := service.GetByToken(context.TODO(), token)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, res)
+		assert.Nil(t, resToken)
+	})
+	t.Run("should return error if no token matches the hash", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return([]*client.Token{
+			{Prefix: prefix, Hash: hasher.Hash("test-token-other")},
+		}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		_, _, err := service.GetByToken(context.TODO(), token)
+		assert.Equal(t, client.ErrInvalidToken, err)
+	})
+	t.Run("should return error if token hashed with another pepper", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return([]*client.Token{
+			{Prefix: prefix, Hash: client.NewTokenHasher("other").Hash(token)},
+		}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		_, _, err := service.GetByToken(context.TODO(), token)
+		assert.Equal(t, client.ErrInvalidToken, err)
+	})
+	t.Run("should return error if token revoked", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return([]*client.Token{
+			{Hash: hasher.Hash(token), RevokedAt: &past, ExpiresAt: &future},
+		}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		_, _, err := service.GetByToken(context.TODO(), token)
+		assert.Equal(t, client.ErrTokenRevoked, err)
+	})
+	t.Run("should return error if token expired", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return([]*client.Token{
+			{Hash: hasher.Hash(token), ExpiresAt: &past},
+		}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		_, _, err := service.GetByToken(context.TODO(), token)
+		assert.Equal(t, client.ErrTokenExpired, err)
+	})
+	t.Run("should return client and token if token valid", func(t *testing.T) {
+		currentToken := &client.Token{Id: "token-id", ClientXid: "xid", Hash: hasher.Hash(token)}
+		repository := client_mock.NewClientRepository(t)
+		repository.On("FindByXid", mock.Anything, "xid").Return(&client.Client{Xid: "xid"}, nil)
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindByPrefix", mock.Anything, prefix).Return([]*client.Token{
+			{Id: "other-token-id", Hash: hasher.Hash("test-token-other")},
+			currentToken,
+		}, nil)
+		service := client.NewClientService(repository, tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		res, resToken, err := service.GetByToken(context.TODO(), token)
+		assert.Nil(t, err)
+		assert.Equal(t, "xid", res.Xid)
+		assert.Equal(t, currentToken, resToken)
+	})
+}
+
+func TestClientService_RehashUnhashedTokens(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	hasher := client.NewTokenHasher("pepper")
+
+	t.Run("should return error if failed to find unhashed tokens", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindUnhashed", mock.Anything).Return(nil, mockedErr)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		count, err := service.RehashUnhashedTokens(context.TODO())
+		assert.Equal(t, mockedErr, err)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("should hash tokens and erase the plaintext", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindUnhashed", mock.Anything).Return([]*client.Token{
+			{Id: "first", Token: "0123456789abcdef0123"},
+			{Id: "second", Token: "fedcba98765432100123"},
+		}, nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*client.Token)
+			assert.True(t, ok, "params should be *Token")
+			assert.Empty(t, updateParams.Token)
+			assert.Len(t, updateParams.Prefix, client.TOKEN_PREFIX_LENGTH)
+			if updateParams.Id == "first" {
+				assert.Equal(t, "01234567", updateParams.Prefix)
+				assert.Equal(t, hasher.Hash("0123456789abcdef0123"), updateParams.Hash)
+			}
+		}).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, hasher, time.Hour)
+
+		count, err := service.RehashUnhashedTokens(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+func TestClientService_RotateToken(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	tokenId := "token-id"
+
+	t.Run("should return error if token is owned by another client", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: "other"}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.RotateToken(context.TODO(), xid, tokenId)
+		assert.Equal(t, client.ErrTokenNotFound, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if failed to revoke current token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid}, nil)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.RotateToken(context.TODO(), xid, tokenId)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should issue new token and revoke current token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid, Scopes: []string{client.SCOPE_WALLET_READ}, SignatureRequired: true}, nil)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*client.Token)
+			assert.True(t, ok, "params should be *Token")
+			assert.Equal(t, tokenId, updateParams.Id)
+			assert.NotNil(t, updateParams.RevokedAt)
+		}).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.RotateToken(context.TODO(), xid, tokenId)
+		assert.Nil(t, err)
+		assert.NotEqual(t, tokenId, res.Id)
+		assert.Equal(t, xid, res.ClientXid)
+		assert.NotEmpty(t, res.Token)
+		assert.Equal(t, []string{client.SCOPE_WALLET_READ}, res.Scopes)
+		assert.True(t, res.SignatureRequired)
+		assert.NotEmpty(t, res.SigningSecret)
+	})
+}
+
+func TestClientService_SetSignatureRequired(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	tokenId := "token-id"
+	now := time.Now()
+
+	t.Run("should return error if token is owned by another client", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: "other"}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.SetSignatureRequired(context.TODO(), xid, tokenId, true)
+		assert.Equal(t, client.ErrTokenNotFound, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if token revoked", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid, RevokedAt: &now}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.SetSignatureRequired(context.TODO(), xid, tokenId, true)
+		assert.Equal(t, client.ErrTokenAlreadyRevoked, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if lifting the signature requirement", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid, SignatureRequired: true}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.SetSignatureRequired(context.TODO(), xid, tokenId, false)
+		assert.Equal(t, client.ErrSignatureRequirementPermanent, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if failed to update token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid}, nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.SetSignatureRequired(context.TODO(), xid, tokenId, true)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should update signature requirement of the token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid}, nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*client.Token)
+			assert.True(t, ok, "params should be *Token")
+			assert.True(t, updateParams.SignatureRequired)
+		}).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.SetSignatureRequired(context.TODO(), xid, tokenId, true)
+		assert.Nil(t, err)
+		assert.True(t, res.SignatureRequired)
+	})
+}
+
+func TestClientService_CreateToken(t *testing.T) {
+	xid := "xid"
+	tokenId := "token-id"
+	currentToken := &client.Token{Id: tokenId, ClientXid: xid, Scopes: []string{client.SCOPE_WALLET_READ, client.SCOPE_TOKENS_MANAGE}}
+
+	t.Run("should return error if scopes empty", func(t *testing.T) {
+		service := client.NewClientService(client_mock.NewClientRepository(t), client_mock.NewTokenRepository(t), wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.CreateToken(context.TODO(), xid, tokenId, []string{})
+		assert.Equal(t, client.ErrEmptyScopes, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if scope unknown", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(currentToken, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.CreateToken(context.TODO(), xid, tokenId, []string{"wallet:delete"})
+		assert.Equal(t, client.ErrInvalidScope, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if scope not granted to current token", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(currentToken, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.CreateToken(context.TODO(), xid, tokenId, []string{client.SCOPE_WITHDRAWALS_CREATE})
+		assert.Equal(t, client.ErrScopeNotGranted, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should issue token with requested scopes", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(currentToken, nil)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.CreateToken(context.TODO(), xid, tokenId, []string{client.SCOPE_WALLET_READ})
+		assert.Nil(t, err)
+		assert.NotEqual(t, tokenId, res.Id)
+		assert.NotEmpty(t, res.Token)
+		assert.Equal(t, []string{client.SCOPE_WALLET_READ}, res.Scopes)
+		assert.False(t, res.SignatureRequired)
+	})
+	t.Run("should require the signature if the current token requires it", func(t *testing.T) {
+		signingToken := &client.Token{Id: tokenId, ClientXid: xid, Scopes: currentToken.Scopes, SignatureRequired: true}
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(signingToken, nil)
+		tokenRepository.On("FindByPrefix", mock.Anything, mock.Anything).Return(nil, nil)
+		tokenRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		res, err := service.CreateToken(context.TODO(), xid, tokenId, []string{client.SCOPE_WALLET_READ})
+		assert.Nil(t, err)
+		assert.True(t, res.SignatureRequired)
+		assert.NotEmpty(t, res.SigningSecret)
+	})
+}
+
+func TestClientService_RevokeToken(t *testing.T) {
+	xid := "xid"
+	tokenId := "token-id"
+	now := time.Now()
+
+	t.Run("should return error if token not found", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(nil, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		err := service.RevokeToken(context.TODO(), xid, tokenId)
+		assert.Equal(t, client.ErrTokenNotFound, err)
+	})
+	t.Run("should return error if token already revoked", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid, RevokedAt: &now}, nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		err := service.RevokeToken(context.TODO(), xid, tokenId)
+		assert.Equal(t, client.ErrTokenAlreadyRevoked, err)
+	})
+	t.Run("should revoke token if operation success", func(t *testing.T) {
+		tokenRepository := client_mock.NewTokenRepository(t)
+		tokenRepository.On("FindById", mock.Anything, tokenId).Return(&client.Token{Id: tokenId, ClientXid: xid}, nil)
+		tokenRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*client.Token)
+			assert.True(t, ok, "params should be *Token")
+			assert.NotNil(t, updateParams.RevokedAt)
+		}).Return(nil)
+		service := client.NewClientService(client_mock.NewClientRepository(t), tokenRepository, wallet_mock.NewWalletIService(t), newAuditService(t), &manager.MockStorageManager{}, client.NewTokenHasher("pepper"), time.Hour)
+
+		err := service.RevokeToken(context.TODO(), xid, tokenId)
+		assert.Nil(t, err)
+	})
 }
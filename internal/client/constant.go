@@ -0,0 +1,25 @@
+package client
+
+import "time"
+
+const (
+	DEFAULT_TOKEN_TTL   = 90 * 24 * time.Hour
+	TOKEN_BYTES         = 32
+	TOKEN_PREFIX_LENGTH = 8
+)
+
+const (
+	DEFAULT_SEARCH_LIMIT = 50
+	MAX_SEARCH_LIMIT     = 500
+)
+
+const (
+	DEFAULT_SIGNATURE_MAX_SKEW = 5 * time.Minute
+	SIGNING_SECRET_CONTEXT     = "mini-wallet-signing-secret:"
+	NONCE_SWEEP_INTERVAL       = time.Minute
+)
+
+const AUDIT_ACTION_CREATED = "client.created"
+
+// Prefix of the audit actor of the actions taken by a client, followed by the client xid
+const ACTOR_PREFIX = "client:"
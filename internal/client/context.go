@@ -28,3 +28,34 @@ func FromContext(ctx context.Context) (*Client, error) {
 
 	return currentClient, nil
 }
+
+var tokenKey = key("token_context")
+
+// Inject the token used to authenticate the client into the current context
+func InjectToken(ctx context.Context, data *Token) context.Context {
+	return context.WithValue(ctx, tokenKey, data)
+}
+
+// Extract the token used to authenticate the client from the specified context
+//
+// Return error if token is not exists in the context
+func TokenFromContext(ctx context.Context) (*Token, error) {
+	currentToken, ok := ctx.Value(tokenKey).(*Token)
+	if !ok || currentToken == nil {
+		return nil, ErrInvalidToken
+	}
+
+	return currentToken, nil
+}
+
+// Extract the scopes granted to the token used to authenticate the client from the specified context
+//
+// Return empty scopes if token is not exists in the context
+func ScopesFromContext(ctx context.Context) []string {
+	currentToken, err := TokenFromContext(ctx)
+	if err != nil {
+		return []string{}
+	}
+
+	return currentToken.Scopes
+}
@@ -5,4 +5,18 @@ import (
 )
 
 var ErrXidAlreadyTaken = errors.NewValidationError("xid already taken")
+var ErrClientNotFound = errors.NewNotFoundError("client not found")
 var ErrInvalidClient = errors.NewUnauthorizedError("client invalid")
+var ErrInvalidToken = errors.NewUnauthorizedError("authorization token invalid")
+var ErrTokenExpired = errors.NewUnauthorizedError("authorization token expired")
+var ErrTokenRevoked = errors.NewUnauthorizedError("authorization token revoked")
+var ErrTokenNotFound = errors.NewNotFoundError("token not found")
+var ErrTokenAlreadyRevoked = errors.NewValidationError("token already revoked")
+var ErrEmptyScopes = errors.NewValidationError("scopes is required")
+var ErrInvalidScope = errors.NewValidationError("scope invalid")
+var ErrScopeNotGranted = errors.NewForbiddenError("scope not granted to the current token")
+var ErrSignatureRequired = errors.NewUnauthorizedError("request signature required")
+var ErrSignatureRequirementPermanent = errors.NewValidationError("signature requirement can't be lifted, rotate or revoke the token instead")
+var ErrInvalidSignature = errors.NewUnauthorizedError("request signature invalid")
+var ErrSignatureExpired = errors.NewUnauthorizedError("request timestamp outside the allowed clock skew")
+var ErrNonceReused = errors.NewUnauthorizedError("request nonce already used")
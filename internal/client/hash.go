@@ -0,0 +1,46 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// TokenHasher hashes tokens with HMAC-SHA256 keyed by a server side pepper,
+// so leaked hashes can't be brute forced without the pepper
+type TokenHasher struct {
+	pepper []byte
+}
+
+func NewTokenHasher(pepper string) *TokenHasher {
+	return &TokenHasher{[]byte(pepper)}
+}
+
+func (h *TokenHasher) Hash(token string) string {
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Compare the token against the hash in constant time
+func (h *TokenHasher) Matches(token, hash string) bool {
+	return hmac.Equal([]byte(h.Hash(token)), []byte(hash))
+}
+
+// Derive the secret signing the requests made with the token from the hash of the token.
+// The secret is never stored and can't be derived from a leaked hash without the pepper
+func (h *TokenHasher) SigningSecret(hash string) string {
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(SIGNING_SECRET_CONTEXT))
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Return the first characters of the token used to look up its hash
+func TokenPrefix(token string) string {
+	if len(token) < TOKEN_PREFIX_LENGTH {
+		return token
+	}
+
+	return token[:TOKEN_PREFIX_LENGTH]
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TokenResponse struct {
+	Id                string     `json:"id"`
+	Prefix            string     `json:"prefix"`
+	Scopes            []string   `json:"scopes"`
+	SignatureRequired bool       `json:"signature_required"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+}
+
+// IssuedTokenResponse holds the plaintext and the signing secret of the issued token,
+// they are only returned once
+type IssuedTokenResponse struct {
+	Id                string     `json:"id"`
+	Token             string     `json:"token"`
+	Scopes            []string   `json:"scopes"`
+	SigningSecret     string     `json:"signing_secret"`
+	SignatureRequired bool       `json:"signature_required"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+}
+
+type CreateTokenRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+type UpdateTokenRequest struct {
+	SignatureRequired *bool `json:"signature_required"`
+}
+
+func HandleGetTokens(service client.ClientIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		tokens, err := service.GetTokens(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		res := []*TokenResponse{}
+		for _, token := range tokens {
+			res = append(res, &TokenResponse{
+				Id:                token.Id,
+				Prefix:            token.Prefix,
+				Scopes:            token.Scopes,
+				SignatureRequired: token.SignatureRequired,
+				CreatedAt:         token.CreatedAt,
+				ExpiresAt:         token.ExpiresAt,
+				RevokedAt:         token.RevokedAt,
+			})
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"tokens": res,
+		})
+	}
+}
+
+func HandleCreateToken(service client.ClientIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateTokenRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"scopes": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		currentToken, err := client.TokenFromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		issuedToken, err := service.CreateToken(r.Context(), currentClient.Xid, currentToken.Id, requestBody.Scopes)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"token": &IssuedTokenResponse{
+				Id:                issuedToken.Id,
+				Token:             issuedToken.Token,
+				Scopes:            issuedToken.Scopes,
+				SigningSecret:     issuedToken.SigningSecret,
+				SignatureRequired: issuedToken.SignatureRequired,
+				CreatedAt:         issuedToken.CreatedAt,
+				ExpiresAt:         issuedToken.ExpiresAt,
+			},
+		})
+	}
+}
+
+func HandleRotateToken(service client.ClientIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		currentToken, err := client.TokenFromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		issuedToken, err := service.RotateToken(r.Context(), currentClient.Xid, currentToken.Id)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"token": &IssuedTokenResponse{
+				Id:                issuedToken.Id,
+				Token:             issuedToken.Token,
+				Scopes:            issuedToken.Scopes,
+				SigningSecret:     issuedToken.SigningSecret,
+				SignatureRequired: issuedToken.SignatureRequired,
+				CreatedAt:         issuedToken.CreatedAt,
+				ExpiresAt:         issuedToken.ExpiresAt,
+			},
+		})
+	}
+}
+
+func HandleRevokeToken(service client.ClientIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		err = service.RevokeToken(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, nil)
+	}
+}
+
+func HandleUpdateToken(service client.ClientIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &UpdateTokenRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+		if requestBody.SignatureRequired == nil {
+			response.Failed(w, errors.NewValidationError(map[string]interface{}{
+				"signature_required": []string{
+					"Missing data for required field.",
+				},
+			}))
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		token, err := service.SetSignatureRequired(r.Context(), currentClient.Xid, chi.URLParam(r, "id"), *requestBody.SignatureRequired)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"token": &TokenResponse{
+				Id:                token.Id,
+				Prefix:            token.Prefix,
+				Scopes:            token.Scopes,
+				SignatureRequired: token.SignatureRequired,
+				CreatedAt:         token.CreatedAt,
+				ExpiresAt:         token.ExpiresAt,
+				RevokedAt:         token.RevokedAt,
+			},
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/defryheryanto/mini-wallet/internal/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClientIService is an autogenerated mock type for the ClientIService type
+type ClientIService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, xid
+func (_m *ClientIService) Create(ctx context.Context, xid string) (*client.Client, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *client.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.Client, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.Client); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateToken provides a mock function with given fields: ctx, xid, currentTokenId, scopes
+func (_m *ClientIService) CreateToken(ctx context.Context, xid string, currentTokenId string, scopes []string) (*client.Token, error) {
+	ret := _m.Called(ctx, xid, currentTokenId, scopes)
+
+	var r0 *client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) (*client.Token, error)); ok {
+		return rf(ctx, xid, currentTokenId, scopes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) *client.Token); ok {
+		r0 = rf(ctx, xid, currentTokenId, scopes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, xid, currentTokenId, scopes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByToken provides a mock function with given fields: ctx, token
+func (_m *ClientIService) GetByToken(ctx context.Context, token string) (*client.Client, *client.Token, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *client.Client
+	var r1 *client.Token
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.Client, *client.Token, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.Client); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *client.Token); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByXid provides a mock function with given fields: ctx, xid
+func (_m *ClientIService) GetByXid(ctx context.Context, xid string) (*client.Client, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *client.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.Client, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.Client); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokens provides a mock function with given fields: ctx, xid
+func (_m *ClientIService) GetTokens(ctx context.Context, xid string) ([]*client.Token, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 []*client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*client.Token, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*client.Token); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RehashUnhashedTokens provides a mock function with given fields: ctx
+func (_m *ClientIService) RehashUnhashedTokens(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: ctx, xid, tokenId
+func (_m *ClientIService) RevokeToken(ctx context.Context, xid string, tokenId string) error {
+	ret := _m.Called(ctx, xid, tokenId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, xid, tokenId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateToken provides a mock function with given fields: ctx, xid, currentTokenId
+func (_m *ClientIService) RotateToken(ctx context.Context, xid string, currentTokenId string) (*client.Token, error) {
+	ret := _m.Called(ctx, xid, currentTokenId)
+
+	var r0 *client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*client.Token, error)); ok {
+		return rf(ctx, xid, currentTokenId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *client.Token); ok {
+		r0 = rf(ctx, xid, currentTokenId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, xid, currentTokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchClients provides a mock function with given fields: ctx, params
+func (_m *ClientIService) SearchClients(ctx context.Context, params *client.SearchClientsParams) ([]*client.Client, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*client.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *client.SearchClientsParams) ([]*client.Client, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *client.SearchClientsParams) []*client.Client); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *client.SearchClientsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSignatureRequired provides a mock function with given fields: ctx, xid, tokenId, required
+func (_m *ClientIService) SetSignatureRequired(ctx context.Context, xid string, tokenId string, required bool) (*client.Token, error) {
+	ret := _m.Called(ctx, xid, tokenId, required)
+
+	var r0 *client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (*client.Token, error)); ok {
+		return rf(ctx, xid, tokenId, required)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) *client.Token); ok {
+		r0 = rf(ctx, xid, tokenId, required)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, xid, tokenId, required)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewClientIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewClientIService creates a new instance of ClientIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewClientIService(t mockConstructorTestingTNewClientIService) *ClientIService {
+	mock := &ClientIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
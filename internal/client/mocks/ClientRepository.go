@@ -15,32 +15,6 @@ type ClientRepository struct {
 	mock.Mock
 }
 
-// FindByToken provides a mock function with given fields: ctx, token
-func (_m *ClientRepository) FindByToken(ctx context.Context, token string) (*client.Client, error) {
-	ret := _m.Called(ctx, token)
-
-	var r0 *client.Client
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.Client, error)); ok {
-		return rf(ctx, token)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *client.Client); ok {
-		r0 = rf(ctx, token)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*client.Client)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // FindByXid provides a mock function with given fields: ctx, xid
 func (_m *ClientRepository) FindByXid(ctx context.Context, xid string) (*client.Client, error) {
 	ret := _m.Called(ctx, xid)
@@ -81,6 +55,32 @@ func (_m *ClientRepository) Insert(ctx context.Context, data *client.Client) err
 	return r0
 }
 
+// Search provides a mock function with given fields: ctx, params
+func (_m *ClientRepository) Search(ctx context.Context, params *client.SearchClientsParams) ([]*client.Client, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*client.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *client.SearchClientsParams) ([]*client.Client, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *client.SearchClientsParams) []*client.Client); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *client.SearchClientsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewClientRepository interface {
 	mock.TestingT
 	Cleanup(func())
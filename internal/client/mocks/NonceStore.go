@@ -0,0 +1,54 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NonceStore is an autogenerated mock type for the NonceStore type
+type NonceStore struct {
+	mock.Mock
+}
+
+// Remember provides a mock function with given fields: ctx, key, ttl
+func (_m *NonceStore) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, ttl)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) bool); ok {
+		r0 = rf(ctx, key, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewNonceStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewNonceStore creates a new instance of NonceStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewNonceStore(t mockConstructorTestingTNewNonceStore) *NonceStore {
+	mock := &NonceStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/defryheryanto/mini-wallet/internal/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenRepository is an autogenerated mock type for the TokenRepository type
+type TokenRepository struct {
+	mock.Mock
+}
+
+// FindByClientXid provides a mock function with given fields: ctx, xid
+func (_m *TokenRepository) FindByClientXid(ctx context.Context, xid string) ([]*client.Token, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 []*client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*client.Token, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*client.Token); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *TokenRepository) FindById(ctx context.Context, id string) (*client.Token, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.Token, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.Token); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *TokenRepository) FindByPrefix(ctx context.Context, prefix string) ([]*client.Token, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 []*client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*client.Token, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*client.Token); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUnhashed provides a mock function with given fields: ctx
+func (_m *TokenRepository) FindUnhashed(ctx context.Context) ([]*client.Token, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*client.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*client.Token, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*client.Token); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *TokenRepository) Insert(ctx context.Context, data *client.Token) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *client.Token) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *TokenRepository) Update(ctx context.Context, data *client.Token) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *client.Token) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTokenRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTokenRepository creates a new instance of TokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTokenRepository(t mockConstructorTestingTNewTokenRepository) *TokenRepository {
+	mock := &TokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,12 +2,15 @@ package gorm
 
 import (
 	"context"
+	"strings"
 
 	"github.com/defryheryanto/mini-wallet/internal/client"
 	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
 	"gorm.io/gorm"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type ClientRepository struct {
 	db *gorm.DB
 }
@@ -31,7 +34,7 @@ func (r *ClientRepository) Insert(ctx context.Context, data *client.Client) erro
 func (r *ClientRepository) FindByXid(ctx context.Context, xid string) (*client.Client, error) {
 	result := &Client{}
 
-	err := r.db.Where("xid = ?", xid).First(&result).Error
+	err := r.db.WithContext(ctx).Where("xid = ?", xid).First(&result).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -42,25 +45,27 @@ func (r *ClientRepository) FindByXid(ctx context.Context, xid string) (*client.C
 	return result.ToServiceModel(), nil
 }
 
-func (r *ClientRepository) FindByToken(ctx context.Context, token string) (*client.Client, error) {
-	result := &Client{}
+func (r *ClientRepository) Search(ctx context.Context, params *client.SearchClientsParams) ([]*client.Client, error) {
+	clients := []*Client{}
+
+	query := r.db.WithContext(ctx)
+	if params.Query != "" {
+		query = query.Where("xid LIKE ?", likeEscaper.Replace(params.Query)+"%")
+	}
 
-	err := r.db.Where("token = ?", token).First(&result).Error
+	err := query.Order("xid").Limit(params.Limit).Offset(params.Offset).Find(&clients).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
 		return nil, err
 	}
 
-	return result.ToServiceModel(), nil
+	return ClientSliceToServiceModel(clients), nil
 }
 
 func (r *ClientRepository) getGormClient(ctx context.Context) *gorm.DB {
 	db, err := gorm_manager.ExtractClientFromContext(ctx)
 	if err != nil {
-		return r.db
+		return r.db.WithContext(ctx)
 	}
 
-	return db
+	return db.WithContext(ctx)
 }
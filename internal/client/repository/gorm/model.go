@@ -1,10 +1,14 @@
 package gorm
 
-import "github.com/defryheryanto/mini-wallet/internal/client"
+import (
+	"strings"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+)
 
 type Client struct {
-	Xid   string `gorm:"primaryKey;column:xid"`
-	Token string `gorm:"column:token"`
+	Xid string `gorm:"primaryKey;column:xid"`
 }
 
 func (Client) TableName() string {
@@ -17,14 +21,95 @@ func (Client) FromServiceModel(data *client.Client) *Client {
 	}
 
 	return &Client{
-		Xid:   data.Xid,
-		Token: data.Token,
+		Xid: data.Xid,
 	}
 }
 
 func (c *Client) ToServiceModel() *client.Client {
 	return &client.Client{
-		Xid:   c.Xid,
-		Token: c.Token,
+		Xid: c.Xid,
 	}
 }
+
+func ClientSliceToServiceModel(data []*Client) []*client.Client {
+	if data == nil {
+		return nil
+	}
+
+	clients := []*client.Client{}
+	for _, c := range data {
+		clients = append(clients, c.ToServiceModel())
+	}
+
+	return clients
+}
+
+// Token column holds the plaintext of the tokens issued before tokens were hashed.
+// It is never written, only read to hash the remaining plaintext tokens
+type Token struct {
+	Id                string     `gorm:"primaryKey;column:id"`
+	ClientXid         string     `gorm:"column:client_xid"`
+	Token             *string    `gorm:"column:token"`
+	Prefix            string     `gorm:"column:token_prefix"`
+	Hash              string     `gorm:"column:token_hash"`
+	Scopes            string     `gorm:"column:scopes"`
+	SignatureRequired bool       `gorm:"column:signature_required"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	ExpiresAt         *time.Time `gorm:"column:expires_at"`
+	RevokedAt         *time.Time `gorm:"column:revoked_at"`
+}
+
+func (Token) TableName() string {
+	return "tokens"
+}
+
+func (Token) FromServiceModel(data *client.Token) *Token {
+	if data == nil {
+		return nil
+	}
+
+	return &Token{
+		Id:                data.Id,
+		ClientXid:         data.ClientXid,
+		Prefix:            data.Prefix,
+		Hash:              data.Hash,
+		Scopes:            strings.Join(data.Scopes, " "),
+		SignatureRequired: data.SignatureRequired,
+		CreatedAt:         data.CreatedAt,
+		ExpiresAt:         data.ExpiresAt,
+		RevokedAt:         data.RevokedAt,
+	}
+}
+
+func (t *Token) ToServiceModel() *client.Token {
+	token := ""
+	if t.Token != nil {
+		token = *t.Token
+	}
+
+	return &client.Token{
+		Id:                t.Id,
+		ClientXid:         t.ClientXid,
+		Token:             token,
+		Prefix:            t.Prefix,
+		Hash:              t.Hash,
+		Scopes:            strings.Fields(t.Scopes),
+		SignatureRequired: t.SignatureRequired,
+		CreatedAt:         t.CreatedAt,
+		ExpiresAt:         t.ExpiresAt,
+		RevokedAt:         t.RevokedAt,
+	}
+}
+
+func TokenSliceToServiceModel(data []*Token) []*client.Token {
+	if data == nil {
+		return nil
+	}
+
+	tokens := []*client.Token{}
+	for _, token := range data {
+		tokens = append(tokens, token.ToServiceModel())
+	}
+
+	return tokens
+}
@@ -0,0 +1,97 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db}
+}
+
+func (r *TokenRepository) Insert(ctx context.Context, data *client.Token) error {
+	payload := Token{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TokenRepository) FindById(ctx context.Context, id string) (*client.Token, error) {
+	result := &Token{}
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *TokenRepository) FindByPrefix(ctx context.Context, prefix string) ([]*client.Token, error) {
+	tokens := []*Token{}
+
+	err := r.db.WithContext(ctx).Where("token_prefix = ?", prefix).Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return TokenSliceToServiceModel(tokens), nil
+}
+
+func (r *TokenRepository) FindByClientXid(ctx context.Context, xid string) ([]*client.Token, error) {
+	tokens := []*Token{}
+
+	err := r.db.WithContext(ctx).Where("client_xid = ?", xid).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return TokenSliceToServiceModel(tokens), nil
+}
+
+func (r *TokenRepository) FindUnhashed(ctx context.Context) ([]*client.Token, error) {
+	tokens := []*Token{}
+
+	err := r.db.WithContext(ctx).Where("token_hash IS NULL AND token IS NOT NULL").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return TokenSliceToServiceModel(tokens), nil
+}
+
+func (r *TokenRepository) Update(ctx context.Context, data *client.Token) error {
+	payload := Token{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TokenRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
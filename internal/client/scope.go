@@ -0,0 +1,42 @@
+package client
+
+const (
+	SCOPE_WALLET_READ        = "wallet:read"
+	SCOPE_WALLET_WRITE       = "wallet:write"
+	SCOPE_TRANSACTIONS_READ  = "transactions:read"
+	SCOPE_DEPOSITS_CREATE    = "deposits:create"
+	SCOPE_WITHDRAWALS_CREATE = "withdrawals:create"
+	SCOPE_TOKENS_MANAGE      = "tokens:manage"
+	SCOPE_WEBHOOKS_MANAGE    = "webhooks:manage"
+)
+
+// Every scope known to the service, granted to the token issued when the client is created
+var ALL_SCOPES = []string{
+	SCOPE_WALLET_READ,
+	SCOPE_WALLET_WRITE,
+	SCOPE_TRANSACTIONS_READ,
+	SCOPE_DEPOSITS_CREATE,
+	SCOPE_WITHDRAWALS_CREATE,
+	SCOPE_TOKENS_MANAGE,
+	SCOPE_WEBHOOKS_MANAGE,
+}
+
+func IsValidScope(scope string) bool {
+	for _, known := range ALL_SCOPES {
+		if scope == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Token) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
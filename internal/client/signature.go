@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SignedRequest holds the parts of a request covered by its signature
+type SignedRequest struct {
+	Method string
+	// Path of the request including the query string
+	Path      string
+	Timestamp string
+	Nonce     string
+	Body      []byte
+	Signature string
+}
+
+// Return the string signed by the client, the method, path, unix timestamp, nonce
+// and hex encoded SHA-256 of the body separated by new lines
+func (r *SignedRequest) Canonical() string {
+	bodyHash := sha256.Sum256(r.Body)
+
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.Timestamp,
+		r.Nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// Return the hex encoded HMAC-SHA256 of the canonical request keyed by the signing secret
+func Sign(signingSecret string, r *SignedRequest) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(r.Canonical()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NonceStore remembers the nonces of the verified requests to reject replays
+type NonceStore interface {
+	// Remember the key for the given duration.
+	// Return false if the key is already remembered
+	Remember(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// SignatureVerifier verifies the requests signed with the signing secret of a token
+type SignatureVerifier struct {
+	tokenHasher *TokenHasher
+	nonceStore  NonceStore
+	maxSkew     time.Duration
+}
+
+func NewSignatureVerifier(tokenHasher *TokenHasher, nonceStore NonceStore, maxSkew time.Duration) *SignatureVerifier {
+	return &SignatureVerifier{tokenHasher, nonceStore, maxSkew}
+}
+
+// Verify the signature of the request made with the given token.
+// Unsigned requests are only accepted if the token doesn't require signature.
+//
+// Return error if the signature doesn't match, the timestamp is outside the allowed clock skew
+// or the nonce was already used with the token
+func (v *SignatureVerifier) Verify(ctx context.Context, token *Token, r *SignedRequest) error {
+	if r.Signature == "" {
+		if token.SignatureRequired {
+			return ErrSignatureRequired
+		}
+		return nil
+	}
+	if r.Nonce == "" {
+		return ErrInvalidSignature
+	}
+
+	timestamp, err := strconv.ParseInt(r.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew > v.maxSkew || skew < -v.maxSkew {
+		return ErrSignatureExpired
+	}
+
+	expected := Sign(v.tokenHasher.SigningSecret(token.Hash), r)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(r.Signature))) {
+		return ErrInvalidSignature
+	}
+
+	// The nonce only has to be remembered as long as its timestamp is accepted
+	isNew, err := v.nonceStore.Remember(ctx, token.Id+":"+r.Nonce, 2*v.maxSkew)
+	if err != nil {
+		return err
+	}
+	if !isNew {
+		return ErrNonceReused
+	}
+
+	return nil
+}
+
+// MemoryNonceStore remembers the nonces in memory of the current process,
+// so replays are only rejected if every request of the client reaches the same process
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	expiries  map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		expiries: map[string]time.Time{},
+	}
+}
+
+func (s *MemoryNonceStore) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		for storedKey, expiresAt := range s.expiries {
+			if !now.Before(expiresAt) {
+				delete(s.expiries, storedKey)
+			}
+		}
+		s.nextSweep = now.Add(NONCE_SWEEP_INTERVAL)
+	}
+
+	if expiresAt, ok := s.expiries[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	s.expiries[key] = now.Add(ttl)
+
+	return true, nil
+}
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	client_mock "github.com/defryheryanto/mini-wallet/internal/client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	hasher := client.NewTokenHasher("pepper")
+	token := &client.Token{Id: "token-id", Hash: hasher.Hash("token")}
+	signingSecret := hasher.SigningSecret(token.Hash)
+
+	signedRequest := func(at time.Time) *client.SignedRequest {
+		r := &client.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/v1/wallet/deposits",
+			Timestamp: strconv.FormatInt(at.Unix(), 10),
+			Nonce:     "nonce",
+			Body:      []byte(`{"amount":1000}`),
+		}
+		r.Signature = client.Sign(signingSecret, r)
+		return r
+	}
+
+	t.Run("should accept unsigned request if token doesn't require signature", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(hasher, client.NewMemoryNonceStore(), time.Minute)
+
+		err := verifier.Verify(context.TODO(), token, &client.SignedRequest{Method: "GET", Path: "/api/v1/wallet"})
+		assert.Nil(t, err)
+	})
+	t.Run("should return error if token requires signature and request unsigned", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(hasher, client.NewMemoryNonceStore(), time.Minute)
+		requiringToken := &client.Token{Id: token.Id, Hash: token.Hash, SignatureRequired: true}
+
+		err := verifier.Verify(context.TODO(), requiringToken, &client.SignedRequest{Method: "GET", Path: "/api/v1/wallet"})
+		assert.Equal(t, client.ErrSignatureRequired, err)
+	})
+	t.Run("should return error if timestamp outside clock skew", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(hasher, client.NewMemoryNonceStore(), time.Minute)
+
+		err := verifier.Verify(context.TODO(), token, signedRequest(time.Now().Add(-2*time.Minute)))
+		assert.Equal(t, client.ErrSignatureExpired, err)
+
+		err = verifier.Verify(context.TODO(), token, signedRequest(time.Now().Add(2*time.Minute)))
+		assert.Equal(t, client.ErrSignatureExpired, err)
+	})
+	t.Run("should return error if request tampered", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(hasher, client.NewMemoryNonceStore(), time.Minute)
+		r := signedRequest(time.Now())
+		r.Body = []byte(`{"amount":1000000}`)
+
+		err := verifier.Verify(context.TODO(), token, r)
+		assert.Equal(t, client.ErrInvalidSignature, err)
+	})
+	t.Run("should return error if signed with another secret", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(client.NewTokenHasher("another pepper"), client.NewMemoryNonceStore(), time.Minute)
+
+		err := verifier.Verify(context.TODO(), token, signedRequest(time.Now()))
+		assert.Equal(t, client.ErrInvalidSignature, err)
+	})
+	t.Run("should return error if failed to remember nonce", func(t *testing.T) {
+		mockedErr := fmt.Errorf("mocked")
+		nonceStore := client_mock.NewNonceStore(t)
+		nonceStore.On("Remember", mock.Anything, "token-id:nonce", 2*time.Minute).Return(false, mockedErr)
+		verifier := client.NewSignatureVerifier(hasher, nonceStore, time.Minute)
+
+		err := verifier.Verify(context.TODO(), token, signedRequest(time.Now()))
+		assert.Equal(t, mockedErr, err)
+	})
+	t.Run("should return error if request replayed", func(t *testing.T) {
+		verifier := client.NewSignatureVerifier(hasher, client.NewMemoryNonceStore(), time.Minute)
+		r := signedRequest(time.Now())
+
+		err := verifier.Verify(context.TODO(), token, r)
+		assert.Nil(t, err)
+
+		err = verifier.Verify(context.TODO(), token, r)
+		assert.Equal(t, client.ErrNonceReused, err)
+	})
+}
+
+func TestMemoryNonceStore_Remember(t *testing.T) {
+	t.Run("should forget key once expired", func(t *testing.T) {
+		store := client.NewMemoryNonceStore()
+
+		isNew, err := store.Remember(context.TODO(), "key", time.Millisecond)
+		assert.Nil(t, err)
+		assert.True(t, isNew)
+
+		isNew, err = store.Remember(context.TODO(), "key", time.Millisecond)
+		assert.Nil(t, err)
+		assert.False(t, isNew)
+
+		time.Sleep(2 * time.Millisecond)
+		isNew, err = store.Remember(context.TODO(), "key", time.Millisecond)
+		assert.Nil(t, err)
+		assert.True(t, isNew)
+	})
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Token authenticates the requests of a client.
+// Only the prefix and the hash of the token are stored,
+// the plaintext and the signing secret are only available right after the token is issued.
+// Requests made with a token requiring signature must be signed with its signing secret
+type Token struct {
+	Id                string     `json:"id"`
+	ClientXid         string     `json:"client_xid"`
+	Token             string     `json:"token"`
+	Prefix            string     `json:"prefix"`
+	Hash              string     `json:"-"`
+	Scopes            []string   `json:"scopes"`
+	SignatureRequired bool       `json:"signature_required"`
+	SigningSecret     string     `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+}
+
+type TokenRepository interface {
+	Insert(ctx context.Context, data *Token) error
+	FindById(ctx context.Context, id string) (*Token, error)
+	FindByPrefix(ctx context.Context, prefix string) ([]*Token, error)
+	FindByClientXid(ctx context.Context, xid string) ([]*Token, error)
+	// Return the tokens stored in plaintext before tokens were hashed
+	FindUnhashed(ctx context.Context) ([]*Token, error)
+	Update(ctx context.Context, data *Token) error
+}
+
+// Return true if the token is expired at the given time.
+// Token without expiry never expires
+func (t *Token) IsExpired(at time.Time) bool {
+	return t.ExpiresAt != nil && !at.Before(*t.ExpiresAt)
+}
+
+func (t *Token) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,7 @@
+package currency
+
+// Currency of the wallets created without a currency, and of the wallets created before currencies were introduced
+const DEFAULT_CODE = "IDR"
+
+// Absorbs the floating point error when comparing amounts scaled to minor units
+const PRECISION_TOLERANCE = 1e-6
@@ -0,0 +1,91 @@
+package currency
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Currency is an ISO 4217 currency supported by the service
+type Currency struct {
+	Code string `json:"code"`
+	// Number of digits after the decimal separator, e.g. 2 for USD and 0 for JPY
+	MinorUnits int `json:"minor_units"`
+}
+
+var supported = map[string]*Currency{
+	"AUD": {Code: "AUD", MinorUnits: 2},
+	"BHD": {Code: "BHD", MinorUnits: 3},
+	"EUR": {Code: "EUR", MinorUnits: 2},
+	"GBP": {Code: "GBP", MinorUnits: 2},
+	"IDR": {Code: "IDR", MinorUnits: 2},
+	"JPY": {Code: "JPY", MinorUnits: 0},
+	"KRW": {Code: "KRW", MinorUnits: 0},
+	"KWD": {Code: "KWD", MinorUnits: 3},
+	"MYR": {Code: "MYR", MinorUnits: 2},
+	"PHP": {Code: "PHP", MinorUnits: 2},
+	"SGD": {Code: "SGD", MinorUnits: 2},
+	"THB": {Code: "THB", MinorUnits: 2},
+	"USD": {Code: "USD", MinorUnits: 2},
+	"VND": {Code: "VND", MinorUnits: 0},
+}
+
+// Return the supported currency of the given code
+func Find(code string) (*Currency, error) {
+	c, ok := supported[code]
+	if !ok {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	return c, nil
+}
+
+func IsSupported(code string) bool {
+	_, ok := supported[code]
+	return ok
+}
+
+// Return every supported currency ordered by code
+func All() []*Currency {
+	currencies := make([]*Currency, 0, len(supported))
+	for _, c := range supported {
+		currencies = append(currencies, c)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+
+	return currencies
+}
+
+// Return error if the amount has more decimals than the minor units of the currency
+func (c *Currency) ValidateAmount(amount float64) error {
+	scaled := amount * c.scale()
+	if math.Abs(scaled-math.Round(scaled)) > PRECISION_TOLERANCE {
+		return ErrTooManyDecimals(c)
+	}
+
+	return nil
+}
+
+// Round the amount down to the minor units of the currency,
+// so converted amounts never credit more than the quoted rate allows
+func (c *Currency) Floor(amount float64) float64 {
+	scale := c.scale()
+	return math.Floor(amount*scale+PRECISION_TOLERANCE) / scale
+}
+
+// Round the amount to the minor units of the currency, e.g. to drop the float error of summed amounts
+func (c *Currency) Round(amount float64) float64 {
+	scale := c.scale()
+	return math.Round(amount*scale) / scale
+}
+
+// Format the amount with exactly the minor units of the currency, e.g. 10.50 for USD and 10 for JPY
+func (c *Currency) Format(amount float64) string {
+	return strconv.FormatFloat(c.Round(amount), 'f', c.MinorUnits, 64)
+}
+
+func (c *Currency) scale() float64 {
+	return math.Pow10(c.MinorUnits)
+}
@@ -0,0 +1,66 @@
+package currency_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	t.Run("should return the minor units of the currency", func(t *testing.T) {
+		c, err := currency.Find("JPY")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, c.MinorUnits)
+
+		c, err = currency.Find("KWD")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, c.MinorUnits)
+	})
+
+	t.Run("should return error if the currency is not supported", func(t *testing.T) {
+		_, err := currency.Find("XYZ")
+		assert.Equal(t, currency.ErrUnsupportedCurrency, err)
+	})
+}
+
+func TestCurrency_ValidateAmount(t *testing.T) {
+	usd, _ := currency.Find("USD")
+	jpy, _ := currency.Find("JPY")
+
+	t.Run("should accept amounts within the minor units", func(t *testing.T) {
+		assert.Nil(t, usd.ValidateAmount(10.25))
+		assert.Nil(t, usd.ValidateAmount(0.1+0.2))
+		assert.Nil(t, jpy.ValidateAmount(1500))
+	})
+
+	t.Run("should reject amounts with more decimals than the minor units", func(t *testing.T) {
+		assert.Error(t, usd.ValidateAmount(10.255))
+		assert.Error(t, jpy.ValidateAmount(1500.5))
+	})
+}
+
+func TestCurrency_Floor(t *testing.T) {
+	t.Run("should round down to the minor units", func(t *testing.T) {
+		usd, _ := currency.Find("USD")
+		jpy, _ := currency.Find("JPY")
+
+		assert.Equal(t, 6.45, usd.Floor(6.459))
+		assert.Equal(t, 0.3, usd.Floor(0.1+0.2))
+		assert.Equal(t, float64(1549), jpy.Floor(1549.99))
+	})
+}
+
+func TestCurrency_Format(t *testing.T) {
+	t.Run("should format with exactly the minor units", func(t *testing.T) {
+		usd, _ := currency.Find("USD")
+		jpy, _ := currency.Find("JPY")
+		kwd, _ := currency.Find("KWD")
+
+		assert.Equal(t, "10.50", usd.Format(10.5))
+		assert.Equal(t, "0.30", usd.Format(0.1+0.2))
+		assert.Equal(t, "-5.00", usd.Format(-5))
+		assert.Equal(t, "1500", jpy.Format(1500))
+		assert.Equal(t, "1.250", kwd.Format(1.25))
+	})
+}
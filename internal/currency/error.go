@@ -0,0 +1,13 @@
+package currency
+
+import (
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrUnsupportedCurrency = errors.NewValidationError("currency not supported")
+
+func ErrTooManyDecimals(c *Currency) errors.HandledError {
+	return errors.NewValidationError(fmt.Sprintf("amount in %s can't have more than %d decimals", c.Code, c.MinorUnits))
+}
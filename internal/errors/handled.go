@@ -34,3 +34,24 @@ func NewUnauthorizedError(data interface{}) HandledError {
 		Data:       data,
 	}
 }
+
+func NewForbiddenError(data interface{}) HandledError {
+	return HandledError{
+		HttpStatus: http.StatusForbidden,
+		Data:       data,
+	}
+}
+
+func NewServiceUnavailableError(data interface{}) HandledError {
+	return HandledError{
+		HttpStatus: http.StatusServiceUnavailable,
+		Data:       data,
+	}
+}
+
+func NewTooManyRequestsError(data interface{}) HandledError {
+	return HandledError{
+		HttpStatus: http.StatusTooManyRequests,
+		Data:       data,
+	}
+}
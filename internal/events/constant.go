@@ -0,0 +1,32 @@
+package events
+
+import "time"
+
+const (
+	EVENT_WALLET_CREATED  = "wallet.created"
+	EVENT_WALLET_ENABLED  = "wallet.enabled"
+	EVENT_WALLET_DISABLED = "wallet.disabled"
+	EVENT_WALLET_FROZEN   = "wallet.frozen"
+	EVENT_WALLET_CLOSED   = "wallet.closed"
+
+	EVENT_DEPOSIT_SETTLED    = "deposit.settled"
+	EVENT_DEPOSIT_FAILED     = "deposit.failed"
+	EVENT_WITHDRAWAL_SETTLED = "withdrawal.settled"
+	EVENT_WITHDRAWAL_FAILED  = "withdrawal.failed"
+	EVENT_TRANSFER_COMPLETED = "transfer.completed"
+	EVENT_BALANCE_ADJUSTED   = "balance.adjusted"
+)
+
+// Name subscribing a handler of the in-process publisher to every event
+const ALL_EVENTS = "*"
+
+const (
+	PUBLISHER_INPROCESS = "inprocess"
+	PUBLISHER_STDOUT    = "stdout"
+	PUBLISHER_FILE      = "file"
+)
+
+const (
+	RELAY_BATCH_SIZE = 100
+	RELAY_INTERVAL   = time.Second
+)
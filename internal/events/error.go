@@ -0,0 +1,13 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyEvent = errors.NewValidationError("event is required")
+
+func ErrUnknownEvent(name string) error {
+	return fmt.Errorf("unknown event %q", name)
+}
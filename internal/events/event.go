@@ -0,0 +1,152 @@
+package events
+
+import "encoding/json"
+
+// Event is a state change of the domain, recorded to the outbox and relayed to the publisher
+type Event interface {
+	EventName() string
+	// Id of the entity the event belongs to, the events of an entity are relayed in the order they are recorded
+	AggregateId() string
+}
+
+type WalletCreated struct {
+	WalletId  string `json:"wallet_id"`
+	OwnedBy   string `json:"owned_by"`
+	Name      string `json:"name"`
+	Currency  string `json:"currency"`
+	IsDefault bool   `json:"is_default"`
+}
+
+func (WalletCreated) EventName() string     { return EVENT_WALLET_CREATED }
+func (e WalletCreated) AggregateId() string { return e.WalletId }
+
+// WalletStatus is the data shared by the events of the status transitions of a wallet
+type WalletStatus struct {
+	WalletId       string `json:"wallet_id"`
+	OwnedBy        string `json:"owned_by"`
+	PreviousStatus string `json:"previous_status"`
+	// Reason given by the operator, empty for the transitions made by the client
+	Reason string `json:"reason"`
+}
+
+func (e WalletStatus) AggregateId() string { return e.WalletId }
+
+type WalletEnabled struct {
+	WalletStatus
+}
+
+func (WalletEnabled) EventName() string { return EVENT_WALLET_ENABLED }
+
+type WalletDisabled struct {
+	WalletStatus
+}
+
+func (WalletDisabled) EventName() string { return EVENT_WALLET_DISABLED }
+
+type WalletFrozen struct {
+	WalletStatus
+}
+
+func (WalletFrozen) EventName() string { return EVENT_WALLET_FROZEN }
+
+type WalletClosed struct {
+	WalletStatus
+	// Remaining balance paid out on close
+	Payout float64 `json:"payout"`
+}
+
+func (WalletClosed) EventName() string { return EVENT_WALLET_CLOSED }
+
+// Settlement is the data shared by the events of the outcome of a deposit or a withdrawal
+type Settlement struct {
+	TransactionId string  `json:"transaction_id"`
+	WalletId      string  `json:"wallet_id"`
+	ReferenceId   string  `json:"reference_id"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+}
+
+func (e Settlement) AggregateId() string { return e.WalletId }
+
+type DepositSettled struct {
+	Settlement
+}
+
+func (DepositSettled) EventName() string { return EVENT_DEPOSIT_SETTLED }
+
+type DepositFailed struct {
+	Settlement
+}
+
+func (DepositFailed) EventName() string { return EVENT_DEPOSIT_FAILED }
+
+type WithdrawalSettled struct {
+	Settlement
+}
+
+func (WithdrawalSettled) EventName() string { return EVENT_WITHDRAWAL_SETTLED }
+
+type WithdrawalFailed struct {
+	Settlement
+}
+
+func (WithdrawalFailed) EventName() string { return EVENT_WITHDRAWAL_FAILED }
+
+// TransferCompleted belongs to the source wallet.
+// The amounts differ between wallets of different currencies
+type TransferCompleted struct {
+	ReferenceId         string  `json:"reference_id"`
+	SourceWalletId      string  `json:"source_wallet_id"`
+	SourceTransactionId string  `json:"source_transaction_id"`
+	SourceAmount        float64 `json:"source_amount"`
+	SourceCurrency      string  `json:"source_currency"`
+	TargetWalletId      string  `json:"target_wallet_id"`
+	TargetTransactionId string  `json:"target_transaction_id"`
+	TargetAmount        float64 `json:"target_amount"`
+	TargetCurrency      string  `json:"target_currency"`
+}
+
+func (TransferCompleted) EventName() string     { return EVENT_TRANSFER_COMPLETED }
+func (e TransferCompleted) AggregateId() string { return e.SourceWalletId }
+
+type BalanceAdjusted struct {
+	TransactionId string `json:"transaction_id"`
+	WalletId      string `json:"wallet_id"`
+	ReferenceId   string `json:"reference_id"`
+	// Negative for a debit
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (BalanceAdjusted) EventName() string     { return EVENT_BALANCE_ADJUSTED }
+func (e BalanceAdjusted) AggregateId() string { return e.WalletId }
+
+var registry = map[string]func() Event{
+	EVENT_WALLET_CREATED:     func() Event { return &WalletCreated{} },
+	EVENT_WALLET_ENABLED:     func() Event { return &WalletEnabled{} },
+	EVENT_WALLET_DISABLED:    func() Event { return &WalletDisabled{} },
+	EVENT_WALLET_FROZEN:      func() Event { return &WalletFrozen{} },
+	EVENT_WALLET_CLOSED:      func() Event { return &WalletClosed{} },
+	EVENT_DEPOSIT_SETTLED:    func() Event { return &DepositSettled{} },
+	EVENT_DEPOSIT_FAILED:     func() Event { return &DepositFailed{} },
+	EVENT_WITHDRAWAL_SETTLED: func() Event { return &WithdrawalSettled{} },
+	EVENT_WITHDRAWAL_FAILED:  func() Event { return &WithdrawalFailed{} },
+	EVENT_TRANSFER_COMPLETED: func() Event { return &TransferCompleted{} },
+	EVENT_BALANCE_ADJUSTED:   func() Event { return &BalanceAdjusted{} },
+}
+
+// Return the typed event of the record, as a pointer to the event struct
+func Decode(record *Record) (Event, error) {
+	newEvent, ok := registry[record.Name]
+	if !ok {
+		return nil, ErrUnknownEvent(record.Name)
+	}
+
+	event := newEvent()
+	err := json.Unmarshal(record.Payload, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/google/uuid"
+)
+
+// Record is an event written to the outbox within the database transaction of the change it describes,
+// so an event is only relayed if the change is committed
+type Record struct {
+	Id string `json:"id"`
+	// Assigned by the outbox, the records are relayed in sequence order
+	Sequence    int64           `json:"sequence"`
+	Name        string          `json:"name"`
+	AggregateId string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	RequestId   string          `json:"request_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	PublishedAt *time.Time      `json:"published_at"`
+}
+
+type OutboxRepository interface {
+	Insert(ctx context.Context, data *Record) error
+	// Take the relay lock until the end of the database transaction of the context,
+	// so only one relay publishes at a time. Return false if another relay holds it
+	TryLockRelay(ctx context.Context) (bool, error)
+	// Return up to limit records not published yet, in sequence order
+	FindUnpublished(ctx context.Context, limit int) ([]*Record, error)
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+}
+
+type EventIService interface {
+	Record(ctx context.Context, event Event) error
+	RelayPending(ctx context.Context) (int, error)
+}
+
+type EventService struct {
+	repository     OutboxRepository
+	storageManager manager.StorageManager
+	publisher      Publisher
+}
+
+func NewEventService(repository OutboxRepository, storageManager manager.StorageManager, publisher Publisher) *EventService {
+	return &EventService{repository, storageManager, publisher}
+}
+
+// Write the event to the outbox.
+// Record within the database transaction of the change so the event is only relayed if the change is committed
+func (s *EventService) Record(ctx context.Context, event Event) error {
+	if event == nil {
+		return ErrEmptyEvent
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	record := &Record{
+		Id:          uuidRandom.String(),
+		Name:        event.EventName(),
+		AggregateId: event.AggregateId(),
+		Payload:     payload,
+		RequestId:   logging.RequestIdFromContext(ctx),
+		OccurredAt:  time.Now(),
+	}
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		return s.repository.Insert(ctx, record)
+	})
+}
+
+// Publish the unpublished records in sequence order and mark them as published.
+// The relay stops at the first record the publisher fails on, so no record is published before the ones preceding it.
+// A record is published at least once, it is published again if it can't be marked as published.
+//
+// Return the number of published records, zero if another relay is running
+func (s *EventService) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.repository.TryLockRelay(ctx)
+		if err != nil || !locked {
+			return err
+		}
+
+		records, err := s.repository.FindUnpublished(ctx, RELAY_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			publishErr = s.publisher.Publish(ctx, record)
+			if publishErr != nil {
+				// Keep the records published so far marked
+				return nil
+			}
+
+			err = s.repository.MarkPublished(ctx, record.Id, time.Now())
+			if err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, publishErr
+}
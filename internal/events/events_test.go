@@ -0,0 +1,179 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	"github.com/defryheryanto/mini-wallet/internal/events/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventService_Record(t *testing.T) {
+	t.Run("should return error if event is empty", func(t *testing.T) {
+		service := events.NewEventService(mocks.NewOutboxRepository(t), &manager.MockStorageManager{}, mocks.NewPublisher(t))
+
+		err := service.Record(context.TODO(), nil)
+		assert.Equal(t, events.ErrEmptyEvent, err)
+	})
+
+	t.Run("should write the event to the outbox", func(t *testing.T) {
+		repository := mocks.NewOutboxRepository(t)
+		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			record := args.Get(1).(*events.Record)
+			assert.NotEmpty(t, record.Id)
+			assert.Equal(t, events.EVENT_DEPOSIT_SETTLED, record.Name)
+			assert.Equal(t, "wallet-id", record.AggregateId)
+			assert.JSONEq(t, `{"transaction_id":"trx-id","wallet_id":"wallet-id","reference_id":"ref","amount":100,"currency":"IDR"}`, string(record.Payload))
+			assert.Nil(t, record.PublishedAt)
+		}).Return(nil)
+		service := events.NewEventService(repository, &manager.MockStorageManager{}, mocks.NewPublisher(t))
+
+		err := service.Record(context.TODO(), events.DepositSettled{Settlement: events.Settlement{
+			TransactionId: "trx-id",
+			WalletId:      "wallet-id",
+			ReferenceId:   "ref",
+			Amount:        100,
+			Currency:      "IDR",
+		}})
+		assert.Nil(t, err)
+	})
+}
+
+func TestEventService_RelayPending(t *testing.T) {
+	t.Run("should publish nothing if another relay is running", func(t *testing.T) {
+		repository := mocks.NewOutboxRepository(t)
+		repository.On("TryLockRelay", mock.Anything).Return(false, nil)
+		service := events.NewEventService(repository, &manager.MockStorageManager{}, mocks.NewPublisher(t))
+
+		published, err := service.RelayPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, published)
+	})
+
+	t.Run("should publish the records in order and mark them as published", func(t *testing.T) {
+		records := []*events.Record{{Id: "first", Sequence: 1}, {Id: "second", Sequence: 2}}
+		published := []string{}
+
+		repository := mocks.NewOutboxRepository(t)
+		repository.On("TryLockRelay", mock.Anything).Return(true, nil)
+		repository.On("FindUnpublished", mock.Anything, events.RELAY_BATCH_SIZE).Return(records, nil)
+		repository.On("MarkPublished", mock.Anything, "first", mock.Anything).Return(nil).Once()
+		repository.On("MarkPublished", mock.Anything, "second", mock.Anything).Return(nil).Once()
+		publisher := mocks.NewPublisher(t)
+		publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			published = append(published, args.Get(1).(*events.Record).Id)
+		}).Return(nil)
+		service := events.NewEventService(repository, &manager.MockStorageManager{}, publisher)
+
+		count, err := service.RelayPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"first", "second"}, published)
+	})
+
+	t.Run("should stop at the record failed to publish", func(t *testing.T) {
+		mockedErr := fmt.Errorf("mocked")
+		records := []*events.Record{{Id: "first", Sequence: 1}, {Id: "second", Sequence: 2}, {Id: "third", Sequence: 3}}
+
+		repository := mocks.NewOutboxRepository(t)
+		repository.On("TryLockRelay", mock.Anything).Return(true, nil)
+		repository.On("FindUnpublished", mock.Anything, events.RELAY_BATCH_SIZE).Return(records, nil)
+		repository.On("MarkPublished", mock.Anything, "first", mock.Anything).Return(nil).Once()
+		publisher := mocks.NewPublisher(t)
+		publisher.On("Publish", mock.Anything, records[0]).Return(nil).Once()
+		publisher.On("Publish", mock.Anything, records[1]).Return(mockedErr).Once()
+		service := events.NewEventService(repository, &manager.MockStorageManager{}, publisher)
+
+		count, err := service.RelayPending(context.TODO())
+		assert.Equal(t, mockedErr, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should return error if event is unknown", func(t *testing.T) {
+		_, err := events.Decode(&events.Record{Name: "unknown", Payload: json.RawMessage(`{}`)})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return the typed event of the record", func(t *testing.T) {
+		event, err := events.Decode(&events.Record{
+			Name:    events.EVENT_WALLET_CLOSED,
+			Payload: json.RawMessage(`{"wallet_id":"wallet-id","previous_status":"frozen","reason":"requested","payout":100}`),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &events.WalletClosed{
+			WalletStatus: events.WalletStatus{WalletId: "wallet-id", PreviousStatus: "frozen", Reason: "requested"},
+			Payout:       100,
+		}, event)
+	})
+}
+
+func TestInProcessPublisher_Publish(t *testing.T) {
+	t.Run("should call the handlers of the event and of every event", func(t *testing.T) {
+		publisher := events.NewInProcessPublisher()
+		called := []string{}
+		publisher.Subscribe(events.EVENT_WALLET_CREATED, func(ctx context.Context, record *events.Record) error {
+			called = append(called, "created")
+			return nil
+		})
+		publisher.Subscribe(events.EVENT_WALLET_ENABLED, func(ctx context.Context, record *events.Record) error {
+			called = append(called, "enabled")
+			return nil
+		})
+		publisher.Subscribe(events.ALL_EVENTS, func(ctx context.Context, record *events.Record) error {
+			called = append(called, "all")
+			return nil
+		})
+
+		err := publisher.Publish(context.TODO(), &events.Record{Name: events.EVENT_WALLET_CREATED})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"created", "all"}, called)
+	})
+
+	t.Run("should return the error of the handler", func(t *testing.T) {
+		mockedErr := fmt.Errorf("mocked")
+		publisher := events.NewInProcessPublisher()
+		publisher.Subscribe(events.ALL_EVENTS, func(ctx context.Context, record *events.Record) error {
+			return mockedErr
+		})
+
+		err := publisher.Publish(context.TODO(), &events.Record{Name: events.EVENT_WALLET_CREATED})
+		assert.Equal(t, mockedErr, err)
+	})
+}
+
+func TestWriterPublisher_Publish(t *testing.T) {
+	t.Run("should write the record as a JSON line", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		publisher := events.NewWriterPublisher(buffer)
+
+		err := publisher.Publish(context.TODO(), &events.Record{Id: "event-id", Name: events.EVENT_WALLET_CREATED, Payload: json.RawMessage(`{"wallet_id":"wallet-id"}`)})
+		assert.Nil(t, err)
+		assert.Equal(t, byte('\n'), buffer.Bytes()[buffer.Len()-1])
+
+		record := &events.Record{}
+		assert.Nil(t, json.Unmarshal(buffer.Bytes(), record))
+		assert.Equal(t, "event-id", record.Id)
+		assert.JSONEq(t, `{"wallet_id":"wallet-id"}`, string(record.Payload))
+	})
+}
+
+func TestNewPublisher(t *testing.T) {
+	t.Run("should return error if publisher is unknown", func(t *testing.T) {
+		_, err := events.NewPublisher(&events.PublisherConfig{Type: "kafka"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return the in-process publisher by default", func(t *testing.T) {
+		publisher, err := events.NewPublisher(&events.PublisherConfig{})
+		assert.Nil(t, err)
+		assert.IsType(t, &events.InProcessPublisher{}, publisher)
+	})
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	events "github.com/defryheryanto/mini-wallet/internal/events"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventIService is an autogenerated mock type for the EventIService type
+type EventIService struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: ctx, event
+func (_m *EventIService) Record(ctx context.Context, event events.Event) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, events.Event) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RelayPending provides a mock function with given fields: ctx
+func (_m *EventIService) RelayPending(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEventIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventIService creates a new instance of EventIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventIService(t mockConstructorTestingTNewEventIService) *EventIService {
+	mock := &EventIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	events "github.com/defryheryanto/mini-wallet/internal/events"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// FindUnpublished provides a mock function with given fields: ctx, limit
+func (_m *OutboxRepository) FindUnpublished(ctx context.Context, limit int) ([]*events.Record, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []*events.Record
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*events.Record, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*events.Record); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*events.Record)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *OutboxRepository) Insert(ctx context.Context, data *events.Record) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *events.Record) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkPublished provides a mock function with given fields: ctx, id, publishedAt
+func (_m *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	ret := _m.Called(ctx, id, publishedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, publishedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TryLockRelay provides a mock function with given fields: ctx
+func (_m *OutboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	ret := _m.Called(ctx)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (bool, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOutboxRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOutboxRepository(t mockConstructorTestingTNewOutboxRepository) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
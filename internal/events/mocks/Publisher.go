@@ -0,0 +1,44 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	events "github.com/defryheryanto/mini-wallet/internal/events"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Publisher is an autogenerated mock type for the Publisher type
+type Publisher struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: ctx, record
+func (_m *Publisher) Publish(ctx context.Context, record *events.Record) error {
+	ret := _m.Called(ctx, record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *events.Record) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPublisher interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPublisher creates a new instance of Publisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPublisher(t mockConstructorTestingTNewPublisher) *Publisher {
+	mock := &Publisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
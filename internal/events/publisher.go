@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Publisher delivers the relayed records to the consumers of the events
+type Publisher interface {
+	// Return error if the record is not delivered, the relay publishes it again later
+	Publish(ctx context.Context, record *Record) error
+}
+
+type PublisherConfig struct {
+	// One of the PUBLISHER_ constants, the in-process publisher if empty
+	Type string
+	// File the records are appended to by the file publisher
+	FilePath string
+}
+
+// Return the publisher of the given config
+func NewPublisher(config *PublisherConfig) (Publisher, error) {
+	switch config.Type {
+	case PUBLISHER_INPROCESS, "":
+		return NewInProcessPublisher(), nil
+	case PUBLISHER_STDOUT:
+		return NewWriterPublisher(os.Stdout), nil
+	case PUBLISHER_FILE:
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return NewWriterPublisher(file), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", config.Type)
+	}
+}
+
+type Handler func(ctx context.Context, record *Record) error
+
+// InProcessPublisher passes the records to the handlers subscribed within the application
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{handlers: map[string][]Handler{}}
+}
+
+// Call the handler with the records of the given event name, or of every event if the name is ALL_EVENTS
+func (p *InProcessPublisher) Subscribe(name string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[name] = append(p.handlers[name], handler)
+}
+
+// Call the handlers of the record one after another, stopping at the first error.
+// The handlers already called are called again when the record is published again
+func (p *InProcessPublisher) Publish(ctx context.Context, record *Record) error {
+	p.mu.RLock()
+	handlers := append(append([]Handler{}, p.handlers[record.Name]...), p.handlers[ALL_EVENTS]...)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		err := handler(ctx, record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriterPublisher writes the records as JSON lines, e.g. to the standard output or a file
+type WriterPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterPublisher(writer io.Writer) *WriterPublisher {
+	return &WriterPublisher{writer: writer}
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.writer.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Relay publishes the recorded events in the background.
+// Only one relay publishes at a time, so every instance can run its own relay.
+// Shutdown waits for the batch being published, unpublished events are published on the next start
+type Relay struct {
+	*lifecycle.Loop
+	service EventIService
+}
+
+func NewRelay(service EventIService) *Relay {
+	r := &Relay{service: service}
+	r.Loop = lifecycle.NewLoop("event_relay", r.relay)
+	return r
+}
+
+// Publish a batch of events, the next batch follows right away unless none is left
+func (r *Relay) relay(ctx context.Context) time.Duration {
+	published, err := r.service.RelayPending(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error relaying events", logging.KEY_ERROR, err)
+		return RELAY_INTERVAL
+	}
+	if published < RELAY_BATCH_SIZE {
+		return RELAY_INTERVAL
+	}
+
+	return 0
+}
@@ -0,0 +1,32 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	event_mock "github.com/defryheryanto/mini-wallet/internal/events/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRelay(t *testing.T) {
+	t.Run("should relay the next batch right away while the batches are full", func(t *testing.T) {
+		service := event_mock.NewEventIService(t)
+		service.On("RelayPending", mock.Anything).Return(events.RELAY_BATCH_SIZE, nil).Once()
+		called := make(chan struct{})
+		service.On("RelayPending", mock.Anything).Run(func(args mock.Arguments) {
+			close(called)
+		}).Return(0, nil).Once()
+
+		relay := events.NewRelay(service)
+		relay.Start(context.TODO())
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("next batch not relayed")
+		}
+		assert.Nil(t, relay.Shutdown(context.TODO()))
+	})
+}
@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/events"
+)
+
+type Record struct {
+	Id string `gorm:"primaryKey;column:id"`
+	// Assigned by the database on insert
+	Sequence    int64      `gorm:"column:sequence;->"`
+	Name        string     `gorm:"column:name"`
+	AggregateId string     `gorm:"column:aggregate_id"`
+	Payload     []byte     `gorm:"column:payload"`
+	RequestId   string     `gorm:"column:request_id"`
+	OccurredAt  time.Time  `gorm:"column:occurred_at"`
+	PublishedAt *time.Time `gorm:"column:published_at"`
+}
+
+func (Record) TableName() string {
+	return "domain_events"
+}
+
+func (Record) FromServiceModel(data *events.Record) *Record {
+	if data == nil {
+		return nil
+	}
+
+	return &Record{
+		Id:          data.Id,
+		Sequence:    data.Sequence,
+		Name:        data.Name,
+		AggregateId: data.AggregateId,
+		Payload:     data.Payload,
+		RequestId:   data.RequestId,
+		OccurredAt:  data.OccurredAt,
+		PublishedAt: data.PublishedAt,
+	}
+}
+
+func (r *Record) ToServiceModel() *events.Record {
+	return &events.Record{
+		Id:          r.Id,
+		Sequence:    r.Sequence,
+		Name:        r.Name,
+		AggregateId: r.AggregateId,
+		Payload:     r.Payload,
+		RequestId:   r.RequestId,
+		OccurredAt:  r.OccurredAt,
+		PublishedAt: r.PublishedAt,
+	}
+}
+
+func RecordsToServiceModel(data []*Record) []*events.Record {
+	if data == nil {
+		return nil
+	}
+
+	records := []*events.Record{}
+	for _, r := range data {
+		records = append(records, r.ToServiceModel())
+	}
+
+	return records
+}
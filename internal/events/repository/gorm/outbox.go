@@ -0,0 +1,71 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+)
+
+// Key of the advisory lock letting one relay publish at a time
+const relayLockKey = 7_265_432_902
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db}
+}
+
+func (r *OutboxRepository) Insert(ctx context.Context, data *events.Record) error {
+	payload := Record{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	locked := false
+
+	err := r.getGormClient(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(&locked).Error
+	if err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+func (r *OutboxRepository) FindUnpublished(ctx context.Context, limit int) ([]*events.Record, error) {
+	records := []*Record{}
+
+	err := r.getGormClient(ctx).
+		Where("published_at IS NULL").
+		Order("sequence").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return RecordsToServiceModel(records), nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return r.getGormClient(ctx).Model(&Record{}).Where("id = ?", id).Update("published_at", publishedAt).Error
+}
+
+func (r *OutboxRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
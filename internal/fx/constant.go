@@ -0,0 +1,5 @@
+package fx
+
+import "time"
+
+const DEFAULT_QUOTE_TTL = 30 * time.Second
@@ -0,0 +1,13 @@
+package fx
+
+import "github.com/defryheryanto/mini-wallet/internal/errors"
+
+var ErrRateNotFound = errors.NewValidationError("no rate between the currencies")
+var ErrSameCurrency = errors.NewValidationError("currencies of the conversion must differ")
+var ErrInvalidRate = errors.NewValidationError("rate must be greater than zero")
+var ErrDuplicateRate = errors.NewValidationError("rate of the currency pair given more than once")
+var ErrInvalidAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrQuoteNotFound = errors.NewNotFoundError("quote not found")
+var ErrQuoteExpired = errors.NewValidationError("quote expired")
+var ErrQuoteAlreadyUsed = errors.NewValidationError("quote already used")
+var ErrQuoteMismatch = errors.NewValidationError("quote doesn't match the currencies or the amount of the transfer")
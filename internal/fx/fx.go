@@ -0,0 +1,218 @@
+package fx
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/google/uuid"
+)
+
+// Quote locks the rate of a conversion for the client until it expires.
+// A quote converts exactly its source amount and can be used once
+type Quote struct {
+	Id           string     `json:"id"`
+	ClientXid    string     `json:"client_xid"`
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Rate         float64    `json:"rate"`
+	SourceAmount float64    `json:"source_amount"`
+	TargetAmount float64    `json:"target_amount"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type QuoteRepository interface {
+	Insert(ctx context.Context, data *Quote) error
+	// Find the quote and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*Quote, error)
+	Update(ctx context.Context, data *Quote) error
+}
+
+// RateRepository stores the current rates, shared by every instance
+type RateRepository interface {
+	FindAll(ctx context.Context) ([]*Rate, error)
+	// Replace every stored rate with the given rates, has to run within a database transaction
+	ReplaceAll(ctx context.Context, rates []*Rate) error
+}
+
+type FxIService interface {
+	GetRates(ctx context.Context) ([]*Rate, error)
+	SetRates(ctx context.Context, rates []*Rate) ([]*Rate, error)
+	SeedRates(ctx context.Context, rates []*Rate) error
+	CreateQuote(ctx context.Context, params *CreateQuoteParams) (*Quote, error)
+	UseQuote(ctx context.Context, params *UseQuoteParams) (*Quote, error)
+}
+
+type FxService struct {
+	quoteRepository QuoteRepository
+	rateRepository  RateRepository
+	storageManager  manager.StorageManager
+	quoteTTL        time.Duration
+}
+
+func NewFxService(quoteRepository QuoteRepository, rateRepository RateRepository, storageManager manager.StorageManager, quoteTTL time.Duration) *FxService {
+	return &FxService{quoteRepository, rateRepository, storageManager, quoteTTL}
+}
+
+func (q *Quote) IsExpired(at time.Time) bool {
+	return !at.Before(q.ExpiresAt)
+}
+
+func (q *Quote) IsUsed() bool {
+	return q.UsedAt != nil
+}
+
+// Return the current rates ordered by pair
+func (s *FxService) GetRates(ctx context.Context) ([]*Rate, error) {
+	rates, err := s.rateRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return pairKey(rates[i].From, rates[i].To) < pairKey(rates[j].From, rates[j].To)
+	})
+
+	return rates, nil
+}
+
+// Replace the stored rates with the given rates. Quotes created before keep their locked rate
+func (s *FxService) SetRates(ctx context.Context, rates []*Rate) ([]*Rate, error) {
+	err := ValidateRates(rates)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, rate := range rates {
+		rate.UpdatedAt = now
+	}
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		return s.rateRepository.ReplaceAll(ctx, rates)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRates(ctx)
+}
+
+// Store the initial rates unless rates are already stored,
+// so the rates set by the operators are kept when an instance starts
+func (s *FxService) SeedRates(ctx context.Context, rates []*Rate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	err := ValidateRates(rates)
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.rateRepository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(stored) > 0 {
+		return nil
+	}
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		return s.rateRepository.ReplaceAll(ctx, rates)
+	})
+}
+
+// Lock the current rate converting the amount for the client
+func (s *FxService) CreateQuote(ctx context.Context, params *CreateQuoteParams) (*Quote, error) {
+	if params.From == params.To {
+		return nil, ErrSameCurrency
+	}
+	if params.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	source, err := currency.Find(params.From)
+	if err != nil {
+		return nil, err
+	}
+	target, err := currency.Find(params.To)
+	if err != nil {
+		return nil, err
+	}
+	err = source.ValidateAmount(params.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.rateRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := NewRateTable(rates).Lookup(params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+
+	targetAmount := target.Floor(params.Amount * rate)
+	if targetAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote := &Quote{
+		Id:           uuidRandom.String(),
+		ClientXid:    params.ClientXid,
+		From:         params.From,
+		To:           params.To,
+		Rate:         rate,
+		SourceAmount: params.Amount,
+		TargetAmount: targetAmount,
+		ExpiresAt:    now.Add(s.quoteTTL),
+		CreatedAt:    now,
+	}
+	err = s.quoteRepository.Insert(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// Mark the quote as used if it matches the conversion, so it can't be used again.
+// Has to run within the database transaction of the conversion, so the quote is released if the conversion fails
+func (s *FxService) UseQuote(ctx context.Context, params *UseQuoteParams) (*Quote, error) {
+	quote, err := s.quoteRepository.FindByIdForUpdate(ctx, params.QuoteId)
+	if err != nil {
+		return nil, err
+	}
+	if quote == nil || quote.ClientXid != params.ClientXid {
+		return nil, ErrQuoteNotFound
+	}
+	if quote.IsUsed() {
+		return nil, ErrQuoteAlreadyUsed
+	}
+
+	now := time.Now()
+	if quote.IsExpired(now) {
+		return nil, ErrQuoteExpired
+	}
+	if quote.From != params.From || quote.To != params.To || quote.SourceAmount != params.Amount {
+		return nil, ErrQuoteMismatch
+	}
+
+	quote.UsedAt = &now
+	err = s.quoteRepository.Update(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,157 @@
+package fx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	fx_mock "github.com/defryheryanto/mini-wallet/internal/fx/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRateRepository(t *testing.T) *fx_mock.RateRepository {
+	repository := fx_mock.NewRateRepository(t)
+	repository.On("FindAll", mock.Anything).Return([]*fx.Rate{{From: "USD", To: "JPY", Rate: 150.456}}, nil)
+	return repository
+}
+
+func TestFxService_SetRates(t *testing.T) {
+	t.Run("should return error if rates are invalid", func(t *testing.T) {
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.SetRates(context.TODO(), []*fx.Rate{{From: "USD", To: "JPY", Rate: 0}})
+		assert.Equal(t, fx.ErrInvalidRate, err)
+	})
+
+	t.Run("should replace the stored rates", func(t *testing.T) {
+		rates := []*fx.Rate{{From: "USD", To: "JPY", Rate: 151}}
+		repository := fx_mock.NewRateRepository(t)
+		repository.On("ReplaceAll", mock.Anything, rates).Return(nil)
+		repository.On("FindAll", mock.Anything).Return(rates, nil)
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), repository, &manager.MockStorageManager{}, time.Minute)
+
+		result, err := service.SetRates(context.TODO(), rates)
+		assert.Nil(t, err)
+		assert.Equal(t, rates, result)
+		assert.WithinDuration(t, time.Now(), rates[0].UpdatedAt, time.Second)
+	})
+}
+
+func TestFxService_SeedRates(t *testing.T) {
+	rates := []*fx.Rate{{From: "USD", To: "JPY", Rate: 151}}
+
+	t.Run("should keep the stored rates", func(t *testing.T) {
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), newRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		assert.Nil(t, service.SeedRates(context.TODO(), rates))
+	})
+
+	t.Run("should store the rates if none are stored", func(t *testing.T) {
+		repository := fx_mock.NewRateRepository(t)
+		repository.On("FindAll", mock.Anything).Return([]*fx.Rate{}, nil)
+		repository.On("ReplaceAll", mock.Anything, rates).Return(nil)
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), repository, &manager.MockStorageManager{}, time.Minute)
+
+		assert.Nil(t, service.SeedRates(context.TODO(), rates))
+	})
+}
+
+func TestFxService_CreateQuote(t *testing.T) {
+	t.Run("should return error if currencies are the same", func(t *testing.T) {
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.CreateQuote(context.TODO(), &fx.CreateQuoteParams{ClientXid: "test", From: "USD", To: "USD", Amount: 10})
+		assert.Equal(t, fx.ErrSameCurrency, err)
+	})
+
+	t.Run("should return error if rate is unknown", func(t *testing.T) {
+		service := fx.NewFxService(fx_mock.NewQuoteRepository(t), newRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.CreateQuote(context.TODO(), &fx.CreateQuoteParams{ClientXid: "test", From: "USD", To: "EUR", Amount: 10})
+		assert.Equal(t, fx.ErrRateNotFound, err)
+	})
+
+	t.Run("should lock the rate and floor the target amount to the target minor units", func(t *testing.T) {
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := fx.NewFxService(repository, newRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		quote, err := service.CreateQuote(context.TODO(), &fx.CreateQuoteParams{ClientXid: "test", From: "USD", To: "JPY", Amount: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, 150.456, quote.Rate)
+		assert.Equal(t, float64(1504), quote.TargetAmount)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), quote.ExpiresAt, time.Second)
+	})
+}
+
+func TestFxService_UseQuote(t *testing.T) {
+	params := &fx.UseQuoteParams{QuoteId: "quote-id", ClientXid: "test", From: "USD", To: "JPY", Amount: 10}
+	newQuote := func() *fx.Quote {
+		return &fx.Quote{
+			Id:           "quote-id",
+			ClientXid:    "test",
+			From:         "USD",
+			To:           "JPY",
+			SourceAmount: 10,
+			TargetAmount: 1504,
+			ExpiresAt:    time.Now().Add(time.Minute),
+		}
+	}
+
+	t.Run("should return error if quote belongs to another client", func(t *testing.T) {
+		quote := newQuote()
+		quote.ClientXid = "other"
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "quote-id").Return(quote, nil)
+		service := fx.NewFxService(repository, fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.UseQuote(context.TODO(), params)
+		assert.Equal(t, fx.ErrQuoteNotFound, err)
+	})
+
+	t.Run("should return error if quote already used", func(t *testing.T) {
+		quote := newQuote()
+		usedAt := time.Now()
+		quote.UsedAt = &usedAt
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "quote-id").Return(quote, nil)
+		service := fx.NewFxService(repository, fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.UseQuote(context.TODO(), params)
+		assert.Equal(t, fx.ErrQuoteAlreadyUsed, err)
+	})
+
+	t.Run("should return error if quote expired", func(t *testing.T) {
+		quote := newQuote()
+		quote.ExpiresAt = time.Now().Add(-time.Second)
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "quote-id").Return(quote, nil)
+		service := fx.NewFxService(repository, fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.UseQuote(context.TODO(), params)
+		assert.Equal(t, fx.ErrQuoteExpired, err)
+	})
+
+	t.Run("should return error if amount differs from the quote", func(t *testing.T) {
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "quote-id").Return(newQuote(), nil)
+		service := fx.NewFxService(repository, fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		_, err := service.UseQuote(context.TODO(), &fx.UseQuoteParams{QuoteId: "quote-id", ClientXid: "test", From: "USD", To: "JPY", Amount: 20})
+		assert.Equal(t, fx.ErrQuoteMismatch, err)
+	})
+
+	t.Run("should mark the quote as used", func(t *testing.T) {
+		repository := fx_mock.NewQuoteRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "quote-id").Return(newQuote(), nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := fx.NewFxService(repository, fx_mock.NewRateRepository(t), &manager.MockStorageManager{}, time.Minute)
+
+		quote, err := service.UseQuote(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.True(t, quote.IsUsed())
+	})
+}
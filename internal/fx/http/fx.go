@@ -0,0 +1,101 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+)
+
+type QuoteResponse struct {
+	Id           string    `json:"id"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	Rate         float64   `json:"rate"`
+	SourceAmount float64   `json:"source_amount"`
+	TargetAmount float64   `json:"target_amount"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type CreateQuoteRequest struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+// Respond with the current rates along with the supported currencies
+func HandleGetRates(service fx.FxIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rates, err := service.GetRates(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"currencies": currency.All(),
+			"rates":      rates,
+		})
+	}
+}
+
+func HandleCreateQuote(service fx.FxIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateQuoteRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"from": []string{
+						"Missing data for required field.",
+					},
+					"to": []string{
+						"Missing data for required field.",
+					},
+					"amount": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		quote, err := service.CreateQuote(r.Context(), &fx.CreateQuoteParams{
+			ClientXid: currentClient.Xid,
+			From:      requestBody.From,
+			To:        requestBody.To,
+			Amount:    requestBody.Amount,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"quote": &QuoteResponse{
+				Id:           quote.Id,
+				From:         quote.From,
+				To:           quote.To,
+				Rate:         quote.Rate,
+				SourceAmount: quote.SourceAmount,
+				TargetAmount: quote.TargetAmount,
+				ExpiresAt:    quote.ExpiresAt,
+			},
+		})
+	}
+}
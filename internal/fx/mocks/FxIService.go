@@ -0,0 +1,148 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	fx "github.com/defryheryanto/mini-wallet/internal/fx"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FxIService is an autogenerated mock type for the FxIService type
+type FxIService struct {
+	mock.Mock
+}
+
+// CreateQuote provides a mock function with given fields: ctx, params
+func (_m *FxIService) CreateQuote(ctx context.Context, params *fx.CreateQuoteParams) (*fx.Quote, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *fx.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.CreateQuoteParams) (*fx.Quote, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.CreateQuoteParams) *fx.Quote); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fx.Quote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fx.CreateQuoteParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRates provides a mock function with given fields: ctx
+func (_m *FxIService) GetRates(ctx context.Context) ([]*fx.Rate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*fx.Rate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*fx.Rate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*fx.Rate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fx.Rate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SeedRates provides a mock function with given fields: ctx, rates
+func (_m *FxIService) SeedRates(ctx context.Context, rates []*fx.Rate) error {
+	ret := _m.Called(ctx, rates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*fx.Rate) error); ok {
+		r0 = rf(ctx, rates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRates provides a mock function with given fields: ctx, rates
+func (_m *FxIService) SetRates(ctx context.Context, rates []*fx.Rate) ([]*fx.Rate, error) {
+	ret := _m.Called(ctx, rates)
+
+	var r0 []*fx.Rate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*fx.Rate) ([]*fx.Rate, error)); ok {
+		return rf(ctx, rates)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*fx.Rate) []*fx.Rate); ok {
+		r0 = rf(ctx, rates)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fx.Rate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*fx.Rate) error); ok {
+		r1 = rf(ctx, rates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseQuote provides a mock function with given fields: ctx, params
+func (_m *FxIService) UseQuote(ctx context.Context, params *fx.UseQuoteParams) (*fx.Quote, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *fx.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.UseQuoteParams) (*fx.Quote, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.UseQuoteParams) *fx.Quote); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fx.Quote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fx.UseQuoteParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewFxIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewFxIService creates a new instance of FxIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFxIService(t mockConstructorTestingTNewFxIService) *FxIService {
+	mock := &FxIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
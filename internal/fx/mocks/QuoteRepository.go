@@ -0,0 +1,84 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	fx "github.com/defryheryanto/mini-wallet/internal/fx"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// QuoteRepository is an autogenerated mock type for the QuoteRepository type
+type QuoteRepository struct {
+	mock.Mock
+}
+
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *QuoteRepository) FindByIdForUpdate(ctx context.Context, id string) (*fx.Quote, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *fx.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*fx.Quote, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *fx.Quote); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fx.Quote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *QuoteRepository) Insert(ctx context.Context, data *fx.Quote) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.Quote) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *QuoteRepository) Update(ctx context.Context, data *fx.Quote) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fx.Quote) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewQuoteRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewQuoteRepository creates a new instance of QuoteRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewQuoteRepository(t mockConstructorTestingTNewQuoteRepository) *QuoteRepository {
+	mock := &QuoteRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
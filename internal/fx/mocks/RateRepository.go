@@ -0,0 +1,70 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	fx "github.com/defryheryanto/mini-wallet/internal/fx"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RateRepository is an autogenerated mock type for the RateRepository type
+type RateRepository struct {
+	mock.Mock
+}
+
+// FindAll provides a mock function with given fields: ctx
+func (_m *RateRepository) FindAll(ctx context.Context) ([]*fx.Rate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*fx.Rate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*fx.Rate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*fx.Rate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fx.Rate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceAll provides a mock function with given fields: ctx, rates
+func (_m *RateRepository) ReplaceAll(ctx context.Context, rates []*fx.Rate) error {
+	ret := _m.Called(ctx, rates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*fx.Rate) error); ok {
+		r0 = rf(ctx, rates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRateRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRateRepository creates a new instance of RateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRateRepository(t mockConstructorTestingTNewRateRepository) *RateRepository {
+	mock := &RateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
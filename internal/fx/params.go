@@ -0,0 +1,17 @@
+package fx
+
+type CreateQuoteParams struct {
+	ClientXid string `json:"client_xid"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	// Amount in the From currency
+	Amount float64 `json:"amount"`
+}
+
+type UseQuoteParams struct {
+	QuoteId   string  `json:"quote_id"`
+	ClientXid string  `json:"client_xid"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Amount    float64 `json:"amount"`
+}
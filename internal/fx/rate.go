@@ -0,0 +1,105 @@
+package fx
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+)
+
+// Rate is the amount of the To currency bought by one unit of the From currency
+type Rate struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RateTable indexes the rates by pair to look them up.
+// The inverse of a rate is used when only the opposite pair is known
+type RateTable struct {
+	rates map[string]*Rate
+}
+
+type rateFile struct {
+	Rates []*Rate `json:"rates"`
+}
+
+func NewRateTable(rates []*Rate) *RateTable {
+	table := &RateTable{rates: map[string]*Rate{}}
+	for _, rate := range rates {
+		table.rates[pairKey(rate.From, rate.To)] = rate
+	}
+
+	return table
+}
+
+// Read the rates from the JSON file formatted as {"rates": [{"from": "USD", "to": "IDR", "rate": 15500}]}
+func LoadRates(path string) ([]*Rate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &rateFile{}
+	err = json.Unmarshal(content, file)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, rate := range file.Rates {
+		if rate.UpdatedAt.IsZero() {
+			rate.UpdatedAt = now
+		}
+	}
+	err = ValidateRates(file.Rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Rates, nil
+}
+
+// Return error if any of the rates is between unsupported or same currencies, not positive, or given twice
+func ValidateRates(rates []*Rate) error {
+	seen := map[string]bool{}
+	for _, rate := range rates {
+		if !currency.IsSupported(rate.From) || !currency.IsSupported(rate.To) {
+			return currency.ErrUnsupportedCurrency
+		}
+		if rate.From == rate.To {
+			return ErrSameCurrency
+		}
+		if rate.Rate <= 0 {
+			return ErrInvalidRate
+		}
+		if seen[pairKey(rate.From, rate.To)] || seen[pairKey(rate.To, rate.From)] {
+			return ErrDuplicateRate
+		}
+		seen[pairKey(rate.From, rate.To)] = true
+	}
+
+	return nil
+}
+
+// Return the rate converting the From currency to the To currency
+func (t *RateTable) Lookup(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	if rate, ok := t.rates[pairKey(from, to)]; ok {
+		return rate.Rate, nil
+	}
+	if rate, ok := t.rates[pairKey(to, from)]; ok {
+		return 1 / rate.Rate, nil
+	}
+
+	return 0, ErrRateNotFound
+}
+
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
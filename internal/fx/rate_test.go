@@ -0,0 +1,64 @@
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateTable_Lookup(t *testing.T) {
+	table := fx.NewRateTable([]*fx.Rate{{From: "USD", To: "IDR", Rate: 16000}})
+
+	t.Run("should return the rate of the pair", func(t *testing.T) {
+		rate, err := table.Lookup("USD", "IDR")
+		assert.Nil(t, err)
+		assert.Equal(t, float64(16000), rate)
+	})
+
+	t.Run("should return the inverse rate of the opposite pair", func(t *testing.T) {
+		rate, err := table.Lookup("IDR", "USD")
+		assert.Nil(t, err)
+		assert.Equal(t, 1/float64(16000), rate)
+	})
+
+	t.Run("should return 1 for the same currency", func(t *testing.T) {
+		rate, err := table.Lookup("IDR", "IDR")
+		assert.Nil(t, err)
+		assert.Equal(t, float64(1), rate)
+	})
+
+	t.Run("should return error if pair is unknown", func(t *testing.T) {
+		_, err := table.Lookup("USD", "EUR")
+		assert.Equal(t, fx.ErrRateNotFound, err)
+	})
+}
+
+func TestValidateRates(t *testing.T) {
+	t.Run("should return error if currency is not supported", func(t *testing.T) {
+		err := fx.ValidateRates([]*fx.Rate{{From: "XXX", To: "IDR", Rate: 1}})
+		assert.Equal(t, currency.ErrUnsupportedCurrency, err)
+	})
+
+	t.Run("should return error if rate is not positive", func(t *testing.T) {
+		err := fx.ValidateRates([]*fx.Rate{{From: "USD", To: "IDR", Rate: 0}})
+		assert.Equal(t, fx.ErrInvalidRate, err)
+	})
+
+	t.Run("should return error if pair is given in both directions", func(t *testing.T) {
+		err := fx.ValidateRates([]*fx.Rate{
+			{From: "USD", To: "IDR", Rate: 16000},
+			{From: "IDR", To: "USD", Rate: 0.0000625},
+		})
+		assert.Equal(t, fx.ErrDuplicateRate, err)
+	})
+
+	t.Run("should accept valid rates", func(t *testing.T) {
+		err := fx.ValidateRates([]*fx.Rate{
+			{From: "USD", To: "IDR", Rate: 16000},
+			{From: "EUR", To: "USD", Rate: 1.08},
+		})
+		assert.Nil(t, err)
+	})
+}
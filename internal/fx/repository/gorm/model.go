@@ -0,0 +1,91 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+)
+
+type Quote struct {
+	Id           string     `gorm:"primaryKey;column:id"`
+	ClientXid    string     `gorm:"column:client_xid"`
+	FromCurrency string     `gorm:"column:from_currency"`
+	ToCurrency   string     `gorm:"column:to_currency"`
+	Rate         float64    `gorm:"column:rate"`
+	SourceAmount float64    `gorm:"column:source_amount"`
+	TargetAmount float64    `gorm:"column:target_amount"`
+	ExpiresAt    time.Time  `gorm:"column:expires_at"`
+	UsedAt       *time.Time `gorm:"column:used_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+}
+
+func (Quote) TableName() string {
+	return "fx_quotes"
+}
+
+func (Quote) FromServiceModel(data *fx.Quote) *Quote {
+	if data == nil {
+		return nil
+	}
+
+	return &Quote{
+		Id:           data.Id,
+		ClientXid:    data.ClientXid,
+		FromCurrency: data.From,
+		ToCurrency:   data.To,
+		Rate:         data.Rate,
+		SourceAmount: data.SourceAmount,
+		TargetAmount: data.TargetAmount,
+		ExpiresAt:    data.ExpiresAt,
+		UsedAt:       data.UsedAt,
+		CreatedAt:    data.CreatedAt,
+	}
+}
+
+func (q *Quote) ToServiceModel() *fx.Quote {
+	return &fx.Quote{
+		Id:           q.Id,
+		ClientXid:    q.ClientXid,
+		From:         q.FromCurrency,
+		To:           q.ToCurrency,
+		Rate:         q.Rate,
+		SourceAmount: q.SourceAmount,
+		TargetAmount: q.TargetAmount,
+		ExpiresAt:    q.ExpiresAt,
+		UsedAt:       q.UsedAt,
+		CreatedAt:    q.CreatedAt,
+	}
+}
+
+type Rate struct {
+	FromCurrency string    `gorm:"primaryKey;column:from_currency"`
+	ToCurrency   string    `gorm:"primaryKey;column:to_currency"`
+	Rate         float64   `gorm:"column:rate"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+func (Rate) TableName() string {
+	return "fx_rates"
+}
+
+func (Rate) FromServiceModel(data *fx.Rate) *Rate {
+	if data == nil {
+		return nil
+	}
+
+	return &Rate{
+		FromCurrency: data.From,
+		ToCurrency:   data.To,
+		Rate:         data.Rate,
+		UpdatedAt:    data.UpdatedAt,
+	}
+}
+
+func (r *Rate) ToServiceModel() *fx.Rate {
+	return &fx.Rate{
+		From:      r.FromCurrency,
+		To:        r.ToCurrency,
+		Rate:      r.Rate,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
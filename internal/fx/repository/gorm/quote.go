@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuoteRepository struct {
+	db *gorm.DB
+}
+
+func NewQuoteRepository(db *gorm.DB) *QuoteRepository {
+	return &QuoteRepository{db}
+}
+
+func (r *QuoteRepository) Insert(ctx context.Context, data *fx.Quote) error {
+	payload := Quote{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuoteRepository) FindByIdForUpdate(ctx context.Context, id string) (*fx.Quote, error) {
+	result := &Quote{}
+
+	db := r.getGormClient(ctx)
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *QuoteRepository) Update(ctx context.Context, data *fx.Quote) error {
+	payload := Quote{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuoteRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,60 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+)
+
+type RateRepository struct {
+	db *gorm.DB
+}
+
+func NewRateRepository(db *gorm.DB) *RateRepository {
+	return &RateRepository{db}
+}
+
+func (r *RateRepository) FindAll(ctx context.Context) ([]*fx.Rate, error) {
+	results := []*Rate{}
+
+	err := r.getGormClient(ctx).Order("from_currency, to_currency").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]*fx.Rate, 0, len(results))
+	for _, result := range results {
+		rates = append(rates, result.ToServiceModel())
+	}
+
+	return rates, nil
+}
+
+func (r *RateRepository) ReplaceAll(ctx context.Context, rates []*fx.Rate) error {
+	payload := make([]*Rate, 0, len(rates))
+	for _, rate := range rates {
+		payload = append(payload, Rate{}.FromServiceModel(rate))
+	}
+
+	db := r.getGormClient(ctx)
+	err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Rate{}).Error
+	if err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+
+	return db.Create(&payload).Error
+}
+
+func (r *RateRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
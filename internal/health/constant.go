@@ -0,0 +1,10 @@
+package health
+
+import "time"
+
+const (
+	STATUS_UP   = "up"
+	STATUS_DOWN = "down"
+)
+
+const CHECK_TIMEOUT = 3 * time.Second
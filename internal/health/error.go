@@ -0,0 +1,7 @@
+package health
+
+import "fmt"
+
+var ErrShuttingDown = fmt.Errorf("application is shutting down")
+var ErrMigrationPending = fmt.Errorf("database migration pending")
+var ErrMigrationDirty = fmt.Errorf("database migration dirty")
@@ -0,0 +1,93 @@
+package gorm
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/defryheryanto/mini-wallet/internal/health"
+	"gorm.io/gorm"
+)
+
+type schemaMigration struct {
+	Version int64 `gorm:"column:version"`
+	Dirty   bool  `gorm:"column:dirty"`
+}
+
+// Check that the database is reachable
+func DatabaseCheck(db *gorm.DB) health.Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+
+		stats := sqlDB.Stats()
+		details := map[string]interface{}{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+		}
+
+		err = sqlDB.PingContext(ctx)
+		if err != nil {
+			return details, err
+		}
+
+		return details, nil
+	}
+}
+
+// Check that the database schema is migrated up to the latest migration in the given directory.
+// Migration state is read from the schema_migrations table maintained by golang-migrate
+func MigrationCheck(db *gorm.DB, migrations fs.FS, dir string) health.Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		latest, err := latestMigrationVersion(migrations, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		current := &schemaMigration{}
+		err = db.WithContext(ctx).Table("schema_migrations").Limit(1).Find(current).Error
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]interface{}{
+			"current_version": current.Version,
+			"latest_version":  latest,
+			"dirty":           current.Dirty,
+		}
+		if current.Dirty {
+			return details, health.ErrMigrationDirty
+		}
+		if current.Version < latest {
+			return details, health.ErrMigrationPending
+		}
+
+		return details, nil
+	}
+}
+
+func latestMigrationVersion(migrations fs.FS, dir string) (int64, error) {
+	files, err := fs.Glob(migrations, path.Join(dir, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(path.Base(file), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+
+	return latest, nil
+}
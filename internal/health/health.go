@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+)
+
+// Check reports the state of a single component.
+// Return error if the component is not able to serve requests
+type Check func(ctx context.Context) (details map[string]interface{}, err error)
+
+type Component struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status     string                `json:"status"`
+	Components map[string]*Component `json:"components"`
+}
+
+type HealthIService interface {
+	Register(name string, check Check)
+	Readiness(ctx context.Context) *Report
+}
+
+type HealthService struct {
+	lifecycleManager *lifecycle.Manager
+	timeout          time.Duration
+
+	mu     sync.Mutex
+	names  []string
+	checks map[string]Check
+}
+
+func NewHealthService(lifecycleManager *lifecycle.Manager, timeout time.Duration) *HealthService {
+	return &HealthService{
+		lifecycleManager: lifecycleManager,
+		timeout:          timeout,
+		checks:           map[string]Check{},
+	}
+}
+
+// Register the check of a component to be included in the readiness report
+func (s *HealthService) Register(name string, check Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.checks[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.checks[name] = check
+}
+
+// Run every registered check concurrently and report the state of each component.
+// The application is not ready if any component is down or the application is shutting down
+func (s *HealthService) Readiness(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	s.mu.Lock()
+	checks := make(map[string]Check, len(s.checks))
+	for _, name := range s.names {
+		checks[name] = s.checks[name]
+	}
+	s.mu.Unlock()
+
+	report := &Report{
+		Status:     STATUS_UP,
+		Components: map[string]*Component{},
+	}
+
+	lifecycleComponent := &Component{Status: STATUS_UP}
+	if s.lifecycleManager.IsShuttingDown() {
+		lifecycleComponent.Status = STATUS_DOWN
+		lifecycleComponent.Error = ErrShuttingDown.Error()
+	}
+	report.Components["lifecycle"] = lifecycleComponent
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			component := &Component{Status: STATUS_UP}
+			details, err := check(ctx)
+			if err != nil {
+				component.Status = STATUS_DOWN
+				component.Error = err.Error()
+			}
+			component.Details = details
+
+			mu.Lock()
+			report.Components[name] = component
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != STATUS_UP {
+			report.Status = STATUS_DOWN
+			break
+		}
+	}
+
+	return report
+}
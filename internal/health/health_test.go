@@ -0,0 +1,69 @@
+package health_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/health"
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthService_Readiness(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	upCheck := func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"pending": 0}, nil
+	}
+	downCheck := func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, mockedErr
+	}
+
+	t.Run("should report up if every component is up", func(t *testing.T) {
+		service := health.NewHealthService(lifecycle.NewManager(), time.Second)
+		service.Register("first", upCheck)
+		service.Register("second", upCheck)
+
+		report := service.Readiness(context.TODO())
+		assert.Equal(t, health.STATUS_UP, report.Status)
+		assert.Equal(t, health.STATUS_UP, report.Components["first"].Status)
+		assert.Equal(t, health.STATUS_UP, report.Components["second"].Status)
+		assert.Equal(t, 0, report.Components["first"].Details["pending"])
+	})
+
+	t.Run("should report down if any component is down", func(t *testing.T) {
+		service := health.NewHealthService(lifecycle.NewManager(), time.Second)
+		service.Register("first", upCheck)
+		service.Register("second", downCheck)
+
+		report := service.Readiness(context.TODO())
+		assert.Equal(t, health.STATUS_DOWN, report.Status)
+		assert.Equal(t, health.STATUS_UP, report.Components["first"].Status)
+		assert.Equal(t, health.STATUS_DOWN, report.Components["second"].Status)
+		assert.Equal(t, mockedErr.Error(), report.Components["second"].Error)
+	})
+
+	t.Run("should report down if application is shutting down", func(t *testing.T) {
+		lifecycleManager := lifecycle.NewManager()
+		service := health.NewHealthService(lifecycleManager, time.Second)
+		service.Register("first", upCheck)
+		lifecycleManager.StartShutdown()
+
+		report := service.Readiness(context.TODO())
+		assert.Equal(t, health.STATUS_DOWN, report.Status)
+		assert.Equal(t, health.STATUS_DOWN, report.Components["lifecycle"].Status)
+	})
+
+	t.Run("should pass context with deadline to the checks", func(t *testing.T) {
+		service := health.NewHealthService(lifecycle.NewManager(), 10*time.Millisecond)
+		service.Register("slow", func(ctx context.Context) (map[string]interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		report := service.Readiness(context.TODO())
+		assert.Equal(t, health.STATUS_DOWN, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Components["slow"].Error)
+	})
+}
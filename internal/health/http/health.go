@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/health"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+)
+
+func HandleLiveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"status": health.STATUS_UP,
+		})
+	}
+}
+
+func HandleReadiness(service health.HealthIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := service.Readiness(r.Context())
+		if report.Status != health.STATUS_UP {
+			response.Failed(w, errors.NewServiceUnavailableError(report))
+			return
+		}
+
+		response.Success(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/defryheryanto/mini-wallet/internal/admin"
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Authenticate the operator by the admin API key sent as "Authorization: Bearer {key}".
+// The actions taken within the request are audited as the operator
+func AuthenticateOperator(authenticator *admin.Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found {
+				response.Failed(w, admin.ErrInvalidApiKey)
+				return
+			}
+
+			operator, err := authenticator.Authenticate(key)
+			if err != nil {
+				response.Failed(w, err)
+				return
+			}
+
+			ctx := admin.Inject(r.Context(), operator)
+			ctx = audit.InjectActor(ctx, admin.ACTOR_PREFIX+operator.Name)
+			ctx = logging.With(ctx, logging.KEY_OPERATOR, operator.Name)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
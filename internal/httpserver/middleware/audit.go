@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+)
+
+// Inject the IP address of the request into the context, so the audit events recorded within the request carry it
+func AuditContext() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := audit.InjectIp(r.Context(), remoteIp(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func remoteIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
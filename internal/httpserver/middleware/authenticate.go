@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/defryheryanto/mini-wallet/internal/audit"
 	"github.com/defryheryanto/mini-wallet/internal/client"
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
 )
 
 func AuthenticateClient(clientService client.ClientIService) func(next http.Handler) http.Handler {
@@ -19,7 +21,7 @@ func AuthenticateClient(clientService client.ClientIService) func(next http.Hand
 
 			token := authorization[1]
 
-			currentClient, err := clientService.GetByToken(r.Context(), token)
+			currentClient, currentToken, err := clientService.GetByToken(r.Context(), token)
 			if err != nil {
 				response.Failed(w, err)
 				return
@@ -30,6 +32,9 @@ func AuthenticateClient(clientService client.ClientIService) func(next http.Hand
 			}
 
 			ctx := client.Inject(r.Context(), currentClient)
+			ctx = client.InjectToken(ctx, currentToken)
+			ctx = audit.InjectActor(ctx, client.ACTOR_PREFIX+currentClient.Xid)
+			ctx = logging.With(ctx, logging.KEY_CLIENT_XID, currentClient.Xid)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
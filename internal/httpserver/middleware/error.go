@@ -3,3 +3,10 @@ package middleware
 import "github.com/defryheryanto/mini-wallet/internal/errors"
 
 var ErrInvalidToken = errors.NewUnauthorizedError("authorization token invalid")
+
+func ErrInsufficientScope(scope string) errors.HandledError {
+	return errors.NewForbiddenError(map[string]interface{}{
+		"message":        "token is not granted the required scope",
+		"required_scope": scope,
+	})
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	chi_middleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Record the count and latency of every request by its chi route pattern,
+// so requests with different path parameters are grouped together
+func Metrics(m *metrics.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chi_middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			route := metrics.ROUTE_NOT_FOUND
+			if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+				if pattern := routeContext.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			m.ObserveHTTPRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
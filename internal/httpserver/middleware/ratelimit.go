@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+)
+
+const (
+	HEADER_RETRY_AFTER          = "Retry-After"
+	HEADER_RATE_LIMIT_LIMIT     = "X-RateLimit-Limit"
+	HEADER_RATE_LIMIT_REMAINING = "X-RateLimit-Remaining"
+	HEADER_RATE_LIMIT_RESET     = "X-RateLimit-Reset"
+)
+
+// Limit the requests to the routes of the group per authenticated client,
+// or per IP address if the client is not authenticated.
+// The request is let through if the limiter store fails, so an outage of the store doesn't take the API down
+func RateLimit(limiter *ratelimit.Limiter, group string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), group, rateLimitKey(r))
+			if err != nil {
+				logging.FromContext(r.Context()).Error("error taking rate limit token", "group", group, logging.KEY_ERROR, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(HEADER_RATE_LIMIT_LIMIT, strconv.Itoa(result.Limit))
+			w.Header().Set(HEADER_RATE_LIMIT_REMAINING, strconv.Itoa(result.Remaining))
+			w.Header().Set(HEADER_RATE_LIMIT_RESET, strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+			if !result.Allowed {
+				w.Header().Set(HEADER_RETRY_AFTER, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+				response.Failed(w, ratelimit.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimitKey(r *http.Request) string {
+	currentClient, err := client.FromContext(r.Context())
+	if err == nil {
+		return "client:" + currentClient.Xid
+	}
+
+	return "ip:" + remoteIp(r)
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/google/uuid"
+)
+
+const HEADER_REQUEST_ID = "X-Request-ID"
+
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Read the request id from the X-Request-ID header, or generate one if the header is missing or invalid.
+// The request id is echoed back in the response header, injected into the context
+// and attached to every line logged with the logger of the request context
+func RequestId(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(HEADER_REQUEST_ID)
+			if !validRequestId.MatchString(requestId) {
+				requestId = uuid.NewString()
+			}
+			w.Header().Set(HEADER_REQUEST_ID, requestId)
+
+			ctx := logging.InjectRequestId(r.Context(), requestId)
+			ctx = logging.Inject(ctx, logger.With(logging.KEY_REQUEST_ID, requestId))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
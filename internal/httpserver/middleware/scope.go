@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+)
+
+// Reject the request if the token used to authenticate the client is not granted the given scope.
+// Must be used after AuthenticateClient
+func RequireScope(scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			currentToken, err := client.TokenFromContext(r.Context())
+			if err != nil {
+				response.Failed(w, err)
+				return
+			}
+			if !currentToken.HasScope(scope) {
+				response.Failed(w, ErrInsufficientScope(scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
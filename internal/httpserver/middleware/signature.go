@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+)
+
+const (
+	HEADER_SIGNATURE           = "X-Signature"
+	HEADER_SIGNATURE_TIMESTAMP = "X-Signature-Timestamp"
+	HEADER_SIGNATURE_NONCE     = "X-Signature-Nonce"
+)
+
+// Verify the signature of the request against the signing secret of the token used to authenticate the client.
+// Unsigned requests pass through unless the token requires signature.
+// Must be used after AuthenticateClient
+func VerifySignature(verifier *client.SignatureVerifier) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			currentToken, err := client.TokenFromContext(r.Context())
+			if err != nil {
+				response.Failed(w, err)
+				return
+			}
+
+			signedRequest := &client.SignedRequest{
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Timestamp: r.Header.Get(HEADER_SIGNATURE_TIMESTAMP),
+				Nonce:     r.Header.Get(HEADER_SIGNATURE_NONCE),
+				Signature: r.Header.Get(HEADER_SIGNATURE),
+			}
+			if signedRequest.Signature != "" {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					response.Failed(w, err)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				signedRequest.Body = body
+			}
+
+			err = verifier.Verify(r.Context(), currentToken, signedRequest)
+			if err != nil {
+				response.Failed(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
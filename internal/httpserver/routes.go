@@ -3,29 +3,152 @@ package httpserver
 import (
 	"net/http"
 
+	activity_http "github.com/defryheryanto/mini-wallet/internal/activity/http"
+	admin_http "github.com/defryheryanto/mini-wallet/internal/admin/http"
 	"github.com/defryheryanto/mini-wallet/internal/app"
+	audit_http "github.com/defryheryanto/mini-wallet/internal/audit/http"
+	balance_http "github.com/defryheryanto/mini-wallet/internal/balance/http"
+	"github.com/defryheryanto/mini-wallet/internal/client"
 	client_http "github.com/defryheryanto/mini-wallet/internal/client/http"
+	fx_http "github.com/defryheryanto/mini-wallet/internal/fx/http"
+	health_http "github.com/defryheryanto/mini-wallet/internal/health/http"
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/middleware"
+	paymentrequest_http "github.com/defryheryanto/mini-wallet/internal/paymentrequest/http"
+	payout_http "github.com/defryheryanto/mini-wallet/internal/payout/http"
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+	schedule_http "github.com/defryheryanto/mini-wallet/internal/schedule/http"
+	statement_http "github.com/defryheryanto/mini-wallet/internal/statement/http"
 	transaction_http "github.com/defryheryanto/mini-wallet/internal/transaction/http"
+	twofactor_http "github.com/defryheryanto/mini-wallet/internal/twofactor/http"
 	wallet_http "github.com/defryheryanto/mini-wallet/internal/wallet/http"
+	webhook_http "github.com/defryheryanto/mini-wallet/internal/webhook/http"
 	"github.com/go-chi/chi/v5"
 )
 
 func HandleRoutes(application *app.Application) http.Handler {
 	root := chi.NewRouter()
+	root.Use(middleware.RequestId(application.Logger))
+	root.Use(middleware.Tracing())
+	root.Use(middleware.Metrics(application.Metrics))
+	root.Use(middleware.AuditContext())
 
-	root.Post("/api/v1/init", client_http.HandleCreateClient(application.ClientService))
+	root.Handle("/metrics", application.Metrics.Handler())
+	root.Get("/healthz", health_http.HandleLiveness())
+	root.Get("/readyz", health_http.HandleReadiness(application.HealthService))
+
+	root.With(middleware.RateLimit(application.RateLimiter, ratelimit.GROUP_INIT)).Post("/api/v1/init", client_http.HandleCreateClient(application.ClientService))
 
 	root.Group(func(r chi.Router) {
 		r.Use(middleware.AuthenticateClient(application.ClientService))
+		r.Use(middleware.VerifySignature(application.SignatureVerifier))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(application.RateLimiter, ratelimit.GROUP_READ))
+
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/wallet", wallet_http.HandleViewWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/wallet/balance", balance_http.HandleGetBalance(application.BalanceService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/events", activity_http.HandleStreamEvents(application.ActivityBroker, application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/transactions", transaction_http.HandleGetWalletTransactions(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/transactions/export", transaction_http.HandleExportTransactions(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/statements", statement_http.HandleGetStatements(application.StatementService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/statements/{id}", statement_http.HandleGetStatement(application.StatementService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/schedules", schedule_http.HandleGetSchedules(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallet/schedules/{id}", schedule_http.HandleGetSchedule(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/wallets", wallet_http.HandleListWallets(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/wallets/{wallet_id}", wallet_http.HandleViewWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/wallets/{wallet_id}/balance", balance_http.HandleGetBalance(application.BalanceService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/events", activity_http.HandleStreamEvents(application.ActivityBroker, application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/transactions", transaction_http.HandleGetWalletTransactions(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/transactions/export", transaction_http.HandleExportTransactions(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/statements", statement_http.HandleGetStatements(application.StatementService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/statements/{id}", statement_http.HandleGetStatement(application.StatementService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/schedules", schedule_http.HandleGetSchedules(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/wallets/{wallet_id}/schedules/{id}", schedule_http.HandleGetSchedule(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/payouts/batches/{id}", payout_http.HandleGetBatch(application.PayoutService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/payment-requests/incoming", paymentrequest_http.HandleGetIncomingPaymentRequests(application.PaymentRequestService))
+			r.With(middleware.RequireScope(client.SCOPE_TRANSACTIONS_READ)).Get("/api/v1/payment-requests/outgoing", paymentrequest_http.HandleGetOutgoingPaymentRequests(application.PaymentRequestService))
+			r.With(middleware.RequireScope(client.SCOPE_TOKENS_MANAGE)).Get("/api/v1/tokens", client_http.HandleGetTokens(application.ClientService))
+			r.With(middleware.RequireScope(client.SCOPE_WEBHOOKS_MANAGE)).Get("/api/v1/webhooks", webhook_http.HandleGetEndpoints(application.WebhookService))
+			r.With(middleware.RequireScope(client.SCOPE_WEBHOOKS_MANAGE)).Get("/api/v1/webhooks/{id}/deliveries", webhook_http.HandleGetDeliveries(application.WebhookService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_READ)).Get("/api/v1/fx/rates", fx_http.HandleGetRates(application.FxService))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(application.RateLimiter, ratelimit.GROUP_WRITE))
+
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Post("/api/v1/wallet", wallet_http.HandleEnableWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Patch("/api/v1/wallet", wallet_http.HandleUpdateWalletStatus(application.WalletService))
+
+			r.With(middleware.RequireScope(client.SCOPE_DEPOSITS_CREATE)).Post("/api/v1/wallet/deposits", transaction_http.HandleCreateDeposit(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallet/withdrawals", transaction_http.HandleCreateWithdrawal(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallet/withdrawals/{id}/confirm", transaction_http.HandleConfirmWithdrawal(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallet/transfers", transaction_http.HandleCreateTransfer(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallet/transfers/{id}/confirm", transaction_http.HandleConfirmTransfer(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallet/schedules", schedule_http.HandleCreateSchedule(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Delete("/api/v1/wallet/schedules/{id}", schedule_http.HandleCancelSchedule(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/fx/quotes", fx_http.HandleCreateQuote(application.FxService))
+
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Post("/api/v1/wallets", wallet_http.HandleCreateWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Post("/api/v1/wallets/{wallet_id}", wallet_http.HandleEnableWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Patch("/api/v1/wallets/{wallet_id}", wallet_http.HandleUpdateWalletStatus(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Put("/api/v1/wallets/{wallet_id}/name", wallet_http.HandleRenameWallet(application.WalletService))
+			r.With(middleware.RequireScope(client.SCOPE_WALLET_WRITE)).Post("/api/v1/wallets/{wallet_id}/default", wallet_http.HandleSetDefaultWallet(application.WalletService))
+
+			r.With(middleware.RequireScope(client.SCOPE_DEPOSITS_CREATE)).Post("/api/v1/wallets/{wallet_id}/deposits", transaction_http.HandleCreateDeposit(application.TransactionService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallets/{wallet_id}/withdrawals", transaction_http.HandleCreateWithdrawal(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallets/{wallet_id}/withdrawals/{id}/confirm", transaction_http.HandleConfirmWithdrawal(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallets/{wallet_id}/transfers", transaction_http.HandleCreateTransfer(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallets/{wallet_id}/transfers/{id}/confirm", transaction_http.HandleConfirmTransfer(application.WithdrawalConfirmationService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/wallets/{wallet_id}/schedules", schedule_http.HandleCreateSchedule(application.ScheduleService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Delete("/api/v1/wallets/{wallet_id}/schedules/{id}", schedule_http.HandleCancelSchedule(application.ScheduleService))
+
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/payouts/batches", payout_http.HandleCreateBatch(application.PayoutService))
+			r.With(middleware.RequireScope(client.SCOPE_DEPOSITS_CREATE)).Post("/api/v1/payment-requests", paymentrequest_http.HandleCreatePaymentRequest(application.PaymentRequestService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/payment-requests/{id}/accept", paymentrequest_http.HandleAcceptPaymentRequest(application.PaymentRequestService))
+			r.With(middleware.RequireScope(client.SCOPE_WITHDRAWALS_CREATE)).Post("/api/v1/payment-requests/{id}/decline", paymentrequest_http.HandleDeclinePaymentRequest(application.PaymentRequestService))
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireScope(client.SCOPE_TOKENS_MANAGE))
+
+				r.Post("/api/v1/tokens", client_http.HandleCreateToken(application.ClientService))
+				r.Post("/api/v1/tokens/rotate", client_http.HandleRotateToken(application.ClientService))
+				r.Patch("/api/v1/tokens/{id}", client_http.HandleUpdateToken(application.ClientService))
+				r.Delete("/api/v1/tokens/{id}", client_http.HandleRevokeToken(application.ClientService))
+
+				r.Post("/api/v1/totp", twofactor_http.HandleEnroll(application.TwoFactorService))
+				r.Post("/api/v1/totp/activate", twofactor_http.HandleActivate(application.TwoFactorService))
+				r.Delete("/api/v1/totp", twofactor_http.HandleDisable(application.TwoFactorService))
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireScope(client.SCOPE_WEBHOOKS_MANAGE))
+
+				r.Post("/api/v1/webhooks", webhook_http.HandleCreateEndpoint(application.WebhookService))
+				r.Delete("/api/v1/webhooks/{id}", webhook_http.HandleDeleteEndpoint(application.WebhookService))
+				r.Post("/api/v1/webhooks/deliveries/{id}/redeliver", webhook_http.HandleRedeliver(application.WebhookService))
+			})
+		})
+	})
+
+	root.Route("/admin/v1", func(r chi.Router) {
+		r.Use(middleware.AuthenticateOperator(application.AdminAuthenticator))
+
+		r.Get("/clients", admin_http.HandleSearchClients(application.AdminService))
+		r.Get("/wallets", admin_http.HandleSearchWallets(application.AdminService))
+		r.Get("/wallets/{id}", admin_http.HandleViewWallet(application.AdminService))
+		r.Get("/wallets/{id}/transactions", admin_http.HandleGetWalletTransactions(application.AdminService))
+		r.Post("/wallets/{id}/disable", admin_http.HandleDisableWallet(application.AdminService))
+		r.Post("/wallets/{id}/freeze", admin_http.HandleFreezeWallet(application.AdminService))
+		r.Post("/wallets/{id}/unfreeze", admin_http.HandleUnfreezeWallet(application.AdminService))
+		r.Post("/wallets/{id}/close", admin_http.HandleCloseWallet(application.AdminService))
+		r.Post("/wallets/{id}/adjustments", admin_http.HandleAdjustBalance(application.AdminService))
+		r.Post("/transactions/{id}/retry", admin_http.HandleRetryTransaction(application.AdminService))
+		r.Post("/transactions/{id}/fail", admin_http.HandleFailTransaction(application.AdminService))
 
-		r.Get("/api/v1/wallet", wallet_http.HandleViewWallet(application.WalletService))
-		r.Post("/api/v1/wallet", wallet_http.HandleEnableWallet(application.WalletService))
-		r.Patch("/api/v1/wallet", wallet_http.HandleUpdateWalletStatus(application.WalletService))
+		r.Put("/fx/rates", admin_http.HandleSetFxRates(application.AdminService))
 
-		r.Get("/api/v1/wallet/transactions", transaction_http.HandleGetWalletTransactions(application.TransactionService))
-		r.Post("/api/v1/wallet/deposits", transaction_http.HandleCreateDeposit(application.TransactionService))
-		r.Post("/api/v1/wallet/withdrawals", transaction_http.HandleCreateWithdrawal(application.TransactionService))
+		r.Get("/audit/events", audit_http.HandleSearchEvents(application.AuditService))
+		r.Get("/audit/verify", audit_http.HandleVerifyChain(application.AuditService))
 	})
 
 	return root
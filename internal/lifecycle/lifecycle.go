@@ -0,0 +1,87 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Worker is a background process that has to be drained before the application exits
+type Worker interface {
+	Name() string
+	Shutdown(ctx context.Context) error
+}
+
+type Manager struct {
+	mu           sync.Mutex
+	workers      []Worker
+	shuttingDown bool
+}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Register the worker to be drained when the application shuts down
+func (m *Manager) Register(worker Worker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.workers = append(m.workers, worker)
+}
+
+// Return true once Shutdown has been called
+func (m *Manager) IsShuttingDown() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.shuttingDown
+}
+
+// Flag the application as shutting down without stopping the workers yet,
+// so readiness probes can fail before the server stops accepting connections
+func (m *Manager) StartShutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.shuttingDown = true
+}
+
+// Shut down every registered worker concurrently and wait for them to finish.
+// Each worker should stop accepting new work immediately and finish the running one before the context deadline.
+//
+// Return error if any of the workers failed to shut down before the context is done
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.shuttingDown = true
+	workers := make([]Worker, len(m.workers))
+	copy(workers, m.workers)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(workers))
+	for i, worker := range workers {
+		wg.Add(1)
+		go func(i int, worker Worker) {
+			defer wg.Done()
+
+			logging.FromContext(ctx).Info("shutting down worker", "worker", worker.Name())
+			errs[i] = worker.Shutdown(ctx)
+		}(i, worker)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", workers[i].Name(), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to shut down workers %v", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package lifecycle_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWorker struct {
+	name     string
+	err      error
+	shutdown bool
+}
+
+func (w *fakeWorker) Name() string {
+	return w.name
+}
+
+func (w *fakeWorker) Shutdown(ctx context.Context) error {
+	w.shutdown = true
+	return w.err
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Run("should shut down every registered worker", func(t *testing.T) {
+		first := &fakeWorker{name: "first"}
+		second := &fakeWorker{name: "second"}
+		manager := lifecycle.NewManager()
+		manager.Register(first)
+		manager.Register(second)
+
+		assert.False(t, manager.IsShuttingDown())
+		err := manager.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.True(t, manager.IsShuttingDown())
+		assert.True(t, first.shutdown)
+		assert.True(t, second.shutdown)
+	})
+
+	t.Run("should return error if any worker failed to shut down", func(t *testing.T) {
+		failing := &fakeWorker{name: "failing", err: fmt.Errorf("mocked")}
+		other := &fakeWorker{name: "other"}
+		manager := lifecycle.NewManager()
+		manager.Register(failing)
+		manager.Register(other)
+
+		err := manager.Shutdown(context.TODO())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "failing")
+		assert.True(t, other.shutdown)
+	})
+}
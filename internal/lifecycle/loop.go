@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Tick does one round of the work of a background worker.
+// Return how long to wait before the next round, the next round starts right away if it is not positive
+type Tick func(ctx context.Context) time.Duration
+
+// Loop runs the tick of a background worker round after round until the worker is shut down.
+// It is the Worker of the background process, to be registered to the Manager
+type Loop struct {
+	name string
+	tick Tick
+	// Ticks are cancelled on shutdown instead of being waited for
+	interruptible bool
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started atomic.Bool
+	done    chan struct{}
+}
+
+// Return the loop of the given tick. A running tick is waited for on shutdown
+func NewLoop(name string, tick Tick) *Loop {
+	return newLoop(name, tick, false)
+}
+
+// Return the loop of the given tick. The context of a running tick is cancelled on shutdown
+func NewInterruptibleLoop(name string, tick Tick) *Loop {
+	return newLoop(name, tick, true)
+}
+
+func newLoop(name string, tick Tick, interruptible bool) *Loop {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Loop{
+		name:          name,
+		tick:          tick,
+		interruptible: interruptible,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+}
+
+func (l *Loop) Name() string {
+	return l.name
+}
+
+// Start running the ticks in the background, the first one right away.
+// The ticks receive the logger of the given context but not its cancellation
+func (l *Loop) Start(ctx context.Context) {
+	if !l.started.CompareAndSwap(false, true) {
+		return
+	}
+
+	parent := context.Background()
+	if l.interruptible {
+		parent = l.ctx
+	}
+	ctx = logging.Inject(parent, logging.FromContext(ctx))
+
+	go func() {
+		defer close(l.done)
+		for {
+			if !l.wait(l.tick(ctx)) {
+				return
+			}
+		}
+	}()
+}
+
+// Stop the loop and wait for the running tick to return
+func (l *Loop) Shutdown(ctx context.Context) error {
+	l.cancel()
+	if !l.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Return false if the loop is shut down before the given duration
+func (l *Loop) wait(duration time.Duration) bool {
+	if duration <= 0 {
+		return l.ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-l.ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,76 @@
+package lifecycle_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoop(t *testing.T) {
+	t.Run("should shut down without being started", func(t *testing.T) {
+		loop := lifecycle.NewLoop("test", func(ctx context.Context) time.Duration {
+			t.Fatal("tick called without start")
+			return 0
+		})
+
+		assert.Nil(t, loop.Shutdown(context.TODO()))
+	})
+
+	t.Run("should run the ticks until shutdown and wait for the running one", func(t *testing.T) {
+		ticked := make(chan struct{})
+		release := make(chan struct{})
+		finished := false
+		count := 0
+		loop := lifecycle.NewLoop("test", func(ctx context.Context) time.Duration {
+			count++
+			if count < 3 {
+				return 0
+			}
+			close(ticked)
+			<-release
+			assert.Nil(t, ctx.Err())
+			finished = true
+			return time.Hour
+		})
+
+		loop.Start(context.TODO())
+		<-ticked
+		go close(release)
+		assert.Nil(t, loop.Shutdown(context.TODO()))
+		assert.True(t, finished)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("should cancel the running tick of an interruptible loop on shutdown", func(t *testing.T) {
+		ticked := make(chan struct{})
+		loop := lifecycle.NewInterruptibleLoop("test", func(ctx context.Context) time.Duration {
+			close(ticked)
+			<-ctx.Done()
+			return 0
+		})
+
+		loop.Start(context.TODO())
+		<-ticked
+		assert.Nil(t, loop.Shutdown(context.TODO()))
+	})
+
+	t.Run("should return the context error if the running tick doesn't return in time", func(t *testing.T) {
+		ticked := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		loop := lifecycle.NewLoop("test", func(ctx context.Context) time.Duration {
+			close(ticked)
+			<-release
+			return 0
+		})
+
+		loop.Start(context.TODO())
+		<-ticked
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, loop.Shutdown(ctx))
+	})
+}
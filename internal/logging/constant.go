@@ -0,0 +1,10 @@
+package logging
+
+const (
+	KEY_REQUEST_ID     = "request_id"
+	KEY_CLIENT_XID     = "client_xid"
+	KEY_OPERATOR       = "operator"
+	KEY_WALLET_ID      = "wallet_id"
+	KEY_TRANSACTION_ID = "transaction_id"
+	KEY_ERROR          = "error"
+)
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type key string
+
+var loggerKey = key("logger_context")
+var requestIdKey = key("request_id_context")
+
+// Inject the logger into the current context
+func Inject(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Extract the logger from the specified context
+//
+// Return the default logger if logger is not exists in the context
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey).(*slog.Logger)
+	if !ok || logger == nil {
+		return slog.Default()
+	}
+
+	return logger
+}
+
+// Attach the attributes to every line logged with the logger of the returned context
+func With(ctx context.Context, args ...any) context.Context {
+	return Inject(ctx, FromContext(ctx).With(args...))
+}
+
+// Inject the request id into the current context
+func InjectRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey, requestId)
+}
+
+// Extract the request id from the specified context
+//
+// Return empty string if request id is not exists in the context
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey).(string)
+	return requestId
+}
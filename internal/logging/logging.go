@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Create a logger writing JSON lines with the given minimum level
+func NewLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Parse the level name (debug, info, warn, error)
+//
+// Return info level if the name is unknown
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
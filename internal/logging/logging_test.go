@@ -0,0 +1,48 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("should return default logger if logger is not injected", func(t *testing.T) {
+		assert.Equal(t, slog.Default(), logging.FromContext(context.TODO()))
+	})
+
+	t.Run("should log attributes attached to the context", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		ctx := logging.Inject(context.TODO(), logging.NewLogger(buffer, slog.LevelInfo))
+		ctx = logging.With(ctx, logging.KEY_REQUEST_ID, "request-id")
+		ctx = logging.With(ctx, logging.KEY_CLIENT_XID, "client-xid")
+
+		logging.FromContext(ctx).Info("test")
+
+		line := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(buffer.Bytes(), &line))
+		assert.Equal(t, "test", line["msg"])
+		assert.Equal(t, "request-id", line[logging.KEY_REQUEST_ID])
+		assert.Equal(t, "client-xid", line[logging.KEY_CLIENT_XID])
+	})
+
+	t.Run("should not log below the minimum level", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger := logging.NewLogger(buffer, logging.ParseLevel("warn"))
+
+		logger.Info("test")
+		assert.Equal(t, 0, buffer.Len())
+	})
+}
+
+func TestRequestIdFromContext(t *testing.T) {
+	assert.Equal(t, "", logging.RequestIdFromContext(context.TODO()))
+
+	ctx := logging.InjectRequestId(context.TODO(), "request-id")
+	assert.Equal(t, "request-id", logging.RequestIdFromContext(ctx))
+}
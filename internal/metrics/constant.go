@@ -0,0 +1,16 @@
+package metrics
+
+import "time"
+
+const NAMESPACE = "mini_wallet"
+
+const (
+	OUTCOME_SUCCESS     = "success"
+	OUTCOME_FAILED      = "failed"
+	OUTCOME_COMMITTED   = "committed"
+	OUTCOME_ROLLED_BACK = "rolled_back"
+)
+
+const ROUTE_NOT_FOUND = "not_found"
+
+const SCRAPE_TIMEOUT = 5 * time.Second
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests          *prometheus.CounterVec
+	httpRequestDuration   *prometheus.HistogramVec
+	settlements           *prometheus.CounterVec
+	settlementDuration    *prometheus.HistogramVec
+	dbTransactionDuration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests handled, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests, by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		settlements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Name:      "settlements_total",
+			Help:      "Number of settled transactions, by transaction type and outcome.",
+		}, []string{"type", "outcome"}),
+		settlementDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Name:      "settlement_duration_seconds",
+			Help:      "Time from queueing a transaction until it is settled, by transaction type and outcome.",
+			Buckets:   []float64{1, 2.5, 5, 5.5, 6, 7.5, 10, 15, 30, 60},
+		}, []string{"type", "outcome"}),
+		dbTransactionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Name:      "db_transaction_duration_seconds",
+			Help:      "Duration of database transactions, by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.settlements,
+		m.settlementDuration,
+		m.dbTransactionDuration,
+	)
+
+	return m
+}
+
+// Serve the registered metrics in Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveSettlement(trx *transaction.Transaction, latency time.Duration, err error) {
+	outcome := OUTCOME_SUCCESS
+	if err != nil {
+		outcome = OUTCOME_FAILED
+	}
+
+	m.settlements.WithLabelValues(trx.Type, outcome).Inc()
+	m.settlementDuration.WithLabelValues(trx.Type, outcome).Observe(latency.Seconds())
+}
+
+// Expose the settlement queue depth and record the outcome of every settlement of the given worker
+func (m *Metrics) RegisterSettlementWorker(worker *transaction.SettlementWorker) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "settlement_queue_depth",
+		Help:      "Number of settlements that are queued or running.",
+	}, func() float64 {
+		return float64(worker.Pending())
+	}))
+	worker.OnSettled(m.ObserveSettlement)
+}
+
+// Expose the number of wallets and their total balance by status.
+// The statistics are queried from the wallet service on every scrape
+func (m *Metrics) RegisterWalletStatistics(service wallet.WalletIService) {
+	m.registry.MustRegister(newWalletCollector(service))
+}
+
+// Wrap the storage manager so the duration of every database transaction is recorded
+func (m *Metrics) InstrumentStorageManager(storageManager manager.StorageManager) manager.StorageManager {
+	return &instrumentedStorageManager{storageManager, m.dbTransactionDuration}
+}
+
+type instrumentedStorageManager struct {
+	manager.StorageManager
+	duration *prometheus.HistogramVec
+}
+
+func (m *instrumentedStorageManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := m.StorageManager.RunInTransaction(ctx, fn)
+
+	outcome := OUTCOME_COMMITTED
+	if err != nil {
+		outcome = OUTCOME_ROLLED_BACK
+	}
+	m.duration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+
+	return err
+}
@@ -0,0 +1,106 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/metrics"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, recorder.Code)
+
+	return recorder.Body.String()
+}
+
+func TestMetrics_ObserveHTTPRequest(t *testing.T) {
+	m := metrics.NewMetrics()
+	m.ObserveHTTPRequest("GET", "/api/v1/wallet", 200, 10*time.Millisecond)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `mini_wallet_http_requests_total{method="GET",route="/api/v1/wallet",status="200"} 1`)
+	assert.Contains(t, body, `mini_wallet_http_request_duration_seconds_count{method="GET",route="/api/v1/wallet"} 1`)
+}
+
+func TestMetrics_RegisterSettlementWorker(t *testing.T) {
+	m := metrics.NewMetrics()
+	worker := transaction.NewSettlementWorker(0)
+	m.RegisterSettlementWorker(worker)
+
+	settled := make(chan struct{})
+	err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test", Type: transaction.TYPE_DEPOSIT}, func(ctx context.Context) error {
+		defer close(settled)
+		return fmt.Errorf("mocked")
+	})
+	assert.Nil(t, err)
+	<-settled
+	assert.Nil(t, worker.Shutdown(context.TODO()))
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `mini_wallet_settlement_queue_depth 0`)
+	assert.Contains(t, body, `mini_wallet_settlements_total{outcome="failed",type="deposit"} 1`)
+	assert.Contains(t, body, `mini_wallet_settlement_duration_seconds_count{outcome="failed",type="deposit"} 1`)
+}
+
+func TestMetrics_RegisterWalletStatistics(t *testing.T) {
+	t.Run("should expose wallet statistics", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetStatistics", mock.Anything).Return([]*wallet.Statistics{
+			{Status: wallet.STATUS_ENABLED, Currency: "IDR", Count: 2, TotalBalance: 150_000},
+			{Status: wallet.STATUS_ENABLED, Currency: "USD", Count: 1, TotalBalance: 25.5},
+			{Status: wallet.STATUS_DISABLED, Currency: "IDR", Count: 1, TotalBalance: 0},
+		}, nil)
+
+		m := metrics.NewMetrics()
+		m.RegisterWalletStatistics(walletService)
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `mini_wallet_wallets{currency="IDR",status="enabled"} 2`)
+		assert.Contains(t, body, `mini_wallet_wallets{currency="IDR",status="disabled"} 1`)
+		assert.Contains(t, body, `mini_wallet_wallet_balance_total{currency="IDR",status="enabled"} 150000`)
+		assert.Contains(t, body, `mini_wallet_wallet_balance_total{currency="USD",status="enabled"} 25.5`)
+	})
+
+	t.Run("should not expose wallet statistics if failed to get statistics", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetStatistics", mock.Anything).Return(nil, fmt.Errorf("mocked"))
+
+		m := metrics.NewMetrics()
+		m.RegisterWalletStatistics(walletService)
+
+		recorder := httptest.NewRecorder()
+		m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		assert.NotContains(t, recorder.Body.String(), `mini_wallet_wallets{`)
+	})
+}
+
+func TestMetrics_InstrumentStorageManager(t *testing.T) {
+	m := metrics.NewMetrics()
+	storageManager := m.InstrumentStorageManager(&manager.MockStorageManager{})
+
+	err := storageManager.RunInTransaction(context.TODO(), func(ctx context.Context) error {
+		return nil
+	})
+	assert.Nil(t, err)
+
+	mockedErr := fmt.Errorf("mocked")
+	err = storageManager.RunInTransaction(context.TODO(), func(ctx context.Context) error {
+		return mockedErr
+	})
+	assert.Equal(t, mockedErr, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `mini_wallet_db_transaction_duration_seconds_count{outcome="committed"} 1`)
+	assert.Contains(t, body, `mini_wallet_db_transaction_duration_seconds_count{outcome="rolled_back"} 1`)
+}
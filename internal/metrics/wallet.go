@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type walletCollector struct {
+	service      wallet.WalletIService
+	wallets      *prometheus.Desc
+	totalBalance *prometheus.Desc
+}
+
+func newWalletCollector(service wallet.WalletIService) *walletCollector {
+	return &walletCollector{
+		service: service,
+		wallets: prometheus.NewDesc(
+			prometheus.BuildFQName(NAMESPACE, "", "wallets"),
+			"Number of wallets, by status and currency.",
+			[]string{"status", "currency"}, nil,
+		),
+		totalBalance: prometheus.NewDesc(
+			prometheus.BuildFQName(NAMESPACE, "", "wallet_balance_total"),
+			"Sum of the balance of every wallet, by status and currency.",
+			[]string{"status", "currency"}, nil,
+		),
+	}
+}
+
+func (c *walletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.wallets
+	ch <- c.totalBalance
+}
+
+func (c *walletCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), SCRAPE_TIMEOUT)
+	defer cancel()
+
+	statistics, err := c.service.GetStatistics(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.wallets, err)
+		ch <- prometheus.NewInvalidMetric(c.totalBalance, err)
+		return
+	}
+
+	for _, stat := range statistics {
+		ch <- prometheus.MustNewConstMetric(c.wallets, prometheus.GaugeValue, float64(stat.Count), stat.Status, stat.Currency)
+		ch <- prometheus.MustNewConstMetric(c.totalBalance, prometheus.GaugeValue, stat.TotalBalance, stat.Status, stat.Currency)
+	}
+}
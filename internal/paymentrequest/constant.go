@@ -0,0 +1,24 @@
+package paymentrequest
+
+import "time"
+
+const (
+	STATUS_PENDING  = "pending"
+	STATUS_ACCEPTED = "accepted"
+	STATUS_DECLINED = "declined"
+	// Pending past its expiry, never stored but reported in place of pending
+	STATUS_EXPIRED = "expired"
+)
+
+const (
+	DEFAULT_EXPIRY = 7 * 24 * time.Hour
+	MAX_EXPIRY     = 30 * 24 * time.Hour
+)
+
+const (
+	MAX_MEMO_LENGTH = 255
+	// Unexpired pending requests a client can have at once
+	MAX_PENDING_REQUESTS = 100
+	// Requests returned per listing, the latest first
+	LIST_LIMIT = 100
+)
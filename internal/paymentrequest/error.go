@@ -0,0 +1,19 @@
+package paymentrequest
+
+import (
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyPayerXid = errors.NewValidationError("payer xid is required")
+var ErrSelfRequest = errors.NewValidationError("payer must differ from the requester")
+var ErrNonPositiveAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrMemoTooLong = errors.NewValidationError(fmt.Sprintf("memo can have up to %d characters", MAX_MEMO_LENGTH))
+var ErrExpiryInPast = errors.NewValidationError("expires_at must be in the future")
+var ErrExpiryTooLate = errors.NewValidationError(fmt.Sprintf("expires_at must be within %d days", int(MAX_EXPIRY.Hours()/24)))
+var ErrTooManyPendingRequests = errors.NewValidationError(fmt.Sprintf("a client can have up to %d pending payment requests", MAX_PENDING_REQUESTS))
+var ErrRequestNotFound = errors.NewNotFoundError("payment request not found")
+var ErrRequestNotPending = errors.NewValidationError("payment request is already answered")
+var ErrRequestExpired = errors.NewValidationError("payment request expired")
+var ErrConfirmationRequired = errors.NewValidationError("otp is required to pay a request above the confirmation threshold")
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreatePaymentRequestRequest struct {
+	PayerXid  string    `json:"payer_xid"`
+	WalletId  string    `json:"wallet_id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Memo      string    `json:"memo"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AcceptPaymentRequestRequest struct {
+	WalletId string `json:"wallet_id"`
+	Otp      string `json:"otp"`
+}
+
+func HandleCreatePaymentRequest(service paymentrequest.PaymentRequestIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreatePaymentRequestRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.CreateRequest(r.Context(), &paymentrequest.CreateRequestParams{
+			RequesterXid: currentClient.Xid,
+			WalletId:     requestBody.WalletId,
+			PayerXid:     requestBody.PayerXid,
+			Amount:       requestBody.Amount,
+			Currency:     requestBody.Currency,
+			Memo:         requestBody.Memo,
+			ExpiresAt:    requestBody.ExpiresAt,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"payment_request": result,
+		})
+	}
+}
+
+func HandleGetIncomingPaymentRequests(service paymentrequest.PaymentRequestIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		requests, err := service.GetIncomingRequests(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"payment_requests": requests,
+		})
+	}
+}
+
+func HandleGetOutgoingPaymentRequests(service paymentrequest.PaymentRequestIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		requests, err := service.GetOutgoingRequests(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"payment_requests": requests,
+		})
+	}
+}
+
+func HandleAcceptPaymentRequest(service paymentrequest.PaymentRequestIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &AcceptPaymentRequestRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.AcceptRequest(r.Context(), &paymentrequest.AcceptRequestParams{
+			PayerXid:  currentClient.Xid,
+			RequestId: chi.URLParam(r, "id"),
+			WalletId:  requestBody.WalletId,
+			Otp:       requestBody.Otp,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"payment_request": result,
+		})
+	}
+}
+
+func HandleDeclinePaymentRequest(service paymentrequest.PaymentRequestIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.DeclineRequest(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"payment_request": result,
+		})
+	}
+}
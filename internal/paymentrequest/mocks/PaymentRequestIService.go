@@ -0,0 +1,160 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	paymentrequest "github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentRequestIService is an autogenerated mock type for the PaymentRequestIService type
+type PaymentRequestIService struct {
+	mock.Mock
+}
+
+// AcceptRequest provides a mock function with given fields: ctx, params
+func (_m *PaymentRequestIService) AcceptRequest(ctx context.Context, params *paymentrequest.AcceptRequestParams) (*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.AcceptRequestParams) (*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.AcceptRequestParams) *paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *paymentrequest.AcceptRequestParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRequest provides a mock function with given fields: ctx, params
+func (_m *PaymentRequestIService) CreateRequest(ctx context.Context, params *paymentrequest.CreateRequestParams) (*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.CreateRequestParams) (*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.CreateRequestParams) *paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *paymentrequest.CreateRequestParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeclineRequest provides a mock function with given fields: ctx, payerXid, id
+func (_m *PaymentRequestIService) DeclineRequest(ctx context.Context, payerXid string, id string) (*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, payerXid, id)
+
+	var r0 *paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, payerXid, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, payerXid, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, payerXid, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncomingRequests provides a mock function with given fields: ctx, payerXid
+func (_m *PaymentRequestIService) GetIncomingRequests(ctx context.Context, payerXid string) ([]*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, payerXid)
+
+	var r0 []*paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, payerXid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, payerXid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, payerXid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutgoingRequests provides a mock function with given fields: ctx, requesterXid
+func (_m *PaymentRequestIService) GetOutgoingRequests(ctx context.Context, requesterXid string) ([]*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, requesterXid)
+
+	var r0 []*paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, requesterXid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, requesterXid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, requesterXid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPaymentRequestIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPaymentRequestIService creates a new instance of PaymentRequestIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPaymentRequestIService(t mockConstructorTestingTNewPaymentRequestIService) *PaymentRequestIService {
+	mock := &PaymentRequestIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	paymentrequest "github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PaymentRequestRepository is an autogenerated mock type for the PaymentRequestRepository type
+type PaymentRequestRepository struct {
+	mock.Mock
+}
+
+// CountPendingByRequesterXid provides a mock function with given fields: ctx, requesterXid, now
+func (_m *PaymentRequestRepository) CountPendingByRequesterXid(ctx context.Context, requesterXid string, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, requesterXid, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, requesterXid, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, requesterXid, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, requesterXid, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllByPayerXid provides a mock function with given fields: ctx, payerXid, limit
+func (_m *PaymentRequestRepository) FindAllByPayerXid(ctx context.Context, payerXid string, limit int) ([]*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, payerXid, limit)
+
+	var r0 []*paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, payerXid, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, payerXid, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, payerXid, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllByRequesterXid provides a mock function with given fields: ctx, requesterXid, limit
+func (_m *PaymentRequestRepository) FindAllByRequesterXid(ctx context.Context, requesterXid string, limit int) ([]*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, requesterXid, limit)
+
+	var r0 []*paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, requesterXid, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, requesterXid, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, requesterXid, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *PaymentRequestRepository) FindById(ctx context.Context, id string) (*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *PaymentRequestRepository) FindByIdForUpdate(ctx context.Context, id string) (*paymentrequest.PaymentRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *paymentrequest.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*paymentrequest.PaymentRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *paymentrequest.PaymentRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*paymentrequest.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *PaymentRequestRepository) Insert(ctx context.Context, data *paymentrequest.PaymentRequest) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.PaymentRequest) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *PaymentRequestRepository) Update(ctx context.Context, data *paymentrequest.PaymentRequest) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *paymentrequest.PaymentRequest) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPaymentRequestRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPaymentRequestRepository creates a new instance of PaymentRequestRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPaymentRequestRepository(t mockConstructorTestingTNewPaymentRequestRepository) *PaymentRequestRepository {
+	mock := &PaymentRequestRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,25 @@
+package paymentrequest
+
+import "time"
+
+type CreateRequestParams struct {
+	RequesterXid string `json:"requester_xid"`
+	// Wallet of the requester receiving the amount, the default wallet of the requester if empty
+	WalletId string  `json:"wallet_id"`
+	PayerXid string  `json:"payer_xid"`
+	Amount   float64 `json:"amount"`
+	// Has to match the currency of the wallet of the requester, assumed if empty
+	Currency string `json:"currency"`
+	Memo     string `json:"memo"`
+	// DEFAULT_EXPIRY from now if zero
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AcceptRequestParams struct {
+	PayerXid  string `json:"payer_xid"`
+	RequestId string `json:"request_id"`
+	// Wallet of the payer paying the amount, the default wallet of the payer if empty
+	WalletId string `json:"wallet_id"`
+	// TOTP code of the payer, required if the payment has to be confirmed
+	Otp string `json:"otp"`
+}
@@ -0,0 +1,313 @@
+package paymentrequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/google/uuid"
+)
+
+// PaymentRequest asks a client, the payer, to transfer an amount to a wallet of the requester
+type PaymentRequest struct {
+	Id           string `json:"id"`
+	RequesterXid string `json:"requester_xid"`
+	// Wallet of the requester receiving the amount
+	RequesterWalletId string  `json:"requester_wallet_id"`
+	PayerXid          string  `json:"payer_xid"`
+	Amount            float64 `json:"amount"`
+	Currency          string  `json:"currency"`
+	Memo              string  `json:"memo"`
+	Status            string  `json:"status"`
+	// Wallet of the payer and outgoing transfer of the payment, set once accepted
+	PayerWalletId string     `json:"payer_wallet_id"`
+	TransactionId string     `json:"transaction_id"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RespondedAt   *time.Time `json:"responded_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type PaymentRequestRepository interface {
+	Insert(ctx context.Context, data *PaymentRequest) error
+	FindById(ctx context.Context, id string) (*PaymentRequest, error)
+	// Find the request and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*PaymentRequest, error)
+	// Return up to limit requests the client is asked to pay, the latest first
+	FindAllByPayerXid(ctx context.Context, payerXid string, limit int) ([]*PaymentRequest, error)
+	// Return up to limit requests made by the client, the latest first
+	FindAllByRequesterXid(ctx context.Context, requesterXid string, limit int) ([]*PaymentRequest, error)
+	// Count the requests made by the client still pending and not expired at the given time
+	CountPendingByRequesterXid(ctx context.Context, requesterXid string, now time.Time) (int64, error)
+	Update(ctx context.Context, data *PaymentRequest) error
+}
+
+type PaymentRequestIService interface {
+	CreateRequest(ctx context.Context, params *CreateRequestParams) (*PaymentRequest, error)
+	GetIncomingRequests(ctx context.Context, payerXid string) ([]*PaymentRequest, error)
+	GetOutgoingRequests(ctx context.Context, requesterXid string) ([]*PaymentRequest, error)
+	AcceptRequest(ctx context.Context, params *AcceptRequestParams) (*PaymentRequest, error)
+	DeclineRequest(ctx context.Context, payerXid, id string) (*PaymentRequest, error)
+}
+
+type PaymentRequestService struct {
+	repository          PaymentRequestRepository
+	clientService       client.ClientIService
+	walletService       wallet.WalletIService
+	transactionService  transaction.TransactionIService
+	confirmationService transaction.WithdrawalConfirmationIService
+	twoFactorService    twofactor.TwoFactorIService
+	storageManager      manager.StorageManager
+}
+
+func NewPaymentRequestService(
+	repository PaymentRequestRepository,
+	clientService client.ClientIService,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+	confirmationService transaction.WithdrawalConfirmationIService,
+	twoFactorService twofactor.TwoFactorIService,
+	storageManager manager.StorageManager,
+) *PaymentRequestService {
+	return &PaymentRequestService{repository, clientService, walletService, transactionService, confirmationService, twoFactorService, storageManager}
+}
+
+func (r *PaymentRequest) IsExpired(at time.Time) bool {
+	return !at.Before(r.ExpiresAt)
+}
+
+// Ask the payer to transfer the amount to the given wallet of the requester, or to its default wallet if the wallet id is empty
+func (s *PaymentRequestService) CreateRequest(ctx context.Context, params *CreateRequestParams) (*PaymentRequest, error) {
+	if params.PayerXid == "" {
+		return nil, ErrEmptyPayerXid
+	}
+	if params.PayerXid == params.RequesterXid {
+		return nil, ErrSelfRequest
+	}
+	if params.Amount <= 0 {
+		return nil, ErrNonPositiveAmount
+	}
+	if len(params.Memo) > MAX_MEMO_LENGTH {
+		return nil, ErrMemoTooLong
+	}
+
+	now := time.Now()
+	expiresAt := params.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(DEFAULT_EXPIRY)
+	}
+	if !expiresAt.After(now) {
+		return nil, ErrExpiryInPast
+	}
+	if expiresAt.After(now.Add(MAX_EXPIRY)) {
+		return nil, ErrExpiryTooLate
+	}
+
+	payer, err := s.clientService.GetByXid(ctx, params.PayerXid)
+	if err != nil {
+		return nil, err
+	}
+
+	requesterWallet, err := s.walletService.GetWallet(ctx, params.RequesterXid, params.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.walletService.ValidateDeposit(requesterWallet); err != nil {
+		return nil, err
+	}
+	if err = requesterWallet.ValidateAmount(params.Amount, params.Currency); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repository.CountPendingByRequesterXid(ctx, params.RequesterXid, now)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= MAX_PENDING_REQUESTS {
+		return nil, ErrTooManyPendingRequests
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	request := &PaymentRequest{
+		Id:                uuidRandom.String(),
+		RequesterXid:      params.RequesterXid,
+		RequesterWalletId: requesterWallet.Id,
+		PayerXid:          payer.Xid,
+		Amount:            params.Amount,
+		Currency:          requesterWallet.Currency,
+		Memo:              params.Memo,
+		Status:            STATUS_PENDING,
+		ExpiresAt:         expiresAt,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	err = s.repository.Insert(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Return the latest requests the client is asked to pay
+func (s *PaymentRequestService) GetIncomingRequests(ctx context.Context, payerXid string) ([]*PaymentRequest, error) {
+	requests, err := s.repository.FindAllByPayerXid(ctx, payerXid, LIST_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportExpired(requests), nil
+}
+
+// Return the latest requests made by the client
+func (s *PaymentRequestService) GetOutgoingRequests(ctx context.Context, requesterXid string) ([]*PaymentRequest, error) {
+	requests, err := s.repository.FindAllByRequesterXid(ctx, requesterXid, LIST_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportExpired(requests), nil
+}
+
+// Pay the pending request by transferring its amount from the given wallet of the payer, or from its default wallet.
+// The transfer and the acceptance are stored in the same database transaction, so a request is never paid twice.
+// A rejected transfer, e.g. for insufficient balance, leaves the request pending.
+//
+// The payer enrolled to TOTP has to give its code if the amount is above the confirmation threshold of the currency
+func (s *PaymentRequestService) AcceptRequest(ctx context.Context, params *AcceptRequestParams) (*PaymentRequest, error) {
+	err := s.verifyPayer(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *PaymentRequest
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.findPending(ctx, params.PayerXid, params.RequestId)
+		if err != nil {
+			return err
+		}
+
+		outgoing, _, err := s.transactionService.CreateTransfer(ctx, &transaction.CreateTransferParams{
+			CustomerXid:    request.PayerXid,
+			WalletId:       params.WalletId,
+			TargetWalletId: request.RequesterWalletId,
+			ReferenceId:    fmt.Sprintf("payment-request-%s", request.Id),
+			Amount:         request.Amount,
+			Currency:       request.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		request.PayerWalletId = outgoing.WalletId
+		request.TransactionId = outgoing.Id
+		return s.respond(ctx, request, STATUS_ACCEPTED)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Refuse to pay the pending request
+func (s *PaymentRequestService) DeclineRequest(ctx context.Context, payerXid, id string) (*PaymentRequest, error) {
+	var request *PaymentRequest
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.findPending(ctx, payerXid, id)
+		if err != nil {
+			return err
+		}
+
+		return s.respond(ctx, request, STATUS_DECLINED)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Verify the TOTP code of the payer if the payment has to be confirmed.
+// The code is verified before the database transaction of the payment,
+// so the failed attempts are counted even though the payment is rolled back
+func (s *PaymentRequestService) verifyPayer(ctx context.Context, params *AcceptRequestParams) error {
+	request, err := s.repository.FindById(ctx, params.RequestId)
+	if err != nil {
+		return err
+	}
+	err = validatePending(request, params.PayerXid)
+	if err != nil {
+		return err
+	}
+
+	required, err := s.confirmationService.RequiresConfirmation(ctx, params.PayerXid, request.Amount, request.Currency)
+	if err != nil || !required {
+		return err
+	}
+	if params.Otp == "" {
+		return ErrConfirmationRequired
+	}
+
+	return s.twoFactorService.Verify(ctx, params.PayerXid, params.Otp)
+}
+
+// Find and lock the request the payer is asked to pay, return error if it is no longer pending
+func (s *PaymentRequestService) findPending(ctx context.Context, payerXid, id string) (*PaymentRequest, error) {
+	request, err := s.repository.FindByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = validatePending(request, payerXid)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func validatePending(request *PaymentRequest, payerXid string) error {
+	if request == nil || request.PayerXid != payerXid {
+		return ErrRequestNotFound
+	}
+	if request.Status != STATUS_PENDING {
+		return ErrRequestNotPending
+	}
+	if request.IsExpired(time.Now()) {
+		return ErrRequestExpired
+	}
+
+	return nil
+}
+
+func (s *PaymentRequestService) respond(ctx context.Context, request *PaymentRequest, status string) error {
+	now := time.Now()
+	request.Status = status
+	request.RespondedAt = &now
+	request.UpdatedAt = now
+
+	return s.repository.Update(ctx, request)
+}
+
+// Report the pending requests past their expiry as expired
+func reportExpired(requests []*PaymentRequest) []*PaymentRequest {
+	now := time.Now()
+	for _, request := range requests {
+		if request.Status == STATUS_PENDING && request.IsExpired(now) {
+			request.Status = STATUS_EXPIRED
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,258 @@
+package paymentrequest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	client_mock "github.com/defryheryanto/mini-wallet/internal/client/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	paymentrequest_mock "github.com/defryheryanto/mini-wallet/internal/paymentrequest/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	twofactor_mock "github.com/defryheryanto/mini-wallet/internal/twofactor/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type serviceMocks struct {
+	repository          *paymentrequest_mock.PaymentRequestRepository
+	clientService       *client_mock.ClientIService
+	walletService       *wallet_mock.WalletIService
+	transactionService  *transaction_mock.TransactionIService
+	confirmationService *transaction_mock.WithdrawalConfirmationIService
+	twoFactorService    *twofactor_mock.TwoFactorIService
+}
+
+func newService(t *testing.T) (*paymentrequest.PaymentRequestService, *serviceMocks) {
+	mocks := &serviceMocks{
+		repository:          paymentrequest_mock.NewPaymentRequestRepository(t),
+		clientService:       client_mock.NewClientIService(t),
+		walletService:       wallet_mock.NewWalletIService(t),
+		transactionService:  transaction_mock.NewTransactionIService(t),
+		confirmationService: transaction_mock.NewWithdrawalConfirmationIService(t),
+		twoFactorService:    twofactor_mock.NewTwoFactorIService(t),
+	}
+
+	service := paymentrequest.NewPaymentRequestService(
+		mocks.repository,
+		mocks.clientService,
+		mocks.walletService,
+		mocks.transactionService,
+		mocks.confirmationService,
+		mocks.twoFactorService,
+		&manager.MockStorageManager{},
+	)
+	return service, mocks
+}
+
+func TestPaymentRequestService_CreateRequest(t *testing.T) {
+	requesterWallet := &wallet.Wallet{Id: "requester-wallet-id", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+
+	t.Run("should return error if payer is the requester", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateRequest(context.TODO(), &paymentrequest.CreateRequestParams{RequesterXid: "xid", PayerXid: "xid", Amount: 100})
+		assert.Equal(t, paymentrequest.ErrSelfRequest, err)
+	})
+
+	t.Run("should return error if expiry is too far", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateRequest(context.TODO(), &paymentrequest.CreateRequestParams{
+			RequesterXid: "xid",
+			PayerXid:     "payer-xid",
+			Amount:       100,
+			ExpiresAt:    time.Now().Add(paymentrequest.MAX_EXPIRY + time.Hour),
+		})
+		assert.Equal(t, paymentrequest.ErrExpiryTooLate, err)
+	})
+
+	t.Run("should return error if payer doesn't exist", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.clientService.On("GetByXid", mock.Anything, "payer-xid").Return(nil, client.ErrClientNotFound)
+
+		_, err := service.CreateRequest(context.TODO(), &paymentrequest.CreateRequestParams{RequesterXid: "xid", PayerXid: "payer-xid", Amount: 100})
+		assert.Equal(t, client.ErrClientNotFound, err)
+	})
+
+	t.Run("should return error if requester has too many pending requests", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.clientService.On("GetByXid", mock.Anything, "payer-xid").Return(&client.Client{Xid: "payer-xid"}, nil)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(requesterWallet, nil)
+		mocks.walletService.On("ValidateDeposit", requesterWallet).Return(nil)
+		mocks.repository.On("CountPendingByRequesterXid", mock.Anything, "xid", mock.Anything).Return(int64(paymentrequest.MAX_PENDING_REQUESTS), nil)
+
+		_, err := service.CreateRequest(context.TODO(), &paymentrequest.CreateRequestParams{RequesterXid: "xid", PayerXid: "payer-xid", Amount: 100})
+		assert.Equal(t, paymentrequest.ErrTooManyPendingRequests, err)
+	})
+
+	t.Run("should ask the payer to pay to the wallet of the requester", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.clientService.On("GetByXid", mock.Anything, "payer-xid").Return(&client.Client{Xid: "payer-xid"}, nil)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(requesterWallet, nil)
+		mocks.walletService.On("ValidateDeposit", requesterWallet).Return(nil)
+		mocks.repository.On("CountPendingByRequesterXid", mock.Anything, "xid", mock.Anything).Return(int64(0), nil)
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+		request, err := service.CreateRequest(context.TODO(), &paymentrequest.CreateRequestParams{RequesterXid: "xid", PayerXid: "payer-xid", Amount: 100, Memo: "dinner"})
+		assert.Nil(t, err)
+		assert.Equal(t, paymentrequest.STATUS_PENDING, request.Status)
+		assert.Equal(t, "requester-wallet-id", request.RequesterWalletId)
+		assert.Equal(t, "IDR", request.Currency)
+		assert.Equal(t, "dinner", request.Memo)
+		assert.WithinDuration(t, time.Now().Add(paymentrequest.DEFAULT_EXPIRY), request.ExpiresAt, time.Minute)
+	})
+}
+
+func TestPaymentRequestService_GetIncomingRequests(t *testing.T) {
+	t.Run("should report the pending requests past their expiry as expired", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindAllByPayerXid", mock.Anything, "payer-xid", paymentrequest.LIST_LIMIT).Return([]*paymentrequest.PaymentRequest{
+			{Id: "pending-id", Status: paymentrequest.STATUS_PENDING, ExpiresAt: time.Now().Add(time.Hour)},
+			{Id: "expired-id", Status: paymentrequest.STATUS_PENDING, ExpiresAt: time.Now().Add(-time.Hour)},
+			{Id: "accepted-id", Status: paymentrequest.STATUS_ACCEPTED, ExpiresAt: time.Now().Add(-time.Hour)},
+		}, nil)
+
+		requests, err := service.GetIncomingRequests(context.TODO(), "payer-xid")
+		assert.Nil(t, err)
+		assert.Equal(t, paymentrequest.STATUS_PENDING, requests[0].Status)
+		assert.Equal(t, paymentrequest.STATUS_EXPIRED, requests[1].Status)
+		assert.Equal(t, paymentrequest.STATUS_ACCEPTED, requests[2].Status)
+	})
+}
+
+func TestPaymentRequestService_AcceptRequest(t *testing.T) {
+	newRequest := func() *paymentrequest.PaymentRequest {
+		return &paymentrequest.PaymentRequest{
+			Id:                "request-id",
+			RequesterXid:      "xid",
+			RequesterWalletId: "requester-wallet-id",
+			PayerXid:          "payer-xid",
+			Amount:            100,
+			Currency:          "IDR",
+			Status:            paymentrequest.STATUS_PENDING,
+			ExpiresAt:         time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("should return error if request is asked to another payer", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "other-xid", RequestId: "request-id"})
+		assert.Equal(t, paymentrequest.ErrRequestNotFound, err)
+	})
+
+	t.Run("should return error if request is already answered", func(t *testing.T) {
+		request := newRequest()
+		request.Status = paymentrequest.STATUS_DECLINED
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(request, nil)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id"})
+		assert.Equal(t, paymentrequest.ErrRequestNotPending, err)
+	})
+
+	t.Run("should return error if request expired", func(t *testing.T) {
+		request := newRequest()
+		request.ExpiresAt = time.Now().Add(-time.Second)
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(request, nil)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id"})
+		assert.Equal(t, paymentrequest.ErrRequestExpired, err)
+	})
+
+	t.Run("should require the code of the payer enrolled to TOTP", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "payer-xid", float64(100), "IDR").Return(true, nil)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id"})
+		assert.Equal(t, paymentrequest.ErrConfirmationRequired, err)
+	})
+
+	t.Run("should return error if code of the payer invalid", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "payer-xid", float64(100), "IDR").Return(true, nil)
+		mocks.twoFactorService.On("Verify", mock.Anything, "payer-xid", "123456").Return(twofactor.ErrInvalidCode)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id", Otp: "123456"})
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+
+	t.Run("should transfer the amount once the code of the payer is verified", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "payer-xid", float64(100), "IDR").Return(true, nil)
+		mocks.twoFactorService.On("Verify", mock.Anything, "payer-xid", "123456").Return(nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(&transaction.Transaction{Id: "trx-id"}, &transaction.Transaction{}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		request, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id", Otp: "123456"})
+		assert.Nil(t, err)
+		assert.Equal(t, paymentrequest.STATUS_ACCEPTED, request.Status)
+	})
+
+	t.Run("should leave the request pending if transfer is rejected", func(t *testing.T) {
+		request := newRequest()
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "payer-xid", float64(100), "IDR").Return(false, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "request-id").Return(request, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, wallet.ErrInsufficientBalance)
+
+		_, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id"})
+		assert.Equal(t, wallet.ErrInsufficientBalance, err)
+		assert.Equal(t, paymentrequest.STATUS_PENDING, request.Status)
+	})
+
+	t.Run("should transfer the amount to the requester", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindById", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "payer-xid", float64(100), "IDR").Return(false, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "request-id").Return(newRequest(), nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, &transaction.CreateTransferParams{
+			CustomerXid:    "payer-xid",
+			WalletId:       "payer-wallet-id",
+			TargetWalletId: "requester-wallet-id",
+			ReferenceId:    "payment-request-request-id",
+			Amount:         100,
+			Currency:       "IDR",
+		}).Return(&transaction.Transaction{Id: "trx-id", WalletId: "payer-wallet-id"}, &transaction.Transaction{}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		request, err := service.AcceptRequest(context.TODO(), &paymentrequest.AcceptRequestParams{PayerXid: "payer-xid", RequestId: "request-id", WalletId: "payer-wallet-id"})
+		assert.Nil(t, err)
+		assert.Equal(t, paymentrequest.STATUS_ACCEPTED, request.Status)
+		assert.Equal(t, "trx-id", request.TransactionId)
+		assert.Equal(t, "payer-wallet-id", request.PayerWalletId)
+		assert.NotNil(t, request.RespondedAt)
+	})
+}
+
+func TestPaymentRequestService_DeclineRequest(t *testing.T) {
+	t.Run("should decline the pending request", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "request-id").Return(&paymentrequest.PaymentRequest{
+			Id:        "request-id",
+			PayerXid:  "payer-xid",
+			Status:    paymentrequest.STATUS_PENDING,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		request, err := service.DeclineRequest(context.TODO(), "payer-xid", "request-id")
+		assert.Nil(t, err)
+		assert.Equal(t, paymentrequest.STATUS_DECLINED, request.Status)
+		assert.NotNil(t, request.RespondedAt)
+	})
+}
@@ -0,0 +1,83 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+)
+
+type PaymentRequest struct {
+	Id                string     `gorm:"primaryKey;column:id"`
+	RequesterXid      string     `gorm:"column:requester_xid"`
+	RequesterWalletId string     `gorm:"column:requester_wallet_id"`
+	PayerXid          string     `gorm:"column:payer_xid"`
+	Amount            float64    `gorm:"column:amount"`
+	Currency          string     `gorm:"column:currency"`
+	Memo              string     `gorm:"column:memo"`
+	Status            string     `gorm:"column:status"`
+	PayerWalletId     string     `gorm:"column:payer_wallet_id"`
+	TransactionId     string     `gorm:"column:transaction_id"`
+	ExpiresAt         time.Time  `gorm:"column:expires_at"`
+	RespondedAt       *time.Time `gorm:"column:responded_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+}
+
+func (PaymentRequest) TableName() string {
+	return "payment_requests"
+}
+
+func (PaymentRequest) FromServiceModel(data *paymentrequest.PaymentRequest) *PaymentRequest {
+	if data == nil {
+		return nil
+	}
+
+	return &PaymentRequest{
+		Id:                data.Id,
+		RequesterXid:      data.RequesterXid,
+		RequesterWalletId: data.RequesterWalletId,
+		PayerXid:          data.PayerXid,
+		Amount:            data.Amount,
+		Currency:          data.Currency,
+		Memo:              data.Memo,
+		Status:            data.Status,
+		PayerWalletId:     data.PayerWalletId,
+		TransactionId:     data.TransactionId,
+		ExpiresAt:         data.ExpiresAt,
+		RespondedAt:       data.RespondedAt,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
+
+func (p *PaymentRequest) ToServiceModel() *paymentrequest.PaymentRequest {
+	return &paymentrequest.PaymentRequest{
+		Id:                p.Id,
+		RequesterXid:      p.RequesterXid,
+		RequesterWalletId: p.RequesterWalletId,
+		PayerXid:          p.PayerXid,
+		Amount:            p.Amount,
+		Currency:          p.Currency,
+		Memo:              p.Memo,
+		Status:            p.Status,
+		PayerWalletId:     p.PayerWalletId,
+		TransactionId:     p.TransactionId,
+		ExpiresAt:         p.ExpiresAt,
+		RespondedAt:       p.RespondedAt,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
+
+func PaymentRequestsToServiceModel(data []*PaymentRequest) []*paymentrequest.PaymentRequest {
+	if data == nil {
+		return nil
+	}
+
+	requests := []*paymentrequest.PaymentRequest{}
+	for _, p := range data {
+		requests = append(requests, p.ToServiceModel())
+	}
+
+	return requests
+}
@@ -0,0 +1,110 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/paymentrequest"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRequestRepository(db *gorm.DB) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db}
+}
+
+func (r *PaymentRequestRepository) Insert(ctx context.Context, data *paymentrequest.PaymentRequest) error {
+	payload := PaymentRequest{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PaymentRequestRepository) FindById(ctx context.Context, id string) (*paymentrequest.PaymentRequest, error) {
+	p := &PaymentRequest{}
+
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&p).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return p.ToServiceModel(), nil
+}
+
+func (r *PaymentRequestRepository) FindByIdForUpdate(ctx context.Context, id string) (*paymentrequest.PaymentRequest, error) {
+	p := &PaymentRequest{}
+
+	err := r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&p).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return p.ToServiceModel(), nil
+}
+
+func (r *PaymentRequestRepository) FindAllByPayerXid(ctx context.Context, payerXid string, limit int) ([]*paymentrequest.PaymentRequest, error) {
+	return r.findAll(ctx, "payer_xid = ?", payerXid, limit)
+}
+
+func (r *PaymentRequestRepository) FindAllByRequesterXid(ctx context.Context, requesterXid string, limit int) ([]*paymentrequest.PaymentRequest, error) {
+	return r.findAll(ctx, "requester_xid = ?", requesterXid, limit)
+}
+
+func (r *PaymentRequestRepository) CountPendingByRequesterXid(ctx context.Context, requesterXid string, now time.Time) (int64, error) {
+	var count int64
+
+	err := r.getGormClient(ctx).Model(&PaymentRequest{}).
+		Where("requester_xid = ? AND status = ? AND expires_at > ?", requesterXid, paymentrequest.STATUS_PENDING, now).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PaymentRequestRepository) Update(ctx context.Context, data *paymentrequest.PaymentRequest) error {
+	payload := PaymentRequest{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PaymentRequestRepository) findAll(ctx context.Context, query string, xid string, limit int) ([]*paymentrequest.PaymentRequest, error) {
+	requests := []*PaymentRequest{}
+
+	err := r.getGormClient(ctx).Where(query, xid).Order("created_at DESC, id").Limit(limit).Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return PaymentRequestsToServiceModel(requests), nil
+}
+
+func (r *PaymentRequestRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,48 @@
+package payout
+
+import "time"
+
+const (
+	BATCH_STATUS_PENDING    = "pending"
+	BATCH_STATUS_PROCESSING = "processing"
+	// Every item succeeded
+	BATCH_STATUS_COMPLETED = "completed"
+	// Some items succeeded and the others failed
+	BATCH_STATUS_PARTIALLY_FAILED = "partially_failed"
+	// Every item failed
+	BATCH_STATUS_FAILED = "failed"
+)
+
+const (
+	ITEM_STATUS_PENDING = "pending"
+	ITEM_STATUS_SUCCESS = "success"
+	ITEM_STATUS_FAILED  = "failed"
+)
+
+const (
+	ITEM_TYPE_TRANSFER   = "transfer"
+	ITEM_TYPE_WITHDRAWAL = "withdrawal"
+)
+
+const (
+	CSV_COLUMN_REFERENCE_ID = "reference_id"
+	CSV_COLUMN_TO_WALLET_ID = "to_wallet_id"
+	CSV_COLUMN_AMOUNT       = "amount"
+	CSV_COLUMN_CURRENCY     = "currency"
+)
+
+const (
+	MAX_BATCH_ITEMS = 1000
+	// Size of the body of the requests creating a batch
+	MAX_REQUEST_SIZE = 1 << 20
+)
+
+const (
+	// Items processed per pass, across the unfinished batches
+	PROCESS_ITEM_LIMIT = 100
+	// Unfinished batches looked up per pass, the earliest first
+	PROCESS_BATCH_LIMIT = 10
+	PROCESS_INTERVAL    = 5 * time.Second
+	// Length the error of a failed item is cut to
+	MAX_ERROR_LENGTH = 500
+)
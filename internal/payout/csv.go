@@ -0,0 +1,83 @@
+package payout
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parse the items of a batch from CSV with a header row naming its columns, in any order:
+// reference_id and amount are required, to_wallet_id and currency are optional.
+// The line of each item is its line in the CSV
+func ParseCSV(reader io.Reader) ([]*ItemParams, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyItems
+	}
+	if err != nil {
+		return nil, ErrInvalidCSV(err.Error())
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// Spreadsheets may start the file with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case CSV_COLUMN_REFERENCE_ID, CSV_COLUMN_TO_WALLET_ID, CSV_COLUMN_AMOUNT, CSV_COLUMN_CURRENCY:
+		default:
+			return nil, ErrInvalidCSV(fmt.Sprintf("unknown column %q", name))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, ErrInvalidCSV(fmt.Sprintf("duplicate column %q", name))
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{CSV_COLUMN_REFERENCE_ID, CSV_COLUMN_AMOUNT} {
+		if _, ok := columns[name]; !ok {
+			return nil, ErrInvalidCSV(fmt.Sprintf("missing column %q", name))
+		}
+	}
+
+	value := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	items := []*ItemParams{}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidCSV(err.Error())
+		}
+		if len(items) == MAX_BATCH_ITEMS {
+			return nil, ErrTooManyItems
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		amount, err := strconv.ParseFloat(value(record, CSV_COLUMN_AMOUNT), 64)
+		if err != nil {
+			return nil, ErrInvalidCSV(fmt.Sprintf("line %d: amount must be a number", line))
+		}
+
+		items = append(items, &ItemParams{
+			Line:           line,
+			ReferenceId:    value(record, CSV_COLUMN_REFERENCE_ID),
+			TargetWalletId: value(record, CSV_COLUMN_TO_WALLET_ID),
+			Amount:         amount,
+			Currency:       value(record, CSV_COLUMN_CURRENCY),
+		})
+	}
+
+	return items, nil
+}
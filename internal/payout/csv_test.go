@@ -0,0 +1,40 @@
+package payout_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("should parse the items by the columns of the header", func(t *testing.T) {
+		items, err := payout.ParseCSV(strings.NewReader("\ufeffAmount,reference_id,to_wallet_id\n100.5,ref-1,wallet-1\n200,ref-2,\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, []*payout.ItemParams{
+			{Line: 2, ReferenceId: "ref-1", TargetWalletId: "wallet-1", Amount: 100.5},
+			{Line: 3, ReferenceId: "ref-2", Amount: 200},
+		}, items)
+	})
+
+	t.Run("should return error if a required column is missing", func(t *testing.T) {
+		_, err := payout.ParseCSV(strings.NewReader("reference_id,to_wallet_id\nref-1,wallet-1\n"))
+		assert.Equal(t, payout.ErrInvalidCSV(`missing column "amount"`), err)
+	})
+
+	t.Run("should return error on unknown column", func(t *testing.T) {
+		_, err := payout.ParseCSV(strings.NewReader("reference_id,amount,memo\nref-1,100,rent\n"))
+		assert.Equal(t, payout.ErrInvalidCSV(`unknown column "memo"`), err)
+	})
+
+	t.Run("should return error with the line of the invalid amount", func(t *testing.T) {
+		_, err := payout.ParseCSV(strings.NewReader("reference_id,amount\nref-1,100\nref-2,ten\n"))
+		assert.Equal(t, payout.ErrInvalidCSV("line 3: amount must be a number"), err)
+	})
+
+	t.Run("should return error if the csv is empty", func(t *testing.T) {
+		_, err := payout.ParseCSV(strings.NewReader(""))
+		assert.Equal(t, payout.ErrEmptyItems, err)
+	})
+}
@@ -0,0 +1,40 @@
+package payout
+
+import (
+	goerrors "errors"
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyItems = errors.NewValidationError("batch requires at least one item")
+var ErrTooManyItems = errors.NewValidationError(fmt.Sprintf("a batch can have up to %d items", MAX_BATCH_ITEMS))
+var ErrEmptyReferenceId = errors.NewValidationError("reference id is required")
+var ErrDuplicateReferenceId = errors.NewValidationError("reference id is used by another item of the batch")
+var ErrNonPositiveAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrSameWallet = errors.NewValidationError("target wallet must differ from the wallet of the batch")
+var ErrCurrencyMismatch = errors.NewValidationError("batch transfers require wallets of the same currency")
+var ErrConfirmationRequired = errors.NewValidationError("withdrawal or transfer to another client above the confirmation threshold can't be made in a batch")
+var ErrBatchNotFound = errors.NewNotFoundError("payout batch not found")
+var ErrWithdrawalFailed = errors.NewValidationError("withdrawal failed to settle")
+
+// Returned by the items waiting for the settlement of their withdrawal
+var errWithdrawalSettling = goerrors.New("withdrawal is settling")
+
+// ItemError is the validation error of an item of a batch
+type ItemError struct {
+	Line        int    `json:"line"`
+	ReferenceId string `json:"reference_id"`
+	Error       string `json:"error"`
+}
+
+func ErrInvalidItems(itemErrors []*ItemError) errors.HandledError {
+	return errors.NewValidationError(map[string]interface{}{
+		"message": "invalid batch items",
+		"items":   itemErrors,
+	})
+}
+
+func ErrInvalidCSV(reason string) errors.HandledError {
+	return errors.NewValidationError(fmt.Sprintf("invalid csv: %s", reason))
+}
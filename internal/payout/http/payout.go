@@ -0,0 +1,108 @@
+package http
+
+import (
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateBatchRequest struct {
+	Items []*BatchItemRequest `json:"items"`
+}
+
+type BatchItemRequest struct {
+	ReferenceId string  `json:"reference_id"`
+	ToWalletId  string  `json:"to_wallet_id"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+}
+
+// Create the batch from a JSON body, or from a CSV body sent as text/csv.
+// The batch is paid from the wallet of the wallet_id query parameter, the default wallet if empty
+func HandleCreateBatch(service payout.PayoutIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, payout.MAX_REQUEST_SIZE)
+
+		items, err := decodeItems(r)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		batch, err := service.CreateBatch(r.Context(), &payout.CreateBatchParams{
+			CustomerXid: currentClient.Xid,
+			WalletId:    r.URL.Query().Get("wallet_id"),
+			Items:       items,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusAccepted, map[string]interface{}{
+			"batch": batch,
+		})
+	}
+}
+
+func HandleGetBatch(service payout.PayoutIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		batch, items, err := service.GetBatch(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"batch": batch,
+			"items": items,
+		})
+	}
+}
+
+func decodeItems(r *http.Request) ([]*payout.ItemParams, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return payout.ParseCSV(r.Body)
+	}
+
+	requestBody := &CreateBatchRequest{}
+	err := request.DecodeBody(r, &requestBody)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	items := []*payout.ItemParams{}
+	for i, item := range requestBody.Items {
+		if item == nil {
+			item = &BatchItemRequest{}
+		}
+		items = append(items, &payout.ItemParams{
+			Line:           i + 1,
+			ReferenceId:    item.ReferenceId,
+			TargetWalletId: item.ToWalletId,
+			Amount:         item.Amount,
+			Currency:       item.Currency,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,115 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	payout "github.com/defryheryanto/mini-wallet/internal/payout"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutIService is an autogenerated mock type for the PayoutIService type
+type PayoutIService struct {
+	mock.Mock
+}
+
+// CreateBatch provides a mock function with given fields: ctx, params
+func (_m *PayoutIService) CreateBatch(ctx context.Context, params *payout.CreateBatchParams) (*payout.Batch, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *payout.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payout.CreateBatchParams) (*payout.Batch, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *payout.CreateBatchParams) *payout.Batch); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payout.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *payout.CreateBatchParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatch provides a mock function with given fields: ctx, customerXid, id
+func (_m *PayoutIService) GetBatch(ctx context.Context, customerXid string, id string) (*payout.Batch, []*payout.Item, error) {
+	ret := _m.Called(ctx, customerXid, id)
+
+	var r0 *payout.Batch
+	var r1 []*payout.Item
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*payout.Batch, []*payout.Item, error)); ok {
+		return rf(ctx, customerXid, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *payout.Batch); ok {
+		r0 = rf(ctx, customerXid, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payout.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) []*payout.Item); ok {
+		r1 = rf(ctx, customerXid, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*payout.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, customerXid, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ProcessPending provides a mock function with given fields: ctx
+func (_m *PayoutIService) ProcessPending(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPayoutIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPayoutIService creates a new instance of PayoutIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPayoutIService(t mockConstructorTestingTNewPayoutIService) *PayoutIService {
+	mock := &PayoutIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
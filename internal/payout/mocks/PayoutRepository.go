@@ -0,0 +1,228 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	payout "github.com/defryheryanto/mini-wallet/internal/payout"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutRepository is an autogenerated mock type for the PayoutRepository type
+type PayoutRepository struct {
+	mock.Mock
+}
+
+// FindBatchById provides a mock function with given fields: ctx, id
+func (_m *PayoutRepository) FindBatchById(ctx context.Context, id string) (*payout.Batch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *payout.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*payout.Batch, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *payout.Batch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payout.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindBatchByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *PayoutRepository) FindBatchByIdForUpdate(ctx context.Context, id string) (*payout.Batch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *payout.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*payout.Batch, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *payout.Batch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payout.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *PayoutRepository) FindItemByIdForUpdate(ctx context.Context, id string) (*payout.Item, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *payout.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*payout.Item, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *payout.Item); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payout.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemsByBatchId provides a mock function with given fields: ctx, batchId
+func (_m *PayoutRepository) FindItemsByBatchId(ctx context.Context, batchId string) ([]*payout.Item, error) {
+	ret := _m.Called(ctx, batchId)
+
+	var r0 []*payout.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*payout.Item, error)); ok {
+		return rf(ctx, batchId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*payout.Item); ok {
+		r0 = rf(ctx, batchId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*payout.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, batchId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPendingItemIds provides a mock function with given fields: ctx, batchId, limit
+func (_m *PayoutRepository) FindPendingItemIds(ctx context.Context, batchId string, limit int) ([]string, error) {
+	ret := _m.Called(ctx, batchId, limit)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return rf(ctx, batchId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = rf(ctx, batchId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, batchId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUnfinishedBatchIds provides a mock function with given fields: ctx, limit
+func (_m *PayoutRepository) FindUnfinishedBatchIds(ctx context.Context, limit int) ([]string, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]string, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertBatch provides a mock function with given fields: ctx, batch, items
+func (_m *PayoutRepository) InsertBatch(ctx context.Context, batch *payout.Batch, items []*payout.Item) error {
+	ret := _m.Called(ctx, batch, items)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payout.Batch, []*payout.Item) error); ok {
+		r0 = rf(ctx, batch, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBatch provides a mock function with given fields: ctx, data
+func (_m *PayoutRepository) UpdateBatch(ctx context.Context, data *payout.Batch) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payout.Batch) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateItem provides a mock function with given fields: ctx, data
+func (_m *PayoutRepository) UpdateItem(ctx context.Context, data *payout.Item) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payout.Item) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPayoutRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPayoutRepository creates a new instance of PayoutRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPayoutRepository(t mockConstructorTestingTNewPayoutRepository) *PayoutRepository {
+	mock := &PayoutRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
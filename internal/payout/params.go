@@ -0,0 +1,19 @@
+package payout
+
+type CreateBatchParams struct {
+	CustomerXid string `json:"customer_xid"`
+	// Wallet of the customer paying the items, the default wallet of the customer if empty
+	WalletId string        `json:"wallet_id"`
+	Items    []*ItemParams `json:"items"`
+}
+
+type ItemParams struct {
+	// Line of the item in the request, reported along with its validation error
+	Line        int    `json:"line"`
+	ReferenceId string `json:"reference_id"`
+	// Wallet receiving the amount, the item is a withdrawal from the wallet of the batch if empty
+	TargetWalletId string  `json:"to_wallet_id"`
+	Amount         float64 `json:"amount"`
+	// Has to match the currency of the wallet of the batch, assumed if empty
+	Currency string `json:"currency"`
+}
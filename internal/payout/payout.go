@@ -0,0 +1,589 @@
+package payout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/google/uuid"
+)
+
+// Batch pays its items from a wallet of the customer in the background
+type Batch struct {
+	Id          string    `json:"id"`
+	ClientXid   string    `json:"client_xid"`
+	WalletId    string    `json:"wallet_id"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	ItemCount   int       `json:"item_count"`
+	TotalAmount float64   `json:"total_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Set once no item is pending
+	CompletedAt *time.Time `json:"completed_at"`
+	// Counts of the items by status, only set on the batches returned to the customer
+	Summary *Summary `json:"summary,omitempty"`
+}
+
+// Item is a transfer to another wallet, or a withdrawal from the wallet of the batch without target wallet
+type Item struct {
+	Id             string  `json:"id"`
+	BatchId        string  `json:"batch_id"`
+	Line           int     `json:"line"`
+	ReferenceId    string  `json:"reference_id"`
+	Type           string  `json:"type"`
+	TargetWalletId string  `json:"to_wallet_id"`
+	Amount         float64 `json:"amount"`
+	Status         string  `json:"status"`
+	Error          string  `json:"error"`
+	// Outgoing transfer or withdrawal made for the item
+	TransactionId string     `json:"transaction_id"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+}
+
+type Summary struct {
+	PendingCount  int     `json:"pending_count"`
+	SuccessCount  int     `json:"success_count"`
+	FailedCount   int     `json:"failed_count"`
+	SuccessAmount float64 `json:"success_amount"`
+	FailedAmount  float64 `json:"failed_amount"`
+}
+
+type PayoutRepository interface {
+	// Insert the batch along with its items
+	InsertBatch(ctx context.Context, batch *Batch, items []*Item) error
+	FindBatchById(ctx context.Context, id string) (*Batch, error)
+	// Find the batch and lock it until the end of the database transaction of the context
+	FindBatchByIdForUpdate(ctx context.Context, id string) (*Batch, error)
+	// Return the ids of up to limit pending or processing batches, the earliest first
+	FindUnfinishedBatchIds(ctx context.Context, limit int) ([]string, error)
+	UpdateBatch(ctx context.Context, data *Batch) error
+	// Return the items of the batch in line order
+	FindItemsByBatchId(ctx context.Context, batchId string) ([]*Item, error)
+	// Return the ids of up to limit pending items of the batch in line order
+	FindPendingItemIds(ctx context.Context, batchId string, limit int) ([]string, error)
+	// Find the item and lock it until the end of the database transaction of the context
+	FindItemByIdForUpdate(ctx context.Context, id string) (*Item, error)
+	UpdateItem(ctx context.Context, data *Item) error
+}
+
+type PayoutIService interface {
+	CreateBatch(ctx context.Context, params *CreateBatchParams) (*Batch, error)
+	GetBatch(ctx context.Context, customerXid, id string) (*Batch, []*Item, error)
+	ProcessPending(ctx context.Context) (int, error)
+}
+
+type PayoutService struct {
+	repository         PayoutRepository
+	walletService      wallet.WalletIService
+	transactionService transaction.TransactionIService
+	twoFactorService   twofactor.TwoFactorIService
+	storageManager     manager.StorageManager
+	// Withdrawals above the threshold of their currency require the confirmation of the customers enrolled to TOTP
+	withdrawalThresholds transaction.ConfirmationThresholds
+}
+
+func NewPayoutService(
+	repository PayoutRepository,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+	twoFactorService twofactor.TwoFactorIService,
+	storageManager manager.StorageManager,
+	withdrawalThresholds transaction.ConfirmationThresholds,
+) *PayoutService {
+	return &PayoutService{repository, walletService, transactionService, twoFactorService, storageManager, withdrawalThresholds}
+}
+
+// Validate every item of the batch and queue the batch to be processed in the background.
+// The batch is rejected with the errors of all the invalid items if any item is invalid,
+// or if the balance of the wallet doesn't cover the total amount
+func (s *PayoutService) CreateBatch(ctx context.Context, params *CreateBatchParams) (*Batch, error) {
+	if len(params.Items) == 0 {
+		return nil, ErrEmptyItems
+	}
+	if len(params.Items) > MAX_BATCH_ITEMS {
+		return nil, ErrTooManyItems
+	}
+
+	sourceWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.walletService.ValidateWallet(sourceWallet); err != nil {
+		return nil, err
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	batch := &Batch{
+		Id:        uuidRandom.String(),
+		ClientXid: params.CustomerXid,
+		WalletId:  sourceWallet.Id,
+		Currency:  sourceWallet.Currency,
+		Status:    BATCH_STATUS_PENDING,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	validator := &itemValidator{
+		service:       s,
+		customerXid:   params.CustomerXid,
+		sourceWallet:  sourceWallet,
+		references:    map[string]bool{},
+		targetWallets: map[string]*wallet.Wallet{},
+	}
+	items := []*Item{}
+	itemErrors := []*ItemError{}
+	for _, itemParams := range params.Items {
+		item, err := validator.validate(ctx, itemParams)
+		if err != nil {
+			if _, invalid := err.(errors.HandledError); !invalid {
+				return nil, err
+			}
+			itemErrors = append(itemErrors, &ItemError{Line: itemParams.Line, ReferenceId: itemParams.ReferenceId, Error: err.Error()})
+			continue
+		}
+
+		itemId, err := uuid.NewRandom()
+		if err != nil {
+			return nil, err
+		}
+		item.Id = itemId.String()
+		item.BatchId = batch.Id
+		items = append(items, item)
+		batch.TotalAmount += item.Amount
+	}
+	if len(itemErrors) > 0 {
+		return nil, ErrInvalidItems(itemErrors)
+	}
+	if sourceWallet.Balance < batch.TotalAmount {
+		return nil, wallet.ErrInsufficientBalance
+	}
+	batch.ItemCount = len(items)
+
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		return s.repository.InsertBatch(ctx, batch, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Summary = summarize(items)
+	return batch, nil
+}
+
+// Return the batch of the customer along with its items
+func (s *PayoutService) GetBatch(ctx context.Context, customerXid, id string) (*Batch, []*Item, error) {
+	batch, err := s.repository.FindBatchById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if batch == nil || batch.ClientXid != customerXid {
+		return nil, nil, ErrBatchNotFound
+	}
+
+	items, err := s.repository.FindItemsByBatchId(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batch.Summary = summarize(items)
+	return batch, items, nil
+}
+
+// Process the pending items of the unfinished batches, the earliest batch first, up to PROCESS_ITEM_LIMIT items.
+// An item failing to be processed doesn't stop the others, and a batch is finished once none of its items is pending.
+//
+// Return the number of items processed, including the failed items recorded
+func (s *PayoutService) ProcessPending(ctx context.Context) (int, error) {
+	ids, err := s.repository.FindUnfinishedBatchIds(ctx, PROCESS_BATCH_LIMIT)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, id := range ids {
+		if processed >= PROCESS_ITEM_LIMIT {
+			break
+		}
+
+		batchCtx := logging.With(ctx, "payout_batch_id", id)
+		count, err := s.processBatch(batchCtx, id, PROCESS_ITEM_LIMIT-processed)
+		processed += count
+		if err != nil {
+			logging.FromContext(batchCtx).Error("error processing payout batch", logging.KEY_ERROR, err)
+		}
+	}
+
+	return processed, nil
+}
+
+func (s *PayoutService) processBatch(ctx context.Context, id string, limit int) (int, error) {
+	batch, err := s.repository.FindBatchById(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if batch == nil || batch.isFinished() {
+		return 0, nil
+	}
+	if batch.Status == BATCH_STATUS_PENDING {
+		batch.Status = BATCH_STATUS_PROCESSING
+		batch.UpdatedAt = time.Now()
+		err = s.repository.UpdateBatch(ctx, batch)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	itemIds, err := s.repository.FindPendingItemIds(ctx, id, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := logging.FromContext(ctx)
+	processed := 0
+	for _, itemId := range itemIds {
+		err := s.processItem(ctx, batch, itemId)
+		if err == errWithdrawalSettling {
+			continue
+		}
+		if err != nil {
+			logger.Error("error processing payout item", "payout_item_id", itemId, logging.KEY_ERROR, err)
+			continue
+		}
+		processed++
+	}
+	if processed < len(itemIds) {
+		return processed, nil
+	}
+
+	return processed, s.finishBatch(ctx, id)
+}
+
+// Pay the pending item and record its outcome. The item is locked while it is paid
+// and skipped if it is no longer pending, e.g. paid by another instance.
+//
+// A transfer is made in the database transaction recording the item, so it is never made twice.
+// A withdrawal is made outside of it as its settlement is queued once the withdrawal is stored,
+// a withdrawal made for the item by an attempt that couldn't record it is found by its reference id.
+// The item keeps its withdrawal while it is settled and takes the outcome of the settlement on a later pass.
+//
+// A payment rejected by the wallets, e.g. for insufficient balance, fails the item for good.
+// Other errors, including the service being unavailable, are returned, the item is retried on the next pass.
+//
+// Return errWithdrawalSettling if the withdrawal of the item is not settled yet
+func (s *PayoutService) processItem(ctx context.Context, batch *Batch, id string) error {
+	var payErr error
+	settling := false
+	err := s.storageManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		item, err := s.repository.FindItemByIdForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if item == nil || item.Status != ITEM_STATUS_PENDING {
+			return nil
+		}
+
+		if item.Type == ITEM_TYPE_TRANSFER {
+			trx, err := s.transfer(txCtx, batch, item)
+			if err != nil {
+				payErr = err
+				return err
+			}
+
+			item.record(trx.Id, nil)
+			return s.repository.UpdateItem(txCtx, item)
+		}
+
+		trx, err := s.withdraw(ctx, batch, item)
+		if err != nil {
+			payErr = err
+			return err
+		}
+
+		switch trx.Status {
+		case transaction.STATUS_SUCCESS:
+			item.record(trx.Id, nil)
+		case transaction.STATUS_FAILED:
+			item.TransactionId = trx.Id
+			item.record(trx.Id, ErrWithdrawalFailed)
+		default:
+			settling = true
+			if item.TransactionId == trx.Id {
+				return nil
+			}
+			item.TransactionId = trx.Id
+		}
+		return s.repository.UpdateItem(txCtx, item)
+	})
+	if payErr == nil {
+		if err == nil && settling {
+			return errWithdrawalSettling
+		}
+		return err
+	}
+	if !isRejection(payErr) {
+		return payErr
+	}
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		item, err := s.repository.FindItemByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if item == nil || item.Status != ITEM_STATUS_PENDING {
+			return nil
+		}
+
+		item.record("", payErr)
+		return s.repository.UpdateItem(ctx, item)
+	})
+}
+
+// Return true if the payment is rejected for good, e.g. for insufficient balance.
+// A server error, e.g. the service shutting down, may not happen on the next attempt
+func isRejection(err error) bool {
+	handledErr, ok := err.(errors.HandledError)
+	return ok && handledErr.HttpStatus < http.StatusInternalServerError
+}
+
+func (s *PayoutService) transfer(ctx context.Context, batch *Batch, item *Item) (*transaction.Transaction, error) {
+	outgoing, _, err := s.transactionService.CreateTransfer(ctx, &transaction.CreateTransferParams{
+		CustomerXid:    batch.ClientXid,
+		WalletId:       batch.WalletId,
+		TargetWalletId: item.TargetWalletId,
+		ReferenceId:    item.ReferenceId,
+		Amount:         item.Amount,
+		Currency:       batch.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outgoing, nil
+}
+
+// Return the withdrawal of the item, made on the first attempt
+func (s *PayoutService) withdraw(ctx context.Context, batch *Batch, item *Item) (*transaction.Transaction, error) {
+	if item.TransactionId != "" {
+		trx, err := s.transactionService.GetTransactionByReferenceId(ctx, item.ReferenceId, transaction.TYPE_WITHDRAWAL)
+		if err != nil {
+			return nil, err
+		}
+		if trx == nil || trx.Id != item.TransactionId {
+			return nil, fmt.Errorf("withdrawal %s of the payout item not found", item.TransactionId)
+		}
+		return trx, nil
+	}
+
+	trx, err := s.transactionService.CreateWithdrawal(ctx, &transaction.CreateWithdrawalParams{
+		CustomerXid: batch.ClientXid,
+		WalletId:    batch.WalletId,
+		ReferenceId: item.ReferenceId,
+		Amount:      item.Amount,
+		Currency:    batch.Currency,
+	})
+	if err != transaction.ErrReferenceNoAlreadyExists {
+		return trx, err
+	}
+
+	existing, findErr := s.transactionService.GetTransactionByReferenceId(ctx, item.ReferenceId, transaction.TYPE_WITHDRAWAL)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if existing == nil || existing.WalletId != batch.WalletId || existing.Amount != item.Amount {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Set the status of the batch from the statuses of its items once none of them is pending
+func (s *PayoutService) finishBatch(ctx context.Context, id string) error {
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		batch, err := s.repository.FindBatchByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if batch == nil || batch.isFinished() {
+			return nil
+		}
+
+		items, err := s.repository.FindItemsByBatchId(ctx, id)
+		if err != nil {
+			return err
+		}
+		summary := summarize(items)
+		if summary.PendingCount > 0 {
+			return nil
+		}
+
+		switch {
+		case summary.FailedCount == 0:
+			batch.Status = BATCH_STATUS_COMPLETED
+		case summary.SuccessCount == 0:
+			batch.Status = BATCH_STATUS_FAILED
+		default:
+			batch.Status = BATCH_STATUS_PARTIALLY_FAILED
+		}
+		now := time.Now()
+		batch.CompletedAt = &now
+		batch.UpdatedAt = now
+		return s.repository.UpdateBatch(ctx, batch)
+	})
+}
+
+func (b *Batch) isFinished() bool {
+	return b.Status != BATCH_STATUS_PENDING && b.Status != BATCH_STATUS_PROCESSING
+}
+
+func (i *Item) record(transactionId string, payErr error) {
+	now := time.Now()
+	i.ProcessedAt = &now
+	if payErr != nil {
+		i.Status = ITEM_STATUS_FAILED
+		i.Error = payErr.Error()
+		if len(i.Error) > MAX_ERROR_LENGTH {
+			i.Error = i.Error[:MAX_ERROR_LENGTH]
+		}
+		return
+	}
+
+	i.Status = ITEM_STATUS_SUCCESS
+	i.TransactionId = transactionId
+}
+
+func summarize(items []*Item) *Summary {
+	summary := &Summary{}
+	for _, item := range items {
+		switch item.Status {
+		case ITEM_STATUS_SUCCESS:
+			summary.SuccessCount++
+			summary.SuccessAmount += item.Amount
+		case ITEM_STATUS_FAILED:
+			summary.FailedCount++
+			summary.FailedAmount += item.Amount
+		default:
+			summary.PendingCount++
+		}
+	}
+
+	return summary
+}
+
+// itemValidator validates the items of a batch, keeping what the items share, e.g. the target wallets
+type itemValidator struct {
+	service       *PayoutService
+	customerXid   string
+	sourceWallet  *wallet.Wallet
+	references    map[string]bool
+	targetWallets map[string]*wallet.Wallet
+	isEnrolled    *bool
+}
+
+// Return the item of the given params, or the error rejecting them
+func (v *itemValidator) validate(ctx context.Context, params *ItemParams) (*Item, error) {
+	if params.ReferenceId == "" {
+		return nil, ErrEmptyReferenceId
+	}
+	if v.references[params.ReferenceId] {
+		return nil, ErrDuplicateReferenceId
+	}
+	v.references[params.ReferenceId] = true
+
+	if params.Amount <= 0 {
+		return nil, ErrNonPositiveAmount
+	}
+	err := v.sourceWallet.ValidateAmount(params.Amount, params.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &Item{
+		Line:           params.Line,
+		ReferenceId:    params.ReferenceId,
+		Type:           ITEM_TYPE_WITHDRAWAL,
+		TargetWalletId: params.TargetWalletId,
+		Amount:         params.Amount,
+		Status:         ITEM_STATUS_PENDING,
+	}
+	transactionType := transaction.TYPE_WITHDRAWAL
+	if params.TargetWalletId != "" {
+		item.Type = ITEM_TYPE_TRANSFER
+		transactionType = transaction.TYPE_TRANSFER_OUT
+		err = v.validateTarget(ctx, params.TargetWalletId, params.Amount)
+	} else {
+		err = v.validateConfirmation(ctx, params.Amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := v.service.transactionService.GetTransactionByReferenceId(ctx, params.ReferenceId, transactionType)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, transaction.ErrReferenceNoAlreadyExists
+	}
+
+	return item, nil
+}
+
+func (v *itemValidator) validateTarget(ctx context.Context, targetWalletId string, amount float64) error {
+	if targetWalletId == v.sourceWallet.Id {
+		return ErrSameWallet
+	}
+
+	targetWallet, ok := v.targetWallets[targetWalletId]
+	if !ok {
+		var err error
+		targetWallet, err = v.service.walletService.GetWalletById(ctx, targetWalletId)
+		if err != nil {
+			return err
+		}
+		v.targetWallets[targetWalletId] = targetWallet
+	}
+	if err := v.service.walletService.ValidateDeposit(targetWallet); err != nil {
+		return err
+	}
+	if targetWallet.Currency != v.sourceWallet.Currency {
+		return ErrCurrencyMismatch
+	}
+	if targetWallet.OwnedBy != v.customerXid {
+		return v.validateConfirmation(ctx, amount)
+	}
+
+	return nil
+}
+
+// Reject the withdrawals and the transfers to wallets of other clients the customer would have to confirm,
+// which a batch can't do
+func (v *itemValidator) validateConfirmation(ctx context.Context, amount float64) error {
+	if !v.service.withdrawalThresholds.Exceeds(amount, v.sourceWallet.Currency) {
+		return nil
+	}
+
+	if v.isEnrolled == nil {
+		isEnrolled, err := v.service.twoFactorService.IsEnrolled(ctx, v.customerXid)
+		if err != nil {
+			return err
+		}
+		v.isEnrolled = &isEnrolled
+	}
+	if *v.isEnrolled {
+		return ErrConfirmationRequired
+	}
+
+	return nil
+}
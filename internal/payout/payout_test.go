@@ -0,0 +1,380 @@
+package payout_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	payout_mock "github.com/defryheryanto/mini-wallet/internal/payout/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	twofactor_mock "github.com/defryheryanto/mini-wallet/internal/twofactor/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const withdrawalThreshold = 1000
+
+type serviceMocks struct {
+	repository         *payout_mock.PayoutRepository
+	walletService      *wallet_mock.WalletIService
+	transactionService *transaction_mock.TransactionIService
+	twoFactorService   *twofactor_mock.TwoFactorIService
+}
+
+func newService(t *testing.T) (*payout.PayoutService, *serviceMocks) {
+	mocks := &serviceMocks{
+		repository:         payout_mock.NewPayoutRepository(t),
+		walletService:      wallet_mock.NewWalletIService(t),
+		transactionService: transaction_mock.NewTransactionIService(t),
+		twoFactorService:   twofactor_mock.NewTwoFactorIService(t),
+	}
+
+	service := payout.NewPayoutService(
+		mocks.repository,
+		mocks.walletService,
+		mocks.transactionService,
+		mocks.twoFactorService,
+		&manager.MockStorageManager{},
+		transaction.ConfirmationThresholds{"IDR": withdrawalThreshold},
+	)
+	return service, mocks
+}
+
+func TestPayoutService_CreateBatch(t *testing.T) {
+	newSourceWallet := func() *wallet.Wallet {
+		return &wallet.Wallet{Id: "source-id", Currency: "IDR", Balance: 5000, Status: wallet.STATUS_ENABLED}
+	}
+	targetWallet := &wallet.Wallet{Id: "target-id", OwnedBy: "other", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+	ownWallet := &wallet.Wallet{Id: "own-id", OwnedBy: "xid", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+
+	t.Run("should return error if batch has no item", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateBatch(context.TODO(), &payout.CreateBatchParams{CustomerXid: "xid"})
+		assert.Equal(t, payout.ErrEmptyItems, err)
+	})
+
+	t.Run("should return error if batch has too many items", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateBatch(context.TODO(), &payout.CreateBatchParams{CustomerXid: "xid", Items: make([]*payout.ItemParams, payout.MAX_BATCH_ITEMS+1)})
+		assert.Equal(t, payout.ErrTooManyItems, err)
+	})
+
+	t.Run("should reject the batch with the errors of every invalid item", func(t *testing.T) {
+		sourceWallet := newSourceWallet()
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(targetWallet, nil).Once()
+		mocks.walletService.On("GetWalletById", mock.Anything, "unknown-id").Return(nil, wallet.ErrWalletNotFound)
+		mocks.walletService.On("GetWalletById", mock.Anything, "own-id").Return(ownWallet, nil).Once()
+		mocks.walletService.On("ValidateDeposit", targetWallet).Return(nil)
+		mocks.walletService.On("ValidateDeposit", ownWallet).Return(nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-1", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-12", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-2", transaction.TYPE_WITHDRAWAL).Return(&transaction.Transaction{}, nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-6", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		mocks.twoFactorService.On("IsEnrolled", mock.Anything, "xid").Return(true, nil).Once()
+
+		_, err := service.CreateBatch(context.TODO(), &payout.CreateBatchParams{
+			CustomerXid: "xid",
+			Items: []*payout.ItemParams{
+				{Line: 1, ReferenceId: "ref-1", TargetWalletId: "target-id", Amount: 100},
+				{Line: 2, ReferenceId: "ref-2", Amount: 100},
+				{Line: 3, ReferenceId: "ref-1", TargetWalletId: "target-id", Amount: 100},
+				{Line: 4, ReferenceId: "ref-4", TargetWalletId: "unknown-id", Amount: 100},
+				{Line: 5, ReferenceId: "ref-5", Amount: withdrawalThreshold + 1},
+				{Line: 6, ReferenceId: "ref-6", TargetWalletId: "target-id", Amount: 100},
+				{Line: 7, ReferenceId: "ref-7", Amount: withdrawalThreshold + 1},
+				{Line: 8, ReferenceId: "ref-8", TargetWalletId: "source-id", Amount: 100},
+				{Line: 9, ReferenceId: "", Amount: 100},
+				{Line: 10, ReferenceId: "ref-10", Amount: 0},
+				{Line: 11, ReferenceId: "ref-11", TargetWalletId: "target-id", Amount: withdrawalThreshold + 1},
+				{Line: 12, ReferenceId: "ref-12", TargetWalletId: "own-id", Amount: withdrawalThreshold + 1},
+			},
+		})
+		assert.Equal(t, payout.ErrInvalidItems([]*payout.ItemError{
+			{Line: 2, ReferenceId: "ref-2", Error: transaction.ErrReferenceNoAlreadyExists.Error()},
+			{Line: 3, ReferenceId: "ref-1", Error: payout.ErrDuplicateReferenceId.Error()},
+			{Line: 4, ReferenceId: "ref-4", Error: wallet.ErrWalletNotFound.Error()},
+			{Line: 5, ReferenceId: "ref-5", Error: payout.ErrConfirmationRequired.Error()},
+			{Line: 7, ReferenceId: "ref-7", Error: payout.ErrConfirmationRequired.Error()},
+			{Line: 8, ReferenceId: "ref-8", Error: payout.ErrSameWallet.Error()},
+			{Line: 9, ReferenceId: "", Error: payout.ErrEmptyReferenceId.Error()},
+			{Line: 10, ReferenceId: "ref-10", Error: payout.ErrNonPositiveAmount.Error()},
+			{Line: 11, ReferenceId: "ref-11", Error: payout.ErrConfirmationRequired.Error()},
+		}), err)
+	})
+
+	t.Run("should return error if balance doesn't cover the total amount", func(t *testing.T) {
+		sourceWallet := newSourceWallet()
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, mock.Anything, transaction.TYPE_WITHDRAWAL).Return(nil, nil)
+
+		_, err := service.CreateBatch(context.TODO(), &payout.CreateBatchParams{
+			CustomerXid: "xid",
+			Items: []*payout.ItemParams{
+				{Line: 1, ReferenceId: "ref-1", Amount: 1000},
+				{Line: 2, ReferenceId: "ref-2", Amount: 1000},
+				{Line: 3, ReferenceId: "ref-3", Amount: 1000},
+				{Line: 4, ReferenceId: "ref-4", Amount: 1000},
+				{Line: 5, ReferenceId: "ref-5", Amount: 1000},
+				{Line: 6, ReferenceId: "ref-6", Amount: 1000},
+			},
+		})
+		assert.Equal(t, wallet.ErrInsufficientBalance, err)
+	})
+
+	t.Run("should queue the batch along with its items", func(t *testing.T) {
+		sourceWallet := newSourceWallet()
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "wallet-id").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(targetWallet, nil)
+		mocks.walletService.On("ValidateDeposit", targetWallet).Return(nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		mocks.repository.On("InsertBatch", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			batch := args.Get(1).(*payout.Batch)
+			items := args.Get(2).([]*payout.Item)
+			assert.Len(t, items, 2)
+			assert.Equal(t, batch.Id, items[0].BatchId)
+			assert.Equal(t, payout.ITEM_TYPE_TRANSFER, items[0].Type)
+			assert.Equal(t, payout.ITEM_TYPE_WITHDRAWAL, items[1].Type)
+			assert.Equal(t, payout.ITEM_STATUS_PENDING, items[1].Status)
+		}).Return(nil)
+
+		batch, err := service.CreateBatch(context.TODO(), &payout.CreateBatchParams{
+			CustomerXid: "xid",
+			WalletId:    "wallet-id",
+			Items: []*payout.ItemParams{
+				{Line: 1, ReferenceId: "ref-1", TargetWalletId: "target-id", Amount: 100},
+				{Line: 2, ReferenceId: "ref-2", Amount: 200, Currency: "IDR"},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, payout.BATCH_STATUS_PENDING, batch.Status)
+		assert.Equal(t, "source-id", batch.WalletId)
+		assert.Equal(t, 2, batch.ItemCount)
+		assert.Equal(t, float64(300), batch.TotalAmount)
+		assert.Equal(t, &payout.Summary{PendingCount: 2}, batch.Summary)
+	})
+}
+
+func TestPayoutService_GetBatch(t *testing.T) {
+	t.Run("should return error if batch belongs to another client", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", ClientXid: "other-xid"}, nil)
+
+		_, _, err := service.GetBatch(context.TODO(), "xid", "batch-id")
+		assert.Equal(t, payout.ErrBatchNotFound, err)
+	})
+
+	t.Run("should summarize the items of the batch", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", ClientXid: "xid"}, nil)
+		mocks.repository.On("FindItemsByBatchId", mock.Anything, "batch-id").Return([]*payout.Item{
+			{Status: payout.ITEM_STATUS_SUCCESS, Amount: 100},
+			{Status: payout.ITEM_STATUS_SUCCESS, Amount: 50},
+			{Status: payout.ITEM_STATUS_FAILED, Amount: 20},
+			{Status: payout.ITEM_STATUS_PENDING, Amount: 10},
+		}, nil)
+
+		batch, items, err := service.GetBatch(context.TODO(), "xid", "batch-id")
+		assert.Nil(t, err)
+		assert.Len(t, items, 4)
+		assert.Equal(t, &payout.Summary{PendingCount: 1, SuccessCount: 2, FailedCount: 1, SuccessAmount: 150, FailedAmount: 20}, batch.Summary)
+	})
+}
+
+func TestPayoutService_ProcessPending(t *testing.T) {
+	newBatch := func() *payout.Batch {
+		return &payout.Batch{Id: "batch-id", ClientXid: "xid", WalletId: "source-id", Currency: "IDR", Status: payout.BATCH_STATUS_PENDING}
+	}
+
+	t.Run("should pay the transfer and keep the withdrawal pending while it settles", func(t *testing.T) {
+		transferItem := &payout.Item{Id: "item-1", ReferenceId: "ref-1", Type: payout.ITEM_TYPE_TRANSFER, TargetWalletId: "target-id", Amount: 100, Status: payout.ITEM_STATUS_PENDING}
+		withdrawalItem := &payout.Item{Id: "item-2", ReferenceId: "ref-2", Type: payout.ITEM_TYPE_WITHDRAWAL, Amount: 200, Status: payout.ITEM_STATUS_PENDING}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(newBatch(), nil)
+		mocks.repository.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *payout.Batch) bool {
+			return batch.Status == payout.BATCH_STATUS_PROCESSING
+		})).Return(nil).Once()
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1", "item-2"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(transferItem, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-2").Return(withdrawalItem, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, &transaction.CreateTransferParams{
+			CustomerXid:    "xid",
+			WalletId:       "source-id",
+			TargetWalletId: "target-id",
+			ReferenceId:    "ref-1",
+			Amount:         100,
+			Currency:       "IDR",
+		}).Return(&transaction.Transaction{Id: "trx-1", Status: transaction.STATUS_SUCCESS}, &transaction.Transaction{}, nil)
+		mocks.transactionService.On("CreateWithdrawal", mock.Anything, &transaction.CreateWithdrawalParams{
+			CustomerXid: "xid",
+			WalletId:    "source-id",
+			ReferenceId: "ref-2",
+			Amount:      200,
+			Currency:    "IDR",
+		}).Return(&transaction.Transaction{Id: "trx-2", Status: transaction.STATUS_PENDING}, nil)
+		mocks.repository.On("UpdateItem", mock.Anything, mock.Anything).Return(nil).Twice()
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, payout.ITEM_STATUS_SUCCESS, transferItem.Status)
+		assert.Equal(t, "trx-1", transferItem.TransactionId)
+		assert.Equal(t, payout.ITEM_STATUS_PENDING, withdrawalItem.Status)
+		assert.Equal(t, "trx-2", withdrawalItem.TransactionId)
+	})
+
+	t.Run("should record the outcome of the settled withdrawal and complete the batch", func(t *testing.T) {
+		paidItem := &payout.Item{Id: "item-1", Status: payout.ITEM_STATUS_SUCCESS}
+		withdrawalItem := &payout.Item{Id: "item-2", ReferenceId: "ref-2", Type: payout.ITEM_TYPE_WITHDRAWAL, Amount: 200, Status: payout.ITEM_STATUS_PENDING, TransactionId: "trx-2"}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-2"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-2").Return(withdrawalItem, nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-2", transaction.TYPE_WITHDRAWAL).Return(&transaction.Transaction{Id: "trx-2", Status: transaction.STATUS_SUCCESS}, nil)
+		mocks.repository.On("UpdateItem", mock.Anything, withdrawalItem).Return(nil).Once()
+		mocks.repository.On("FindBatchByIdForUpdate", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindItemsByBatchId", mock.Anything, "batch-id").Return([]*payout.Item{paidItem, withdrawalItem}, nil)
+		mocks.repository.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *payout.Batch) bool {
+			return batch.Status == payout.BATCH_STATUS_COMPLETED && batch.CompletedAt != nil
+		})).Return(nil).Once()
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, payout.ITEM_STATUS_SUCCESS, withdrawalItem.Status)
+		mocks.transactionService.AssertNotCalled(t, "CreateWithdrawal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail the item of the withdrawal failed to settle", func(t *testing.T) {
+		withdrawalItem := &payout.Item{Id: "item-1", ReferenceId: "ref-1", Type: payout.ITEM_TYPE_WITHDRAWAL, Amount: 200, Status: payout.ITEM_STATUS_PENDING, TransactionId: "trx-1"}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(withdrawalItem, nil)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-1", transaction.TYPE_WITHDRAWAL).Return(&transaction.Transaction{Id: "trx-1", Status: transaction.STATUS_FAILED}, nil)
+		mocks.repository.On("UpdateItem", mock.Anything, withdrawalItem).Return(nil).Once()
+		mocks.repository.On("FindBatchByIdForUpdate", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindItemsByBatchId", mock.Anything, "batch-id").Return([]*payout.Item{withdrawalItem}, nil)
+		mocks.repository.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *payout.Batch) bool {
+			return batch.Status == payout.BATCH_STATUS_FAILED
+		})).Return(nil).Once()
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, payout.ITEM_STATUS_FAILED, withdrawalItem.Status)
+		assert.Equal(t, payout.ErrWithdrawalFailed.Error(), withdrawalItem.Error)
+		assert.Equal(t, "trx-1", withdrawalItem.TransactionId)
+	})
+
+	t.Run("should fail the rejected item and finish the batch as partially failed", func(t *testing.T) {
+		failingItem := &payout.Item{Id: "item-1", ReferenceId: "ref-1", Type: payout.ITEM_TYPE_TRANSFER, TargetWalletId: "target-id", Amount: 100, Status: payout.ITEM_STATUS_PENDING}
+		paidItem := &payout.Item{Id: "item-2", Status: payout.ITEM_STATUS_SUCCESS}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(failingItem, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, wallet.ErrInsufficientBalance)
+		mocks.repository.On("UpdateItem", mock.Anything, failingItem).Return(nil).Once()
+		mocks.repository.On("FindBatchByIdForUpdate", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindItemsByBatchId", mock.Anything, "batch-id").Return([]*payout.Item{failingItem, paidItem}, nil)
+		mocks.repository.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *payout.Batch) bool {
+			return batch.Status == payout.BATCH_STATUS_PARTIALLY_FAILED
+		})).Return(nil).Once()
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, payout.ITEM_STATUS_FAILED, failingItem.Status)
+		assert.Equal(t, wallet.ErrInsufficientBalance.Error(), failingItem.Error)
+	})
+
+	t.Run("should leave the item pending if the payment errored", func(t *testing.T) {
+		item := &payout.Item{Id: "item-1", Type: payout.ITEM_TYPE_TRANSFER, Status: payout.ITEM_STATUS_PENDING}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(item, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("mocked"))
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, processed)
+		assert.Equal(t, payout.ITEM_STATUS_PENDING, item.Status)
+	})
+
+	t.Run("should leave the item pending if the service is unavailable", func(t *testing.T) {
+		item := &payout.Item{Id: "item-1", ReferenceId: "ref-1", Type: payout.ITEM_TYPE_WITHDRAWAL, Amount: 200, Status: payout.ITEM_STATUS_PENDING}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(item, nil)
+		mocks.transactionService.On("CreateWithdrawal", mock.Anything, mock.Anything).Return(nil, transaction.ErrSettlementStopped)
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, processed)
+		assert.Equal(t, payout.ITEM_STATUS_PENDING, item.Status)
+		mocks.repository.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should record the withdrawal made by the previous attempt", func(t *testing.T) {
+		item := &payout.Item{Id: "item-1", ReferenceId: "ref-1", Type: payout.ITEM_TYPE_WITHDRAWAL, Amount: 200, Status: payout.ITEM_STATUS_PENDING}
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", WalletId: "source-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(item, nil)
+		mocks.transactionService.On("CreateWithdrawal", mock.Anything, mock.Anything).Return(nil, transaction.ErrReferenceNoAlreadyExists)
+		mocks.transactionService.On("GetTransactionByReferenceId", mock.Anything, "ref-1", transaction.TYPE_WITHDRAWAL).Return(&transaction.Transaction{Id: "trx-1", WalletId: "source-id", Amount: 200, Status: transaction.STATUS_SUCCESS}, nil)
+		mocks.repository.On("UpdateItem", mock.Anything, item).Return(nil)
+		mocks.repository.On("FindBatchByIdForUpdate", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindItemsByBatchId", mock.Anything, "batch-id").Return([]*payout.Item{item}, nil)
+		mocks.repository.On("UpdateBatch", mock.Anything, mock.Anything).Return(nil)
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, payout.ITEM_STATUS_SUCCESS, item.Status)
+		assert.Equal(t, "trx-1", item.TransactionId)
+	})
+
+	t.Run("should skip the item already paid by another instance", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindUnfinishedBatchIds", mock.Anything, payout.PROCESS_BATCH_LIMIT).Return([]string{"batch-id"}, nil)
+		mocks.repository.On("FindBatchById", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_PROCESSING}, nil)
+		mocks.repository.On("FindPendingItemIds", mock.Anything, "batch-id", payout.PROCESS_ITEM_LIMIT).Return([]string{"item-1"}, nil)
+		mocks.repository.On("FindItemByIdForUpdate", mock.Anything, "item-1").Return(&payout.Item{Id: "item-1", Status: payout.ITEM_STATUS_SUCCESS}, nil)
+		mocks.repository.On("FindBatchByIdForUpdate", mock.Anything, "batch-id").Return(&payout.Batch{Id: "batch-id", Status: payout.BATCH_STATUS_COMPLETED}, nil)
+
+		processed, err := service.ProcessPending(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, processed)
+	})
+}
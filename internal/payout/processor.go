@@ -0,0 +1,37 @@
+package payout
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Processor pays the items of the payout batches in the background.
+// An item is locked while it is paid, so every instance can run its own processor.
+// Shutdown waits for the items being paid, items left pending are paid on the next start
+type Processor struct {
+	*lifecycle.Loop
+	service PayoutIService
+}
+
+func NewProcessor(service PayoutIService) *Processor {
+	p := &Processor{service: service}
+	p.Loop = lifecycle.NewLoop("payout", p.process)
+	return p
+}
+
+// Process a pass of pending items, the next pass follows right away unless none is left
+func (p *Processor) process(ctx context.Context) time.Duration {
+	processed, err := p.service.ProcessPending(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error processing payout batches", logging.KEY_ERROR, err)
+		return PROCESS_INTERVAL
+	}
+	if processed < PROCESS_ITEM_LIMIT {
+		return PROCESS_INTERVAL
+	}
+
+	return 0
+}
@@ -0,0 +1,32 @@
+package payout_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	payout_mock "github.com/defryheryanto/mini-wallet/internal/payout/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessor(t *testing.T) {
+	t.Run("should process the next pass right away while the passes are full", func(t *testing.T) {
+		service := payout_mock.NewPayoutIService(t)
+		service.On("ProcessPending", mock.Anything).Return(payout.PROCESS_ITEM_LIMIT, nil).Once()
+		called := make(chan struct{})
+		service.On("ProcessPending", mock.Anything).Run(func(args mock.Arguments) {
+			close(called)
+		}).Return(0, nil).Once()
+
+		processor := payout.NewProcessor(service)
+		processor.Start(context.TODO())
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("next pass not processed")
+		}
+		assert.Nil(t, processor.Shutdown(context.TODO()))
+	})
+}
@@ -0,0 +1,125 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+)
+
+type Batch struct {
+	Id          string     `gorm:"primaryKey;column:id"`
+	ClientXid   string     `gorm:"column:client_xid"`
+	WalletId    string     `gorm:"column:wallet_id"`
+	Currency    string     `gorm:"column:currency"`
+	Status      string     `gorm:"column:status"`
+	ItemCount   int        `gorm:"column:item_count"`
+	TotalAmount float64    `gorm:"column:total_amount"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+}
+
+func (Batch) TableName() string {
+	return "payout_batches"
+}
+
+func (Batch) FromServiceModel(data *payout.Batch) *Batch {
+	if data == nil {
+		return nil
+	}
+
+	return &Batch{
+		Id:          data.Id,
+		ClientXid:   data.ClientXid,
+		WalletId:    data.WalletId,
+		Currency:    data.Currency,
+		Status:      data.Status,
+		ItemCount:   data.ItemCount,
+		TotalAmount: data.TotalAmount,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+		CompletedAt: data.CompletedAt,
+	}
+}
+
+func (b *Batch) ToServiceModel() *payout.Batch {
+	return &payout.Batch{
+		Id:          b.Id,
+		ClientXid:   b.ClientXid,
+		WalletId:    b.WalletId,
+		Currency:    b.Currency,
+		Status:      b.Status,
+		ItemCount:   b.ItemCount,
+		TotalAmount: b.TotalAmount,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+		CompletedAt: b.CompletedAt,
+	}
+}
+
+type Item struct {
+	Id             string     `gorm:"primaryKey;column:id"`
+	BatchId        string     `gorm:"column:batch_id"`
+	Line           int        `gorm:"column:line"`
+	ReferenceId    string     `gorm:"column:reference_id"`
+	Type           string     `gorm:"column:type"`
+	TargetWalletId string     `gorm:"column:target_wallet_id"`
+	Amount         float64    `gorm:"column:amount"`
+	Status         string     `gorm:"column:status"`
+	Error          string     `gorm:"column:error"`
+	TransactionId  string     `gorm:"column:transaction_id"`
+	ProcessedAt    *time.Time `gorm:"column:processed_at"`
+}
+
+func (Item) TableName() string {
+	return "payout_items"
+}
+
+func (Item) FromServiceModel(data *payout.Item) *Item {
+	if data == nil {
+		return nil
+	}
+
+	return &Item{
+		Id:             data.Id,
+		BatchId:        data.BatchId,
+		Line:           data.Line,
+		ReferenceId:    data.ReferenceId,
+		Type:           data.Type,
+		TargetWalletId: data.TargetWalletId,
+		Amount:         data.Amount,
+		Status:         data.Status,
+		Error:          data.Error,
+		TransactionId:  data.TransactionId,
+		ProcessedAt:    data.ProcessedAt,
+	}
+}
+
+func (i *Item) ToServiceModel() *payout.Item {
+	return &payout.Item{
+		Id:             i.Id,
+		BatchId:        i.BatchId,
+		Line:           i.Line,
+		ReferenceId:    i.ReferenceId,
+		Type:           i.Type,
+		TargetWalletId: i.TargetWalletId,
+		Amount:         i.Amount,
+		Status:         i.Status,
+		Error:          i.Error,
+		TransactionId:  i.TransactionId,
+		ProcessedAt:    i.ProcessedAt,
+	}
+}
+
+func ItemsToServiceModel(data []*Item) []*payout.Item {
+	if data == nil {
+		return nil
+	}
+
+	items := []*payout.Item{}
+	for _, i := range data {
+		items = append(items, i.ToServiceModel())
+	}
+
+	return items
+}
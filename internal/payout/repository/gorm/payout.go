@@ -0,0 +1,149 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/payout"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Items inserted per statement
+const insertBatchSize = 200
+
+type PayoutRepository struct {
+	db *gorm.DB
+}
+
+func NewPayoutRepository(db *gorm.DB) *PayoutRepository {
+	return &PayoutRepository{db}
+}
+
+func (r *PayoutRepository) InsertBatch(ctx context.Context, batch *payout.Batch, items []*payout.Item) error {
+	db := r.getGormClient(ctx)
+
+	err := db.Create(Batch{}.FromServiceModel(batch)).Error
+	if err != nil {
+		return err
+	}
+
+	payload := []*Item{}
+	for _, item := range items {
+		payload = append(payload, Item{}.FromServiceModel(item))
+	}
+	err = db.CreateInBatches(payload, insertBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) FindBatchById(ctx context.Context, id string) (*payout.Batch, error) {
+	return r.findBatchById(r.getGormClient(ctx), id)
+}
+
+func (r *PayoutRepository) FindBatchByIdForUpdate(ctx context.Context, id string) (*payout.Batch, error) {
+	return r.findBatchById(r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *PayoutRepository) FindUnfinishedBatchIds(ctx context.Context, limit int) ([]string, error) {
+	ids := []string{}
+
+	err := r.getGormClient(ctx).Model(&Batch{}).
+		Where("status IN ?", []string{payout.BATCH_STATUS_PENDING, payout.BATCH_STATUS_PROCESSING}).
+		Order("created_at, id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *PayoutRepository) UpdateBatch(ctx context.Context, data *payout.Batch) error {
+	payload := Batch{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) FindItemsByBatchId(ctx context.Context, batchId string) ([]*payout.Item, error) {
+	items := []*Item{}
+
+	err := r.getGormClient(ctx).Where("batch_id = ?", batchId).Order("line, id").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ItemsToServiceModel(items), nil
+}
+
+func (r *PayoutRepository) FindPendingItemIds(ctx context.Context, batchId string, limit int) ([]string, error) {
+	ids := []string{}
+
+	err := r.getGormClient(ctx).Model(&Item{}).
+		Where("batch_id = ? AND status = ?", batchId, payout.ITEM_STATUS_PENDING).
+		Order("line, id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *PayoutRepository) FindItemByIdForUpdate(ctx context.Context, id string) (*payout.Item, error) {
+	i := &Item{}
+
+	err := r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&i).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return i.ToServiceModel(), nil
+}
+
+func (r *PayoutRepository) UpdateItem(ctx context.Context, data *payout.Item) error {
+	payload := Item{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) findBatchById(db *gorm.DB, id string) (*payout.Batch, error) {
+	b := &Batch{}
+
+	err := db.Where("id = ?", id).First(&b).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return b.ToServiceModel(), nil
+}
+
+func (r *PayoutRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
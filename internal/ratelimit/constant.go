@@ -0,0 +1,18 @@
+package ratelimit
+
+import "time"
+
+// Route groups sharing the same limit
+const (
+	GROUP_INIT  = "init"
+	GROUP_READ  = "read"
+	GROUP_WRITE = "write"
+)
+
+var DEFAULT_LIMITS = map[string]Limit{
+	GROUP_INIT:  {Requests: 10, Period: time.Minute},
+	GROUP_READ:  {Requests: 300, Period: time.Minute},
+	GROUP_WRITE: {Requests: 60, Period: time.Minute},
+}
+
+const SWEEP_INTERVAL = time.Minute
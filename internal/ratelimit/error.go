@@ -0,0 +1,9 @@
+package ratelimit
+
+import (
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrRateLimited = errors.NewTooManyRequestsError("too many requests, try again later")
+var ErrInvalidLimit = errors.NewValidationError("limit must be formatted as {requests}/{period}, e.g. 60/1m")
+var ErrUnknownGroup = errors.NewValidationError("rate limit group unknown")
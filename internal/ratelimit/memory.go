@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	period    time.Duration
+}
+
+// MemoryStore holds the buckets in memory of the current process,
+// so every instance limits the requests it receives on its own
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	nextSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	rate := limit.Rate()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.period = limit.Period
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+
+	result := &Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = secondsToDuration((capacity - b.tokens) / rate)
+
+	return result, nil
+}
+
+// Remove the buckets idle for longer than their period, they are full by then
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) > b.period {
+			delete(s.buckets, key)
+		}
+	}
+	s.nextSweep = now.Add(SWEEP_INTERVAL)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
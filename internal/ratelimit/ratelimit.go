@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows the given number of requests per period.
+// The bucket holds up to Requests tokens and refills continuously over the period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Result of taking a token from the bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Time until the next token is available, zero if allowed
+	RetryAfter time.Duration
+	// Time until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// Store holds the token buckets, shared stores allow the limits to hold across instances
+type Store interface {
+	// Take a token from the bucket of the key, refilled according to the limit
+	Take(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// Limiter applies the limit of the route group to the buckets of the store
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+}
+
+func NewLimiter(store Store, limits map[string]Limit) *Limiter {
+	return &Limiter{store, limits}
+}
+
+// Take a token from the bucket of the key in the route group
+func (l *Limiter) Allow(ctx context.Context, group, key string) (*Result, error) {
+	limit, ok := l.limits[group]
+	if !ok {
+		return nil, ErrUnknownGroup
+	}
+
+	return l.store.Take(ctx, group+":"+key, limit)
+}
+
+// Return the refill rate in tokens per second
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Parse the limit formatted as {requests}/{period}, e.g. "60/1m"
+func ParseLimit(value string) (Limit, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
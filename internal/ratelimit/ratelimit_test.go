@@ -0,0 +1,86 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	t.Run("should parse requests per period", func(t *testing.T) {
+		limit, err := ratelimit.ParseLimit("60/1m")
+		assert.Nil(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 60, Period: time.Minute}, limit)
+		assert.Equal(t, float64(1), limit.Rate())
+	})
+	t.Run("should return error if limit malformed", func(t *testing.T) {
+		for _, value := range []string{"", "60", "60/", "a/1m", "0/1m", "60/0s", "60/1m/1"} {
+			_, err := ratelimit.ParseLimit(value)
+			assert.Equal(t, ratelimit.ErrInvalidLimit, err, value)
+		}
+	})
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limits := map[string]ratelimit.Limit{
+		ratelimit.GROUP_WRITE: {Requests: 2, Period: time.Hour},
+	}
+
+	t.Run("should return error if group unknown", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), limits)
+
+		res, err := limiter.Allow(context.TODO(), ratelimit.GROUP_READ, "client:xid")
+		assert.Equal(t, ratelimit.ErrUnknownGroup, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should reject requests once the bucket is empty", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), limits)
+
+		res, err := limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:xid")
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2, res.Limit)
+		assert.Equal(t, 1, res.Remaining)
+
+		res, err = limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:xid")
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+
+		res, err = limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:xid")
+		assert.Nil(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.InDelta(t, (30 * time.Minute).Seconds(), res.RetryAfter.Seconds(), 1)
+		assert.InDelta(t, time.Hour.Seconds(), res.ResetAfter.Seconds(), 1)
+	})
+	t.Run("should keep separate buckets per key", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), limits)
+
+		limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:xid")
+		limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:xid")
+
+		res, err := limiter.Allow(context.TODO(), ratelimit.GROUP_WRITE, "client:other")
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+	})
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Run("should refill the bucket over the period", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 1, Period: 20 * time.Millisecond}
+
+		res, _ := store.Take(context.TODO(), "key", limit)
+		assert.True(t, res.Allowed)
+		res, _ = store.Take(context.TODO(), "key", limit)
+		assert.False(t, res.Allowed)
+
+		time.Sleep(25 * time.Millisecond)
+		res, _ = store.Take(context.TODO(), "key", limit)
+		assert.True(t, res.Allowed)
+	})
+}
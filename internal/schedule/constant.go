@@ -0,0 +1,36 @@
+package schedule
+
+import "time"
+
+const (
+	STATUS_ACTIVE = "active"
+	// No run is left, the last occurrence ran or the rule has no occurrence left
+	STATUS_COMPLETED = "completed"
+	STATUS_CANCELLED = "cancelled"
+)
+
+const (
+	RUN_STATUS_SUCCESS = "success"
+	RUN_STATUS_FAILED  = "failed"
+)
+
+const (
+	FREQ_DAILY   = "DAILY"
+	FREQ_WEEKLY  = "WEEKLY"
+	FREQ_MONTHLY = "MONTHLY"
+)
+
+const (
+	MAX_RULE_INTERVAL = 52
+	// Days searched for the next occurrence of a rule, long enough for the rules occurring once every few years
+	MAX_RULE_SCAN_DAYS = 5 * 366
+)
+
+const MAX_SCHEDULES_PER_WALLET = 50
+
+const (
+	RUN_BATCH_SIZE = 50
+	RUN_INTERVAL   = 30 * time.Second
+	// Length the error of a failed run is cut to
+	MAX_ERROR_LENGTH = 500
+)
@@ -0,0 +1,22 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyTargetWalletId = errors.NewValidationError("target wallet id is required")
+var ErrNonPositiveAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrSameWallet = errors.NewValidationError("target wallet must differ from the source wallet")
+var ErrCurrencyMismatch = errors.NewValidationError("scheduled transfers require wallets of the same currency")
+var ErrStartInPast = errors.NewValidationError("start_at must be in the future")
+var ErrNoOccurrence = errors.NewValidationError("rule has no occurrence")
+var ErrTooManySchedules = errors.NewValidationError(fmt.Sprintf("a wallet can have up to %d active schedules", MAX_SCHEDULES_PER_WALLET))
+var ErrScheduleNotFound = errors.NewNotFoundError("schedule not found")
+var ErrConfirmationRequired = errors.NewValidationError("transfer to another client above the confirmation threshold can't be scheduled")
+var ErrScheduleNotActive = errors.NewValidationError("schedule is no longer active")
+
+func ErrInvalidRule(reason string) errors.HandledError {
+	return errors.NewValidationError(fmt.Sprintf("invalid rule: %s", reason))
+}
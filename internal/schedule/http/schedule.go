@@ -0,0 +1,117 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateScheduleRequest struct {
+	ToWalletId string    `json:"to_wallet_id"`
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	Rule       string    `json:"rule"`
+	StartAt    time.Time `json:"start_at"`
+}
+
+func HandleCreateSchedule(service schedule.ScheduleIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateScheduleRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.CreateSchedule(r.Context(), &schedule.CreateScheduleParams{
+			CustomerXid:    currentClient.Xid,
+			WalletId:       chi.URLParam(r, "wallet_id"),
+			TargetWalletId: requestBody.ToWalletId,
+			Amount:         requestBody.Amount,
+			Currency:       requestBody.Currency,
+			Rule:           requestBody.Rule,
+			StartAt:        requestBody.StartAt,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"schedule": result,
+		})
+	}
+}
+
+func HandleGetSchedules(service schedule.ScheduleIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		schedules, err := service.GetSchedules(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"schedules": schedules,
+		})
+	}
+}
+
+func HandleGetSchedule(service schedule.ScheduleIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.GetSchedule(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"schedule": result,
+		})
+	}
+}
+
+func HandleCancelSchedule(service schedule.ScheduleIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.CancelSchedule(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"schedule": result,
+		})
+	}
+}
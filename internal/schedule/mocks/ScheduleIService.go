@@ -0,0 +1,158 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	schedule "github.com/defryheryanto/mini-wallet/internal/schedule"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScheduleIService is an autogenerated mock type for the ScheduleIService type
+type ScheduleIService struct {
+	mock.Mock
+}
+
+// CancelSchedule provides a mock function with given fields: ctx, customerXid, walletId, id
+func (_m *ScheduleIService) CancelSchedule(ctx context.Context, customerXid string, walletId string, id string) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, customerXid, walletId, id)
+
+	var r0 *schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*schedule.Schedule, error)); ok {
+		return rf(ctx, customerXid, walletId, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *schedule.Schedule); ok {
+		r0 = rf(ctx, customerXid, walletId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSchedule provides a mock function with given fields: ctx, params
+func (_m *ScheduleIService) CreateSchedule(ctx context.Context, params *schedule.CreateScheduleParams) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *schedule.CreateScheduleParams) (*schedule.Schedule, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *schedule.CreateScheduleParams) *schedule.Schedule); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *schedule.CreateScheduleParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedule provides a mock function with given fields: ctx, customerXid, walletId, id
+func (_m *ScheduleIService) GetSchedule(ctx context.Context, customerXid string, walletId string, id string) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, customerXid, walletId, id)
+
+	var r0 *schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*schedule.Schedule, error)); ok {
+		return rf(ctx, customerXid, walletId, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *schedule.Schedule); ok {
+		r0 = rf(ctx, customerXid, walletId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedules provides a mock function with given fields: ctx, customerXid, walletId
+func (_m *ScheduleIService) GetSchedules(ctx context.Context, customerXid string, walletId string) ([]*schedule.Schedule, error) {
+	ret := _m.Called(ctx, customerXid, walletId)
+
+	var r0 []*schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*schedule.Schedule, error)); ok {
+		return rf(ctx, customerXid, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*schedule.Schedule); ok {
+		r0 = rf(ctx, customerXid, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunDue provides a mock function with given fields: ctx
+func (_m *ScheduleIService) RunDue(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewScheduleIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewScheduleIService creates a new instance of ScheduleIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewScheduleIService(t mockConstructorTestingTNewScheduleIService) *ScheduleIService {
+	mock := &ScheduleIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	schedule "github.com/defryheryanto/mini-wallet/internal/schedule"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ScheduleRepository is an autogenerated mock type for the ScheduleRepository type
+type ScheduleRepository struct {
+	mock.Mock
+}
+
+// CountActiveByWalletId provides a mock function with given fields: ctx, walletId
+func (_m *ScheduleRepository) CountActiveByWalletId(ctx context.Context, walletId string) (int64, error) {
+	ret := _m.Called(ctx, walletId)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, walletId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllByWalletId provides a mock function with given fields: ctx, walletId
+func (_m *ScheduleRepository) FindAllByWalletId(ctx context.Context, walletId string) ([]*schedule.Schedule, error) {
+	ret := _m.Called(ctx, walletId)
+
+	var r0 []*schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*schedule.Schedule, error)); ok {
+		return rf(ctx, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*schedule.Schedule); ok {
+		r0 = rf(ctx, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *ScheduleRepository) FindById(ctx context.Context, id string) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*schedule.Schedule, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *schedule.Schedule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *ScheduleRepository) FindByIdForUpdate(ctx context.Context, id string) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *schedule.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*schedule.Schedule, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *schedule.Schedule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDueIds provides a mock function with given fields: ctx, now, limit
+func (_m *ScheduleRepository) FindDueIds(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]string, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []string); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *ScheduleRepository) Insert(ctx context.Context, data *schedule.Schedule) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *schedule.Schedule) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *ScheduleRepository) Update(ctx context.Context, data *schedule.Schedule) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *schedule.Schedule) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewScheduleRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewScheduleRepository creates a new instance of ScheduleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewScheduleRepository(t mockConstructorTestingTNewScheduleRepository) *ScheduleRepository {
+	mock := &ScheduleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
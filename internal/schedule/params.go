@@ -0,0 +1,18 @@
+package schedule
+
+import "time"
+
+type CreateScheduleParams struct {
+	CustomerXid string `json:"customer_xid"`
+	// Wallet of the customer the amount is taken from, the default wallet of the customer if empty
+	WalletId string `json:"wallet_id"`
+	// Wallet receiving the amount, owned by any client
+	TargetWalletId string  `json:"target_wallet_id"`
+	Amount         float64 `json:"amount"`
+	// Has to match the currency of the source wallet, assumed if empty
+	Currency string `json:"currency"`
+	// Recurrence of the transfer, e.g. "FREQ=WEEKLY;BYDAY=MO". Runs once at StartAt if empty
+	Rule string `json:"rule"`
+	// First possible run, the time of day of the runs
+	StartAt time.Time `json:"start_at"`
+}
@@ -0,0 +1,92 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+)
+
+type Schedule struct {
+	Id                string     `gorm:"primaryKey;column:id"`
+	ClientXid         string     `gorm:"column:client_xid"`
+	WalletId          string     `gorm:"column:wallet_id"`
+	TargetWalletId    string     `gorm:"column:target_wallet_id"`
+	Amount            float64    `gorm:"column:amount"`
+	Currency          string     `gorm:"column:currency"`
+	Rule              string     `gorm:"column:rule"`
+	StartAt           time.Time  `gorm:"column:start_at"`
+	Status            string     `gorm:"column:status"`
+	NextRunAt         *time.Time `gorm:"column:next_run_at"`
+	RunCount          int        `gorm:"column:run_count"`
+	LastRunAt         *time.Time `gorm:"column:last_run_at"`
+	LastRunStatus     string     `gorm:"column:last_run_status"`
+	LastRunError      string     `gorm:"column:last_run_error"`
+	LastTransactionId string     `gorm:"column:last_transaction_id"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+}
+
+func (Schedule) TableName() string {
+	return "transfer_schedules"
+}
+
+func (Schedule) FromServiceModel(data *schedule.Schedule) *Schedule {
+	if data == nil {
+		return nil
+	}
+
+	return &Schedule{
+		Id:                data.Id,
+		ClientXid:         data.ClientXid,
+		WalletId:          data.WalletId,
+		TargetWalletId:    data.TargetWalletId,
+		Amount:            data.Amount,
+		Currency:          data.Currency,
+		Rule:              data.Rule,
+		StartAt:           data.StartAt,
+		Status:            data.Status,
+		NextRunAt:         data.NextRunAt,
+		RunCount:          data.RunCount,
+		LastRunAt:         data.LastRunAt,
+		LastRunStatus:     data.LastRunStatus,
+		LastRunError:      data.LastRunError,
+		LastTransactionId: data.LastTransactionId,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
+
+func (s *Schedule) ToServiceModel() *schedule.Schedule {
+	return &schedule.Schedule{
+		Id:                s.Id,
+		ClientXid:         s.ClientXid,
+		WalletId:          s.WalletId,
+		TargetWalletId:    s.TargetWalletId,
+		Amount:            s.Amount,
+		Currency:          s.Currency,
+		Rule:              s.Rule,
+		StartAt:           s.StartAt,
+		Status:            s.Status,
+		NextRunAt:         s.NextRunAt,
+		RunCount:          s.RunCount,
+		LastRunAt:         s.LastRunAt,
+		LastRunStatus:     s.LastRunStatus,
+		LastRunError:      s.LastRunError,
+		LastTransactionId: s.LastTransactionId,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+	}
+}
+
+func SchedulesToServiceModel(data []*Schedule) []*schedule.Schedule {
+	if data == nil {
+		return nil
+	}
+
+	schedules := []*schedule.Schedule{}
+	for _, s := range data {
+		schedules = append(schedules, s.ToServiceModel())
+	}
+
+	return schedules
+}
@@ -0,0 +1,111 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db}
+}
+
+func (r *ScheduleRepository) Insert(ctx context.Context, data *schedule.Schedule) error {
+	payload := Schedule{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) FindById(ctx context.Context, id string) (*schedule.Schedule, error) {
+	return r.findById(r.getGormClient(ctx), id)
+}
+
+func (r *ScheduleRepository) FindByIdForUpdate(ctx context.Context, id string) (*schedule.Schedule, error) {
+	return r.findById(r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *ScheduleRepository) FindAllByWalletId(ctx context.Context, walletId string) ([]*schedule.Schedule, error) {
+	schedules := []*Schedule{}
+
+	err := r.getGormClient(ctx).Where("wallet_id = ?", walletId).Order("created_at DESC, id").Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SchedulesToServiceModel(schedules), nil
+}
+
+func (r *ScheduleRepository) CountActiveByWalletId(ctx context.Context, walletId string) (int64, error) {
+	var count int64
+
+	err := r.getGormClient(ctx).Model(&Schedule{}).
+		Where("wallet_id = ? AND status = ?", walletId, schedule.STATUS_ACTIVE).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *ScheduleRepository) FindDueIds(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ids := []string{}
+
+	err := r.getGormClient(ctx).Model(&Schedule{}).
+		Where("status = ? AND next_run_at <= ?", schedule.STATUS_ACTIVE, now).
+		Order("next_run_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *ScheduleRepository) Update(ctx context.Context, data *schedule.Schedule) error {
+	payload := Schedule{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) findById(db *gorm.DB, id string) (*schedule.Schedule, error) {
+	s := &Schedule{}
+
+	err := db.Where("id = ?", id).First(&s).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.ToServiceModel(), nil
+}
+
+func (r *ScheduleRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
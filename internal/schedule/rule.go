@@ -0,0 +1,189 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is the recurrence of a schedule, a subset of the iCalendar RRULE (RFC 5545).
+// Occurrences are computed in UTC at the time of day of the start of the schedule
+type Rule struct {
+	Freq string
+	// Runs every Interval days, weeks or months
+	Interval int
+	// Days of the week of the weekly rules, the weekday of the start if empty
+	ByDay []time.Weekday
+	// Day of the month of the monthly rules, the day of the start if zero. Months without the day are skipped
+	ByMonthDay int
+	// Number of runs, unlimited if zero
+	Count int
+	// No run happens after Until if set
+	Until *time.Time
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse the rule formatted as RRULE parts, e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10".
+// FREQ is one of DAILY, WEEKLY and MONTHLY, and UNTIL is formatted as 20060102T150405Z or 20060102
+func ParseRule(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, partValue, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, ErrInvalidRule(fmt.Sprintf("%q is not formatted as NAME=VALUE", part))
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(partValue)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(partValue)
+			if err == nil && (rule.Interval < 1 || rule.Interval > MAX_RULE_INTERVAL) {
+				err = fmt.Errorf("out of range")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(partValue, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					err = fmt.Errorf("unknown day %q", day)
+					break
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = strconv.Atoi(partValue)
+			if err == nil && (rule.ByMonthDay < 1 || rule.ByMonthDay > 31) {
+				err = fmt.Errorf("out of range")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(partValue)
+			if err == nil && rule.Count < 1 {
+				err = fmt.Errorf("out of range")
+			}
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(partValue)
+			rule.Until = &until
+		default:
+			return nil, ErrInvalidRule(fmt.Sprintf("unsupported part %s", name))
+		}
+		if err != nil {
+			return nil, ErrInvalidRule(fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	switch {
+	case rule.Freq != FREQ_DAILY && rule.Freq != FREQ_WEEKLY && rule.Freq != FREQ_MONTHLY:
+		return nil, ErrInvalidRule("FREQ must be DAILY, WEEKLY or MONTHLY")
+	case len(rule.ByDay) > 0 && rule.Freq != FREQ_WEEKLY:
+		return nil, ErrInvalidRule("BYDAY is only supported by WEEKLY rules")
+	case rule.ByMonthDay != 0 && rule.Freq != FREQ_MONTHLY:
+		return nil, ErrInvalidRule("BYMONTHDAY is only supported by MONTHLY rules")
+	case rule.Count != 0 && rule.Until != nil:
+		return nil, ErrInvalidRule("COUNT and UNTIL can't be combined")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	until, err := time.Parse("20060102T150405Z", value)
+	if err == nil {
+		return until, nil
+	}
+
+	// A date includes the whole day
+	until, err = time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not formatted as 20060102T150405Z or 20060102")
+	}
+	return until.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// Return the first occurrence of the rule starting at start strictly after the given time,
+// false if the rule has no occurrence left. Count is left to the caller, who knows the number of runs
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	start = start.UTC()
+	startDate := truncateDay(start)
+	timeOfDay := start.Sub(startDate)
+
+	day := startDate
+	if afterDate := truncateDay(after.UTC()); afterDate.After(day) {
+		day = afterDate
+	}
+
+	for i := 0; i < MAX_RULE_SCAN_DAYS; i, day = i+1, day.AddDate(0, 0, 1) {
+		occurrence := day.Add(timeOfDay)
+		if !occurrence.After(after) || !r.matches(startDate, day) {
+			continue
+		}
+		if r.Until != nil && occurrence.After(*r.Until) {
+			return time.Time{}, false
+		}
+
+		return occurrence, true
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) matches(startDate, day time.Time) bool {
+	switch r.Freq {
+	case FREQ_DAILY:
+		return daysBetween(startDate, day)%r.Interval == 0
+	case FREQ_WEEKLY:
+		weeks := daysBetween(weekStart(startDate), weekStart(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == startDate.Weekday()
+		}
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case FREQ_MONTHLY:
+		months := (day.Year()-startDate.Year())*12 + int(day.Month()-startDate.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if r.ByMonthDay == 0 {
+			return day.Day() == startDate.Day()
+		}
+		return day.Day() == r.ByMonthDay
+	default:
+		return false
+	}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours()) / 24
+}
+
+// Return the Monday starting the week of the day, weeks start on Monday as in RRULE by default
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
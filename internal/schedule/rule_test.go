@@ -0,0 +1,91 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Run("should parse the rule", func(t *testing.T) {
+		rule, err := schedule.ParseRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10")
+		assert.Nil(t, err)
+		assert.Equal(t, &schedule.Rule{
+			Freq:     schedule.FREQ_WEEKLY,
+			Interval: 2,
+			ByDay:    []time.Weekday{time.Monday, time.Friday},
+			Count:    10,
+		}, rule)
+	})
+
+	t.Run("should include the whole day of a date until", func(t *testing.T) {
+		rule, err := schedule.ParseRule("FREQ=DAILY;UNTIL=20240131")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2024, 1, 31, 23, 59, 59, 999_999_999, time.UTC), *rule.Until)
+	})
+
+	t.Run("should return error if rule is invalid", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"FREQ=YEARLY",
+			"FREQ=DAILY;INTERVAL=0",
+			"FREQ=DAILY;BYDAY=MO",
+			"FREQ=WEEKLY;BYDAY=XX",
+			"FREQ=WEEKLY;BYMONTHDAY=1",
+			"FREQ=MONTHLY;BYMONTHDAY=32",
+			"FREQ=DAILY;COUNT=2;UNTIL=20240131",
+			"FREQ=DAILY;BYHOUR=1",
+			"FREQ",
+		} {
+			_, err := schedule.ParseRule(value)
+			assert.NotNil(t, err, value)
+		}
+	})
+}
+
+func TestRule_Next(t *testing.T) {
+	// Wednesday
+	start := time.Date(2024, 1, 3, 9, 30, 0, 0, time.UTC)
+
+	next := func(t *testing.T, value string, after time.Time) time.Time {
+		rule, err := schedule.ParseRule(value)
+		assert.Nil(t, err)
+
+		occurrence, ok := rule.Next(start, after)
+		assert.True(t, ok)
+		return occurrence
+	}
+
+	t.Run("should start at the start if it occurs", func(t *testing.T) {
+		assert.Equal(t, start, next(t, "FREQ=DAILY", start.Add(-time.Nanosecond)))
+	})
+
+	t.Run("should return the occurrence after the given time", func(t *testing.T) {
+		assert.Equal(t, time.Date(2024, 1, 6, 9, 30, 0, 0, time.UTC), next(t, "FREQ=DAILY;INTERVAL=3", start))
+		assert.Equal(t, time.Date(2024, 1, 9, 9, 30, 0, 0, time.UTC), next(t, "FREQ=DAILY;INTERVAL=3", time.Date(2024, 1, 6, 9, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("should occur on the given days of every other week", func(t *testing.T) {
+		// Friday of the first week, then Monday two weeks after the start
+		assert.Equal(t, time.Date(2024, 1, 5, 9, 30, 0, 0, time.UTC), next(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start))
+		assert.Equal(t, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), next(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", time.Date(2024, 1, 5, 9, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("should occur on the weekday of the start without days", func(t *testing.T) {
+		assert.Equal(t, time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC), next(t, "FREQ=WEEKLY", start))
+	})
+
+	t.Run("should skip the months without the day", func(t *testing.T) {
+		assert.Equal(t, time.Date(2024, 3, 31, 9, 30, 0, 0, time.UTC), next(t, "FREQ=MONTHLY;BYMONTHDAY=31", time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("should have no occurrence after until", func(t *testing.T) {
+		rule, err := schedule.ParseRule("FREQ=MONTHLY;UNTIL=20240131")
+		assert.Nil(t, err)
+
+		_, ok := rule.Next(start, start)
+		assert.False(t, ok)
+	})
+}
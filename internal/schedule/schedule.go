@@ -0,0 +1,409 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/google/uuid"
+)
+
+// Schedule transfers an amount between wallets at the occurrences of its rule, or once at its start without rule
+type Schedule struct {
+	Id             string  `json:"id"`
+	ClientXid      string  `json:"client_xid"`
+	WalletId       string  `json:"wallet_id"`
+	TargetWalletId string  `json:"target_wallet_id"`
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	// RRULE recurrence, empty for the schedules running once
+	Rule    string    `json:"rule"`
+	StartAt time.Time `json:"start_at"`
+	Status  string    `json:"status"`
+	// Nil once the schedule is no longer active
+	NextRunAt *time.Time `json:"next_run_at"`
+	RunCount  int        `json:"run_count"`
+	// Occurrence the last run was made for
+	LastRunAt     *time.Time `json:"last_run_at"`
+	LastRunStatus string     `json:"last_run_status"`
+	LastRunError  string     `json:"last_run_error"`
+	// Outgoing transaction of the last successful run
+	LastTransactionId string    `json:"last_transaction_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ScheduleRepository interface {
+	Insert(ctx context.Context, data *Schedule) error
+	FindById(ctx context.Context, id string) (*Schedule, error)
+	// Find the schedule and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*Schedule, error)
+	// Return the schedules of the wallet, the latest first
+	FindAllByWalletId(ctx context.Context, walletId string) ([]*Schedule, error)
+	CountActiveByWalletId(ctx context.Context, walletId string) (int64, error)
+	// Return the ids of up to limit active schedules due at the given time, the earliest first
+	FindDueIds(ctx context.Context, now time.Time, limit int) ([]string, error)
+	Update(ctx context.Context, data *Schedule) error
+}
+
+type ScheduleIService interface {
+	CreateSchedule(ctx context.Context, params *CreateScheduleParams) (*Schedule, error)
+	GetSchedules(ctx context.Context, customerXid, walletId string) ([]*Schedule, error)
+	GetSchedule(ctx context.Context, customerXid, walletId, id string) (*Schedule, error)
+	CancelSchedule(ctx context.Context, customerXid, walletId, id string) (*Schedule, error)
+	RunDue(ctx context.Context) (int, error)
+}
+
+type ScheduleService struct {
+	repository          ScheduleRepository
+	walletService       wallet.WalletIService
+	transactionService  transaction.TransactionIService
+	confirmationService transaction.WithdrawalConfirmationIService
+	storageManager      manager.StorageManager
+}
+
+func NewScheduleService(
+	repository ScheduleRepository,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+	confirmationService transaction.WithdrawalConfirmationIService,
+	storageManager manager.StorageManager,
+) *ScheduleService {
+	return &ScheduleService{repository, walletService, transactionService, confirmationService, storageManager}
+}
+
+// Schedule the transfer from the given wallet of the customer, or from its default wallet if the wallet id is empty.
+// The schedule starts now if no start is given
+func (s *ScheduleService) CreateSchedule(ctx context.Context, params *CreateScheduleParams) (*Schedule, error) {
+	if params.TargetWalletId == "" {
+		return nil, ErrEmptyTargetWalletId
+	}
+	if params.Amount <= 0 {
+		return nil, ErrNonPositiveAmount
+	}
+
+	now := time.Now()
+	startAt := params.StartAt
+	if startAt.IsZero() {
+		startAt = now
+	}
+	if startAt.Before(now.Add(-time.Minute)) {
+		return nil, ErrStartInPast
+	}
+	startAt = startAt.UTC().Truncate(time.Second)
+
+	nextRunAt := startAt
+	if params.Rule != "" {
+		rule, err := ParseRule(params.Rule)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		nextRunAt, ok = rule.Next(startAt, startAt.Add(-time.Nanosecond))
+		if !ok {
+			return nil, ErrNoOccurrence
+		}
+	}
+
+	sourceWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.walletService.ValidateWallet(sourceWallet); err != nil {
+		return nil, err
+	}
+	if err = sourceWallet.ValidateAmount(params.Amount, params.Currency); err != nil {
+		return nil, err
+	}
+	if sourceWallet.Id == params.TargetWalletId {
+		return nil, ErrSameWallet
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, params.TargetWalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.walletService.ValidateDeposit(targetWallet); err != nil {
+		return nil, err
+	}
+	if targetWallet.Currency != sourceWallet.Currency {
+		return nil, ErrCurrencyMismatch
+	}
+	if targetWallet.OwnedBy != params.CustomerXid {
+		err = s.validateConfirmation(ctx, params.CustomerXid, params.Amount, sourceWallet.Currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	active, err := s.repository.CountActiveByWalletId(ctx, sourceWallet.Id)
+	if err != nil {
+		return nil, err
+	}
+	if active >= MAX_SCHEDULES_PER_WALLET {
+		return nil, ErrTooManySchedules
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &Schedule{
+		Id:             uuidRandom.String(),
+		ClientXid:      params.CustomerXid,
+		WalletId:       sourceWallet.Id,
+		TargetWalletId: targetWallet.Id,
+		Amount:         params.Amount,
+		Currency:       sourceWallet.Currency,
+		Rule:           params.Rule,
+		StartAt:        startAt,
+		Status:         STATUS_ACTIVE,
+		NextRunAt:      &nextRunAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err = s.repository.Insert(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Return the schedules of the given wallet of the customer, or of its default wallet if the wallet id is empty
+func (s *ScheduleService) GetSchedules(ctx context.Context, customerXid, walletId string) ([]*Schedule, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindAllByWalletId(ctx, targetWallet.Id)
+}
+
+// Return the schedule if it belongs to the given wallet of the customer, or to its default wallet if the wallet id is empty
+func (s *ScheduleService) GetSchedule(ctx context.Context, customerXid, walletId, id string) (*Schedule, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOwned(ctx, targetWallet.Id, id, s.repository.FindById)
+}
+
+// Stop the active schedule, a run already started completes
+func (s *ScheduleService) CancelSchedule(ctx context.Context, customerXid, walletId, id string) (*Schedule, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule *Schedule
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		schedule, err = s.findOwned(ctx, targetWallet.Id, id, s.repository.FindByIdForUpdate)
+		if err != nil {
+			return err
+		}
+		if schedule.Status != STATUS_ACTIVE {
+			return ErrScheduleNotActive
+		}
+
+		schedule.Status = STATUS_CANCELLED
+		schedule.NextRunAt = nil
+		schedule.UpdatedAt = time.Now()
+		return s.repository.Update(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Run the due schedules one after another. A schedule failing to run doesn't stop the others.
+//
+// Return the number of schedules run, including the failed runs recorded
+func (s *ScheduleService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := s.repository.FindDueIds(ctx, now, RUN_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := logging.FromContext(ctx)
+	ran := 0
+	for _, id := range ids {
+		err := s.run(ctx, id, now)
+		if err != nil {
+			logger.Error("error running schedule", "schedule_id", id, logging.KEY_ERROR, err)
+			continue
+		}
+		ran++
+	}
+
+	return ran, nil
+}
+
+// Transfer the amount of the due schedule and record the run in the same database transaction,
+// so a run is never made twice even if the instance stops in between. The schedule is locked during the run
+// and skipped if it is no longer due, e.g. run by another instance.
+//
+// A transfer rejected by the wallets, e.g. for insufficient balance, is recorded as a failed run and not retried,
+// like a transfer to a wallet of another client the customer would have to confirm since enrolling to TOTP.
+// Other errors are returned, the run is retried on the next pass
+func (s *ScheduleService) run(ctx context.Context, id string, now time.Time) error {
+	var runErr error
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		schedule, err := s.repository.FindByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !schedule.isDue(now) {
+			return nil
+		}
+
+		err = s.validateRun(ctx, schedule)
+		if err != nil {
+			if _, rejected := err.(errors.HandledError); rejected {
+				runErr = err
+			}
+			return err
+		}
+
+		outgoing, _, err := s.transactionService.CreateTransfer(ctx, &transaction.CreateTransferParams{
+			CustomerXid:    schedule.ClientXid,
+			WalletId:       schedule.WalletId,
+			TargetWalletId: schedule.TargetWalletId,
+			ReferenceId:    schedule.runReferenceId(),
+			Amount:         schedule.Amount,
+			Currency:       schedule.Currency,
+		})
+		if err != nil {
+			runErr = err
+			return err
+		}
+
+		schedule.recordRun(now, outgoing.Id, nil)
+		return s.repository.Update(ctx, schedule)
+	})
+	if runErr == nil {
+		return err
+	}
+	if _, rejected := runErr.(errors.HandledError); !rejected {
+		return runErr
+	}
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		schedule, err := s.repository.FindByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !schedule.isDue(now) {
+			return nil
+		}
+
+		schedule.recordRun(now, "", runErr)
+		return s.repository.Update(ctx, schedule)
+	})
+}
+
+// Reject the run of a transfer to a wallet of another client the customer has to confirm
+func (s *ScheduleService) validateRun(ctx context.Context, schedule *Schedule) error {
+	required, err := s.confirmationService.RequiresConfirmation(ctx, schedule.ClientXid, schedule.Amount, schedule.Currency)
+	if err != nil || !required {
+		return err
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, schedule.TargetWalletId)
+	if err != nil {
+		return err
+	}
+	if targetWallet.OwnedBy != schedule.ClientXid {
+		return ErrConfirmationRequired
+	}
+
+	return nil
+}
+
+// Reject the transfers the customer would have to confirm with its TOTP code, which a schedule can't do
+func (s *ScheduleService) validateConfirmation(ctx context.Context, customerXid string, amount float64, currency string) error {
+	required, err := s.confirmationService.RequiresConfirmation(ctx, customerXid, amount, currency)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrConfirmationRequired
+	}
+
+	return nil
+}
+
+func (s *ScheduleService) findOwned(
+	ctx context.Context,
+	walletId, id string,
+	find func(ctx context.Context, id string) (*Schedule, error),
+) (*Schedule, error) {
+	schedule, err := find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.WalletId != walletId {
+		return nil, ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+func (s *Schedule) isDue(now time.Time) bool {
+	return s != nil && s.Status == STATUS_ACTIVE && s.NextRunAt != nil && !s.NextRunAt.After(now)
+}
+
+// Reference of the transfer of the current occurrence, the same on every attempt of the occurrence
+func (s *Schedule) runReferenceId() string {
+	return fmt.Sprintf("schedule-%s-%d", s.Id, s.NextRunAt.Unix())
+}
+
+// Record the outcome of the run of the current occurrence and move to the next occurrence.
+// Occurrences missed while no instance was running are skipped, the schedule completes once no occurrence is left
+func (s *Schedule) recordRun(now time.Time, transactionId string, runErr error) {
+	runAt := *s.NextRunAt
+	s.RunCount++
+	s.LastRunAt = &runAt
+	s.LastRunStatus = RUN_STATUS_SUCCESS
+	s.LastRunError = ""
+	if runErr != nil {
+		s.LastRunStatus = RUN_STATUS_FAILED
+		s.LastRunError = runErr.Error()
+		if len(s.LastRunError) > MAX_ERROR_LENGTH {
+			s.LastRunError = s.LastRunError[:MAX_ERROR_LENGTH]
+		}
+	} else {
+		s.LastTransactionId = transactionId
+	}
+	s.UpdatedAt = now
+
+	s.NextRunAt = nil
+	s.Status = STATUS_COMPLETED
+	if s.Rule == "" {
+		return
+	}
+
+	rule, err := ParseRule(s.Rule)
+	if err != nil || (rule.Count > 0 && s.RunCount >= rule.Count) {
+		return
+	}
+	after := runAt
+	if now.After(after) {
+		after = now
+	}
+	if next, ok := rule.Next(s.StartAt, after); ok {
+		s.NextRunAt = &next
+		s.Status = STATUS_ACTIVE
+	}
+}
@@ -0,0 +1,247 @@
+package schedule_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	schedule_mock "github.com/defryheryanto/mini-wallet/internal/schedule/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type serviceMocks struct {
+	repository          *schedule_mock.ScheduleRepository
+	walletService       *wallet_mock.WalletIService
+	transactionService  *transaction_mock.TransactionIService
+	confirmationService *transaction_mock.WithdrawalConfirmationIService
+}
+
+func newService(t *testing.T) (*schedule.ScheduleService, *serviceMocks) {
+	mocks := &serviceMocks{
+		repository:          schedule_mock.NewScheduleRepository(t),
+		walletService:       wallet_mock.NewWalletIService(t),
+		transactionService:  transaction_mock.NewTransactionIService(t),
+		confirmationService: transaction_mock.NewWithdrawalConfirmationIService(t),
+	}
+
+	return schedule.NewScheduleService(mocks.repository, mocks.walletService, mocks.transactionService, mocks.confirmationService, &manager.MockStorageManager{}), mocks
+}
+
+func TestScheduleService_CreateSchedule(t *testing.T) {
+	sourceWallet := &wallet.Wallet{Id: "source-id", OwnedBy: "xid", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+	targetWallet := &wallet.Wallet{Id: "target-id", OwnedBy: "other", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+
+	t.Run("should return error if rule is invalid", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "target-id", Amount: 100, Rule: "FREQ=HOURLY"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return error if start is in the past", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "target-id", Amount: 100, StartAt: time.Now().Add(-time.Hour)})
+		assert.Equal(t, schedule.ErrStartInPast, err)
+	})
+
+	t.Run("should return error if currencies of the wallets differ", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "USD"}, nil)
+		mocks.walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+
+		_, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "target-id", Amount: 100})
+		assert.Equal(t, schedule.ErrCurrencyMismatch, err)
+	})
+
+	t.Run("should return error if transfer to another client has to be confirmed", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(targetWallet, nil)
+		mocks.walletService.On("ValidateDeposit", targetWallet).Return(nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(true, nil)
+
+		_, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "target-id", Amount: 100})
+		assert.Equal(t, schedule.ErrConfirmationRequired, err)
+	})
+
+	t.Run("should schedule transfer to own wallet without confirmation", func(t *testing.T) {
+		ownWallet := &wallet.Wallet{Id: "own-id", OwnedBy: "xid", Currency: "IDR", Status: wallet.STATUS_ENABLED}
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "own-id").Return(ownWallet, nil)
+		mocks.walletService.On("ValidateDeposit", ownWallet).Return(nil)
+		mocks.repository.On("CountActiveByWalletId", mock.Anything, "source-id").Return(int64(0), nil)
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+		created, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "own-id", Amount: 100})
+		assert.Nil(t, err)
+		assert.Equal(t, "own-id", created.TargetWalletId)
+	})
+
+	t.Run("should schedule the first occurrence of the rule", func(t *testing.T) {
+		startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(sourceWallet, nil)
+		mocks.walletService.On("ValidateWallet", sourceWallet).Return(nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(targetWallet, nil)
+		mocks.walletService.On("ValidateDeposit", targetWallet).Return(nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(false, nil)
+		mocks.repository.On("CountActiveByWalletId", mock.Anything, "source-id").Return(int64(0), nil)
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+		created, err := service.CreateSchedule(context.TODO(), &schedule.CreateScheduleParams{CustomerXid: "xid", TargetWalletId: "target-id", Amount: 100, Rule: "FREQ=DAILY", StartAt: startAt})
+		assert.Nil(t, err)
+		assert.Equal(t, schedule.STATUS_ACTIVE, created.Status)
+		assert.Equal(t, "source-id", created.WalletId)
+		assert.Equal(t, "IDR", created.Currency)
+		assert.Equal(t, startAt, *created.NextRunAt)
+	})
+}
+
+func TestScheduleService_CancelSchedule(t *testing.T) {
+	t.Run("should return error if schedule belongs to another wallet", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(&schedule.Schedule{Id: "schedule-id", WalletId: "other-id"}, nil)
+
+		_, err := service.CancelSchedule(context.TODO(), "xid", "", "schedule-id")
+		assert.Equal(t, schedule.ErrScheduleNotFound, err)
+	})
+
+	t.Run("should stop the schedule", func(t *testing.T) {
+		nextRunAt := time.Now()
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "xid", "").Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(&schedule.Schedule{Id: "schedule-id", WalletId: "wallet-id", Status: schedule.STATUS_ACTIVE, NextRunAt: &nextRunAt}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		cancelled, err := service.CancelSchedule(context.TODO(), "xid", "", "schedule-id")
+		assert.Nil(t, err)
+		assert.Equal(t, schedule.STATUS_CANCELLED, cancelled.Status)
+		assert.Nil(t, cancelled.NextRunAt)
+	})
+}
+
+func TestScheduleService_RunDue(t *testing.T) {
+	newDueSchedule := func(rule string) *schedule.Schedule {
+		startAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		return &schedule.Schedule{
+			Id:             "schedule-id",
+			ClientXid:      "xid",
+			WalletId:       "source-id",
+			TargetWalletId: "target-id",
+			Amount:         100,
+			Currency:       "IDR",
+			Rule:           rule,
+			StartAt:        startAt,
+			Status:         schedule.STATUS_ACTIVE,
+			NextRunAt:      &startAt,
+		}
+	}
+
+	t.Run("should transfer and move to the next occurrence", func(t *testing.T) {
+		due := newDueSchedule("FREQ=DAILY")
+		runAt := *due.NextRunAt
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindDueIds", mock.Anything, mock.Anything, schedule.RUN_BATCH_SIZE).Return([]string{"schedule-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(due, nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(false, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, &transaction.CreateTransferParams{
+			CustomerXid:    "xid",
+			WalletId:       "source-id",
+			TargetWalletId: "target-id",
+			ReferenceId:    fmt.Sprintf("schedule-schedule-id-%d", runAt.Unix()),
+			Amount:         100,
+			Currency:       "IDR",
+		}).Return(&transaction.Transaction{Id: "trx-id"}, &transaction.Transaction{}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*schedule.Schedule)
+			assert.Equal(t, schedule.STATUS_ACTIVE, updated.Status)
+			assert.Equal(t, schedule.RUN_STATUS_SUCCESS, updated.LastRunStatus)
+			assert.Equal(t, "trx-id", updated.LastTransactionId)
+			assert.Equal(t, 1, updated.RunCount)
+			assert.Equal(t, runAt, *updated.LastRunAt)
+			assert.Equal(t, runAt.AddDate(0, 0, 1), *updated.NextRunAt)
+		}).Return(nil)
+
+		ran, err := service.RunDue(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("should record the rejected transfer and complete the one-off schedule", func(t *testing.T) {
+		due := newDueSchedule("")
+
+		service, mocks := newService(t)
+		mocks.repository.On("FindDueIds", mock.Anything, mock.Anything, schedule.RUN_BATCH_SIZE).Return([]string{"schedule-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(due, nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(false, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, wallet.ErrInsufficientBalance)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*schedule.Schedule)
+			assert.Equal(t, schedule.STATUS_COMPLETED, updated.Status)
+			assert.Equal(t, schedule.RUN_STATUS_FAILED, updated.LastRunStatus)
+			assert.Equal(t, wallet.ErrInsufficientBalance.Error(), updated.LastRunError)
+			assert.Nil(t, updated.NextRunAt)
+		}).Return(nil).Once()
+
+		ran, err := service.RunDue(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("should record the run to another client the customer has to confirm", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindDueIds", mock.Anything, mock.Anything, schedule.RUN_BATCH_SIZE).Return([]string{"schedule-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(newDueSchedule("FREQ=DAILY"), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(true, nil)
+		mocks.walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", OwnedBy: "other"}, nil)
+		mocks.repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*schedule.Schedule)
+			assert.Equal(t, schedule.RUN_STATUS_FAILED, updated.LastRunStatus)
+			assert.Equal(t, schedule.ErrConfirmationRequired.Error(), updated.LastRunError)
+		}).Return(nil).Once()
+
+		ran, err := service.RunDue(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("should leave the schedule due if the transfer errored", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.repository.On("FindDueIds", mock.Anything, mock.Anything, schedule.RUN_BATCH_SIZE).Return([]string{"schedule-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(newDueSchedule(""), nil)
+		mocks.confirmationService.On("RequiresConfirmation", mock.Anything, "xid", float64(100), "IDR").Return(false, nil)
+		mocks.transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("mocked"))
+
+		ran, err := service.RunDue(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, ran)
+	})
+
+	t.Run("should skip the schedule already run by another instance", func(t *testing.T) {
+		nextRunAt := time.Now().Add(time.Hour)
+		service, mocks := newService(t)
+		mocks.repository.On("FindDueIds", mock.Anything, mock.Anything, schedule.RUN_BATCH_SIZE).Return([]string{"schedule-id"}, nil)
+		mocks.repository.On("FindByIdForUpdate", mock.Anything, "schedule-id").Return(&schedule.Schedule{Id: "schedule-id", Status: schedule.STATUS_ACTIVE, NextRunAt: &nextRunAt}, nil)
+
+		ran, err := service.RunDue(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ran)
+	})
+}
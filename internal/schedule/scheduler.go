@@ -0,0 +1,37 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Scheduler runs the due schedules in the background.
+// A schedule is locked while it runs, so every instance can run its own scheduler.
+// Shutdown waits for the schedules being run, schedules due in the meantime are run on the next start
+type Scheduler struct {
+	*lifecycle.Loop
+	service ScheduleIService
+}
+
+func NewScheduler(service ScheduleIService) *Scheduler {
+	s := &Scheduler{service: service}
+	s.Loop = lifecycle.NewLoop("schedule", s.runDue)
+	return s
+}
+
+// Run a batch of due schedules, the next batch follows right away unless none is left
+func (s *Scheduler) runDue(ctx context.Context) time.Duration {
+	ran, err := s.service.RunDue(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error running schedules", logging.KEY_ERROR, err)
+		return RUN_INTERVAL
+	}
+	if ran < RUN_BATCH_SIZE {
+		return RUN_INTERVAL
+	}
+
+	return 0
+}
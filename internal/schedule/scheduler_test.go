@@ -0,0 +1,32 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/schedule"
+	schedule_mock "github.com/defryheryanto/mini-wallet/internal/schedule/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("should run the next batch right away while the batches are full", func(t *testing.T) {
+		service := schedule_mock.NewScheduleIService(t)
+		service.On("RunDue", mock.Anything).Return(schedule.RUN_BATCH_SIZE, nil).Once()
+		called := make(chan struct{})
+		service.On("RunDue", mock.Anything).Run(func(args mock.Arguments) {
+			close(called)
+		}).Return(0, nil).Once()
+
+		scheduler := schedule.NewScheduler(service)
+		scheduler.Start(context.TODO())
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("next batch not run")
+		}
+		assert.Nil(t, scheduler.Shutdown(context.TODO()))
+	})
+}
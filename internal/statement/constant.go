@@ -0,0 +1,10 @@
+package statement
+
+import "time"
+
+// Delay after the end of the month before the statements are generated,
+// so transactions made right before the end of the month are settled
+const GENERATION_DELAY = time.Hour
+
+// Delay before generating the statements again after listing the wallets failed
+const GENERATION_RETRY_DELAY = 5 * time.Minute
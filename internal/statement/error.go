@@ -0,0 +1,6 @@
+package statement
+
+import "github.com/defryheryanto/mini-wallet/internal/errors"
+
+var ErrStatementNotFound = errors.NewNotFoundError("statement not found")
+var ErrPeriodNotEnded = errors.NewValidationError("statement period has not ended yet")
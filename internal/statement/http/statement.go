@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+	"github.com/go-chi/chi/v5"
+)
+
+func HandleGetStatements(service statement.StatementIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		statements, err := service.GetStatements(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"statements": statements,
+		})
+	}
+}
+
+func HandleGetStatement(service statement.StatementIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result, err := service.GetStatement(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"statement": result,
+		})
+	}
+}
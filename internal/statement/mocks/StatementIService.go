@@ -0,0 +1,134 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	statement "github.com/defryheryanto/mini-wallet/internal/statement"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StatementIService is an autogenerated mock type for the StatementIService type
+type StatementIService struct {
+	mock.Mock
+}
+
+// Generate provides a mock function with given fields: ctx, walletId, month
+func (_m *StatementIService) Generate(ctx context.Context, walletId string, month time.Time) (*statement.Statement, error) {
+	ret := _m.Called(ctx, walletId, month)
+
+	var r0 *statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*statement.Statement, error)); ok {
+		return rf(ctx, walletId, month)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *statement.Statement); ok {
+		r0 = rf(ctx, walletId, month)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, walletId, month)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateMonthly provides a mock function with given fields: ctx, month
+func (_m *StatementIService) GenerateMonthly(ctx context.Context, month time.Time) (int, error) {
+	ret := _m.Called(ctx, month)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, month)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, month)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, month)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatement provides a mock function with given fields: ctx, customerXid, walletId, id
+func (_m *StatementIService) GetStatement(ctx context.Context, customerXid string, walletId string, id string) (*statement.Statement, error) {
+	ret := _m.Called(ctx, customerXid, walletId, id)
+
+	var r0 *statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*statement.Statement, error)); ok {
+		return rf(ctx, customerXid, walletId, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *statement.Statement); ok {
+		r0 = rf(ctx, customerXid, walletId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatements provides a mock function with given fields: ctx, customerXid, walletId
+func (_m *StatementIService) GetStatements(ctx context.Context, customerXid string, walletId string) ([]*statement.Statement, error) {
+	ret := _m.Called(ctx, customerXid, walletId)
+
+	var r0 []*statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*statement.Statement, error)); ok {
+		return rf(ctx, customerXid, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*statement.Statement); ok {
+		r0 = rf(ctx, customerXid, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewStatementIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewStatementIService creates a new instance of StatementIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewStatementIService(t mockConstructorTestingTNewStatementIService) *StatementIService {
+	mock := &StatementIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	statement "github.com/defryheryanto/mini-wallet/internal/statement"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StatementRepository is an autogenerated mock type for the StatementRepository type
+type StatementRepository struct {
+	mock.Mock
+}
+
+// FindAllByWalletId provides a mock function with given fields: ctx, walletId
+func (_m *StatementRepository) FindAllByWalletId(ctx context.Context, walletId string) ([]*statement.Statement, error) {
+	ret := _m.Called(ctx, walletId)
+
+	var r0 []*statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*statement.Statement, error)); ok {
+		return rf(ctx, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*statement.Statement); ok {
+		r0 = rf(ctx, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *StatementRepository) FindById(ctx context.Context, id string) (*statement.Statement, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*statement.Statement, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *statement.Statement); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByWalletIdAndPeriod provides a mock function with given fields: ctx, walletId, periodStart
+func (_m *StatementRepository) FindByWalletIdAndPeriod(ctx context.Context, walletId string, periodStart time.Time) (*statement.Statement, error) {
+	ret := _m.Called(ctx, walletId, periodStart)
+
+	var r0 *statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*statement.Statement, error)); ok {
+		return rf(ctx, walletId, periodStart)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *statement.Statement); ok {
+		r0 = rf(ctx, walletId, periodStart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, walletId, periodStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *StatementRepository) Insert(ctx context.Context, data *statement.Statement) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *statement.Statement) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewStatementRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewStatementRepository creates a new instance of StatementRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewStatementRepository(t mockConstructorTestingTNewStatementRepository) *StatementRepository {
+	mock := &StatementRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,14 @@
+package statement
+
+import "time"
+
+// Return the start of the month of the given time in UTC
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Return the start of the month after the month of the given time in UTC
+func NextMonthStart(t time.Time) time.Time {
+	return MonthStart(t).AddDate(0, 1, 0)
+}
@@ -0,0 +1,77 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+)
+
+type Statement struct {
+	Id               string    `gorm:"primaryKey;column:id"`
+	WalletId         string    `gorm:"column:wallet_id"`
+	Currency         string    `gorm:"column:currency"`
+	PeriodStart      time.Time `gorm:"column:period_start"`
+	PeriodEnd        time.Time `gorm:"column:period_end"`
+	OpeningBalance   float64   `gorm:"column:opening_balance"`
+	TotalCredits     float64   `gorm:"column:total_credits"`
+	TotalDebits      float64   `gorm:"column:total_debits"`
+	TotalFees        float64   `gorm:"column:total_fees"`
+	ClosingBalance   float64   `gorm:"column:closing_balance"`
+	TransactionCount int       `gorm:"column:transaction_count"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+}
+
+func (Statement) TableName() string {
+	return "statements"
+}
+
+func (Statement) FromServiceModel(data *statement.Statement) *Statement {
+	if data == nil {
+		return nil
+	}
+
+	return &Statement{
+		Id:               data.Id,
+		WalletId:         data.WalletId,
+		Currency:         data.Currency,
+		PeriodStart:      data.PeriodStart,
+		PeriodEnd:        data.PeriodEnd,
+		OpeningBalance:   data.OpeningBalance,
+		TotalCredits:     data.TotalCredits,
+		TotalDebits:      data.TotalDebits,
+		TotalFees:        data.TotalFees,
+		ClosingBalance:   data.ClosingBalance,
+		TransactionCount: data.TransactionCount,
+		CreatedAt:        data.CreatedAt,
+	}
+}
+
+func (s *Statement) ToServiceModel() *statement.Statement {
+	return &statement.Statement{
+		Id:               s.Id,
+		WalletId:         s.WalletId,
+		Currency:         s.Currency,
+		PeriodStart:      s.PeriodStart.UTC(),
+		PeriodEnd:        s.PeriodEnd.UTC(),
+		OpeningBalance:   s.OpeningBalance,
+		TotalCredits:     s.TotalCredits,
+		TotalDebits:      s.TotalDebits,
+		TotalFees:        s.TotalFees,
+		ClosingBalance:   s.ClosingBalance,
+		TransactionCount: s.TransactionCount,
+		CreatedAt:        s.CreatedAt,
+	}
+}
+
+func SliceToServiceModel(data []*Statement) []*statement.Statement {
+	if data == nil {
+		return nil
+	}
+
+	statements := []*statement.Statement{}
+	for _, s := range data {
+		statements = append(statements, s.ToServiceModel())
+	}
+
+	return statements
+}
@@ -0,0 +1,82 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StatementRepository struct {
+	db *gorm.DB
+}
+
+func NewStatementRepository(db *gorm.DB) *StatementRepository {
+	return &StatementRepository{db}
+}
+
+func (r *StatementRepository) Insert(ctx context.Context, data *statement.Statement) error {
+	s := Statement{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "period_start"}},
+		DoNothing: true,
+	}).Create(&s).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *StatementRepository) FindById(ctx context.Context, id string) (*statement.Statement, error) {
+	s := &Statement{}
+
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&s).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.ToServiceModel(), nil
+}
+
+func (r *StatementRepository) FindByWalletIdAndPeriod(ctx context.Context, walletId string, periodStart time.Time) (*statement.Statement, error) {
+	s := &Statement{}
+
+	err := r.getGormClient(ctx).Where("wallet_id = ? AND period_start = ?", walletId, periodStart).First(&s).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.ToServiceModel(), nil
+}
+
+func (r *StatementRepository) FindAllByWalletId(ctx context.Context, walletId string) ([]*statement.Statement, error) {
+	statements := []*Statement{}
+
+	err := r.getGormClient(ctx).Where("wallet_id = ?", walletId).Order("period_start DESC").Find(&statements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(statements), nil
+}
+
+func (r *StatementRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,51 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Scheduler generates the statements of every wallet once a month has ended.
+// On start it generates the statements of the last ended month, so a month missed while no instance was running is caught up.
+// Generation is idempotent, so every instance can run its own scheduler.
+// Shutdown stops the running generation, statements not generated yet are generated on the next start
+type Scheduler struct {
+	*lifecycle.Loop
+	service StatementIService
+	// Month generated next
+	month time.Time
+}
+
+func NewScheduler(service StatementIService) *Scheduler {
+	s := &Scheduler{
+		service: service,
+		month:   MonthStart(time.Now()).AddDate(0, -1, 0),
+	}
+	s.Loop = lifecycle.NewInterruptibleLoop("statement", s.generate)
+	return s
+}
+
+// Generate the statements of the month once it has ended and move on to the next month.
+// Return how long to wait for the end of the month, or before retrying the failed generation
+func (s *Scheduler) generate(ctx context.Context) time.Duration {
+	due := NextMonthStart(s.month).Add(GENERATION_DELAY)
+	if time.Now().Before(due) {
+		return time.Until(due)
+	}
+
+	logger := logging.FromContext(ctx)
+	month := s.month.Format("2006-01")
+	logger.Info("generating statements", "month", month)
+	generated, err := s.service.GenerateMonthly(ctx, s.month)
+	if err != nil {
+		logger.Error("error generating statements", "month", month, logging.KEY_ERROR, err)
+		return GENERATION_RETRY_DELAY
+	}
+	logger.Info("statements generated", "month", month, "count", generated)
+
+	s.month = NextMonthStart(s.month)
+	return time.Until(NextMonthStart(s.month).Add(GENERATION_DELAY))
+}
@@ -0,0 +1,40 @@
+package statement_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+	statement_mock "github.com/defryheryanto/mini-wallet/internal/statement/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("should catch up the last ended month on start", func(t *testing.T) {
+		lastMonth := statement.MonthStart(time.Now()).AddDate(0, -1, 0)
+		if time.Now().Before(statement.MonthStart(time.Now()).Add(statement.GENERATION_DELAY)) {
+			t.Skip("the last ended month is generated after the generation delay")
+		}
+
+		generated := make(chan time.Time, 1)
+		service := statement_mock.NewStatementIService(t)
+		service.On("GenerateMonthly", mock.Anything, lastMonth).Run(func(args mock.Arguments) {
+			generated <- args.Get(1).(time.Time)
+		}).Return(0, nil)
+		scheduler := statement.NewScheduler(service)
+
+		scheduler.Start(context.TODO())
+		select {
+		case month := <-generated:
+			assert.Equal(t, lastMonth, month)
+		case <-time.After(time.Second):
+			t.Fatal("statements of the last month not generated")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.Nil(t, scheduler.Shutdown(ctx))
+	})
+}
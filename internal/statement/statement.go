@@ -0,0 +1,191 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/google/uuid"
+)
+
+// Statement summarizes the successful transactions of a wallet within a month.
+// Statements are never changed once generated
+type Statement struct {
+	Id       string `json:"id"`
+	WalletId string `json:"wallet_id"`
+	Currency string `json:"currency"`
+	// The period is [PeriodStart, PeriodEnd)
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	OpeningBalance float64   `json:"opening_balance"`
+	TotalCredits   float64   `json:"total_credits"`
+	// Debits other than the fees
+	TotalDebits      float64   `json:"total_debits"`
+	TotalFees        float64   `json:"total_fees"`
+	ClosingBalance   float64   `json:"closing_balance"`
+	TransactionCount int       `json:"transaction_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type StatementRepository interface {
+	// Insert the statement unless the wallet already has a statement of the period
+	Insert(ctx context.Context, data *Statement) error
+	FindById(ctx context.Context, id string) (*Statement, error)
+	FindByWalletIdAndPeriod(ctx context.Context, walletId string, periodStart time.Time) (*Statement, error)
+	// Return the statements of the wallet, the latest period first
+	FindAllByWalletId(ctx context.Context, walletId string) ([]*Statement, error)
+}
+
+type StatementIService interface {
+	Generate(ctx context.Context, walletId string, month time.Time) (*Statement, error)
+	GenerateMonthly(ctx context.Context, month time.Time) (int, error)
+	GetStatements(ctx context.Context, customerXid, walletId string) ([]*Statement, error)
+	GetStatement(ctx context.Context, customerXid, walletId, id string) (*Statement, error)
+}
+
+type StatementService struct {
+	repository         StatementRepository
+	walletService      wallet.WalletIService
+	transactionService transaction.TransactionIService
+}
+
+func NewStatementService(
+	repository StatementRepository,
+	walletService wallet.WalletIService,
+	transactionService transaction.TransactionIService,
+) *StatementService {
+	return &StatementService{repository, walletService, transactionService}
+}
+
+// Generate the statement of the wallet for the month of the given time.
+// Return the existing statement if the wallet already has one for the month
+func (s *StatementService) Generate(ctx context.Context, walletId string, month time.Time) (*Statement, error) {
+	targetWallet, err := s.walletService.GetWalletById(ctx, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generate(ctx, targetWallet, month, false)
+}
+
+// Generate the statements of every wallet for the month of the given time, skipping the wallets
+// without balance nor transactions in the month. A wallet failing to generate doesn't stop the others.
+//
+// Return the number of generated statements
+func (s *StatementService) GenerateMonthly(ctx context.Context, month time.Time) (int, error) {
+	logger := logging.FromContext(ctx)
+	generated := 0
+
+	for offset := 0; ; offset += wallet.MAX_SEARCH_LIMIT {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+
+		wallets, err := s.walletService.SearchWallets(ctx, &wallet.SearchWalletsParams{Limit: wallet.MAX_SEARCH_LIMIT, Offset: offset})
+		if err != nil {
+			return generated, err
+		}
+
+		for _, targetWallet := range wallets {
+			statement, err := s.generate(ctx, targetWallet, month, true)
+			if err != nil {
+				logger.Error("error generating statement", logging.KEY_WALLET_ID, targetWallet.Id, logging.KEY_ERROR, err)
+				continue
+			}
+			if statement != nil {
+				generated++
+			}
+		}
+
+		if len(wallets) < wallet.MAX_SEARCH_LIMIT {
+			return generated, nil
+		}
+	}
+}
+
+// Return the statements of the given wallet of the customer, or of its default wallet if the wallet id is empty
+func (s *StatementService) GetStatements(ctx context.Context, customerXid, walletId string) ([]*Statement, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindAllByWalletId(ctx, targetWallet.Id)
+}
+
+// Return the statement if it belongs to the given wallet of the customer, or to its default wallet if the wallet id is empty
+func (s *StatementService) GetStatement(ctx context.Context, customerXid, walletId, id string) (*Statement, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if statement == nil || statement.WalletId != targetWallet.Id {
+		return nil, ErrStatementNotFound
+	}
+
+	return statement, nil
+}
+
+// Return nil statement if skipEmpty is set and the wallet had neither balance nor transactions in the month
+func (s *StatementService) generate(ctx context.Context, targetWallet *wallet.Wallet, month time.Time, skipEmpty bool) (*Statement, error) {
+	periodStart := MonthStart(month)
+	periodEnd := NextMonthStart(month)
+	if periodEnd.After(time.Now()) {
+		return nil, ErrPeriodNotEnded
+	}
+
+	existing, err := s.repository.FindByWalletIdAndPeriod(ctx, targetWallet.Id, periodStart)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	walletCurrency, err := currency.Find(targetWallet.Currency)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.transactionService.SummarizePeriod(ctx, targetWallet.Id, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	if skipEmpty && summary.TransactionCount == 0 && walletCurrency.Round(summary.OpeningBalance) == 0 {
+		return nil, nil
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{
+		Id:               uuidRandom.String(),
+		WalletId:         targetWallet.Id,
+		Currency:         walletCurrency.Code,
+		PeriodStart:      periodStart,
+		PeriodEnd:        periodEnd,
+		OpeningBalance:   walletCurrency.Round(summary.OpeningBalance),
+		TotalCredits:     walletCurrency.Round(summary.TotalCredits),
+		TotalDebits:      walletCurrency.Round(summary.TotalDebits),
+		TotalFees:        walletCurrency.Round(summary.TotalFees),
+		ClosingBalance:   walletCurrency.Round(summary.ClosingBalance),
+		TransactionCount: summary.TransactionCount,
+		CreatedAt:        time.Now(),
+	}
+	err = s.repository.Insert(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another instance may have generated the statement concurrently, the stored one wins
+	return s.repository.FindByWalletIdAndPeriod(ctx, targetWallet.Id, periodStart)
+}
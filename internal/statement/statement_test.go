@@ -0,0 +1,156 @@
+package statement_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/statement"
+	statement_mock "github.com/defryheryanto/mini-wallet/internal/statement/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type serviceMocks struct {
+	repository         *statement_mock.StatementRepository
+	walletService      *wallet_mock.WalletIService
+	transactionService *transaction_mock.TransactionIService
+}
+
+func newService(t *testing.T) (*statement.StatementService, *serviceMocks) {
+	mocks := &serviceMocks{
+		repository:         statement_mock.NewStatementRepository(t),
+		walletService:      wallet_mock.NewWalletIService(t),
+		transactionService: transaction_mock.NewTransactionIService(t),
+	}
+
+	return statement.NewStatementService(mocks.repository, mocks.walletService, mocks.transactionService), mocks
+}
+
+func TestMonthStart(t *testing.T) {
+	t.Run("should return the start of the month in UTC", func(t *testing.T) {
+		at := time.Date(2024, 3, 1, 2, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+		assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), statement.MonthStart(at))
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), statement.NextMonthStart(at))
+	})
+
+	t.Run("should roll over the year", func(t *testing.T) {
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), statement.NextMonthStart(time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestStatementService_Generate(t *testing.T) {
+	month := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	targetWallet := &wallet.Wallet{Id: "wallet-id", Currency: "IDR"}
+
+	t.Run("should return error if the month has not ended", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(targetWallet, nil)
+
+		_, err := service.Generate(context.TODO(), "wallet-id", time.Now())
+		assert.Equal(t, statement.ErrPeriodNotEnded, err)
+	})
+
+	t.Run("should return the existing statement of the month", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(targetWallet, nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "wallet-id", periodStart).Return(&statement.Statement{Id: "statement-id"}, nil)
+
+		result, err := service.Generate(context.TODO(), "wallet-id", month)
+		assert.Nil(t, err)
+		assert.Equal(t, "statement-id", result.Id)
+	})
+
+	t.Run("should store the summary of the month", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(targetWallet, nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "wallet-id", periodStart).Return(nil, nil).Once()
+		mocks.transactionService.On("SummarizePeriod", mock.Anything, "wallet-id", periodStart, periodEnd).Return(&transaction.PeriodSummary{
+			OpeningBalance:   1000,
+			TotalCredits:     0.1 + 0.2,
+			TotalDebits:      100,
+			TotalFees:        5,
+			ClosingBalance:   895.3,
+			TransactionCount: 4,
+		}, nil)
+		var inserted *statement.Statement
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			inserted = args.Get(1).(*statement.Statement)
+			assert.Equal(t, "IDR", inserted.Currency)
+			assert.Equal(t, periodEnd, inserted.PeriodEnd)
+			assert.Equal(t, 0.3, inserted.TotalCredits)
+			assert.Equal(t, float64(5), inserted.TotalFees)
+			assert.Equal(t, 895.3, inserted.ClosingBalance)
+			assert.Equal(t, 4, inserted.TransactionCount)
+		}).Return(nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "wallet-id", periodStart).Return(func(context.Context, string, time.Time) *statement.Statement {
+			return inserted
+		}, nil).Once()
+
+		result, err := service.Generate(context.TODO(), "wallet-id", month)
+		assert.Nil(t, err)
+		assert.Equal(t, inserted, result)
+	})
+}
+
+func TestStatementService_GenerateMonthly(t *testing.T) {
+	month := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should return error if failed to list the wallets", func(t *testing.T) {
+		mockedErr := fmt.Errorf("mocked")
+		service, mocks := newService(t)
+		mocks.walletService.On("SearchWallets", mock.Anything, mock.Anything).Return(nil, mockedErr)
+
+		_, err := service.GenerateMonthly(context.TODO(), month)
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should skip empty wallets and continue after a failed wallet", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("SearchWallets", mock.Anything, &wallet.SearchWalletsParams{Limit: wallet.MAX_SEARCH_LIMIT}).Return([]*wallet.Wallet{
+			{Id: "failed-id", Currency: "IDR"},
+			{Id: "empty-id", Currency: "IDR"},
+			{Id: "active-id", Currency: "IDR"},
+		}, nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "failed-id", month).Return(nil, fmt.Errorf("mocked"))
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "empty-id", month).Return(nil, nil)
+		mocks.transactionService.On("SummarizePeriod", mock.Anything, "empty-id", mock.Anything, mock.Anything).Return(&transaction.PeriodSummary{}, nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "active-id", month).Return(nil, nil).Once()
+		mocks.transactionService.On("SummarizePeriod", mock.Anything, "active-id", mock.Anything, mock.Anything).Return(&transaction.PeriodSummary{OpeningBalance: 100, ClosingBalance: 100}, nil)
+		mocks.repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		mocks.repository.On("FindByWalletIdAndPeriod", mock.Anything, "active-id", month).Return(&statement.Statement{Id: "statement-id"}, nil).Once()
+
+		generated, err := service.GenerateMonthly(context.TODO(), month)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, generated)
+	})
+}
+
+func TestStatementService_GetStatement(t *testing.T) {
+	t.Run("should return error if statement belongs to another wallet", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.repository.On("FindById", mock.Anything, "statement-id").Return(&statement.Statement{Id: "statement-id", WalletId: "other-wallet-id"}, nil)
+
+		_, err := service.GetStatement(context.TODO(), "test", "", "statement-id")
+		assert.Equal(t, statement.ErrStatementNotFound, err)
+	})
+
+	t.Run("should return the statement of the wallet", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.walletService.On("GetWallet", mock.Anything, "test", "wallet-id").Return(&wallet.Wallet{Id: "wallet-id"}, nil)
+		mocks.repository.On("FindById", mock.Anything, "statement-id").Return(&statement.Statement{Id: "statement-id", WalletId: "wallet-id"}, nil)
+
+		result, err := service.GetStatement(context.TODO(), "test", "wallet-id", "statement-id")
+		assert.Nil(t, err)
+		assert.Equal(t, "statement-id", result.Id)
+	})
+}
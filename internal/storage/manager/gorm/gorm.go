@@ -2,8 +2,9 @@ package gorm
 
 import (
 	"context"
-	"log"
 
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	"gorm.io/gorm"
 )
 
@@ -19,20 +20,37 @@ func NewGormStorageManager(db *gorm.DB) *GormStorageManager {
 // Any SQL queries that want to use this database transaction should use the gorm client inside the context
 // use ExtractClientFromContext(context.Context) to get the gorm client inside the context
 //
-// Transaction will be rollback if received error from the given function. And will be commited if received no error
+// Transaction will be rollback if received error from the given function. And will be commited if received no error.
+// The function joins the database transaction of the context if there is one, so services can be composed in one transaction.
+// The after-commit hooks registered within the outermost transaction are called once it is committed
 func (m *GormStorageManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	db := m.db.Begin()
+	if _, err := ExtractClientFromContext(ctx); err == nil {
+		return fn(ctx)
+	}
+
+	db := m.db.WithContext(ctx).Begin()
 	ctx = InjectClientToContext(ctx, db)
+	ctx, hooks := manager.WithAfterCommitHooks(ctx)
 
 	err := fn(ctx)
 	if err != nil {
 		rollbackErr := db.Rollback().Error
 		if rollbackErr != nil {
-			log.Printf("error rollback %v\n", err)
+			logging.FromContext(ctx).Error("error rollback", logging.KEY_ERROR, rollbackErr)
 		}
 		return err
 	}
 
-	db.Commit()
+	err = db.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	hooks.Run()
 	return nil
 }
+
+// Call fn once the database transaction of the context is committed, or immediately if there is none
+func (m *GormStorageManager) AfterCommit(ctx context.Context, fn func()) {
+	manager.RegisterAfterCommit(ctx, fn)
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"sync"
+)
+
+type afterCommitKey struct{}
+
+// AfterCommitHooks holds the functions to call once the outermost database transaction is committed
+type AfterCommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// Return the context collecting the after-commit hooks of a new outermost database transaction
+func WithAfterCommitHooks(ctx context.Context) (context.Context, *AfterCommitHooks) {
+	hooks := &AfterCommitHooks{}
+	return context.WithValue(ctx, afterCommitKey{}, hooks), hooks
+}
+
+// Register fn to be called once the database transaction of the context is committed.
+// fn is called immediately if the context has no database transaction
+func RegisterAfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*AfterCommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// Call the registered functions in registration order
+func (h *AfterCommitHooks) Run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+func hasAfterCommitHooks(ctx context.Context) bool {
+	_, ok := ctx.Value(afterCommitKey{}).(*AfterCommitHooks)
+	return ok
+}
@@ -4,4 +4,7 @@ import "context"
 
 type StorageManager interface {
 	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// Call fn once the database transaction of the context is committed, or immediately if there is none.
+	// fn is dropped if the transaction is rolled back
+	AfterCommit(ctx context.Context, fn func())
 }
@@ -8,5 +8,20 @@ type MockStorageManager struct {
 }
 
 func (m *MockStorageManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return fn(ctx)
+	if hasAfterCommitHooks(ctx) {
+		return fn(ctx)
+	}
+
+	ctx, hooks := WithAfterCommitHooks(ctx)
+	err := fn(ctx)
+	if err != nil {
+		return err
+	}
+
+	hooks.Run()
+	return nil
+}
+
+func (m *MockStorageManager) AfterCommit(ctx context.Context, fn func()) {
+	RegisterAfterCommit(ctx, fn)
 }
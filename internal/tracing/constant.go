@@ -0,0 +1,20 @@
+package tracing
+
+const INSTRUMENTATION_NAME = "github.com/defryheryanto/mini-wallet"
+
+const (
+	EXPORTER_NONE   = "none"
+	EXPORTER_OTLP   = "otlp"
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_FILE   = "file"
+)
+
+const (
+	ATTRIBUTE_CLIENT_XID     = "client.xid"
+	ATTRIBUTE_WALLET_ID      = "wallet.id"
+	ATTRIBUTE_TRANSACTION_ID = "transaction.id"
+	ATTRIBUTE_REFERENCE_ID   = "transaction.reference_id"
+	ATTRIBUTE_AMOUNT         = "transaction.amount"
+	// Wallet receiving a transfer
+	ATTRIBUTE_TARGET_WALLET_ID = "transaction.target_wallet_id"
+)
@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	// One of EXPORTER_NONE, EXPORTER_OTLP, EXPORTER_STDOUT or EXPORTER_FILE
+	Exporter    string
+	FilePath    string
+	ServiceName string
+}
+
+// Provider owns the exporter of the spans and flushes them on shutdown
+type Provider struct {
+	provider *sdktrace.TracerProvider
+	closer   io.Closer
+}
+
+// Install the tracer provider for the configured exporter as the global one,
+// and propagate trace context through W3C trace context and baggage headers.
+//
+// Return nil provider if tracing is disabled
+func Setup(ctx context.Context, config *Config) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var closer io.Closer
+	var err error
+	switch config.Exporter {
+	case EXPORTER_NONE, "":
+		return nil, nil
+	case EXPORTER_OTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case EXPORTER_STDOUT:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case EXPORTER_FILE:
+		var file *os.File
+		file, err = os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		closer = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return &Provider{provider, closer}, nil
+}
+
+func (p *Provider) Name() string {
+	return "tracing"
+}
+
+// Flush the remaining spans to the exporter
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.provider.Shutdown(ctx)
+	if p.closer != nil {
+		if closeErr := p.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// Start a span with the tracer of this application
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(INSTRUMENTATION_NAME).Start(ctx, name, opts...)
+}
+
+// Record the error, if any, and end the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
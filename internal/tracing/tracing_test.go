@@ -0,0 +1,98 @@
+package tracing_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	event_mock "github.com/defryheryanto/mini-wallet/internal/events/mocks"
+	fx_mock "github.com/defryheryanto/mini-wallet/internal/fx/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/tracing"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	webhook_mock "github.com/defryheryanto/mini-wallet/internal/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+
+	return recorder
+}
+
+func TestWalletService(t *testing.T) {
+	recorder := setupRecorder(t)
+	mockedErr := fmt.Errorf("mocked")
+
+	walletService := wallet_mock.NewWalletIService(t)
+	walletService.On("GetWalletByXid", mock.Anything, "test").Return(&wallet.Wallet{}, nil)
+	walletService.On("AddBalance", mock.Anything, "wallet-id", float64(10_000)).Return(mockedErr)
+	service := tracing.WalletService(walletService)
+
+	_, err := service.GetWalletByXid(context.TODO(), "test")
+	assert.Nil(t, err)
+	err = service.AddBalance(context.TODO(), "wallet-id", 10_000)
+	assert.Equal(t, mockedErr, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "WalletService.GetWalletByXid", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "WalletService.AddBalance", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestSettlementWorker_Link(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	repository := transaction_mock.NewTransactionRepository(t)
+	repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return([]*transaction.Transaction{
+		{Id: "test-id", Type: transaction.TYPE_DEPOSIT, WalletId: "wallet-id", Amount: 10_000},
+	}, nil)
+	repository.On("FindByIdForUpdate", mock.Anything, "test-id").Return(&transaction.Transaction{Id: "test-id", Status: transaction.STATUS_PENDING}, nil)
+	settled := make(chan struct{})
+	repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(settled)
+	}).Return(nil)
+	walletService := wallet_mock.NewWalletIService(t)
+	walletService.On("AddBalance", mock.Anything, "wallet-id", float64(10_000)).Return(nil)
+	walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: "test"}, nil)
+	webhookService := webhook_mock.NewWebhookIService(t)
+	webhookService.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	eventService := event_mock.NewEventIService(t)
+	eventService.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	worker := transaction.NewSettlementWorker(0)
+	service := tracing.TransactionService(transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhookService, eventService, &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE)))
+
+	err := service.ResumePendingSettlements(context.TODO())
+	assert.Nil(t, err)
+	<-settled
+	assert.Nil(t, worker.Shutdown(context.TODO()))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	parent := spans["TransactionService.ResumePendingSettlements"]
+	settlement := spans["SettlementWorker.settle"]
+	assert.NotNil(t, parent)
+	assert.NotNil(t, settlement)
+	assert.NotEqual(t, parent.SpanContext().TraceID(), settlement.SpanContext().TraceID())
+	assert.Len(t, settlement.Links(), 1)
+	assert.Equal(t, parent.SpanContext().SpanID(), settlement.Links()[0].SpanContext.SpanID())
+}
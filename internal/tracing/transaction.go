@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type transactionService struct {
+	transaction.TransactionIService
+}
+
+// Wrap the transaction service so every call is recorded as a span.
+// Methods without a span are forwarded as is
+func TransactionService(service transaction.TransactionIService) transaction.TransactionIService {
+	return &transactionService{service}
+}
+
+func (s *transactionService) GetTransactionsByCustomerXid(ctx context.Context, xid, walletId string) (_ []*transaction.Transaction, err error) {
+	ctx, span := Start(ctx, "TransactionService.GetTransactionsByCustomerXid", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, xid),
+		attribute.String(ATTRIBUTE_WALLET_ID, walletId),
+	))
+	defer func() { End(span, err) }()
+
+	return s.TransactionIService.GetTransactionsByCustomerXid(ctx, xid, walletId)
+}
+
+func (s *transactionService) CreateDeposit(ctx context.Context, params *transaction.CreateDepositParams) (trx *transaction.Transaction, err error) {
+	ctx, span := Start(ctx, "TransactionService.CreateDeposit", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, params.CustomerXid),
+		attribute.String(ATTRIBUTE_REFERENCE_ID, params.ReferenceId),
+		attribute.Float64(ATTRIBUTE_AMOUNT, params.Amount),
+	))
+	defer func() {
+		if trx != nil {
+			span.SetAttributes(attribute.String(ATTRIBUTE_TRANSACTION_ID, trx.Id), attribute.String(ATTRIBUTE_WALLET_ID, trx.WalletId))
+		}
+		End(span, err)
+	}()
+
+	return s.TransactionIService.CreateDeposit(ctx, params)
+}
+
+func (s *transactionService) CreateWithdrawal(ctx context.Context, params *transaction.CreateWithdrawalParams) (trx *transaction.Transaction, err error) {
+	ctx, span := Start(ctx, "TransactionService.CreateWithdrawal", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, params.CustomerXid),
+		attribute.String(ATTRIBUTE_REFERENCE_ID, params.ReferenceId),
+		attribute.Float64(ATTRIBUTE_AMOUNT, params.Amount),
+	))
+	defer func() {
+		if trx != nil {
+			span.SetAttributes(attribute.String(ATTRIBUTE_TRANSACTION_ID, trx.Id), attribute.String(ATTRIBUTE_WALLET_ID, trx.WalletId))
+		}
+		End(span, err)
+	}()
+
+	return s.TransactionIService.CreateWithdrawal(ctx, params)
+}
+
+func (s *transactionService) CreateTransfer(ctx context.Context, params *transaction.CreateTransferParams) (outgoing *transaction.Transaction, incoming *transaction.Transaction, err error) {
+	ctx, span := Start(ctx, "TransactionService.CreateTransfer", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, params.CustomerXid),
+		attribute.String(ATTRIBUTE_REFERENCE_ID, params.ReferenceId),
+		attribute.Float64(ATTRIBUTE_AMOUNT, params.Amount),
+		attribute.String(ATTRIBUTE_TARGET_WALLET_ID, params.TargetWalletId),
+	))
+	defer func() {
+		if outgoing != nil {
+			span.SetAttributes(attribute.String(ATTRIBUTE_TRANSACTION_ID, outgoing.Id), attribute.String(ATTRIBUTE_WALLET_ID, outgoing.WalletId))
+		}
+		End(span, err)
+	}()
+
+	return s.TransactionIService.CreateTransfer(ctx, params)
+}
+
+func (s *transactionService) ExportTransactions(ctx context.Context, params *transaction.ExportTransactionsParams, writer transaction.ExportWriter) (err error) {
+	ctx, span := Start(ctx, "TransactionService.ExportTransactions", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, params.CustomerXid),
+		attribute.String(ATTRIBUTE_WALLET_ID, params.WalletId),
+	))
+	defer func() { End(span, err) }()
+
+	return s.TransactionIService.ExportTransactions(ctx, params, writer)
+}
+
+func (s *transactionService) ResumePendingSettlements(ctx context.Context) (err error) {
+	ctx, span := Start(ctx, "TransactionService.ResumePendingSettlements")
+	defer func() { End(span, err) }()
+
+	return s.TransactionIService.ResumePendingSettlements(ctx)
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type walletService struct {
+	wallet.WalletIService
+}
+
+// Wrap the wallet service so every call is recorded as a span.
+// Methods without a span are forwarded as is
+func WalletService(service wallet.WalletIService) wallet.WalletIService {
+	return &walletService{service}
+}
+
+func (s *walletService) Create(ctx context.Context, params *wallet.CreateWalletParams) (_ *wallet.Wallet, err error) {
+	ctx, span := Start(ctx, "WalletService.Create", trace.WithAttributes(attribute.String(ATTRIBUTE_CLIENT_XID, params.OwnedBy)))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.Create(ctx, params)
+}
+
+func (s *walletService) UpdateStatus(ctx context.Context, customerXid, walletId string, isEnabled bool) (_ *wallet.Wallet, err error) {
+	ctx, span := Start(ctx, "WalletService.UpdateStatus", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, customerXid),
+		attribute.String(ATTRIBUTE_WALLET_ID, walletId),
+		attribute.Bool("wallet.enabled", isEnabled),
+	))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.UpdateStatus(ctx, customerXid, walletId, isEnabled)
+}
+
+func (s *walletService) GetWalletByXid(ctx context.Context, customerXid string) (_ *wallet.Wallet, err error) {
+	ctx, span := Start(ctx, "WalletService.GetWalletByXid", trace.WithAttributes(attribute.String(ATTRIBUTE_CLIENT_XID, customerXid)))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.GetWalletByXid(ctx, customerXid)
+}
+
+func (s *walletService) GetWallet(ctx context.Context, customerXid, walletId string) (_ *wallet.Wallet, err error) {
+	ctx, span := Start(ctx, "WalletService.GetWallet", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_CLIENT_XID, customerXid),
+		attribute.String(ATTRIBUTE_WALLET_ID, walletId),
+	))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.GetWallet(ctx, customerXid, walletId)
+}
+
+func (s *walletService) AddBalance(ctx context.Context, walletId string, amount float64) (err error) {
+	ctx, span := Start(ctx, "WalletService.AddBalance", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_WALLET_ID, walletId),
+		attribute.Float64(ATTRIBUTE_AMOUNT, amount),
+	))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.AddBalance(ctx, walletId, amount)
+}
+
+func (s *walletService) DeductBalance(ctx context.Context, walletId string, amount float64) (err error) {
+	ctx, span := Start(ctx, "WalletService.DeductBalance", trace.WithAttributes(
+		attribute.String(ATTRIBUTE_WALLET_ID, walletId),
+		attribute.Float64(ATTRIBUTE_AMOUNT, amount),
+	))
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.DeductBalance(ctx, walletId, amount)
+}
+
+func (s *walletService) GetStatistics(ctx context.Context) (_ []*wallet.Statistics, err error) {
+	ctx, span := Start(ctx, "WalletService.GetStatistics")
+	defer func() { End(span, err) }()
+
+	return s.WalletIService.GetStatistics(ctx)
+}
@@ -0,0 +1,314 @@
+package transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	"github.com/google/uuid"
+)
+
+// WithdrawalChallenge holds a withdrawal, or a transfer to a wallet of another client,
+// waiting to be confirmed with the TOTP code of the customer
+type WithdrawalChallenge struct {
+	Id          string `json:"id"`
+	Type        string `json:"type"`
+	CustomerXid string `json:"customer_xid"`
+	WalletId    string `json:"wallet_id"`
+	// Wallet receiving the amount and quote of the conversion, set for transfers only
+	TargetWalletId string    `json:"target_wallet_id"`
+	QuoteId        string    `json:"quote_id"`
+	ReferenceId    string    `json:"reference_id"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	TransactionId  string    `json:"transaction_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	// Set while the challenge is being confirmed
+	ClaimedAt *time.Time `json:"claimed_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ChallengeRepository interface {
+	Insert(ctx context.Context, data *WithdrawalChallenge) error
+	FindById(ctx context.Context, id string) (*WithdrawalChallenge, error)
+	// Find the challenge and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*WithdrawalChallenge, error)
+	Update(ctx context.Context, data *WithdrawalChallenge) error
+}
+
+type WithdrawalConfirmationIService interface {
+	RequestWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*Transaction, *WithdrawalChallenge, error)
+	ConfirmWithdrawal(ctx context.Context, customerXid, challengeId, code string) (*Transaction, error)
+	RequestTransfer(ctx context.Context, params *CreateTransferParams) (*Transaction, *Transaction, *WithdrawalChallenge, error)
+	ConfirmTransfer(ctx context.Context, customerXid, challengeId, code string) (*Transaction, *Transaction, error)
+	RequiresConfirmation(ctx context.Context, customerXid string, amount float64, currency string) (bool, error)
+}
+
+// WithdrawalConfirmationService requires the customers enrolled to TOTP to confirm with their code
+// the withdrawals and the transfers to wallets of other clients above the threshold of their currency
+type WithdrawalConfirmationService struct {
+	challengeRepository ChallengeRepository
+	transactionService  TransactionIService
+	twoFactorService    twofactor.TwoFactorIService
+	storageManager      manager.StorageManager
+	thresholds          ConfirmationThresholds
+}
+
+func NewWithdrawalConfirmationService(
+	challengeRepository ChallengeRepository,
+	transactionService TransactionIService,
+	twoFactorService twofactor.TwoFactorIService,
+	storageManager manager.StorageManager,
+	thresholds ConfirmationThresholds,
+) *WithdrawalConfirmationService {
+	return &WithdrawalConfirmationService{challengeRepository, transactionService, twoFactorService, storageManager, thresholds}
+}
+
+func (c *WithdrawalChallenge) IsExpired(at time.Time) bool {
+	return !at.Before(c.ExpiresAt)
+}
+
+// Return whether the challenge is being confirmed by a request that hasn't given it up yet.
+// A claim older than CHALLENGE_CLAIM_LEASE is left by a confirmation that never finished, e.g. the application stopped in the middle of it
+func (c *WithdrawalChallenge) IsClaimed(at time.Time) bool {
+	return c.Status == CHALLENGE_STATUS_CONFIRMING && c.ClaimedAt != nil && at.Before(c.ClaimedAt.Add(CHALLENGE_CLAIM_LEASE))
+}
+
+// Create the withdrawal, or a challenge to be confirmed if the amount is above the threshold of the currency of the wallet
+// and the customer is enrolled to TOTP.
+// Exactly one of the returned transaction and challenge is not nil on success
+func (s *WithdrawalConfirmationService) RequestWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*Transaction, *WithdrawalChallenge, error) {
+	sourceWallet, err := s.transactionService.ValidateWithdrawal(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	required, err := s.RequiresConfirmation(ctx, params.CustomerXid, params.Amount, sourceWallet.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	if required {
+		challenge, err := s.createChallenge(ctx, &WithdrawalChallenge{
+			Type:        CHALLENGE_TYPE_WITHDRAWAL,
+			CustomerXid: params.CustomerXid,
+			WalletId:    sourceWallet.Id,
+			ReferenceId: params.ReferenceId,
+			Amount:      params.Amount,
+			Currency:    sourceWallet.Currency,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, challenge, nil
+	}
+
+	trx, err := s.transactionService.CreateWithdrawal(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return trx, nil, nil
+}
+
+// Create the transfer, or a challenge to be confirmed if the target wallet is owned by another client,
+// the amount is above the threshold of the currency of the source wallet and the customer is enrolled to TOTP.
+// Either both returned transactions or the challenge are not nil on success
+func (s *WithdrawalConfirmationService) RequestTransfer(ctx context.Context, params *CreateTransferParams) (*Transaction, *Transaction, *WithdrawalChallenge, error) {
+	sourceWallet, targetWallet, err := s.transactionService.ValidateTransfer(ctx, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if targetWallet.OwnedBy != params.CustomerXid {
+		required, err := s.RequiresConfirmation(ctx, params.CustomerXid, params.Amount, sourceWallet.Currency)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if required {
+			challenge, err := s.createChallenge(ctx, &WithdrawalChallenge{
+				Type:           CHALLENGE_TYPE_TRANSFER,
+				CustomerXid:    params.CustomerXid,
+				WalletId:       sourceWallet.Id,
+				TargetWalletId: targetWallet.Id,
+				QuoteId:        params.QuoteId,
+				ReferenceId:    params.ReferenceId,
+				Amount:         params.Amount,
+				Currency:       sourceWallet.Currency,
+			})
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			return nil, nil, challenge, nil
+		}
+	}
+
+	outgoing, incoming, err := s.transactionService.CreateTransfer(ctx, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return outgoing, incoming, nil, nil
+}
+
+// Return whether the customer has to confirm a debit of the amount with its TOTP code,
+// i.e. the amount is above the threshold of the currency and the customer is enrolled to TOTP
+func (s *WithdrawalConfirmationService) RequiresConfirmation(ctx context.Context, customerXid string, amount float64, currency string) (bool, error) {
+	if !s.thresholds.Exceeds(amount, currency) {
+		return false, nil
+	}
+
+	return s.twoFactorService.IsEnrolled(ctx, customerXid)
+}
+
+// Verify the code of the customer and create the withdrawal held by the challenge.
+// The challenge is claimed first, so concurrent confirmations can't create the withdrawal twice.
+// It is released for another attempt if the code or the withdrawal is rejected
+func (s *WithdrawalConfirmationService) ConfirmWithdrawal(ctx context.Context, customerXid, challengeId, code string) (*Transaction, error) {
+	var trx *Transaction
+	err := s.confirm(ctx, customerXid, challengeId, code, CHALLENGE_TYPE_WITHDRAWAL, func(challenge *WithdrawalChallenge) (string, error) {
+		var err error
+		trx, err = s.transactionService.CreateWithdrawal(ctx, &CreateWithdrawalParams{
+			CustomerXid: challenge.CustomerXid,
+			WalletId:    challenge.WalletId,
+			ReferenceId: challenge.ReferenceId,
+			Amount:      challenge.Amount,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return trx.Id, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trx, nil
+}
+
+// Verify the code of the customer and create the transfer held by the challenge, like ConfirmWithdrawal.
+// The quote of a transfer between currencies may have expired since the challenge was created
+func (s *WithdrawalConfirmationService) ConfirmTransfer(ctx context.Context, customerXid, challengeId, code string) (*Transaction, *Transaction, error) {
+	var outgoing, incoming *Transaction
+	err := s.confirm(ctx, customerXid, challengeId, code, CHALLENGE_TYPE_TRANSFER, func(challenge *WithdrawalChallenge) (string, error) {
+		var err error
+		outgoing, incoming, err = s.transactionService.CreateTransfer(ctx, &CreateTransferParams{
+			CustomerXid:    challenge.CustomerXid,
+			WalletId:       challenge.WalletId,
+			TargetWalletId: challenge.TargetWalletId,
+			ReferenceId:    challenge.ReferenceId,
+			Amount:         challenge.Amount,
+			Currency:       challenge.Currency,
+			QuoteId:        challenge.QuoteId,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return outgoing.Id, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outgoing, incoming, nil
+}
+
+// Claim the challenge of the given type, verify the code and make the debit it holds,
+// which returns the id of its transaction
+func (s *WithdrawalConfirmationService) confirm(
+	ctx context.Context,
+	customerXid, challengeId, code, challengeType string,
+	debit func(challenge *WithdrawalChallenge) (string, error),
+) error {
+	challenge, err := s.claimChallenge(ctx, customerXid, challengeId, challengeType)
+	if err != nil {
+		return err
+	}
+
+	err = s.twoFactorService.Verify(ctx, customerXid, code)
+	if err != nil {
+		s.releaseChallenge(ctx, challenge)
+		return err
+	}
+
+	transactionId, err := debit(challenge)
+	if err != nil {
+		s.releaseChallenge(ctx, challenge)
+		return err
+	}
+
+	challenge.Status = CHALLENGE_STATUS_CONFIRMED
+	challenge.TransactionId = transactionId
+	challenge.ClaimedAt = nil
+	return s.challengeRepository.Update(ctx, challenge)
+}
+
+// Lock the pending challenge of the customer and mark it as being confirmed.
+// A challenge whose claim has lapsed is claimed again, the reference id of its debit keeps it from being made twice
+func (s *WithdrawalConfirmationService) claimChallenge(ctx context.Context, customerXid, challengeId, challengeType string) (*WithdrawalChallenge, error) {
+	var challenge *WithdrawalChallenge
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		challenge, err = s.challengeRepository.FindByIdForUpdate(ctx, challengeId)
+		if err != nil {
+			return err
+		}
+		if challenge == nil || challenge.CustomerXid != customerXid || challenge.Type != challengeType {
+			return ErrChallengeNotFound
+		}
+		now := time.Now()
+		if challenge.IsClaimed(now) {
+			return ErrChallengeBeingConfirmed
+		}
+		if challenge.Status != CHALLENGE_STATUS_PENDING && challenge.Status != CHALLENGE_STATUS_CONFIRMING {
+			return ErrChallengeAlreadyConfirmed
+		}
+		if challenge.IsExpired(now) {
+			return ErrChallengeExpired
+		}
+
+		challenge.Status = CHALLENGE_STATUS_CONFIRMING
+		challenge.ClaimedAt = &now
+		return s.challengeRepository.Update(ctx, challenge)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// Mark the claimed challenge as pending again, so the customer can retry it before it expires
+func (s *WithdrawalConfirmationService) releaseChallenge(ctx context.Context, challenge *WithdrawalChallenge) {
+	challenge.Status = CHALLENGE_STATUS_PENDING
+	challenge.ClaimedAt = nil
+	err := s.challengeRepository.Update(ctx, challenge)
+	if err != nil {
+		logging.FromContext(ctx).Error("error releasing challenge", "challenge_id", challenge.Id, logging.KEY_ERROR, err)
+	}
+}
+
+func (s *WithdrawalConfirmationService) createChallenge(ctx context.Context, challenge *WithdrawalChallenge) (*WithdrawalChallenge, error) {
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge.Id = uuidRandom.String()
+	challenge.Status = CHALLENGE_STATUS_PENDING
+	challenge.ExpiresAt = now.Add(CHALLENGE_TTL)
+	challenge.CreatedAt = now
+	err = s.challengeRepository.Insert(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,435 @@
+package transaction_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	twofactor_mock "github.com/defryheryanto/mini-wallet/internal/twofactor/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithdrawalConfirmationService_RequestWithdrawal(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	params := &transaction.CreateWithdrawalParams{
+		CustomerXid: "xid",
+		ReferenceId: "ref",
+		Amount:      1000,
+	}
+	sourceWallet := &wallet.Wallet{Id: "wallet-id", Currency: "IDR"}
+
+	t.Run("should return error if withdrawal invalid", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(nil, mockedErr)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, trx)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create withdrawal if amount not above threshold", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(sourceWallet, nil)
+		transactionService.On("CreateWithdrawal", mock.Anything, params).Return(&transaction.Transaction{Id: "trx"}, nil)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{"IDR": 1000})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, "trx", trx.Id)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should confirm withdrawal if only another currency has a threshold", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(sourceWallet, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(true, nil)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{"USD": 1000})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Nil(t, trx)
+		assert.NotNil(t, challenge)
+	})
+	t.Run("should create withdrawal if customer not enrolled", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(sourceWallet, nil)
+		transactionService.On("CreateWithdrawal", mock.Anything, params).Return(&transaction.Transaction{Id: "trx"}, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(false, nil)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, "trx", trx.Id)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should return error if failed to insert challenge", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(sourceWallet, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(true, nil)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, trx)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create challenge if customer enrolled and amount above threshold", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateWithdrawal", mock.Anything, params).Return(sourceWallet, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(true, nil)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, challenge, err := service.RequestWithdrawal(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Nil(t, trx)
+		assert.NotEmpty(t, challenge.Id)
+		assert.Equal(t, transaction.CHALLENGE_STATUS_PENDING, challenge.Status)
+		assert.Equal(t, params.ReferenceId, challenge.ReferenceId)
+		assert.Equal(t, params.Amount, challenge.Amount)
+	})
+}
+
+func TestWithdrawalConfirmationService_ConfirmWithdrawal(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	challengeId := "challenge"
+	code := "123456"
+	pendingChallenge := func() *transaction.WithdrawalChallenge {
+		return &transaction.WithdrawalChallenge{
+			Id:          challengeId,
+			Type:        transaction.CHALLENGE_TYPE_WITHDRAWAL,
+			CustomerXid: xid,
+			ReferenceId: "ref",
+			Amount:      1000,
+			Status:      transaction.CHALLENGE_STATUS_PENDING,
+			ExpiresAt:   time.Now().Add(time.Minute),
+		}
+	}
+
+	t.Run("should return error if challenge owned by another customer", func(t *testing.T) {
+		challenge := pendingChallenge()
+		challenge.CustomerXid = "other"
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeNotFound, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should return error if challenge holds a transfer", func(t *testing.T) {
+		challenge := pendingChallenge()
+		challenge.Type = transaction.CHALLENGE_TYPE_TRANSFER
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeNotFound, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should return error if challenge already confirmed", func(t *testing.T) {
+		challenge := pendingChallenge()
+		challenge.Status = transaction.CHALLENGE_STATUS_CONFIRMED
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeAlreadyConfirmed, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should return error if challenge being confirmed", func(t *testing.T) {
+		claimedAt := time.Now().Add(-time.Second)
+		challenge := pendingChallenge()
+		challenge.Status = transaction.CHALLENGE_STATUS_CONFIRMING
+		challenge.ClaimedAt = &claimedAt
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeBeingConfirmed, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should return error if the lapsed claim of the challenge expired", func(t *testing.T) {
+		claimedAt := time.Now().Add(-transaction.CHALLENGE_CLAIM_LEASE)
+		challenge := pendingChallenge()
+		challenge.Status = transaction.CHALLENGE_STATUS_CONFIRMING
+		challenge.ClaimedAt = &claimedAt
+		challenge.ExpiresAt = time.Now().Add(-time.Second)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeExpired, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should claim again the challenge whose claim lapsed", func(t *testing.T) {
+		claimedAt := time.Now().Add(-transaction.CHALLENGE_CLAIM_LEASE)
+		challenge := pendingChallenge()
+		challenge.Status = transaction.CHALLENGE_STATUS_CONFIRMING
+		challenge.ClaimedAt = &claimedAt
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		claims := []*time.Time{}
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			claims = append(claims, args.Get(1).(*transaction.WithdrawalChallenge).ClaimedAt)
+		}).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(nil)
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("CreateWithdrawal", mock.Anything, mock.Anything).Return(&transaction.Transaction{Id: "trx"}, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Nil(t, err)
+		assert.Equal(t, "trx", trx.Id)
+		assert.Len(t, claims, 2)
+		assert.True(t, claims[0].After(claimedAt))
+		assert.Nil(t, claims[1])
+	})
+	t.Run("should return error if challenge expired", func(t *testing.T) {
+		challenge := pendingChallenge()
+		challenge.ExpiresAt = time.Now().Add(-time.Minute)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeExpired, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should return error if code invalid", func(t *testing.T) {
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(pendingChallenge(), nil)
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(twofactor.ErrInvalidCode)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+		assert.Nil(t, trx)
+		releaseParams := challengeRepository.Calls[len(challengeRepository.Calls)-1].Arguments.Get(1).(*transaction.WithdrawalChallenge)
+		assert.Equal(t, transaction.CHALLENGE_STATUS_PENDING, releaseParams.Status)
+	})
+	t.Run("should return error if failed to create withdrawal", func(t *testing.T) {
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(pendingChallenge(), nil)
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(nil)
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("CreateWithdrawal", mock.Anything, mock.Anything).Return(nil, mockedErr)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, trx)
+	})
+	t.Run("should create withdrawal and confirm challenge", func(t *testing.T) {
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(pendingChallenge(), nil)
+		statuses := []string{}
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*transaction.WithdrawalChallenge)
+			assert.True(t, ok, "params should be *WithdrawalChallenge")
+			statuses = append(statuses, updateParams.Status)
+		}).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(nil)
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("CreateWithdrawal", mock.Anything, &transaction.CreateWithdrawalParams{
+			CustomerXid: xid,
+			ReferenceId: "ref",
+			Amount:      1000,
+		}).Return(&transaction.Transaction{Id: "trx"}, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		trx, err := service.ConfirmWithdrawal(context.TODO(), xid, challengeId, code)
+		assert.Nil(t, err)
+		assert.Equal(t, "trx", trx.Id)
+		assert.Equal(t, []string{transaction.CHALLENGE_STATUS_CONFIRMING, transaction.CHALLENGE_STATUS_CONFIRMED}, statuses)
+	})
+}
+
+func TestWithdrawalConfirmationService_RequestTransfer(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	params := &transaction.CreateTransferParams{
+		CustomerXid:    "xid",
+		TargetWalletId: "target-wallet-id",
+		ReferenceId:    "ref",
+		Amount:         1000,
+	}
+	sourceWallet := &wallet.Wallet{Id: "wallet-id", OwnedBy: "xid", Currency: "IDR"}
+	otherClientWallet := &wallet.Wallet{Id: "target-wallet-id", OwnedBy: "other", Currency: "IDR"}
+	outgoing := &transaction.Transaction{Id: "outgoing"}
+	incoming := &transaction.Transaction{Id: "incoming"}
+
+	t.Run("should return error if transfer invalid", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateTransfer", mock.Anything, params).Return(nil, nil, mockedErr)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, challenge, err := service.RequestTransfer(context.TODO(), params)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, out)
+		assert.Nil(t, in)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create transfer to own wallet without confirmation", func(t *testing.T) {
+		ownWallet := &wallet.Wallet{Id: "target-wallet-id", OwnedBy: "xid", Currency: "IDR"}
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateTransfer", mock.Anything, params).Return(sourceWallet, ownWallet, nil)
+		transactionService.On("CreateTransfer", mock.Anything, params).Return(outgoing, incoming, nil)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, challenge, err := service.RequestTransfer(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, outgoing, out)
+		assert.Equal(t, incoming, in)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create transfer if amount not above threshold", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateTransfer", mock.Anything, params).Return(sourceWallet, otherClientWallet, nil)
+		transactionService.On("CreateTransfer", mock.Anything, params).Return(outgoing, incoming, nil)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{"IDR": 1000})
+
+		out, _, challenge, err := service.RequestTransfer(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, outgoing, out)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create transfer to another client if customer not enrolled", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateTransfer", mock.Anything, params).Return(sourceWallet, otherClientWallet, nil)
+		transactionService.On("CreateTransfer", mock.Anything, params).Return(outgoing, incoming, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(false, nil)
+		service := transaction.NewWithdrawalConfirmationService(transaction_mock.NewChallengeRepository(t), transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, challenge, err := service.RequestTransfer(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, outgoing, out)
+		assert.Equal(t, incoming, in)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should create challenge for transfer to another client if customer enrolled", func(t *testing.T) {
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("ValidateTransfer", mock.Anything, params).Return(sourceWallet, otherClientWallet, nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("IsEnrolled", mock.Anything, params.CustomerXid).Return(true, nil)
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, challenge, err := service.RequestTransfer(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Nil(t, out)
+		assert.Nil(t, in)
+		assert.NotEmpty(t, challenge.Id)
+		assert.Equal(t, transaction.CHALLENGE_TYPE_TRANSFER, challenge.Type)
+		assert.Equal(t, transaction.CHALLENGE_STATUS_PENDING, challenge.Status)
+		assert.Equal(t, sourceWallet.Id, challenge.WalletId)
+		assert.Equal(t, otherClientWallet.Id, challenge.TargetWalletId)
+		assert.Equal(t, params.Amount, challenge.Amount)
+	})
+}
+
+func TestWithdrawalConfirmationService_ConfirmTransfer(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	challengeId := "challenge"
+	code := "123456"
+	pendingChallenge := func() *transaction.WithdrawalChallenge {
+		return &transaction.WithdrawalChallenge{
+			Id:             challengeId,
+			Type:           transaction.CHALLENGE_TYPE_TRANSFER,
+			CustomerXid:    xid,
+			WalletId:       "wallet-id",
+			TargetWalletId: "target-wallet-id",
+			ReferenceId:    "ref",
+			Amount:         1000,
+			Currency:       "IDR",
+			Status:         transaction.CHALLENGE_STATUS_PENDING,
+			ExpiresAt:      time.Now().Add(time.Minute),
+		}
+	}
+
+	t.Run("should return error if challenge holds a withdrawal", func(t *testing.T) {
+		challenge := pendingChallenge()
+		challenge.Type = transaction.CHALLENGE_TYPE_WITHDRAWAL
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(challenge, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transaction_mock.NewTransactionIService(t), twofactor_mock.NewTwoFactorIService(t), &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, err := service.ConfirmTransfer(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, transaction.ErrChallengeNotFound, err)
+		assert.Nil(t, out)
+		assert.Nil(t, in)
+	})
+	t.Run("should release challenge if failed to create transfer", func(t *testing.T) {
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(pendingChallenge(), nil)
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(nil)
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, nil, mockedErr)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, err := service.ConfirmTransfer(context.TODO(), xid, challengeId, code)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, out)
+		assert.Nil(t, in)
+		releaseParams := challengeRepository.Calls[len(challengeRepository.Calls)-1].Arguments.Get(1).(*transaction.WithdrawalChallenge)
+		assert.Equal(t, transaction.CHALLENGE_STATUS_PENDING, releaseParams.Status)
+	})
+	t.Run("should create transfer and confirm challenge", func(t *testing.T) {
+		challengeRepository := transaction_mock.NewChallengeRepository(t)
+		challengeRepository.On("FindByIdForUpdate", mock.Anything, challengeId).Return(pendingChallenge(), nil)
+		var confirmed *transaction.WithdrawalChallenge
+		challengeRepository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			confirmed = args.Get(1).(*transaction.WithdrawalChallenge)
+		}).Return(nil)
+		twoFactorService := twofactor_mock.NewTwoFactorIService(t)
+		twoFactorService.On("Verify", mock.Anything, xid, code).Return(nil)
+		transactionService := transaction_mock.NewTransactionIService(t)
+		transactionService.On("CreateTransfer", mock.Anything, &transaction.CreateTransferParams{
+			CustomerXid:    xid,
+			WalletId:       "wallet-id",
+			TargetWalletId: "target-wallet-id",
+			ReferenceId:    "ref",
+			Amount:         1000,
+			Currency:       "IDR",
+		}).Return(&transaction.Transaction{Id: "outgoing"}, &transaction.Transaction{Id: "incoming"}, nil)
+		service := transaction.NewWithdrawalConfirmationService(challengeRepository, transactionService, twoFactorService, &manager.MockStorageManager{}, transaction.ConfirmationThresholds{})
+
+		out, in, err := service.ConfirmTransfer(context.TODO(), xid, challengeId, code)
+		assert.Nil(t, err)
+		assert.Equal(t, "outgoing", out.Id)
+		assert.Equal(t, "incoming", in.Id)
+		assert.Equal(t, transaction.CHALLENGE_STATUS_CONFIRMED, confirmed.Status)
+		assert.Equal(t, "outgoing", confirmed.TransactionId)
+	})
+}
@@ -1,5 +1,7 @@
 package transaction
 
+import "time"
+
 const (
 	STATUS_PENDING = "pending"
 	STATUS_SUCCESS = "success"
@@ -7,4 +9,38 @@ const (
 
 	TYPE_DEPOSIT    = "deposit"
 	TYPE_WITHDRAWAL = "withdrawal"
+	// Manual corrections of the balance made by operators
+	TYPE_ADJUSTMENT_CREDIT = "adjustment_credit"
+	TYPE_ADJUSTMENT_DEBIT  = "adjustment_debit"
+	// Remaining balance paid out when the wallet is closed
+	TYPE_PAYOUT = "payout"
+	// Both sides of a transfer between wallets, sharing the reference id
+	TYPE_TRANSFER_OUT = "transfer_out"
+	TYPE_TRANSFER_IN  = "transfer_in"
+	// Fees charged to the wallet, reported apart from the other debits on statements
+	TYPE_FEE = "fee"
+)
+
+// Types adding the amount to the balance of the wallet, the other types deduct it
+var CREDIT_TYPES = []string{TYPE_DEPOSIT, TYPE_ADJUSTMENT_CREDIT, TYPE_TRANSFER_IN}
+
+const SETTLEMENT_DELAY = 5 * time.Second
+
+const (
+	CHALLENGE_TYPE_WITHDRAWAL = "withdrawal"
+	CHALLENGE_TYPE_TRANSFER   = "transfer"
+
+	CHALLENGE_STATUS_PENDING = "pending"
+	// The code of the challenge is being verified and its debit made
+	CHALLENGE_STATUS_CONFIRMING = "confirming"
+	CHALLENGE_STATUS_CONFIRMED  = "confirmed"
+
+	CHALLENGE_TTL = 5 * time.Minute
+	// Time a confirmation holds the challenge, after which another confirmation can claim it
+	CHALLENGE_CLAIM_LEASE = time.Minute
 )
+
+// Period exported when the start of the period is not given
+const DEFAULT_EXPORT_PERIOD = 30 * 24 * time.Hour
+
+const TRACER_NAME = "github.com/defryheryanto/mini-wallet/internal/transaction"
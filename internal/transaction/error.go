@@ -1,9 +1,30 @@
 package transaction
 
 import (
+	goerrors "errors"
+
 	"github.com/defryheryanto/mini-wallet/internal/errors"
 )
 
 var ErrReferenceNoAlreadyExists = errors.NewValidationError("reference number already exists")
 var ErrEmptyCustomerXid = errors.NewValidationError("customer xid is required")
 var ErrEmptyReferenceId = errors.NewValidationError("reference id is required")
+var ErrSettlementStopped = errors.NewServiceUnavailableError("service is shutting down, try again later")
+var ErrInvalidTransactionType = errors.NewValidationError("transaction type invalid")
+var ErrChallengeNotFound = errors.NewNotFoundError("withdrawal challenge not found")
+var ErrChallengeAlreadyConfirmed = errors.NewValidationError("withdrawal challenge already confirmed")
+var ErrChallengeBeingConfirmed = errors.NewValidationError("withdrawal challenge being confirmed by another request")
+var ErrChallengeExpired = errors.NewValidationError("withdrawal challenge expired")
+var ErrTransactionNotFound = errors.NewNotFoundError("transaction not found")
+var ErrTransactionNotPending = errors.NewValidationError("transaction is not pending")
+var ErrEmptyWalletId = errors.NewValidationError("wallet id is required")
+var ErrInvalidAmount = errors.NewValidationError("amount must not be zero")
+var ErrNonPositiveAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrEmptyTargetWalletId = errors.NewValidationError("target wallet id is required")
+var ErrSameWallet = errors.NewValidationError("can't transfer to the same wallet")
+var ErrQuoteRequired = errors.NewValidationError("quote id is required to transfer between currencies")
+var ErrQuoteNotApplicable = errors.NewValidationError("quote can't be used to transfer within the same currency")
+var ErrInvalidPeriod = errors.NewValidationError("from must be before to")
+
+// Returned within the settlement to roll back when the transaction is no longer pending
+var errSettlementSkipped = goerrors.New("settlement skipped")
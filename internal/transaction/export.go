@@ -0,0 +1,153 @@
+package transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+)
+
+// ExportPeriod describes the exported period of a wallet
+type ExportPeriod struct {
+	WalletId string
+	Currency *currency.Currency
+	From     time.Time
+	To       time.Time
+	// Balance at the start of the period
+	OpeningBalance float64
+	// Balance at the end of the period, only set once every transaction is written
+	ClosingBalance float64
+}
+
+// ExportWriter writes the exported transactions in a file format
+type ExportWriter interface {
+	// Called once before the transactions
+	WriteOpening(period *ExportPeriod) error
+	// Called for each transaction in order, along with the balance right after the transaction
+	WriteTransaction(trx *Transaction, balance float64) error
+	// Called once after the transactions
+	WriteClosing(period *ExportPeriod) error
+}
+
+func IsCredit(trxType string) bool {
+	for _, creditType := range CREDIT_TYPES {
+		if trxType == creditType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the amount as it changes the balance, negative if the transaction deducts the balance
+func (t *Transaction) SignedAmount() float64 {
+	if IsCredit(t.Type) {
+		return t.Amount
+	}
+
+	return -t.Amount
+}
+
+// Return how much the successful transactions of the wallet made within [from, to) changed its balance.
+// Zero from sums every transaction before to
+func (s *TransactionService) GetBalanceChange(ctx context.Context, walletId string, from, to time.Time) (float64, error) {
+	if !from.Before(to) {
+		return 0, ErrInvalidPeriod
+	}
+
+	return s.repository.SumBalanceChange(ctx, walletId, from, to)
+}
+
+// Sum the successful transactions of the wallet within [from, to) along with the balance before and after the period
+func (s *TransactionService) SummarizePeriod(ctx context.Context, walletId string, from, to time.Time) (*PeriodSummary, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidPeriod
+	}
+
+	openingBalance, err := s.repository.SumBalanceChange(ctx, walletId, time.Time{}, from)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repository.SumAmountsByType(ctx, walletId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PeriodSummary{OpeningBalance: openingBalance}
+	for _, total := range totals {
+		switch {
+		case IsCredit(total.Type):
+			summary.TotalCredits += total.Amount
+		case total.Type == TYPE_FEE:
+			summary.TotalFees += total.Amount
+		default:
+			summary.TotalDebits += total.Amount
+		}
+		summary.TransactionCount += total.Count
+	}
+	summary.ClosingBalance = summary.OpeningBalance + summary.TotalCredits - summary.TotalDebits - summary.TotalFees
+
+	return summary, nil
+}
+
+// Write the successful transactions of the wallet within the period to the writer along with the running balance.
+// Transactions are streamed from the database as they are written, so the whole period is never held in memory
+func (s *TransactionService) ExportTransactions(ctx context.Context, params *ExportTransactionsParams, writer ExportWriter) error {
+	if params.CustomerXid == "" {
+		return ErrEmptyCustomerXid
+	}
+
+	to := params.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := params.From
+	if from.IsZero() {
+		from = to.Add(-DEFAULT_EXPORT_PERIOD)
+	}
+	if !from.Before(to) {
+		return ErrInvalidPeriod
+	}
+
+	targetWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
+	if err != nil {
+		return err
+	}
+	if targetWallet.Status == wallet.STATUS_DISABLED {
+		return wallet.ErrWalletDisabled
+	}
+	walletCurrency, err := currency.Find(targetWallet.Currency)
+	if err != nil {
+		return err
+	}
+
+	openingBalance, err := s.repository.SumBalanceChange(ctx, targetWallet.Id, time.Time{}, from)
+	if err != nil {
+		return err
+	}
+
+	period := &ExportPeriod{
+		WalletId:       targetWallet.Id,
+		Currency:       walletCurrency,
+		From:           from,
+		To:             to,
+		OpeningBalance: walletCurrency.Round(openingBalance),
+	}
+	err = writer.WriteOpening(period)
+	if err != nil {
+		return err
+	}
+
+	balance := period.OpeningBalance
+	err = s.repository.StreamSuccessTransactions(ctx, targetWallet.Id, from, to, func(trx *Transaction) error {
+		balance = walletCurrency.Round(balance + trx.SignedAmount())
+		return writer.WriteTransaction(trx, balance)
+	})
+	if err != nil {
+		return err
+	}
+
+	period.ClosingBalance = balance
+	return writer.WriteClosing(period)
+}
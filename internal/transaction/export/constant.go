@@ -0,0 +1,16 @@
+package export
+
+const (
+	FORMAT_CSV   = "csv"
+	FORMAT_OFX   = "ofx"
+	FORMAT_JSONL = "jsonl"
+)
+
+const (
+	RECORD_OPENING_BALANCE = "opening_balance"
+	RECORD_TRANSACTION     = "transaction"
+	RECORD_CLOSING_BALANCE = "closing_balance"
+)
+
+// Layout of the date and time in OFX, always in UTC
+const OFX_TIME_LAYOUT = "20060102150405.000[+0:UTC]"
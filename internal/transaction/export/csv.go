@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+)
+
+var csvHeader = []string{
+	"record",
+	"transacted_at",
+	"id",
+	"type",
+	"status",
+	"amount",
+	"currency",
+	"reference_id",
+	"counterparty_wallet_id",
+	"balance",
+}
+
+// CSVWriter writes a row for each transaction between the opening and closing balance rows.
+// Debits have negative amounts
+type CSVWriter struct {
+	w      *csv.Writer
+	period *transaction.ExportPeriod
+}
+
+func NewCSVWriter(w io.Writer) transaction.ExportWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (c *CSVWriter) WriteOpening(period *transaction.ExportPeriod) error {
+	c.period = period
+
+	err := c.w.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	return c.writeBalance(RECORD_OPENING_BALANCE, period.From, period.OpeningBalance)
+}
+
+func (c *CSVWriter) WriteTransaction(trx *transaction.Transaction, balance float64) error {
+	return c.w.Write([]string{
+		RECORD_TRANSACTION,
+		trx.TransactedAt.UTC().Format(time.RFC3339),
+		trx.Id,
+		trx.Type,
+		trx.Status,
+		c.period.Currency.Format(trx.SignedAmount()),
+		trx.Currency,
+		trx.ReferenceId,
+		trx.CounterpartyWalletId,
+		c.period.Currency.Format(balance),
+	})
+}
+
+func (c *CSVWriter) WriteClosing(period *transaction.ExportPeriod) error {
+	err := c.writeBalance(RECORD_CLOSING_BALANCE, period.To, period.ClosingBalance)
+	if err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *CSVWriter) writeBalance(record string, at time.Time, balance float64) error {
+	return c.w.Write([]string{
+		record,
+		at.UTC().Format(time.RFC3339),
+		"",
+		"",
+		"",
+		"",
+		c.period.Currency.Code,
+		"",
+		"",
+		c.period.Currency.Format(balance),
+	})
+}
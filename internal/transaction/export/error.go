@@ -0,0 +1,5 @@
+package export
+
+import "github.com/defryheryanto/mini-wallet/internal/errors"
+
+var ErrUnsupportedFormat = errors.NewValidationError("format must be one of csv, ofx or jsonl")
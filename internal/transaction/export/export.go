@@ -0,0 +1,51 @@
+package export
+
+import (
+	"io"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+)
+
+// Format is a file format the transactions can be exported to
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newWriter   func(w io.Writer) transaction.ExportWriter
+}
+
+var formats = map[string]*Format{
+	FORMAT_CSV: {
+		Name:        FORMAT_CSV,
+		ContentType: "text/csv; charset=utf-8",
+		Extension:   "csv",
+		newWriter:   NewCSVWriter,
+	},
+	FORMAT_OFX: {
+		Name:        FORMAT_OFX,
+		ContentType: "application/x-ofx",
+		Extension:   "ofx",
+		newWriter:   NewOFXWriter,
+	},
+	FORMAT_JSONL: {
+		Name:        FORMAT_JSONL,
+		ContentType: "application/jsonl",
+		Extension:   "jsonl",
+		newWriter:   NewJSONLWriter,
+	},
+}
+
+// Return the format of the given name
+func Find(name string) (*Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	return format, nil
+}
+
+// Return the writer of the format writing to w
+func (f *Format) NewWriter(w io.Writer) transaction.ExportWriter {
+	return f.newWriter(w)
+}
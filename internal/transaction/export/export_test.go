@@ -0,0 +1,98 @@
+package export_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/transaction/export"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// Write the same period in the format, as the service would
+func writePeriod(t *testing.T, writer transaction.ExportWriter) {
+	idr, _ := currency.Find("IDR")
+	period := &transaction.ExportPeriod{
+		WalletId:       "wallet-id",
+		Currency:       idr,
+		From:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: 1000,
+	}
+	transactions := []*transaction.Transaction{
+		{
+			Id:           "trx-1",
+			Status:       transaction.STATUS_SUCCESS,
+			TransactedAt: time.Date(2024, 1, 5, 9, 30, 0, 0, time.UTC),
+			Type:         transaction.TYPE_DEPOSIT,
+			Amount:       250.5,
+			Currency:     "IDR",
+			ReferenceId:  "ref-1",
+			WalletId:     "wallet-id",
+		},
+		{
+			Id:           "trx-2",
+			Status:       transaction.STATUS_SUCCESS,
+			TransactedAt: time.Date(2024, 1, 10, 17, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+			Type:         transaction.TYPE_WITHDRAWAL,
+			Amount:       100,
+			Currency:     "IDR",
+			ReferenceId:  `rent "january", <flat> & co`,
+			WalletId:     "wallet-id",
+		},
+		{
+			Id:                   "trx-3",
+			Status:               transaction.STATUS_SUCCESS,
+			TransactedAt:         time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC),
+			Type:                 transaction.TYPE_TRANSFER_OUT,
+			Amount:               0.1,
+			Currency:             "IDR",
+			ReferenceId:          "ref-3",
+			WalletId:             "wallet-id",
+			CounterpartyWalletId: "other-wallet-id",
+		},
+	}
+
+	assert.Nil(t, writer.WriteOpening(period))
+	balance := period.OpeningBalance
+	for _, trx := range transactions {
+		balance = idr.Round(balance + trx.SignedAmount())
+		assert.Nil(t, writer.WriteTransaction(trx, balance))
+	}
+	period.ClosingBalance = balance
+	assert.Nil(t, writer.WriteClosing(period))
+}
+
+func TestFormat_NewWriter(t *testing.T) {
+	for _, name := range []string{export.FORMAT_CSV, export.FORMAT_OFX, export.FORMAT_JSONL} {
+		t.Run("should match the golden file of "+name, func(t *testing.T) {
+			format, err := export.Find(name)
+			assert.Nil(t, err)
+
+			output := &bytes.Buffer{}
+			writePeriod(t, format.NewWriter(output))
+
+			golden := filepath.Join("testdata", "export."+format.Extension+".golden")
+			if *update {
+				assert.Nil(t, os.WriteFile(golden, output.Bytes(), 0644))
+			}
+			expected, err := os.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expected), output.String())
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Run("should return error if format is not supported", func(t *testing.T) {
+		_, err := export.Find("xlsx")
+		assert.Equal(t, export.ErrUnsupportedFormat, err)
+	})
+}
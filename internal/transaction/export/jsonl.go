@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+)
+
+type jsonlBalance struct {
+	Record   string      `json:"record"`
+	WalletId string      `json:"wallet_id"`
+	At       time.Time   `json:"at"`
+	Balance  json.Number `json:"balance"`
+	Currency string      `json:"currency"`
+}
+
+type jsonlTransaction struct {
+	Record               string      `json:"record"`
+	Id                   string      `json:"id"`
+	TransactedAt         time.Time   `json:"transacted_at"`
+	Type                 string      `json:"type"`
+	Status               string      `json:"status"`
+	Amount               json.Number `json:"amount"`
+	Currency             string      `json:"currency"`
+	ReferenceId          string      `json:"reference_id"`
+	CounterpartyWalletId string      `json:"counterparty_wallet_id,omitempty"`
+	Balance              json.Number `json:"balance"`
+}
+
+// JSONLWriter writes a JSON object per line, the opening balance first and the closing balance last.
+// Amounts are numbers with the minor units of the currency, debits are negative
+type JSONLWriter struct {
+	encoder *json.Encoder
+	period  *transaction.ExportPeriod
+}
+
+func NewJSONLWriter(w io.Writer) transaction.ExportWriter {
+	return &JSONLWriter{encoder: json.NewEncoder(w)}
+}
+
+func (j *JSONLWriter) WriteOpening(period *transaction.ExportPeriod) error {
+	j.period = period
+
+	return j.writeBalance(RECORD_OPENING_BALANCE, period.From, period.OpeningBalance)
+}
+
+func (j *JSONLWriter) WriteTransaction(trx *transaction.Transaction, balance float64) error {
+	return j.encoder.Encode(&jsonlTransaction{
+		Record:               RECORD_TRANSACTION,
+		Id:                   trx.Id,
+		TransactedAt:         trx.TransactedAt.UTC(),
+		Type:                 trx.Type,
+		Status:               trx.Status,
+		Amount:               json.Number(j.period.Currency.Format(trx.SignedAmount())),
+		Currency:             trx.Currency,
+		ReferenceId:          trx.ReferenceId,
+		CounterpartyWalletId: trx.CounterpartyWalletId,
+		Balance:              json.Number(j.period.Currency.Format(balance)),
+	})
+}
+
+func (j *JSONLWriter) WriteClosing(period *transaction.ExportPeriod) error {
+	return j.writeBalance(RECORD_CLOSING_BALANCE, period.To, period.ClosingBalance)
+}
+
+func (j *JSONLWriter) writeBalance(record string, at time.Time, balance float64) error {
+	return j.encoder.Encode(&jsonlBalance{
+		Record:   record,
+		WalletId: j.period.WalletId,
+		At:       at.UTC(),
+		Balance:  json.Number(j.period.Currency.Format(balance)),
+		Currency: j.period.Currency.Code,
+	})
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+)
+
+const ofxOpening = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <SIGNONMSGSRSV1>
+    <SONRS>
+      <STATUS>
+        <CODE>0</CODE>
+        <SEVERITY>INFO</SEVERITY>
+      </STATUS>
+      <DTSERVER>%[1]s</DTSERVER>
+      <LANGUAGE>ENG</LANGUAGE>
+    </SONRS>
+  </SIGNONMSGSRSV1>
+  <BANKMSGSRSV1>
+    <STMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <STATUS>
+        <CODE>0</CODE>
+        <SEVERITY>INFO</SEVERITY>
+      </STATUS>
+      <STMTRS>
+        <CURDEF>%[2]s</CURDEF>
+        <BANKACCTFROM>
+          <BANKID>mini-wallet</BANKID>
+          <ACCTID>%[3]s</ACCTID>
+          <ACCTTYPE>CHECKING</ACCTTYPE>
+        </BANKACCTFROM>
+        <BANKTRANLIST>
+          <DTSTART>%[4]s</DTSTART>
+          <DTEND>%[1]s</DTEND>
+`
+
+const ofxTransaction = `          <STMTTRN>
+            <TRNTYPE>%s</TRNTYPE>
+            <DTPOSTED>%s</DTPOSTED>
+            <TRNAMT>%s</TRNAMT>
+            <FITID>%s</FITID>
+            <NAME>%s</NAME>
+            <MEMO>%s</MEMO>
+          </STMTTRN>
+`
+
+// The opening balance has no element of its own in OFX, so it's listed in BALLIST
+const ofxClosing = `        </BANKTRANLIST>
+        <LEDGERBAL>
+          <BALAMT>%[1]s</BALAMT>
+          <DTASOF>%[2]s</DTASOF>
+        </LEDGERBAL>
+        <BALLIST>
+          <BAL>
+            <NAME>Opening balance</NAME>
+            <DESC>Balance at the start of the statement</DESC>
+            <BALTYPE>DOLLAR</BALTYPE>
+            <VALUE>%[3]s</VALUE>
+            <DTASOF>%[4]s</DTASOF>
+          </BAL>
+        </BALLIST>
+      </STMTRS>
+    </STMTTRNRS>
+  </BANKMSGSRSV1>
+</OFX>
+`
+
+// OFXWriter writes an OFX 2.2 bank statement of the wallet
+type OFXWriter struct {
+	w      io.Writer
+	period *transaction.ExportPeriod
+}
+
+func NewOFXWriter(w io.Writer) transaction.ExportWriter {
+	return &OFXWriter{w: w}
+}
+
+func (o *OFXWriter) WriteOpening(period *transaction.ExportPeriod) error {
+	o.period = period
+
+	_, err := fmt.Fprintf(o.w, ofxOpening,
+		formatOFXTime(period.To),
+		period.Currency.Code,
+		escapeOFX(period.WalletId),
+		formatOFXTime(period.From),
+	)
+	return err
+}
+
+func (o *OFXWriter) WriteTransaction(trx *transaction.Transaction, balance float64) error {
+	trxType := "DEBIT"
+	if transaction.IsCredit(trx.Type) {
+		trxType = "CREDIT"
+	}
+
+	_, err := fmt.Fprintf(o.w, ofxTransaction,
+		trxType,
+		formatOFXTime(trx.TransactedAt),
+		o.period.Currency.Format(trx.SignedAmount()),
+		escapeOFX(trx.Id),
+		escapeOFX(trx.Type),
+		escapeOFX(trx.ReferenceId),
+	)
+	return err
+}
+
+func (o *OFXWriter) WriteClosing(period *transaction.ExportPeriod) error {
+	_, err := fmt.Fprintf(o.w, ofxClosing,
+		period.Currency.Format(period.ClosingBalance),
+		formatOFXTime(period.To),
+		period.Currency.Format(period.OpeningBalance),
+		formatOFXTime(period.From),
+	)
+	return err
+}
+
+func formatOFXTime(t time.Time) string {
+	return t.UTC().Format(OFX_TIME_LAYOUT)
+}
+
+func escapeOFX(value string) string {
+	escaped := &strings.Builder{}
+	xml.EscapeText(escaped, []byte(value))
+
+	return escaped.String()
+}
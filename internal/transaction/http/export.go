@@ -0,0 +1,106 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/defryheryanto/mini-wallet/internal/transaction/export"
+	"github.com/go-chi/chi/v5"
+)
+
+// attachmentWriter sets the headers of the attachment on the first write,
+// so errors found before anything is exported are still responded as JSON
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, a.filename))
+		a.w.WriteHeader(http.StatusOK)
+	}
+
+	return a.w.Write(p)
+}
+
+// Stream the transactions of the wallet in the requested format.
+// `from` and `to` are RFC 3339 times or dates, `to` as a date includes the whole day
+func HandleExportTransactions(service transaction.TransactionIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		format, err := export.Find(query.Get("format"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		from, err := parseExportTime(query.Get("from"), "from", false)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		to, err := parseExportTime(query.Get("to"), "to", true)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		attachment := &attachmentWriter{
+			w:           w,
+			contentType: format.ContentType,
+			filename:    fmt.Sprintf("transactions-%s.%s", time.Now().UTC().Format("20060102"), format.Extension),
+		}
+		err = service.ExportTransactions(r.Context(), &transaction.ExportTransactionsParams{
+			CustomerXid: currentClient.Xid,
+			WalletId:    chi.URLParam(r, "wallet_id"),
+			From:        from,
+			To:          to,
+		}, format.NewWriter(attachment))
+		if err != nil {
+			if !attachment.started {
+				response.Failed(w, err)
+				return
+			}
+			// The status is already sent, the client only sees the export cut short
+			logging.FromContext(r.Context()).Error("error exporting transactions", logging.KEY_ERROR, err)
+		}
+	}
+}
+
+func parseExportTime(value, field string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.NewValidationError(field + " must be RFC 3339 time or date")
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+
+	return parsed, nil
+}
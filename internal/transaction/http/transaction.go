@@ -10,6 +10,7 @@ import (
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
 	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/go-chi/chi/v5"
 )
 
 type TransactionResponse struct {
@@ -18,7 +19,10 @@ type TransactionResponse struct {
 	TransactedAt time.Time `json:"transacted_at"`
 	Type         string    `json:"type"`
 	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
 	ReferenceId  string    `json:"reference_id"`
+
+	CounterpartyWalletId string `json:"counterparty_wallet_id,omitempty"`
 }
 
 type DepositResponse struct {
@@ -27,6 +31,7 @@ type DepositResponse struct {
 	Status      string    `json:"status"`
 	DepositedAt time.Time `json:"deposited_at"`
 	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
 	ReferenceId string    `json:"reference_id"`
 }
 
@@ -36,17 +41,54 @@ type WithdrawalResponse struct {
 	Status      string    `json:"status"`
 	WithdrawnAt time.Time `json:"withdrawn_at"`
 	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
 	ReferenceId string    `json:"reference_id"`
 }
 
+type WithdrawalChallengeResponse struct {
+	RequiresConfirmation bool      `json:"requires_confirmation"`
+	ChallengeId          string    `json:"challenge_id"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+// TransferResponse describes both sides of the transfer,
+// the converted amount is in the currency of the target wallet
+type TransferResponse struct {
+	Id                string    `json:"id"`
+	TransferredBy     string    `json:"transferred_by"`
+	Status            string    `json:"status"`
+	TransferredAt     time.Time `json:"transferred_at"`
+	FromWalletId      string    `json:"from_wallet_id"`
+	ToWalletId        string    `json:"to_wallet_id"`
+	Amount            float64   `json:"amount"`
+	Currency          string    `json:"currency"`
+	ConvertedAmount   float64   `json:"converted_amount"`
+	ConvertedCurrency string    `json:"converted_currency"`
+	ReferenceId       string    `json:"reference_id"`
+}
+
 type CreateDepositRequest struct {
 	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
 	ReferenceId string  `json:"reference_id"`
 }
 
 type CreateWithdrawalRequest struct {
 	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+	ReferenceId string  `json:"reference_id"`
+}
+
+type CreateTransferRequest struct {
+	ToWalletId  string  `json:"to_wallet_id"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
 	ReferenceId string  `json:"reference_id"`
+	QuoteId     string  `json:"quote_id"`
+}
+
+type ConfirmWithdrawalRequest struct {
+	Otp string `json:"otp"`
 }
 
 func HandleGetWalletTransactions(service transaction.TransactionIService) http.HandlerFunc {
@@ -57,7 +99,7 @@ func HandleGetWalletTransactions(service transaction.TransactionIService) http.H
 			return
 		}
 
-		transactions, err := service.GetTransactionsByCustomerXid(r.Context(), currentClient.Xid)
+		transactions, err := service.GetTransactionsByCustomerXid(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
 		if err != nil {
 			response.Failed(w, err)
 			return
@@ -72,7 +114,10 @@ func HandleGetWalletTransactions(service transaction.TransactionIService) http.H
 				TransactedAt: tr.TransactedAt,
 				Type:         tr.Type,
 				Amount:       tr.Amount,
+				Currency:     tr.Currency,
 				ReferenceId:  tr.ReferenceId,
+
+				CounterpartyWalletId: tr.CounterpartyWalletId,
 			})
 		}
 
@@ -122,8 +167,10 @@ func HandleCreateDeposit(service transaction.TransactionIService) http.HandlerFu
 
 		trx, err := service.CreateDeposit(r.Context(), &transaction.CreateDepositParams{
 			CustomerXid: currentClient.Xid,
+			WalletId:    chi.URLParam(r, "wallet_id"),
 			ReferenceId: requestBody.ReferenceId,
 			Amount:      requestBody.Amount,
+			Currency:    requestBody.Currency,
 		})
 		if err != nil {
 			response.Failed(w, err)
@@ -136,12 +183,15 @@ func HandleCreateDeposit(service transaction.TransactionIService) http.HandlerFu
 			Status:      trx.Status,
 			DepositedAt: trx.TransactedAt,
 			Amount:      trx.Amount,
+			Currency:    trx.Currency,
 			ReferenceId: trx.ReferenceId,
 		})
 	}
 }
 
-func HandleCreateWithdrawal(service transaction.TransactionIService) http.HandlerFunc {
+// Create the withdrawal, or respond with 202 and the id of the challenge
+// if the withdrawal has to be confirmed with the TOTP code of the client
+func HandleCreateWithdrawal(service transaction.WithdrawalConfirmationIService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		errEmptyReferenceIdMsg := map[string]interface{}{
 			"reference_id": []string{
@@ -179,11 +229,63 @@ func HandleCreateWithdrawal(service transaction.TransactionIService) http.Handle
 			return
 		}
 
-		trx, err := service.CreateWithdrawal(r.Context(), &transaction.CreateWithdrawalParams{
+		trx, challenge, err := service.RequestWithdrawal(r.Context(), &transaction.CreateWithdrawalParams{
 			CustomerXid: currentClient.Xid,
+			WalletId:    chi.URLParam(r, "wallet_id"),
 			ReferenceId: requestBody.ReferenceId,
 			Amount:      requestBody.Amount,
+			Currency:    requestBody.Currency,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if challenge != nil {
+			response.Success(w, http.StatusAccepted, &WithdrawalChallengeResponse{
+				RequiresConfirmation: true,
+				ChallengeId:          challenge.Id,
+				ExpiresAt:            challenge.ExpiresAt,
+			})
+			return
+		}
+
+		response.Success(w, http.StatusCreated, &WithdrawalResponse{
+			Id:          trx.Id,
+			WithdrawnBy: currentClient.Xid,
+			Status:      trx.Status,
+			WithdrawnAt: trx.TransactedAt,
+			Amount:      trx.Amount,
+			Currency:    trx.Currency,
+			ReferenceId: trx.ReferenceId,
 		})
+	}
+}
+
+func HandleConfirmWithdrawal(service transaction.WithdrawalConfirmationIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &ConfirmWithdrawalRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"otp": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		trx, err := service.ConfirmWithdrawal(r.Context(), currentClient.Xid, chi.URLParam(r, "id"), requestBody.Otp)
 		if err != nil {
 			response.Failed(w, err)
 			return
@@ -195,7 +297,117 @@ func HandleCreateWithdrawal(service transaction.TransactionIService) http.Handle
 			Status:      trx.Status,
 			WithdrawnAt: trx.TransactedAt,
 			Amount:      trx.Amount,
+			Currency:    trx.Currency,
 			ReferenceId: trx.ReferenceId,
 		})
 	}
 }
+
+// Create the transfer, or respond with 202 and the id of the challenge
+// if the transfer to a wallet of another client has to be confirmed with the TOTP code of the client
+func HandleCreateTransfer(service transaction.WithdrawalConfirmationIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateTransferRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"to_wallet_id": []string{
+						"Missing data for required field.",
+					},
+					"reference_id": []string{
+						"Missing data for required field.",
+					},
+					"amount": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		outgoing, incoming, challenge, err := service.RequestTransfer(r.Context(), &transaction.CreateTransferParams{
+			CustomerXid:    currentClient.Xid,
+			WalletId:       chi.URLParam(r, "wallet_id"),
+			TargetWalletId: requestBody.ToWalletId,
+			ReferenceId:    requestBody.ReferenceId,
+			Amount:         requestBody.Amount,
+			Currency:       requestBody.Currency,
+			QuoteId:        requestBody.QuoteId,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+		if challenge != nil {
+			response.Success(w, http.StatusAccepted, &WithdrawalChallengeResponse{
+				RequiresConfirmation: true,
+				ChallengeId:          challenge.Id,
+				ExpiresAt:            challenge.ExpiresAt,
+			})
+			return
+		}
+
+		writeTransfer(w, currentClient.Xid, outgoing, incoming)
+	}
+}
+
+func HandleConfirmTransfer(service transaction.WithdrawalConfirmationIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &ConfirmWithdrawalRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"otp": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		outgoing, incoming, err := service.ConfirmTransfer(r.Context(), currentClient.Xid, chi.URLParam(r, "id"), requestBody.Otp)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		writeTransfer(w, currentClient.Xid, outgoing, incoming)
+	}
+}
+
+func writeTransfer(w http.ResponseWriter, customerXid string, outgoing, incoming *transaction.Transaction) {
+
+	response.Success(w, http.StatusCreated, &TransferResponse{
+		Id:                outgoing.Id,
+		TransferredBy:     customerXid,
+		Status:            outgoing.Status,
+		TransferredAt:     outgoing.TransactedAt,
+		FromWalletId:      outgoing.WalletId,
+		ToWalletId:        incoming.WalletId,
+		Amount:            outgoing.Amount,
+		Currency:          outgoing.Currency,
+		ConvertedAmount:   incoming.Amount,
+		ConvertedCurrency: incoming.Currency,
+		ReferenceId:       outgoing.ReferenceId,
+	})
+}
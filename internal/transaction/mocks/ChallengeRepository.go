@@ -0,0 +1,110 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ChallengeRepository is an autogenerated mock type for the ChallengeRepository type
+type ChallengeRepository struct {
+	mock.Mock
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *ChallengeRepository) FindById(ctx context.Context, id string) (*transaction.WithdrawalChallenge, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *transaction.WithdrawalChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*transaction.WithdrawalChallenge, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *transaction.WithdrawalChallenge); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.WithdrawalChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *ChallengeRepository) FindByIdForUpdate(ctx context.Context, id string) (*transaction.WithdrawalChallenge, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *transaction.WithdrawalChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*transaction.WithdrawalChallenge, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *transaction.WithdrawalChallenge); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.WithdrawalChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *ChallengeRepository) Insert(ctx context.Context, data *transaction.WithdrawalChallenge) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.WithdrawalChallenge) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *ChallengeRepository) Update(ctx context.Context, data *transaction.WithdrawalChallenge) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.WithdrawalChallenge) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewChallengeRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewChallengeRepository creates a new instance of ChallengeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewChallengeRepository(t mockConstructorTestingTNewChallengeRepository) *ChallengeRepository {
+	mock := &ChallengeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
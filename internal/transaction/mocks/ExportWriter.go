@@ -0,0 +1,70 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExportWriter is an autogenerated mock type for the ExportWriter type
+type ExportWriter struct {
+	mock.Mock
+}
+
+// WriteClosing provides a mock function with given fields: period
+func (_m *ExportWriter) WriteClosing(period *transaction.ExportPeriod) error {
+	ret := _m.Called(period)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*transaction.ExportPeriod) error); ok {
+		r0 = rf(period)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteOpening provides a mock function with given fields: period
+func (_m *ExportWriter) WriteOpening(period *transaction.ExportPeriod) error {
+	ret := _m.Called(period)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*transaction.ExportPeriod) error); ok {
+		r0 = rf(period)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteTransaction provides a mock function with given fields: trx, balance
+func (_m *ExportWriter) WriteTransaction(trx *transaction.Transaction, balance float64) error {
+	ret := _m.Called(trx, balance)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*transaction.Transaction, float64) error); ok {
+		r0 = rf(trx, balance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewExportWriter interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewExportWriter creates a new instance of ExportWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewExportWriter(t mockConstructorTestingTNewExportWriter) *ExportWriter {
+	mock := &ExportWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
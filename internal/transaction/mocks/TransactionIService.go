@@ -0,0 +1,451 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
+
+	wallet "github.com/defryheryanto/mini-wallet/internal/wallet"
+)
+
+// TransactionIService is an autogenerated mock type for the TransactionIService type
+type TransactionIService struct {
+	mock.Mock
+}
+
+// CloseWallet provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) CloseWallet(ctx context.Context, params *transaction.CloseWalletParams) (*wallet.Wallet, *transaction.Transaction, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *wallet.Wallet
+	var r1 *transaction.Transaction
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CloseWalletParams) (*wallet.Wallet, *transaction.Transaction, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CloseWalletParams) *wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CloseWalletParams) *transaction.Transaction); ok {
+		r1 = rf(ctx, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *transaction.CloseWalletParams) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateAdjustment provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) CreateAdjustment(ctx context.Context, params *transaction.CreateAdjustmentParams) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateAdjustmentParams) (*transaction.Transaction, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateAdjustmentParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateAdjustmentParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDeposit provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) CreateDeposit(ctx context.Context, params *transaction.CreateDepositParams) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateDepositParams) (*transaction.Transaction, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateDepositParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateDepositParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTransfer provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) CreateTransfer(ctx context.Context, params *transaction.CreateTransferParams) (*transaction.Transaction, *transaction.Transaction, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 *transaction.Transaction
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) (*transaction.Transaction, *transaction.Transaction, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateTransferParams) *transaction.Transaction); ok {
+		r1 = rf(ctx, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *transaction.CreateTransferParams) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateWithdrawal provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) CreateWithdrawal(ctx context.Context, params *transaction.CreateWithdrawalParams) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) (*transaction.Transaction, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateWithdrawalParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTransactions provides a mock function with given fields: ctx, params, writer
+func (_m *TransactionIService) ExportTransactions(ctx context.Context, params *transaction.ExportTransactionsParams, writer transaction.ExportWriter) error {
+	ret := _m.Called(ctx, params, writer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.ExportTransactionsParams, transaction.ExportWriter) error); ok {
+		r0 = rf(ctx, params, writer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailTransaction provides a mock function with given fields: ctx, id
+func (_m *TransactionIService) FailTransaction(ctx context.Context, id string) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*transaction.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceChange provides a mock function with given fields: ctx, walletId, from, to
+func (_m *TransactionIService) GetBalanceChange(ctx context.Context, walletId string, from time.Time, to time.Time) (float64, error) {
+	ret := _m.Called(ctx, walletId, from, to)
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (float64, error)); ok {
+		return rf(ctx, walletId, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) float64); ok {
+		r0 = rf(ctx, walletId, from, to)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionByReferenceId provides a mock function with given fields: ctx, referenceId, transactionType
+func (_m *TransactionIService) GetTransactionByReferenceId(ctx context.Context, referenceId string, transactionType string) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, referenceId, transactionType)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*transaction.Transaction, error)); ok {
+		return rf(ctx, referenceId, transactionType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, referenceId, transactionType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, referenceId, transactionType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionsByCustomerXid provides a mock function with given fields: ctx, xid, walletId
+func (_m *TransactionIService) GetTransactionsByCustomerXid(ctx context.Context, xid string, walletId string) ([]*transaction.Transaction, error) {
+	ret := _m.Called(ctx, xid, walletId)
+
+	var r0 []*transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*transaction.Transaction, error)); ok {
+		return rf(ctx, xid, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*transaction.Transaction); ok {
+		r0 = rf(ctx, xid, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, xid, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionsByWalletId provides a mock function with given fields: ctx, walletId
+func (_m *TransactionIService) GetTransactionsByWalletId(ctx context.Context, walletId string) ([]*transaction.Transaction, error) {
+	ret := _m.Called(ctx, walletId)
+
+	var r0 []*transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*transaction.Transaction, error)); ok {
+		return rf(ctx, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*transaction.Transaction); ok {
+		r0 = rf(ctx, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResumePendingSettlements provides a mock function with given fields: ctx
+func (_m *TransactionIService) ResumePendingSettlements(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RetrySettlement provides a mock function with given fields: ctx, id
+func (_m *TransactionIService) RetrySettlement(ctx context.Context, id string) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*transaction.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SummarizePeriod provides a mock function with given fields: ctx, walletId, from, to
+func (_m *TransactionIService) SummarizePeriod(ctx context.Context, walletId string, from time.Time, to time.Time) (*transaction.PeriodSummary, error) {
+	ret := _m.Called(ctx, walletId, from, to)
+
+	var r0 *transaction.PeriodSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (*transaction.PeriodSummary, error)); ok {
+		return rf(ctx, walletId, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) *transaction.PeriodSummary); ok {
+		r0 = rf(ctx, walletId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.PeriodSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateTransfer provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) ValidateTransfer(ctx context.Context, params *transaction.CreateTransferParams) (*wallet.Wallet, *wallet.Wallet, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *wallet.Wallet
+	var r1 *wallet.Wallet
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) (*wallet.Wallet, *wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) *wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateTransferParams) *wallet.Wallet); ok {
+		r1 = rf(ctx, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *transaction.CreateTransferParams) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ValidateWithdrawal provides a mock function with given fields: ctx, params
+func (_m *TransactionIService) ValidateWithdrawal(ctx context.Context, params *transaction.CreateWithdrawalParams) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) (*wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) *wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateWithdrawalParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTransactionIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTransactionIService creates a new instance of TransactionIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTransactionIService(t mockConstructorTestingTNewTransactionIService) *TransactionIService {
+	mock := &TransactionIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
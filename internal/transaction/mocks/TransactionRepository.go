@@ -4,9 +4,11 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
-	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
 	mock "github.com/stretchr/testify/mock"
+
+	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
 )
 
 // TransactionRepository is an autogenerated mock type for the TransactionRepository type
@@ -40,6 +42,32 @@ func (_m *TransactionRepository) FindById(ctx context.Context, id string) (*tran
 	return r0, r1
 }
 
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *TransactionRepository) FindByIdForUpdate(ctx context.Context, id string) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*transaction.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByReferenceId provides a mock function with given fields: ctx, referenceNo, transactionType
 func (_m *TransactionRepository) FindByReferenceId(ctx context.Context, referenceNo string, transactionType string) (*transaction.Transaction, error) {
 	ret := _m.Called(ctx, referenceNo, transactionType)
@@ -66,6 +94,32 @@ func (_m *TransactionRepository) FindByReferenceId(ctx context.Context, referenc
 	return r0, r1
 }
 
+// FindTransactionsByStatus provides a mock function with given fields: ctx, status
+func (_m *TransactionRepository) FindTransactionsByStatus(ctx context.Context, status string) ([]*transaction.Transaction, error) {
+	ret := _m.Called(ctx, status)
+
+	var r0 []*transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*transaction.Transaction, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*transaction.Transaction); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTransactionsByWalletId provides a mock function with given fields: ctx, walletId
 func (_m *TransactionRepository) FindTransactionsByWalletId(ctx context.Context, walletId string) ([]*transaction.Transaction, error) {
 	ret := _m.Called(ctx, walletId)
@@ -106,6 +160,70 @@ func (_m *TransactionRepository) Insert(ctx context.Context, data *transaction.T
 	return r0
 }
 
+// StreamSuccessTransactions provides a mock function with given fields: ctx, walletId, from, to, fn
+func (_m *TransactionRepository) StreamSuccessTransactions(ctx context.Context, walletId string, from time.Time, to time.Time, fn func(*transaction.Transaction) error) error {
+	ret := _m.Called(ctx, walletId, from, to, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, func(*transaction.Transaction) error) error); ok {
+		r0 = rf(ctx, walletId, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SumAmountsByType provides a mock function with given fields: ctx, walletId, from, to
+func (_m *TransactionRepository) SumAmountsByType(ctx context.Context, walletId string, from time.Time, to time.Time) ([]*transaction.TypeTotal, error) {
+	ret := _m.Called(ctx, walletId, from, to)
+
+	var r0 []*transaction.TypeTotal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]*transaction.TypeTotal, error)); ok {
+		return rf(ctx, walletId, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []*transaction.TypeTotal); ok {
+		r0 = rf(ctx, walletId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transaction.TypeTotal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SumBalanceChange provides a mock function with given fields: ctx, walletId, from, to
+func (_m *TransactionRepository) SumBalanceChange(ctx context.Context, walletId string, from time.Time, to time.Time) (float64, error) {
+	ret := _m.Called(ctx, walletId, from, to)
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (float64, error)); ok {
+		return rf(ctx, walletId, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) float64); ok {
+		r0 = rf(ctx, walletId, from, to)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, data
 func (_m *TransactionRepository) Update(ctx context.Context, data *transaction.Transaction) error {
 	ret := _m.Called(ctx, data)
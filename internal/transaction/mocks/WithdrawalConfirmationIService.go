@@ -0,0 +1,194 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	transaction "github.com/defryheryanto/mini-wallet/internal/transaction"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WithdrawalConfirmationIService is an autogenerated mock type for the WithdrawalConfirmationIService type
+type WithdrawalConfirmationIService struct {
+	mock.Mock
+}
+
+// ConfirmTransfer provides a mock function with given fields: ctx, customerXid, challengeId, code
+func (_m *WithdrawalConfirmationIService) ConfirmTransfer(ctx context.Context, customerXid string, challengeId string, code string) (*transaction.Transaction, *transaction.Transaction, error) {
+	ret := _m.Called(ctx, customerXid, challengeId, code)
+
+	var r0 *transaction.Transaction
+	var r1 *transaction.Transaction
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*transaction.Transaction, *transaction.Transaction, error)); ok {
+		return rf(ctx, customerXid, challengeId, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, customerXid, challengeId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) *transaction.Transaction); ok {
+		r1 = rf(ctx, customerXid, challengeId, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(ctx, customerXid, challengeId, code)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ConfirmWithdrawal provides a mock function with given fields: ctx, customerXid, challengeId, code
+func (_m *WithdrawalConfirmationIService) ConfirmWithdrawal(ctx context.Context, customerXid string, challengeId string, code string) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, customerXid, challengeId, code)
+
+	var r0 *transaction.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*transaction.Transaction, error)); ok {
+		return rf(ctx, customerXid, challengeId, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *transaction.Transaction); ok {
+		r0 = rf(ctx, customerXid, challengeId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerXid, challengeId, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestTransfer provides a mock function with given fields: ctx, params
+func (_m *WithdrawalConfirmationIService) RequestTransfer(ctx context.Context, params *transaction.CreateTransferParams) (*transaction.Transaction, *transaction.Transaction, *transaction.WithdrawalChallenge, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 *transaction.Transaction
+	var r2 *transaction.WithdrawalChallenge
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) (*transaction.Transaction, *transaction.Transaction, *transaction.WithdrawalChallenge, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateTransferParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateTransferParams) *transaction.Transaction); ok {
+		r1 = rf(ctx, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *transaction.CreateTransferParams) *transaction.WithdrawalChallenge); ok {
+		r2 = rf(ctx, params)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*transaction.WithdrawalChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, *transaction.CreateTransferParams) error); ok {
+		r3 = rf(ctx, params)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// RequestWithdrawal provides a mock function with given fields: ctx, params
+func (_m *WithdrawalConfirmationIService) RequestWithdrawal(ctx context.Context, params *transaction.CreateWithdrawalParams) (*transaction.Transaction, *transaction.WithdrawalChallenge, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *transaction.Transaction
+	var r1 *transaction.WithdrawalChallenge
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) (*transaction.Transaction, *transaction.WithdrawalChallenge, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateWithdrawalParams) *transaction.Transaction); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateWithdrawalParams) *transaction.WithdrawalChallenge); ok {
+		r1 = rf(ctx, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*transaction.WithdrawalChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *transaction.CreateWithdrawalParams) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RequiresConfirmation provides a mock function with given fields: ctx, customerXid, amount, currency
+func (_m *WithdrawalConfirmationIService) RequiresConfirmation(ctx context.Context, customerXid string, amount float64, currency string) (bool, error) {
+	ret := _m.Called(ctx, customerXid, amount, currency)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) (bool, error)); ok {
+		return rf(ctx, customerXid, amount, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) bool); ok {
+		r0 = rf(ctx, customerXid, amount, currency)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, string) error); ok {
+		r1 = rf(ctx, customerXid, amount, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWithdrawalConfirmationIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWithdrawalConfirmationIService creates a new instance of WithdrawalConfirmationIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWithdrawalConfirmationIService(t mockConstructorTestingTNewWithdrawalConfirmationIService) *WithdrawalConfirmationIService {
+	mock := &WithdrawalConfirmationIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
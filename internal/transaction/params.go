@@ -1,13 +1,64 @@
 package transaction
 
+import "time"
+
 type CreateDepositParams struct {
-	CustomerXid string  `json:"customer_xid"`
+	CustomerXid string `json:"customer_xid"`
+	// The default wallet of the customer if empty
+	WalletId    string  `json:"wallet_id"`
 	ReferenceId string  `json:"reference_no"`
 	Amount      float64 `json:"amount"`
+	// Has to match the currency of the wallet, assumed if empty
+	Currency string `json:"currency"`
 }
 
 type CreateWithdrawalParams struct {
-	CustomerXid string  `json:"customer_xid"`
+	CustomerXid string `json:"customer_xid"`
+	// The default wallet of the customer if empty
+	WalletId    string  `json:"wallet_id"`
 	ReferenceId string  `json:"reference_no"`
 	Amount      float64 `json:"amount"`
+	// Has to match the currency of the wallet, assumed if empty
+	Currency string `json:"currency"`
+}
+
+type CreateTransferParams struct {
+	CustomerXid string `json:"customer_xid"`
+	// Wallet of the customer the amount is taken from, the default wallet of the customer if empty
+	WalletId string `json:"wallet_id"`
+	// Wallet receiving the amount, owned by any client
+	TargetWalletId string  `json:"target_wallet_id"`
+	ReferenceId    string  `json:"reference_id"`
+	Amount         float64 `json:"amount"`
+	// Has to match the currency of the source wallet, assumed if empty
+	Currency string `json:"currency"`
+	// Quote locking the rate, required if the currencies of the wallets differ
+	QuoteId string `json:"quote_id"`
+}
+
+type CreateAdjustmentParams struct {
+	WalletId string `json:"wallet_id"`
+	// Generated if empty
+	ReferenceId string `json:"reference_id"`
+	// Positive amount credits the wallet, negative amount debits it
+	Amount float64 `json:"amount"`
+}
+
+type CloseWalletParams struct {
+	WalletId string `json:"wallet_id"`
+	Reason   string `json:"reason"`
+	// Pay out the remaining balance instead of rejecting to close a wallet with balance
+	PayoutRemainder bool `json:"payout_remainder"`
+	// Reference of the payout, generated if empty
+	ReferenceId string `json:"reference_id"`
+}
+
+type ExportTransactionsParams struct {
+	CustomerXid string `json:"customer_xid"`
+	// The default wallet of the customer if empty
+	WalletId string `json:"wallet_id"`
+	// Start of the period, DEFAULT_EXPORT_PERIOD before the end if zero
+	From time.Time `json:"from"`
+	// End of the period, exclusive. Now if zero
+	To time.Time `json:"to"`
 }
@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"context"
+
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewChallengeRepository(db *gorm.DB) *ChallengeRepository {
+	return &ChallengeRepository{db}
+}
+
+func (r *ChallengeRepository) Insert(ctx context.Context, data *transaction.WithdrawalChallenge) error {
+	payload := WithdrawalChallenge{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ChallengeRepository) FindById(ctx context.Context, id string) (*transaction.WithdrawalChallenge, error) {
+	result := &WithdrawalChallenge{}
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *ChallengeRepository) FindByIdForUpdate(ctx context.Context, id string) (*transaction.WithdrawalChallenge, error) {
+	result := &WithdrawalChallenge{}
+
+	err := r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *ChallengeRepository) Update(ctx context.Context, data *transaction.WithdrawalChallenge) error {
+	payload := WithdrawalChallenge{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ChallengeRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
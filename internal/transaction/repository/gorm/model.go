@@ -12,8 +12,11 @@ type Transaction struct {
 	TransactedAt time.Time `gorm:"column:transacted_at"`
 	Type         string    `gorm:"column:type"`
 	Amount       float64   `gorm:"column:amount"`
+	Currency     string    `gorm:"column:currency"`
 	ReferenceId  string    `gorm:"column:reference_id"`
 	WalletId     string    `gorm:"column:wallet_id"`
+
+	CounterpartyWalletId string `gorm:"column:counterparty_wallet_id"`
 }
 
 func (Transaction) TableName() string {
@@ -31,8 +34,11 @@ func (Transaction) FromServiceModel(data *transaction.Transaction) *Transaction
 		TransactedAt: data.TransactedAt,
 		Type:         data.Type,
 		Amount:       data.Amount,
+		Currency:     data.Currency,
 		ReferenceId:  data.ReferenceId,
 		WalletId:     data.WalletId,
+
+		CounterpartyWalletId: data.CounterpartyWalletId,
 	}
 }
 
@@ -43,8 +49,11 @@ func (c *Transaction) ToServiceModel() *transaction.Transaction {
 		TransactedAt: c.TransactedAt,
 		Type:         c.Type,
 		Amount:       c.Amount,
+		Currency:     c.Currency,
 		ReferenceId:  c.ReferenceId,
 		WalletId:     c.WalletId,
+
+		CounterpartyWalletId: c.CounterpartyWalletId,
 	}
 }
 
@@ -60,3 +69,76 @@ func SliceToServiceModel(data []*Transaction) []*transaction.Transaction {
 
 	return transactions
 }
+
+type WithdrawalChallenge struct {
+	Id             string     `gorm:"primaryKey;column:id"`
+	Type           string     `gorm:"column:type"`
+	CustomerXid    string     `gorm:"column:customer_xid"`
+	WalletId       string     `gorm:"column:wallet_id"`
+	TargetWalletId string     `gorm:"column:target_wallet_id"`
+	QuoteId        string     `gorm:"column:quote_id"`
+	ReferenceId    string     `gorm:"column:reference_id"`
+	Amount         float64    `gorm:"column:amount"`
+	Currency       string     `gorm:"column:currency"`
+	Status         string     `gorm:"column:status"`
+	TransactionId  *string    `gorm:"column:transaction_id"`
+	ExpiresAt      time.Time  `gorm:"column:expires_at"`
+	ClaimedAt      *time.Time `gorm:"column:claimed_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (WithdrawalChallenge) TableName() string {
+	return "withdrawal_challenges"
+}
+
+func (WithdrawalChallenge) FromServiceModel(data *transaction.WithdrawalChallenge) *WithdrawalChallenge {
+	if data == nil {
+		return nil
+	}
+
+	var transactionId *string
+	if data.TransactionId != "" {
+		transactionId = &data.TransactionId
+	}
+
+	return &WithdrawalChallenge{
+		Id:             data.Id,
+		Type:           data.Type,
+		CustomerXid:    data.CustomerXid,
+		WalletId:       data.WalletId,
+		TargetWalletId: data.TargetWalletId,
+		QuoteId:        data.QuoteId,
+		ReferenceId:    data.ReferenceId,
+		Amount:         data.Amount,
+		Currency:       data.Currency,
+		Status:         data.Status,
+		TransactionId:  transactionId,
+		ExpiresAt:      data.ExpiresAt,
+		ClaimedAt:      data.ClaimedAt,
+		CreatedAt:      data.CreatedAt,
+	}
+}
+
+func (c *WithdrawalChallenge) ToServiceModel() *transaction.WithdrawalChallenge {
+	transactionId := ""
+	if c.TransactionId != nil {
+		transactionId = *c.TransactionId
+	}
+
+	return &transaction.WithdrawalChallenge{
+		Id:             c.Id,
+		Type:           c.Type,
+		CustomerXid:    c.CustomerXid,
+		WalletId:       c.WalletId,
+		TargetWalletId: c.TargetWalletId,
+		QuoteId:        c.QuoteId,
+		ReferenceId:    c.ReferenceId,
+		Amount:         c.Amount,
+		Currency:       c.Currency,
+		Status:         c.Status,
+		TransactionId:  transactionId,
+		ExpiresAt:      c.ExpiresAt,
+		ClaimedAt:      c.ClaimedAt,
+		CreatedAt:      c.CreatedAt,
+	}
+}
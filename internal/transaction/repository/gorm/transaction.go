@@ -2,10 +2,12 @@ package gorm
 
 import (
 	"context"
+	"time"
 
 	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
 	"github.com/defryheryanto/mini-wallet/internal/transaction"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionRepository struct {
@@ -19,7 +21,18 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 func (r *TransactionRepository) FindTransactionsByWalletId(ctx context.Context, walletId string) ([]*transaction.Transaction, error) {
 	transactions := []*Transaction{}
 
-	err := r.db.Where("wallet_id = ?", walletId).Find(&transactions).Error
+	err := r.db.WithContext(ctx).Where("wallet_id = ?", walletId).Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(transactions), nil
+}
+
+func (r *TransactionRepository) FindTransactionsByStatus(ctx context.Context, status string) ([]*transaction.Transaction, error) {
+	transactions := []*Transaction{}
+
+	err := r.db.WithContext(ctx).Where("status = ?", status).Order("transacted_at").Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +43,7 @@ func (r *TransactionRepository) FindTransactionsByWalletId(ctx context.Context,
 func (r *TransactionRepository) FindByReferenceId(ctx context.Context, referenceId, transactionType string) (*transaction.Transaction, error) {
 	transaction := &Transaction{}
 
-	err := r.db.Where("reference_id = ? AND type = ?", referenceId, transactionType).First(&transaction).Error
+	err := r.db.WithContext(ctx).Where("reference_id = ? AND type = ?", referenceId, transactionType).First(&transaction).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -44,7 +57,22 @@ func (r *TransactionRepository) FindByReferenceId(ctx context.Context, reference
 func (r *TransactionRepository) FindById(ctx context.Context, id string) (*transaction.Transaction, error) {
 	transaction := &Transaction{}
 
-	err := r.db.Where("id = ?", id).First(&transaction).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&transaction).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return transaction.ToServiceModel(), nil
+}
+
+func (r *TransactionRepository) FindByIdForUpdate(ctx context.Context, id string) (*transaction.Transaction, error) {
+	transaction := &Transaction{}
+
+	db := r.getGormClient(ctx)
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&transaction).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -79,11 +107,73 @@ func (r *TransactionRepository) Update(ctx context.Context, data *transaction.Tr
 	return nil
 }
 
+func (r *TransactionRepository) SumBalanceChange(ctx context.Context, walletId string, from, to time.Time) (float64, error) {
+	var sum float64
+
+	query := r.getGormClient(ctx).Model(&Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN type IN ? THEN amount ELSE -amount END), 0)", transaction.CREDIT_TYPES).
+		Where("wallet_id = ? AND status = ? AND transacted_at < ?", walletId, transaction.STATUS_SUCCESS, to)
+	if !from.IsZero() {
+		query = query.Where("transacted_at >= ?", from)
+	}
+
+	err := query.Scan(&sum).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return sum, nil
+}
+
+func (r *TransactionRepository) StreamSuccessTransactions(ctx context.Context, walletId string, from, to time.Time, fn func(*transaction.Transaction) error) error {
+	db := r.getGormClient(ctx)
+	rows, err := db.Model(&Transaction{}).
+		Where("wallet_id = ? AND status = ? AND transacted_at >= ? AND transacted_at < ?", walletId, transaction.STATUS_SUCCESS, from, to).
+		Order("transacted_at, id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		trx := &Transaction{}
+		err = db.ScanRows(rows, trx)
+		if err != nil {
+			return err
+		}
+
+		err = fn(trx.ToServiceModel())
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *TransactionRepository) SumAmountsByType(ctx context.Context, walletId string, from, to time.Time) ([]*transaction.TypeTotal, error) {
+	totals := []*transaction.TypeTotal{}
+
+	db := r.getGormClient(ctx)
+	err := db.Model(&Transaction{}).
+		Select("type, SUM(amount) AS amount, COUNT(*) AS count").
+		Where("wallet_id = ? AND status = ? AND transacted_at >= ? AND transacted_at < ?", walletId, transaction.STATUS_SUCCESS, from, to).
+		Group("type").
+		Order("type").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
 func (r *TransactionRepository) getGormClient(ctx context.Context) *gorm.DB {
 	db, err := gorm_manager.ExtractClientFromContext(ctx)
 	if err != nil {
-		return r.db
+		return r.db.WithContext(ctx)
 	}
 
-	return db
+	return db.WithContext(ctx)
 }
@@ -0,0 +1,181 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SettlementWorker settles queued transactions in the background after the settlement delay.
+// It keeps track of every queued settlement so they can be drained when the application shuts down
+type SettlementWorker struct {
+	delay    time.Duration
+	observer func(trx *Transaction, latency time.Duration, err error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stopped bool
+	queued  map[string]bool
+}
+
+func NewSettlementWorker(delay time.Duration) *SettlementWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SettlementWorker{
+		delay:  delay,
+		ctx:    ctx,
+		cancel: cancel,
+		queued: map[string]bool{},
+	}
+}
+
+// Register the function called after every settlement with the time elapsed since the settlement is queued
+func (w *SettlementWorker) OnSettled(observer func(trx *Transaction, latency time.Duration, err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.observer = observer
+}
+
+func (w *SettlementWorker) Name() string {
+	return "settlement"
+}
+
+// Queue the settlement of the given transaction.
+// The settle function is called after the settlement delay, unless the worker is shut down before that.
+// Once called, the settle function is never interrupted by shutdown.
+// It receives the values of the given context, e.g. the logger, but not its cancellation,
+// and runs in a new trace linked to the span of the given context.
+//
+// Return ErrSettlementStopped if the worker is already shut down
+func (w *SettlementWorker) Enqueue(ctx context.Context, trx *Transaction, settle func(ctx context.Context) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stopped {
+		return ErrSettlementStopped
+	}
+	if w.queued[trx.Id] {
+		return nil
+	}
+	w.queued[trx.Id] = true
+	w.wg.Add(1)
+	queuedAt := time.Now()
+	observer := w.observer
+
+	ctx = context.WithoutCancel(ctx)
+	logger := logging.FromContext(ctx)
+	link := trace.LinkFromContext(ctx)
+
+	go func() {
+		defer w.wg.Done()
+		defer w.dequeue(trx.Id)
+
+		logger.Info("settlement queued")
+		timer := time.NewTimer(w.delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-w.ctx.Done():
+			logger.Warn("settlement abandoned on shutdown", "status", trx.Status)
+			return
+		}
+
+		ctx, span := otel.Tracer(TRACER_NAME).Start(ctx, "SettlementWorker.settle",
+			trace.WithNewRoot(),
+			trace.WithLinks(link),
+			trace.WithAttributes(
+				attribute.String("transaction.id", trx.Id),
+				attribute.String("transaction.type", trx.Type),
+				attribute.String("wallet.id", trx.WalletId),
+			),
+		)
+		err := settle(ctx)
+		if err != nil {
+			logger.Error("error settling transaction", logging.KEY_ERROR, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		if observer != nil {
+			observer(trx, time.Since(queuedAt), err)
+		}
+	}()
+
+	return nil
+}
+
+// Return true once the worker is shut down and no longer accepts settlements
+func (w *SettlementWorker) Stopped() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.stopped
+}
+
+// Return the number of settlements that are queued or running
+func (w *SettlementWorker) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.queued)
+}
+
+// Report the state of the settlement queue.
+//
+// Return ErrSettlementStopped if the worker no longer accepts settlements
+func (w *SettlementWorker) Health(ctx context.Context) (map[string]interface{}, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	details := map[string]interface{}{
+		"pending": len(w.queued),
+	}
+	if w.stopped {
+		return details, ErrSettlementStopped
+	}
+
+	return details, nil
+}
+
+// Stop accepting new settlements and wait for the running ones to finish.
+// Settlements that have not started yet are abandoned, their transactions stay pending in the storage
+// and are picked up again by ResumePendingSettlements on the next start.
+//
+// Return the context error if the running settlements did not finish before the context is done
+func (w *SettlementWorker) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		logging.FromContext(ctx).Warn("settlements still running on shutdown", "pending", w.Pending())
+		return ctx.Err()
+	}
+}
+
+func (w *SettlementWorker) dequeue(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.queued, id)
+}
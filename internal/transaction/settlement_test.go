@@ -0,0 +1,95 @@
+package transaction_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementWorker_Enqueue(t *testing.T) {
+	t.Run("should run settlement after the delay", func(t *testing.T) {
+		worker := transaction.NewSettlementWorker(time.Millisecond)
+		settled := make(chan struct{})
+
+		err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test"}, func(ctx context.Context) error {
+			close(settled)
+			return nil
+		})
+		assert.Nil(t, err)
+
+		select {
+		case <-settled:
+		case <-time.After(time.Second):
+			t.Fatal("settlement should be executed")
+		}
+	})
+
+	t.Run("should return error if worker is shut down", func(t *testing.T) {
+		worker := transaction.NewSettlementWorker(time.Millisecond)
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+
+		err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test"}, func(ctx context.Context) error {
+			return nil
+		})
+		assert.Equal(t, transaction.ErrSettlementStopped, err)
+	})
+}
+
+func TestSettlementWorker_Shutdown(t *testing.T) {
+	t.Run("should abandon settlements that have not started", func(t *testing.T) {
+		worker := transaction.NewSettlementWorker(time.Hour)
+		var called int32
+
+		err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test"}, func(ctx context.Context) error {
+			atomic.AddInt32(&called, 1)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, worker.Pending())
+
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&called))
+		assert.Equal(t, 0, worker.Pending())
+	})
+
+	t.Run("should wait for running settlements to finish", func(t *testing.T) {
+		worker := transaction.NewSettlementWorker(0)
+		started := make(chan struct{})
+		var finished int32
+
+		err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test"}, func(ctx context.Context) error {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			atomic.AddInt32(&finished, 1)
+			return nil
+		})
+		assert.Nil(t, err)
+		<-started
+
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&finished))
+	})
+
+	t.Run("should return error if running settlements exceed the deadline", func(t *testing.T) {
+		worker := transaction.NewSettlementWorker(0)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		err := worker.Enqueue(context.TODO(), &transaction.Transaction{Id: "test"}, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		assert.Nil(t, err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, worker.Shutdown(ctx))
+	})
+}
@@ -0,0 +1,51 @@
+package transaction
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+)
+
+// ConfirmationThresholds holds the amount above which a withdrawal has to be confirmed, by currency code.
+// The amounts of different currencies aren't comparable, so every currency has its own threshold
+type ConfirmationThresholds map[string]float64
+
+// Return true if the amount in the given currency is above its threshold.
+// Every amount of a currency without a threshold is above it, so it has to be confirmed
+func (t ConfirmationThresholds) Exceeds(amount float64, currencyCode string) bool {
+	threshold, ok := t[currencyCode]
+	return !ok || amount > threshold
+}
+
+// Parse the thresholds formatted as {currency}:{amount} separated by comma, e.g. "IDR:1000000,USD:100"
+func ParseConfirmationThresholds(value string) (ConfirmationThresholds, error) {
+	thresholds := ConfirmationThresholds{}
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, amount, found := strings.Cut(entry, ":")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !found || code == "" {
+			return nil, fmt.Errorf("invalid threshold entry #%d, expected {currency}:{amount}", i+1)
+		}
+		if !currency.IsSupported(code) {
+			return nil, fmt.Errorf("unsupported currency %q", code)
+		}
+		if _, exists := thresholds[code]; exists {
+			return nil, fmt.Errorf("duplicate threshold for currency %q", code)
+		}
+
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold of currency %q, expected a non-negative amount", code)
+		}
+		thresholds[code] = threshold
+	}
+
+	return thresholds, nil
+}
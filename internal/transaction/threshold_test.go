@@ -0,0 +1,50 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmationThresholds_Exceeds(t *testing.T) {
+	thresholds := transaction.ConfirmationThresholds{"IDR": 1_000_000, "USD": 100}
+
+	t.Run("should compare the amount with the threshold of its currency", func(t *testing.T) {
+		assert.False(t, thresholds.Exceeds(1_000_000, "IDR"))
+		assert.True(t, thresholds.Exceeds(1_000_001, "IDR"))
+		assert.True(t, thresholds.Exceeds(1_000, "USD"))
+	})
+	t.Run("should exceed for currency without threshold", func(t *testing.T) {
+		assert.True(t, thresholds.Exceeds(1, "JPY"))
+	})
+}
+
+func TestParseConfirmationThresholds(t *testing.T) {
+	t.Run("should parse thresholds by currency", func(t *testing.T) {
+		thresholds, err := transaction.ParseConfirmationThresholds("IDR:1000000, usd:100.5")
+		assert.Nil(t, err)
+		assert.Equal(t, transaction.ConfirmationThresholds{"IDR": 1_000_000, "USD": 100.5}, thresholds)
+	})
+	t.Run("should return empty thresholds if value empty", func(t *testing.T) {
+		thresholds, err := transaction.ParseConfirmationThresholds("")
+		assert.Nil(t, err)
+		assert.Empty(t, thresholds)
+	})
+	t.Run("should return error if currency missing", func(t *testing.T) {
+		_, err := transaction.ParseConfirmationThresholds("1000000")
+		assert.NotNil(t, err)
+	})
+	t.Run("should return error if currency unsupported", func(t *testing.T) {
+		_, err := transaction.ParseConfirmationThresholds("XYZ:100")
+		assert.NotNil(t, err)
+	})
+	t.Run("should return error if currency duplicated", func(t *testing.T) {
+		_, err := transaction.ParseConfirmationThresholds("USD:100,USD:200")
+		assert.NotNil(t, err)
+	})
+	t.Run("should return error if amount invalid", func(t *testing.T) {
+		_, err := transaction.ParseConfirmationThresholds("USD:-1")
+		assert.NotNil(t, err)
+	})
+}
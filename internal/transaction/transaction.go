@@ -2,11 +2,17 @@ package transaction
 
 import (
 	"context"
-	"log"
+	"math"
 	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
 	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
 	"github.com/google/uuid"
 )
 
@@ -16,40 +22,96 @@ type Transaction struct {
 	TransactedAt time.Time `json:"transacted_at"`
 	Type         string    `json:"type"`
 	Amount       float64   `json:"amount"`
-	ReferenceId  string    `json:"reference_id"`
-	WalletId     string    `json:"wallet_id"`
+	// ISO 4217 code of the currency of the amount, the currency of the wallet
+	Currency    string `json:"currency"`
+	ReferenceId string `json:"reference_id"`
+	WalletId    string `json:"wallet_id"`
+	// The other wallet of a transfer
+	CounterpartyWalletId string `json:"counterparty_wallet_id"`
+}
+
+// TypeTotal sums the transactions of the same type
+type TypeTotal struct {
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+}
+
+// PeriodSummary sums the successful transactions of a wallet within a period.
+// Credits and debits are positive, debits exclude the fees
+type PeriodSummary struct {
+	OpeningBalance   float64 `json:"opening_balance"`
+	TotalCredits     float64 `json:"total_credits"`
+	TotalDebits      float64 `json:"total_debits"`
+	TotalFees        float64 `json:"total_fees"`
+	ClosingBalance   float64 `json:"closing_balance"`
+	TransactionCount int     `json:"transaction_count"`
 }
 
 type TransactionRepository interface {
 	FindTransactionsByWalletId(ctx context.Context, walletId string) ([]*Transaction, error)
+	FindTransactionsByStatus(ctx context.Context, status string) ([]*Transaction, error)
 	FindByReferenceId(ctx context.Context, referenceNo, transactionType string) (*Transaction, error)
 	FindById(ctx context.Context, id string) (*Transaction, error)
+	// Find the transaction and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*Transaction, error)
 	Insert(ctx context.Context, data *Transaction) error
 	Update(ctx context.Context, data *Transaction) error
+	// Return the sum of the signed amounts of the successful transactions of the wallet made within [from, to).
+	// Zero from sums every transaction before to
+	SumBalanceChange(ctx context.Context, walletId string, from, to time.Time) (float64, error)
+	// Call fn for each successful transaction of the wallet made within [from, to) ordered by time, stopping at the first error
+	StreamSuccessTransactions(ctx context.Context, walletId string, from, to time.Time, fn func(*Transaction) error) error
+	// Return the totals of the successful transactions of the wallet made within [from, to) by type
+	SumAmountsByType(ctx context.Context, walletId string, from, to time.Time) ([]*TypeTotal, error)
 }
 
 type TransactionIService interface {
-	GetTransactionsByCustomerXid(ctx context.Context, xid string) ([]*Transaction, error)
+	GetTransactionsByCustomerXid(ctx context.Context, xid, walletId string) ([]*Transaction, error)
 	CreateDeposit(ctx context.Context, params *CreateDepositParams) (*Transaction, error)
 	CreateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*Transaction, error)
+	ValidateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*wallet.Wallet, error)
+	ResumePendingSettlements(ctx context.Context) error
+	GetTransactionsByWalletId(ctx context.Context, walletId string) ([]*Transaction, error)
+	RetrySettlement(ctx context.Context, id string) (*Transaction, error)
+	FailTransaction(ctx context.Context, id string) (*Transaction, error)
+	CreateAdjustment(ctx context.Context, params *CreateAdjustmentParams) (*Transaction, error)
+	CloseWallet(ctx context.Context, params *CloseWalletParams) (*wallet.Wallet, *Transaction, error)
+	CreateTransfer(ctx context.Context, params *CreateTransferParams) (*Transaction, *Transaction, error)
+	ValidateTransfer(ctx context.Context, params *CreateTransferParams) (*wallet.Wallet, *wallet.Wallet, error)
+	ExportTransactions(ctx context.Context, params *ExportTransactionsParams, writer ExportWriter) error
+	SummarizePeriod(ctx context.Context, walletId string, from, to time.Time) (*PeriodSummary, error)
+	GetBalanceChange(ctx context.Context, walletId string, from, to time.Time) (float64, error)
+	GetTransactionByReferenceId(ctx context.Context, referenceId, transactionType string) (*Transaction, error)
 }
 
 type TransactionService struct {
-	repository     TransactionRepository
-	walletService  wallet.WalletIService
-	storageManager manager.StorageManager
+	repository       TransactionRepository
+	walletService    wallet.WalletIService
+	fxService        fx.FxIService
+	webhookService   webhook.WebhookIService
+	eventService     events.EventIService
+	storageManager   manager.StorageManager
+	settlementWorker *SettlementWorker
+	activityBroker   *activity.Broker
 }
 
 func NewTransactionService(
 	repository TransactionRepository,
 	walletService wallet.WalletIService,
+	fxService fx.FxIService,
+	webhookService webhook.WebhookIService,
+	eventService events.EventIService,
 	storageManager manager.StorageManager,
+	settlementWorker *SettlementWorker,
+	activityBroker *activity.Broker,
 ) *TransactionService {
-	return &TransactionService{repository, walletService, storageManager}
+	return &TransactionService{repository, walletService, fxService, webhookService, eventService, storageManager, settlementWorker, activityBroker}
 }
 
-func (s *TransactionService) GetTransactionsByCustomerXid(ctx context.Context, xid string) ([]*Transaction, error) {
-	targetWallet, err := s.walletService.GetWalletByXid(ctx, xid)
+// Return the transactions of the given wallet of the customer, or of its default wallet if the wallet id is empty
+func (s *TransactionService) GetTransactionsByCustomerXid(ctx context.Context, xid, walletId string) ([]*Transaction, error) {
+	targetWallet, err := s.walletService.GetWallet(ctx, xid, walletId)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +138,14 @@ func (s *TransactionService) CreateDeposit(ctx context.Context, params *CreateDe
 		return nil, ErrEmptyReferenceId
 	}
 
-	targetWallet, err := s.walletService.GetWalletByXid(ctx, params.CustomerXid)
+	targetWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
 	if err != nil {
 		return nil, err
 	}
-	if err = s.walletService.ValidateWallet(targetWallet); err != nil {
+	if err = s.walletService.ValidateDeposit(targetWallet); err != nil {
+		return nil, err
+	}
+	if err = targetWallet.ValidateAmount(params.Amount, params.Currency); err != nil {
 		return nil, err
 	}
 
@@ -92,6 +157,11 @@ func (s *TransactionService) CreateDeposit(ctx context.Context, params *CreateDe
 		return nil, ErrReferenceNoAlreadyExists
 	}
 
+	// Reject the transaction before recording it if it can't be settled, instead of leaving it pending
+	if s.settlementWorker.Stopped() {
+		return nil, ErrSettlementStopped
+	}
+
 	uuidRandom, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -115,6 +185,7 @@ func (s *TransactionService) CreateDeposit(ctx context.Context, params *CreateDe
 		TransactedAt: time.Now(),
 		Type:         TYPE_DEPOSIT,
 		Amount:       params.Amount,
+		Currency:     targetWallet.Currency,
 		ReferenceId:  params.ReferenceId,
 		WalletId:     targetWallet.Id,
 	})
@@ -127,33 +198,80 @@ func (s *TransactionService) CreateDeposit(ctx context.Context, params *CreateDe
 		return nil, err
 	}
 
-	go func() {
-		log.Printf("queued %s\n", trx.Id)
-		time.Sleep(5 * time.Second)
-		s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
-			log.Printf("disbursing balance to wallet %s, amount: %f\n", targetWallet.Id, params.Amount)
-			err := s.walletService.AddBalance(ctx, targetWallet.Id, params.Amount)
-			if err != nil {
-				log.Printf("error adding balance to wallet %s, amount %f: %v\n", targetWallet.Id, params.Amount, err)
-				return err
-			}
+	ctx = logging.With(ctx, logging.KEY_WALLET_ID, trx.WalletId, logging.KEY_TRANSACTION_ID, trx.Id)
+	s.notify(ctx, trx)
+	err = s.enqueueSettlement(ctx, trx)
+	if err != nil {
+		return nil, err
+	}
 
-			trx.Status = STATUS_SUCCESS
-			log.Printf("updating transaction %s\n", trx.Id)
-			err = s.repository.Update(ctx, trx)
-			if err != nil {
-				log.Printf("error updating transaction %s: %v\n", trx.Id, err)
-				return err
-			}
+	return trx, nil
+}
 
-			return nil
-		})
-	}()
+func (s *TransactionService) CreateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*Transaction, error) {
+	targetWallet, err := s.validateWithdrawal(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject the transaction before recording it if it can't be settled, instead of leaving it pending
+	if s.settlementWorker.Stopped() {
+		return nil, ErrSettlementStopped
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	var randomId string
+	for {
+		randomId = uuidRandom.String()
+		existingTrx, err := s.repository.FindById(ctx, randomId)
+		if err != nil {
+			return nil, err
+		}
+		if existingTrx == nil {
+			break
+		}
+	}
+
+	err = s.repository.Insert(ctx, &Transaction{
+		Id:           randomId,
+		Status:       STATUS_PENDING,
+		TransactedAt: time.Now(),
+		Type:         TYPE_WITHDRAWAL,
+		Amount:       params.Amount,
+		Currency:     targetWallet.Currency,
+		ReferenceId:  params.ReferenceId,
+		WalletId:     targetWallet.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trx, err := s.repository.FindByReferenceId(ctx, params.ReferenceId, TYPE_WITHDRAWAL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logging.With(ctx, logging.KEY_WALLET_ID, trx.WalletId, logging.KEY_TRANSACTION_ID, trx.Id)
+	s.notify(ctx, trx)
+	err = s.enqueueSettlement(ctx, trx)
+	if err != nil {
+		return nil, err
+	}
 
 	return trx, nil
 }
 
-func (s *TransactionService) CreateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*Transaction, error) {
+// Return the wallet of the customer the withdrawal is made from, or error if the withdrawal can't be made,
+// e.g. the wallet is disabled, the balance is insufficient or the reference id is already used
+func (s *TransactionService) ValidateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*wallet.Wallet, error) {
+	return s.validateWithdrawal(ctx, params)
+}
+
+func (s *TransactionService) validateWithdrawal(ctx context.Context, params *CreateWithdrawalParams) (*wallet.Wallet, error) {
 	if params.CustomerXid == "" {
 		return nil, ErrEmptyCustomerXid
 	}
@@ -161,13 +279,16 @@ func (s *TransactionService) CreateWithdrawal(ctx context.Context, params *Creat
 		return nil, ErrEmptyReferenceId
 	}
 
-	targetWallet, err := s.walletService.GetWalletByXid(ctx, params.CustomerXid)
+	targetWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
 	if err != nil {
 		return nil, err
 	}
 	if err = s.walletService.ValidateWallet(targetWallet); err != nil {
 		return nil, err
 	}
+	if err = targetWallet.ValidateAmount(params.Amount, params.Currency); err != nil {
+		return nil, err
+	}
 	if targetWallet.Balance < params.Amount {
 		return nil, wallet.ErrInsufficientBalance
 	}
@@ -180,63 +301,575 @@ func (s *TransactionService) CreateWithdrawal(ctx context.Context, params *Creat
 		return nil, ErrReferenceNoAlreadyExists
 	}
 
-	uuidRandom, err := uuid.NewRandom()
+	return targetWallet, nil
+}
+
+// Queue the settlement of every transaction left pending by the previous run,
+// e.g. the ones abandoned when the application was shut down
+func (s *TransactionService) ResumePendingSettlements(ctx context.Context) error {
+	transactions, err := s.repository.FindTransactionsByStatus(ctx, STATUS_PENDING)
+	if err != nil {
+		return err
+	}
+
+	for _, trx := range transactions {
+		trxCtx := logging.With(ctx, logging.KEY_WALLET_ID, trx.WalletId, logging.KEY_TRANSACTION_ID, trx.Id)
+		err = s.settlementWorker.Enqueue(trxCtx, trx, s.settle(trx))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Return the transactions of the wallet regardless of the wallet status
+func (s *TransactionService) GetTransactionsByWalletId(ctx context.Context, walletId string) ([]*Transaction, error) {
+	transactions, err := s.repository.FindTransactionsByWalletId(ctx, walletId)
 	if err != nil {
 		return nil, err
 	}
 
-	var randomId string
-	for {
-		randomId = uuidRandom.String()
-		existingTrx, err := s.repository.FindById(ctx, randomId)
+	return transactions, nil
+}
+
+// Return the transaction of the given type made with the reference id, nil if the reference id is unused
+func (s *TransactionService) GetTransactionByReferenceId(ctx context.Context, referenceId, transactionType string) (*Transaction, error) {
+	return s.repository.FindByReferenceId(ctx, referenceId, transactionType)
+}
+
+// Queue the settlement of the pending transaction again, e.g. when its settlement got stuck
+func (s *TransactionService) RetrySettlement(ctx context.Context, id string) (*Transaction, error) {
+	trx, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trx == nil {
+		return nil, ErrTransactionNotFound
+	}
+	if trx.Status != STATUS_PENDING {
+		return nil, ErrTransactionNotPending
+	}
+
+	trxCtx := logging.With(ctx, logging.KEY_WALLET_ID, trx.WalletId, logging.KEY_TRANSACTION_ID, trx.Id)
+	err = s.settlementWorker.Enqueue(trxCtx, trx, s.settle(trx))
+	if err != nil {
+		return nil, err
+	}
+
+	return trx, nil
+}
+
+// Mark the pending transaction as failed without moving the balance.
+// A settlement still queued for the transaction skips it afterwards
+func (s *TransactionService) FailTransaction(ctx context.Context, id string) (*Transaction, error) {
+	var trx *Transaction
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		trx, err = s.repository.FindByIdForUpdate(ctx, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		if existingTrx == nil {
-			break
+		if trx == nil {
+			return ErrTransactionNotFound
+		}
+		if trx.Status != STATUS_PENDING {
+			return ErrTransactionNotPending
 		}
+
+		trx.Status = STATUS_FAILED
+		err = s.repository.Update(ctx, trx)
+		if err != nil {
+			return err
+		}
+
+		return s.publishStatus(ctx, trx)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.repository.Insert(ctx, &Transaction{
-		Id:           randomId,
-		Status:       STATUS_PENDING,
+	s.notify(ctx, trx)
+	return trx, nil
+}
+
+// Credit or debit the wallet immediately and record the adjustment as a successful transaction
+func (s *TransactionService) CreateAdjustment(ctx context.Context, params *CreateAdjustmentParams) (*Transaction, error) {
+	if params.WalletId == "" {
+		return nil, ErrEmptyWalletId
+	}
+	if params.Amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, params.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err = targetWallet.ValidateAmount(math.Abs(params.Amount), ""); err != nil {
+		return nil, err
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	trx := &Transaction{
+		Id:           uuidRandom.String(),
+		Status:       STATUS_SUCCESS,
 		TransactedAt: time.Now(),
-		Type:         TYPE_WITHDRAWAL,
+		Type:         TYPE_ADJUSTMENT_CREDIT,
 		Amount:       params.Amount,
+		Currency:     targetWallet.Currency,
 		ReferenceId:  params.ReferenceId,
-		WalletId:     targetWallet.Id,
-	})
+		WalletId:     params.WalletId,
+	}
+	if params.Amount < 0 {
+		trx.Type = TYPE_ADJUSTMENT_DEBIT
+		trx.Amount = -params.Amount
+	}
+	if trx.ReferenceId == "" {
+		trx.ReferenceId = trx.Id
+	}
+
+	existingTrx, err := s.repository.FindByReferenceId(ctx, trx.ReferenceId, trx.Type)
 	if err != nil {
 		return nil, err
 	}
+	if existingTrx != nil {
+		return nil, ErrReferenceNoAlreadyExists
+	}
+
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if trx.Type == TYPE_ADJUSTMENT_CREDIT {
+			err = s.walletService.AddBalance(ctx, trx.WalletId, trx.Amount)
+		} else {
+			err = s.walletService.DeductBalance(ctx, trx.WalletId, trx.Amount)
+		}
+		if err != nil {
+			return err
+		}
 
-	trx, err = s.repository.FindByReferenceId(ctx, params.ReferenceId, TYPE_WITHDRAWAL)
+		err = s.repository.Insert(ctx, trx)
+		if err != nil {
+			return err
+		}
+
+		return s.eventService.Record(ctx, events.BalanceAdjusted{
+			TransactionId: trx.Id,
+			WalletId:      trx.WalletId,
+			ReferenceId:   trx.ReferenceId,
+			Amount:        params.Amount,
+			Currency:      trx.Currency,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		log.Printf("queued %s\n", trx.Id)
-		time.Sleep(5 * time.Second)
-		s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
-			log.Printf("deducting balance to wallet %s, amount: %f\n", targetWallet.Id, params.Amount)
-			err := s.walletService.DeductBalance(ctx, targetWallet.Id, params.Amount)
+	s.notify(ctx, trx)
+	return trx, nil
+}
+
+// Close the wallet and record the payout of the remaining balance, if any, as a successful transaction
+func (s *TransactionService) CloseWallet(ctx context.Context, params *CloseWalletParams) (*wallet.Wallet, *Transaction, error) {
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payoutTrx := &Transaction{
+		Id:          uuidRandom.String(),
+		Status:      STATUS_SUCCESS,
+		Type:        TYPE_PAYOUT,
+		ReferenceId: params.ReferenceId,
+		WalletId:    params.WalletId,
+	}
+	if payoutTrx.ReferenceId == "" {
+		payoutTrx.ReferenceId = payoutTrx.Id
+	}
+
+	existingTrx, err := s.repository.FindByReferenceId(ctx, payoutTrx.ReferenceId, TYPE_PAYOUT)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existingTrx != nil {
+		return nil, nil, ErrReferenceNoAlreadyExists
+	}
+
+	var closedWallet *wallet.Wallet
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var payout float64
+		closedWallet, payout, err = s.walletService.Close(ctx, &wallet.CloseWalletParams{
+			WalletId:        params.WalletId,
+			Reason:          params.Reason,
+			PayoutRemainder: params.PayoutRemainder,
+		})
+		if err != nil {
+			return err
+		}
+		if payout == 0 {
+			payoutTrx = nil
+			return nil
+		}
+
+		payoutTrx.Amount = payout
+		payoutTrx.Currency = closedWallet.Currency
+		payoutTrx.TransactedAt = time.Now()
+		return s.repository.Insert(ctx, payoutTrx)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if payoutTrx != nil {
+		s.notify(ctx, payoutTrx)
+	}
+	return closedWallet, payoutTrx, nil
+}
+
+// Move the amount from the wallet of the customer to the target wallet immediately
+// and record both sides as successful transactions sharing the reference id.
+// Transfers between currencies convert the amount with the rate locked by the quote of the customer.
+//
+// Return the outgoing and the incoming transactions
+func (s *TransactionService) CreateTransfer(ctx context.Context, params *CreateTransferParams) (*Transaction, *Transaction, error) {
+	sourceWallet, targetWallet, err := s.validateTransfer(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outgoingId, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+	incomingId, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var outgoing, incoming *Transaction
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		// Validate the transfer again once both wallets are locked, so concurrent transfers can't spend the same balance.
+		// The source wallet is pinned in case the default wallet of the client changed in between
+		err := s.walletService.Lock(ctx, sourceWallet.Id, targetWallet.Id)
+		if err != nil {
+			return err
+		}
+		lockedParams := *params
+		lockedParams.WalletId = sourceWallet.Id
+		sourceWallet, targetWallet, err = s.validateTransfer(ctx, &lockedParams)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		outgoing = &Transaction{
+			Id:                   outgoingId.String(),
+			Status:               STATUS_SUCCESS,
+			TransactedAt:         now,
+			Type:                 TYPE_TRANSFER_OUT,
+			Amount:               params.Amount,
+			Currency:             sourceWallet.Currency,
+			ReferenceId:          params.ReferenceId,
+			WalletId:             sourceWallet.Id,
+			CounterpartyWalletId: targetWallet.Id,
+		}
+		incoming = &Transaction{
+			Id:                   incomingId.String(),
+			Status:               STATUS_SUCCESS,
+			TransactedAt:         now,
+			Type:                 TYPE_TRANSFER_IN,
+			Amount:               params.Amount,
+			Currency:             targetWallet.Currency,
+			ReferenceId:          params.ReferenceId,
+			WalletId:             targetWallet.Id,
+			CounterpartyWalletId: sourceWallet.Id,
+		}
+
+		if sourceWallet.Currency != targetWallet.Currency {
+			quote, err := s.fxService.UseQuote(ctx, &fx.UseQuoteParams{
+				QuoteId:   params.QuoteId,
+				ClientXid: params.CustomerXid,
+				From:      sourceWallet.Currency,
+				To:        targetWallet.Currency,
+				Amount:    params.Amount,
+			})
 			if err != nil {
-				log.Printf("error deduct balance to wallet %s, amount %f: %v\n", targetWallet.Id, params.Amount, err)
+				return err
+			}
+			incoming.Amount = quote.TargetAmount
+		}
+
+		err = s.walletService.DeductBalance(ctx, sourceWallet.Id, outgoing.Amount)
+		if err != nil {
+			return err
+		}
+		err = s.walletService.AddBalance(ctx, targetWallet.Id, incoming.Amount)
+		if err != nil {
+			return err
+		}
+
+		err = s.repository.Insert(ctx, outgoing)
+		if err != nil {
+			return err
+		}
+		err = s.repository.Insert(ctx, incoming)
+		if err != nil {
+			return err
+		}
+
+		return s.eventService.Record(ctx, events.TransferCompleted{
+			ReferenceId:         params.ReferenceId,
+			SourceWalletId:      outgoing.WalletId,
+			SourceTransactionId: outgoing.Id,
+			SourceAmount:        outgoing.Amount,
+			SourceCurrency:      outgoing.Currency,
+			TargetWalletId:      incoming.WalletId,
+			TargetTransactionId: incoming.Id,
+			TargetAmount:        incoming.Amount,
+			TargetCurrency:      incoming.Currency,
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.notify(ctx, outgoing)
+	s.notify(ctx, incoming)
+	return outgoing, incoming, nil
+}
+
+// Return the wallet of the customer the transfer is made from and the wallet receiving it, or error if the transfer can't be made,
+// e.g. the target wallet is disabled or a quote is required
+func (s *TransactionService) ValidateTransfer(ctx context.Context, params *CreateTransferParams) (*wallet.Wallet, *wallet.Wallet, error) {
+	return s.validateTransfer(ctx, params)
+}
+
+func (s *TransactionService) validateTransfer(ctx context.Context, params *CreateTransferParams) (*wallet.Wallet, *wallet.Wallet, error) {
+	if params.CustomerXid == "" {
+		return nil, nil, ErrEmptyCustomerXid
+	}
+	if params.ReferenceId == "" {
+		return nil, nil, ErrEmptyReferenceId
+	}
+	if params.TargetWalletId == "" {
+		return nil, nil, ErrEmptyTargetWalletId
+	}
+	if params.Amount <= 0 {
+		return nil, nil, ErrNonPositiveAmount
+	}
+
+	sourceWallet, err := s.walletService.GetWallet(ctx, params.CustomerXid, params.WalletId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = s.walletService.ValidateWallet(sourceWallet); err != nil {
+		return nil, nil, err
+	}
+	if err = sourceWallet.ValidateAmount(params.Amount, params.Currency); err != nil {
+		return nil, nil, err
+	}
+	if sourceWallet.Balance < params.Amount {
+		return nil, nil, wallet.ErrInsufficientBalance
+	}
+	if sourceWallet.Id == params.TargetWalletId {
+		return nil, nil, ErrSameWallet
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, params.TargetWalletId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = s.walletService.ValidateDeposit(targetWallet); err != nil {
+		return nil, nil, err
+	}
+	if sourceWallet.Currency != targetWallet.Currency && params.QuoteId == "" {
+		return nil, nil, ErrQuoteRequired
+	}
+	if sourceWallet.Currency == targetWallet.Currency && params.QuoteId != "" {
+		return nil, nil, ErrQuoteNotApplicable
+	}
+
+	trx, err := s.repository.FindByReferenceId(ctx, params.ReferenceId, TYPE_TRANSFER_OUT)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trx != nil {
+		return nil, nil, ErrReferenceNoAlreadyExists
+	}
+
+	return sourceWallet, targetWallet, nil
+}
+
+// Return the function that moves the balance of the given transaction
+// and marks the transaction as success in one database transaction.
+// The transaction is locked first and skipped if it is no longer pending,
+// e.g. failed by an operator or settled by a concurrent settlement.
+//
+// The transaction is marked as failed if the balance movement is rejected, e.g. for insufficient balance.
+// Any other error, e.g. a lost database connection, leaves the transaction pending
+// to be settled again by ResumePendingSettlements or RetrySettlement.
+// Either outcome is published to the webhooks within the database transaction of the status update
+func (s *TransactionService) settle(trx *Transaction) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		logger := logging.FromContext(ctx)
+
+		err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+			current, err := s.repository.FindByIdForUpdate(ctx, trx.Id)
+			if err != nil {
+				return err
+			}
+			if current == nil || current.Status != STATUS_PENDING {
+				logger.Warn("transaction no longer pending, skipping settlement")
+				return errSettlementSkipped
+			}
+
+			switch trx.Type {
+			case TYPE_DEPOSIT:
+				logger.Info("disbursing balance to wallet", "amount", trx.Amount)
+				err = s.walletService.AddBalance(ctx, trx.WalletId, trx.Amount)
+			case TYPE_WITHDRAWAL:
+				logger.Info("deducting balance from wallet", "amount", trx.Amount)
+				err = s.walletService.DeductBalance(ctx, trx.WalletId, trx.Amount)
+			default:
+				err = ErrInvalidTransactionType
+			}
+			if err != nil {
+				logger.Error("error settling wallet balance", "amount", trx.Amount, logging.KEY_ERROR, err)
 				return err
 			}
 
 			trx.Status = STATUS_SUCCESS
-			log.Printf("updating transaction %s\n", trx.Id)
+			logger.Info("updating transaction", "status", trx.Status)
 			err = s.repository.Update(ctx, trx)
 			if err != nil {
-				log.Printf("error updating transaction %s: %v\n", trx.Id, err)
+				logger.Error("error updating transaction", logging.KEY_ERROR, err)
 				return err
 			}
 
-			return nil
+			return s.publishStatus(ctx, trx)
 		})
-	}()
+		if err == errSettlementSkipped {
+			return nil
+		}
+		if _, rejected := err.(errors.HandledError); rejected {
+			failErr := s.failRejected(ctx, trx)
+			if failErr == errSettlementSkipped {
+				logger.Warn("transaction no longer pending, skipping failure")
+			} else if failErr != nil {
+				logger.Error("error updating transaction", logging.KEY_ERROR, failErr)
+			} else {
+				s.notify(ctx, trx)
+			}
+			return err
+		}
+		if err != nil {
+			logger.Warn("settlement interrupted, leaving transaction pending", logging.KEY_ERROR, err)
+			return err
+		}
 
-	return trx, nil
+		s.notify(ctx, trx)
+		return nil
+	}
+}
+
+// Queue the settlement of the new pending transaction.
+// The transaction is marked as failed if the worker was shut down since the transaction was recorded,
+// so it isn't settled on the next start after the client is told it was rejected
+func (s *TransactionService) enqueueSettlement(ctx context.Context, trx *Transaction) error {
+	err := s.settlementWorker.Enqueue(ctx, trx, s.settle(trx))
+	if err == nil {
+		return nil
+	}
+
+	failErr := s.failRejected(ctx, trx)
+	if failErr != nil {
+		logging.FromContext(ctx).Error("error failing unqueued transaction", logging.KEY_ERROR, failErr)
+		return err
+	}
+	s.notify(ctx, trx)
+	return err
+}
+
+// Mark the transaction of the rejected settlement as failed, unless it is no longer pending
+func (s *TransactionService) failRejected(ctx context.Context, trx *Transaction) error {
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repository.FindByIdForUpdate(ctx, trx.Id)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Status != STATUS_PENDING {
+			return errSettlementSkipped
+		}
+
+		trx.Status = STATUS_FAILED
+		err = s.repository.Update(ctx, trx)
+		if err != nil {
+			return err
+		}
+
+		return s.publishStatus(ctx, trx)
+	})
+}
+
+// Push the transaction to the activity streams of its wallet once the database transaction of the context is committed,
+// so a transfer made within the transaction of a schedule, a payment request or a payout isn't pushed if it is rolled back
+func (s *TransactionService) notify(ctx context.Context, trx *Transaction) {
+	snapshot := *trx
+	s.storageManager.AfterCommit(ctx, func() {
+		err := s.activityBroker.Publish(snapshot.WalletId, activity.EVENT_TRANSACTION_UPDATED, &snapshot)
+		if err != nil {
+			logging.FromContext(ctx).Error("error publishing transaction activity", logging.KEY_ERROR, err)
+		}
+	})
+}
+
+// Publish the success or failure of the transaction to the webhooks of the owner of its wallet
+// and record it as a domain event
+func (s *TransactionService) publishStatus(ctx context.Context, trx *Transaction) error {
+	eventType := webhook.EVENT_TRANSACTION_SUCCEEDED
+	if trx.Status == STATUS_FAILED {
+		eventType = webhook.EVENT_TRANSACTION_FAILED
+	}
+
+	targetWallet, err := s.walletService.GetWalletById(ctx, trx.WalletId)
+	if err != nil {
+		return err
+	}
+
+	err = s.webhookService.Publish(ctx, &webhook.PublishParams{
+		ClientXid: targetWallet.OwnedBy,
+		Type:      eventType,
+		Data:      trx,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.eventService.Record(ctx, settlementEvent(trx))
+}
+
+// Return the domain event of the outcome of the deposit or withdrawal
+func settlementEvent(trx *Transaction) events.Event {
+	settlement := events.Settlement{
+		TransactionId: trx.Id,
+		WalletId:      trx.WalletId,
+		ReferenceId:   trx.ReferenceId,
+		Amount:        trx.Amount,
+		Currency:      trx.Currency,
+	}
+
+	switch {
+	case trx.Type == TYPE_WITHDRAWAL && trx.Status == STATUS_FAILED:
+		return events.WithdrawalFailed{Settlement: settlement}
+	case trx.Type == TYPE_WITHDRAWAL:
+		return events.WithdrawalSettled{Settlement: settlement}
+	case trx.Status == STATUS_FAILED:
+		return events.DepositFailed{Settlement: settlement}
+	default:
+		return events.DepositSettled{Settlement: settlement}
+	}
 }
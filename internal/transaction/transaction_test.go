@@ -2,19 +2,44 @@ package transaction_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/activity"
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	event_mock "github.com/defryheryanto/mini-wallet/internal/events/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/fx"
+	fx_mock "github.com/defryheryanto/mini-wallet/internal/fx/mocks"
 	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	"github.com/defryheryanto/mini-wallet/internal/transaction"
 	transaction_mock "github.com/defryheryanto/mini-wallet/internal/transaction/mocks"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
 	wallet_mock "github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	webhook_mock "github.com/defryheryanto/mini-wallet/internal/webhook/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// Return the webhook service accepting any event, for the tests not asserting the published events
+func newWebhookService(t *testing.T) *webhook_mock.WebhookIService {
+	webhookService := webhook_mock.NewWebhookIService(t)
+	webhookService.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return webhookService
+}
+
+// Return the event service accepting any event, for the tests not asserting the recorded events
+func newEventService(t *testing.T) *event_mock.EventIService {
+	eventService := event_mock.NewEventIService(t)
+	eventService.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return eventService
+}
+
 func TestTransactionService_GetTransactionsByCustomerXid(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 	customerXid := "test"
@@ -22,11 +47,11 @@ func TestTransactionService_GetTransactionsByCustomerXid(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, customerXid).Return(nil, mockedErr)
+		walletService.On("GetWallet", mock.Anything, customerXid, "").Return(nil, mockedErr)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
-		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid)
+		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid, "")
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, trx)
 	})
@@ -34,11 +59,11 @@ func TestTransactionService_GetTransactionsByCustomerXid(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, customerXid).Return(nil, nil)
+		walletService.On("GetWallet", mock.Anything, customerXid, "").Return(nil, nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
-		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid)
+		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid, "")
 		assert.Equal(t, wallet.ErrWalletNotFound, err)
 		assert.Nil(t, trx)
 	})
@@ -46,13 +71,13 @@ func TestTransactionService_GetTransactionsByCustomerXid(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, customerXid).Return(&wallet.Wallet{
+		walletService.On("GetWallet", mock.Anything, customerXid, "").Return(&wallet.Wallet{
 			Status: wallet.STATUS_DISABLED,
 		}, nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
-		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid)
+		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid, "")
 		assert.Equal(t, wallet.ErrWalletDisabled, err)
 		assert.Nil(t, trx)
 	})
@@ -72,11 +97,11 @@ func TestTransactionService_GetTransactionsByCustomerXid(t *testing.T) {
 		}, nil)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, customerXid).Return(targetWallet, nil)
+		walletService.On("GetWallet", mock.Anything, customerXid, "").Return(targetWallet, nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
-		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid)
+		trx, err := service.GetTransactionsByCustomerXid(context.TODO(), customerXid, "")
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(trx))
 	})
@@ -93,7 +118,7 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 	t.Run("should return error if customer xid is empty", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), &transaction.CreateDepositParams{
 			ReferenceId: "ref-no",
@@ -106,7 +131,7 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 	t.Run("should return error if ref no is empty", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), &transaction.CreateDepositParams{
 			CustomerXid: "test-xid",
@@ -119,9 +144,9 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 	t.Run("should return error if failed to get wallet", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(nil, mockedErr)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(nil, mockedErr)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -131,25 +156,42 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 	t.Run("should return error if wallet not active", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(mockedErr)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(mockedErr)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, trx)
 	})
 
+	t.Run("should return error if amount is not positive", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE, Balance: 20_000}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.CreateDeposit(context.TODO(), &transaction.CreateDepositParams{
+			CustomerXid: params.CustomerXid,
+			ReferenceId: params.ReferenceId,
+			Amount:      -10_000,
+		})
+		assert.Equal(t, wallet.ErrNonPositiveAmount, err)
+		assert.Nil(t, trx)
+	})
+
 	t.Run("should return error if failed to get transaction by ref no", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_DEPOSIT).Return(nil, mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -161,10 +203,10 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_DEPOSIT).Return(&transaction.Transaction{}, nil)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, transaction.ErrReferenceNoAlreadyExists, err)
@@ -177,10 +219,10 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -193,10 +235,10 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 		repository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -211,16 +253,34 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_DEPOSIT).Return(nil, mockedErr).Once()
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, trx)
 	})
 
+	t.Run("should reject the deposit without recording it if the settlement worker is stopped", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_DEPOSIT).Return(nil, nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		worker := transaction.NewSettlementWorker(time.Hour)
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.CreateDeposit(context.TODO(), params)
+		assert.Equal(t, transaction.ErrSettlementStopped, err)
+		assert.Nil(t, trx)
+		repository.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
 	t.Run("should return created transaction if operation success", func(t *testing.T) {
 		createdTransaction := &transaction.Transaction{
 			Id:           "test-id",
@@ -245,10 +305,10 @@ func TestTransactionService_CreateDeposit(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_DEPOSIT).Return(createdTransaction, nil).Once()
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
-		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateDeposit(context.TODO(), params)
 		assert.Nil(t, err)
@@ -273,7 +333,7 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 	t.Run("should return error if customer xid is empty", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), &transaction.CreateWithdrawalParams{
 			ReferenceId: "ref-no",
@@ -286,7 +346,7 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 	t.Run("should return error if ref no is empty", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), &transaction.CreateWithdrawalParams{
 			CustomerXid: "test-xid",
@@ -299,9 +359,9 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 	t.Run("should return error if failed to get wallet", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(nil, mockedErr)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(nil, mockedErr)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -311,10 +371,10 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 	t.Run("should return error if wallet not active", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{}, nil)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(mockedErr)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -324,29 +384,48 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 	t.Run("should return error if wallet balance insufficient", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 0,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  0,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, wallet.ErrInsufficientBalance, err)
 		assert.Nil(t, trx)
 	})
 
+	t.Run("should return error if amount is not positive", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE, Balance: 20_000}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.CreateWithdrawal(context.TODO(), &transaction.CreateWithdrawalParams{
+			CustomerXid: params.CustomerXid,
+			ReferenceId: params.ReferenceId,
+			Amount:      -10_000,
+		})
+		assert.Equal(t, wallet.ErrNonPositiveAmount, err)
+		assert.Nil(t, trx)
+	})
+
 	t.Run("should return error if failed to get transaction by ref no", func(t *testing.T) {
 		repository := transaction_mock.NewTransactionRepository(t)
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_WITHDRAWAL).Return(nil, mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -358,12 +437,13 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_WITHDRAWAL).Return(&transaction.Transaction{}, nil)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, transaction.ErrReferenceNoAlreadyExists, err)
@@ -376,12 +456,13 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -394,12 +475,13 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		repository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -414,12 +496,13 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_WITHDRAWAL).Return(nil, mockedErr).Once()
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Equal(t, mockedErr, err)
@@ -450,12 +533,13 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_WITHDRAWAL).Return(createdTransaction, nil).Once()
 
 		walletService := wallet_mock.NewWalletIService(t)
-		walletService.On("GetWalletByXid", mock.Anything, params.CustomerXid).Return(&wallet.Wallet{
-			Balance: 15_001,
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{
+			Currency: currency.DEFAULT_CODE,
+			Balance:  15_001,
 		}, nil)
 		walletService.On("ValidateWallet", mock.Anything).Return(nil)
 
-		service := transaction.NewTransactionService(repository, walletService, &manager.MockStorageManager{})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
 
 		trx, err := service.CreateWithdrawal(context.TODO(), params)
 		assert.Nil(t, err)
@@ -467,4 +551,621 @@ func TestTransactionService_CreateWithdrawal(t *testing.T) {
 		assert.Equal(t, createdTransaction.ReferenceId, trx.ReferenceId)
 		assert.Equal(t, createdTransaction.WalletId, trx.WalletId)
 	})
+
+	t.Run("should reject the withdrawal without recording it if the settlement worker is stopped", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, params.ReferenceId, transaction.TYPE_WITHDRAWAL).Return(nil, nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, params.CustomerXid, "").Return(&wallet.Wallet{Currency: currency.DEFAULT_CODE, Balance: 10_000}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		worker := transaction.NewSettlementWorker(time.Hour)
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.CreateWithdrawal(context.TODO(), params)
+		assert.Equal(t, transaction.ErrSettlementStopped, err)
+		assert.Nil(t, trx)
+		repository.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_ResumePendingSettlements(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+
+	t.Run("should return error if failed to get pending transactions", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return(nil, mockedErr)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ResumePendingSettlements(context.TODO())
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should settle pending transactions", func(t *testing.T) {
+		pendingTransaction := &transaction.Transaction{
+			Id:       "test-id",
+			Status:   transaction.STATUS_PENDING,
+			Type:     transaction.TYPE_DEPOSIT,
+			Amount:   10_000,
+			WalletId: "test-wallet-id",
+		}
+		settled := make(chan struct{})
+
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return([]*transaction.Transaction{pendingTransaction}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, pendingTransaction.Id).Return(&transaction.Transaction{Id: pendingTransaction.Id, Status: transaction.STATUS_PENDING}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*transaction.Transaction)
+			assert.True(t, ok, "params should be *Transaction")
+			assert.Equal(t, transaction.STATUS_SUCCESS, updateParams.Status)
+			close(settled)
+		}).Return(nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("AddBalance", mock.Anything, pendingTransaction.WalletId, pendingTransaction.Amount).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, pendingTransaction.WalletId).Return(&wallet.Wallet{Id: pendingTransaction.WalletId, OwnedBy: "test"}, nil)
+
+		webhookService := webhook_mock.NewWebhookIService(t)
+		webhookService.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params := args.Get(1).(*webhook.PublishParams)
+			assert.Equal(t, "test", params.ClientXid)
+			assert.Equal(t, webhook.EVENT_TRANSACTION_SUCCEEDED, params.Type)
+		}).Return(nil)
+
+		worker := transaction.NewSettlementWorker(0)
+		broker := activity.NewBroker(activity.HISTORY_SIZE)
+		subscription := broker.Subscribe(pendingTransaction.WalletId, "")
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhookService, newEventService(t), &manager.MockStorageManager{}, worker, broker)
+
+		err := service.ResumePendingSettlements(context.TODO())
+		assert.Nil(t, err)
+		<-settled
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+
+		event := <-subscription.Events
+		settledTransaction := &transaction.Transaction{}
+		assert.Nil(t, json.Unmarshal(event.Data, settledTransaction))
+		assert.Equal(t, activity.EVENT_TRANSACTION_UPDATED, event.Type)
+		assert.Equal(t, transaction.STATUS_SUCCESS, settledTransaction.Status)
+	})
+
+	t.Run("should mark transaction as failed if settlement failed", func(t *testing.T) {
+		pendingTransaction := &transaction.Transaction{
+			Id:       "test-id",
+			Status:   transaction.STATUS_PENDING,
+			Type:     transaction.TYPE_WITHDRAWAL,
+			Amount:   10_000,
+			WalletId: "test-wallet-id",
+		}
+		failed := make(chan struct{})
+
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return([]*transaction.Transaction{pendingTransaction}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, pendingTransaction.Id).Return(&transaction.Transaction{Id: pendingTransaction.Id, Status: transaction.STATUS_PENDING}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*transaction.Transaction)
+			assert.True(t, ok, "params should be *Transaction")
+			assert.Equal(t, transaction.STATUS_FAILED, updateParams.Status)
+			close(failed)
+		}).Return(nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("DeductBalance", mock.Anything, pendingTransaction.WalletId, pendingTransaction.Amount).Return(wallet.ErrInsufficientBalance)
+		walletService.On("GetWalletById", mock.Anything, pendingTransaction.WalletId).Return(&wallet.Wallet{Id: pendingTransaction.WalletId, OwnedBy: "test"}, nil)
+
+		webhookService := webhook_mock.NewWebhookIService(t)
+		webhookService.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params := args.Get(1).(*webhook.PublishParams)
+			assert.Equal(t, webhook.EVENT_TRANSACTION_FAILED, params.Type)
+		}).Return(nil)
+
+		eventService := event_mock.NewEventIService(t)
+		eventService.On("Record", mock.Anything, events.WithdrawalFailed{Settlement: events.Settlement{
+			TransactionId: pendingTransaction.Id,
+			WalletId:      pendingTransaction.WalletId,
+			Amount:        pendingTransaction.Amount,
+		}}).Return(nil)
+
+		worker := transaction.NewSettlementWorker(0)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhookService, eventService, &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ResumePendingSettlements(context.TODO())
+		assert.Nil(t, err)
+		<-failed
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+	})
+
+	t.Run("should leave transaction pending if settlement interrupted", func(t *testing.T) {
+		pendingTransaction := &transaction.Transaction{
+			Id:       "test-id",
+			Status:   transaction.STATUS_PENDING,
+			Type:     transaction.TYPE_WITHDRAWAL,
+			Amount:   10_000,
+			WalletId: "test-wallet-id",
+		}
+
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return([]*transaction.Transaction{pendingTransaction}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, pendingTransaction.Id).Return(&transaction.Transaction{Id: pendingTransaction.Id, Status: transaction.STATUS_PENDING}, nil)
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("DeductBalance", mock.Anything, pendingTransaction.WalletId, pendingTransaction.Amount).Return(mockedErr)
+
+		settled := make(chan error, 1)
+		worker := transaction.NewSettlementWorker(0)
+		worker.OnSettled(func(trx *transaction.Transaction, latency time.Duration, err error) {
+			settled <- err
+		})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhook_mock.NewWebhookIService(t), event_mock.NewEventIService(t), &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ResumePendingSettlements(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, mockedErr, <-settled)
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not fail transaction no longer pending after rejected settlement", func(t *testing.T) {
+		pendingTransaction := &transaction.Transaction{
+			Id:       "test-id",
+			Status:   transaction.STATUS_PENDING,
+			Type:     transaction.TYPE_WITHDRAWAL,
+			Amount:   10_000,
+			WalletId: "test-wallet-id",
+		}
+
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindTransactionsByStatus", mock.Anything, transaction.STATUS_PENDING).Return([]*transaction.Transaction{pendingTransaction}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, pendingTransaction.Id).Return(&transaction.Transaction{Id: pendingTransaction.Id, Status: transaction.STATUS_PENDING}, nil).Once()
+		repository.On("FindByIdForUpdate", mock.Anything, pendingTransaction.Id).Return(&transaction.Transaction{Id: pendingTransaction.Id, Status: transaction.STATUS_FAILED}, nil).Once()
+
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("DeductBalance", mock.Anything, pendingTransaction.WalletId, pendingTransaction.Amount).Return(wallet.ErrInsufficientBalance)
+
+		settled := make(chan error, 1)
+		worker := transaction.NewSettlementWorker(0)
+		worker.OnSettled(func(trx *transaction.Transaction, latency time.Duration, err error) {
+			settled <- err
+		})
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhook_mock.NewWebhookIService(t), event_mock.NewEventIService(t), &manager.MockStorageManager{}, worker, activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ResumePendingSettlements(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, wallet.ErrInsufficientBalance, <-settled)
+		assert.Nil(t, worker.Shutdown(context.TODO()))
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_FailTransaction(t *testing.T) {
+	t.Run("should return error if transaction not found", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "test-id").Return(nil, nil)
+		service := transaction.NewTransactionService(repository, wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.FailTransaction(context.TODO(), "test-id")
+		assert.Equal(t, transaction.ErrTransactionNotFound, err)
+	})
+
+	t.Run("should return error if transaction not pending", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "test-id").Return(&transaction.Transaction{Id: "test-id", Status: transaction.STATUS_SUCCESS}, nil)
+		service := transaction.NewTransactionService(repository, wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.FailTransaction(context.TODO(), "test-id")
+		assert.Equal(t, transaction.ErrTransactionNotPending, err)
+	})
+
+	t.Run("should mark pending transaction as failed", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "test-id").Return(&transaction.Transaction{Id: "test-id", Status: transaction.STATUS_PENDING, WalletId: "wallet-id"}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*transaction.Transaction)
+			assert.True(t, ok, "params should be *Transaction")
+			assert.Equal(t, transaction.STATUS_FAILED, updateParams.Status)
+		}).Return(nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: "test"}, nil)
+		webhookService := webhook_mock.NewWebhookIService(t)
+		webhookService.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params := args.Get(1).(*webhook.PublishParams)
+			assert.Equal(t, "test", params.ClientXid)
+			assert.Equal(t, webhook.EVENT_TRANSACTION_FAILED, params.Type)
+		}).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), webhookService, newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.FailTransaction(context.TODO(), "test-id")
+		assert.Nil(t, err)
+		assert.Equal(t, transaction.STATUS_FAILED, trx.Status)
+	})
+}
+
+func TestTransactionService_CreateAdjustment(t *testing.T) {
+	t.Run("should return error if amount is zero", func(t *testing.T) {
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.CreateAdjustment(context.TODO(), &transaction.CreateAdjustmentParams{WalletId: "wallet-id"})
+		assert.Equal(t, transaction.ErrInvalidAmount, err)
+	})
+
+	t.Run("should return error if amount has too many decimals", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Currency: "JPY"}, nil)
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.CreateAdjustment(context.TODO(), &transaction.CreateAdjustmentParams{WalletId: "wallet-id", Amount: 100.5})
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error if reference id already exists", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_ADJUSTMENT_CREDIT).Return(&transaction.Transaction{}, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Currency: currency.DEFAULT_CODE}, nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.CreateAdjustment(context.TODO(), &transaction.CreateAdjustmentParams{WalletId: "wallet-id", ReferenceId: "ref", Amount: 100})
+		assert.Equal(t, transaction.ErrReferenceNoAlreadyExists, err)
+	})
+
+	t.Run("should debit the wallet for negative amount", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_ADJUSTMENT_DEBIT).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			insertParams, ok := args.Get(1).(*transaction.Transaction)
+			assert.True(t, ok, "params should be *Transaction")
+			assert.Equal(t, transaction.STATUS_SUCCESS, insertParams.Status)
+			assert.Equal(t, float64(100), insertParams.Amount)
+		}).Return(nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("DeductBalance", mock.Anything, "wallet-id", float64(100)).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		trx, err := service.CreateAdjustment(context.TODO(), &transaction.CreateAdjustmentParams{WalletId: "wallet-id", ReferenceId: "ref", Amount: -100})
+		assert.Nil(t, err)
+		assert.Equal(t, transaction.TYPE_ADJUSTMENT_DEBIT, trx.Type)
+	})
+
+	t.Run("should return error if failed to move balance", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_ADJUSTMENT_CREDIT).Return(nil, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWalletById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Currency: currency.DEFAULT_CODE}, nil)
+		walletService.On("AddBalance", mock.Anything, "wallet-id", float64(100)).Return(wallet.ErrWalletDisabled)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, err := service.CreateAdjustment(context.TODO(), &transaction.CreateAdjustmentParams{WalletId: "wallet-id", ReferenceId: "ref", Amount: 100})
+		assert.Equal(t, wallet.ErrWalletDisabled, err)
+	})
+}
+
+func TestTransactionService_CloseWallet(t *testing.T) {
+	t.Run("should return error if failed to close the wallet", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, mock.Anything, transaction.TYPE_PAYOUT).Return(nil, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Close", mock.Anything, mock.Anything).Return(nil, float64(0), wallet.ErrWalletBalanceNotZero)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CloseWallet(context.TODO(), &transaction.CloseWalletParams{WalletId: "wallet-id", Reason: "requested"})
+		assert.Equal(t, wallet.ErrWalletBalanceNotZero, err)
+	})
+
+	t.Run("should not record payout if balance was zero", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, mock.Anything, transaction.TYPE_PAYOUT).Return(nil, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Close", mock.Anything, mock.Anything).Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_CLOSED}, float64(0), nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		closedWallet, payout, err := service.CloseWallet(context.TODO(), &transaction.CloseWalletParams{WalletId: "wallet-id", Reason: "requested"})
+		assert.Nil(t, err)
+		assert.Nil(t, payout)
+		assert.Equal(t, wallet.STATUS_CLOSED, closedWallet.Status)
+	})
+
+	t.Run("should record the payout of the remaining balance", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "payout-ref", transaction.TYPE_PAYOUT).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			insertParams, ok := args.Get(1).(*transaction.Transaction)
+			assert.True(t, ok, "params should be *Transaction")
+			assert.Equal(t, transaction.TYPE_PAYOUT, insertParams.Type)
+			assert.Equal(t, transaction.STATUS_SUCCESS, insertParams.Status)
+			assert.Equal(t, float64(250), insertParams.Amount)
+		}).Return(nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Close", mock.Anything, &wallet.CloseWalletParams{WalletId: "wallet-id", Reason: "requested", PayoutRemainder: true}).
+			Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_CLOSED}, float64(250), nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, payout, err := service.CloseWallet(context.TODO(), &transaction.CloseWalletParams{
+			WalletId:        "wallet-id",
+			Reason:          "requested",
+			PayoutRemainder: true,
+			ReferenceId:     "payout-ref",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "payout-ref", payout.ReferenceId)
+	})
+}
+
+func TestTransactionService_CreateTransfer(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	params := &transaction.CreateTransferParams{
+		CustomerXid:    "test",
+		TargetWalletId: "target-id",
+		ReferenceId:    "ref",
+		Amount:         100,
+	}
+	sourceWallet := &wallet.Wallet{Id: "source-id", Status: wallet.STATUS_ENABLED, Balance: 500, Currency: "IDR"}
+
+	t.Run("should return error if amount is not positive", func(t *testing.T) {
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), &transaction.CreateTransferParams{CustomerXid: "test", TargetWalletId: "target-id", ReferenceId: "ref"})
+		assert.Equal(t, transaction.ErrNonPositiveAmount, err)
+	})
+
+	t.Run("should return error if balance is insufficient", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "source-id", Balance: 50, Currency: "IDR"}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), params)
+		assert.Equal(t, wallet.ErrInsufficientBalance, err)
+	})
+
+	t.Run("should return error if transferring to the same wallet", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "target-id", Balance: 500, Currency: "IDR"}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), params)
+		assert.Equal(t, transaction.ErrSameWallet, err)
+	})
+
+	t.Run("should return error if currencies differ without a quote", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(sourceWallet, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "USD"}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), params)
+		assert.Equal(t, transaction.ErrQuoteRequired, err)
+	})
+
+	t.Run("should move the balance between wallets of the same currency", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil).Twice()
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Lock", mock.Anything, "source-id", "target-id").Return(nil)
+		walletService.On("GetWallet", mock.Anything, "test", mock.Anything).Return(sourceWallet, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "IDR"}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		walletService.On("DeductBalance", mock.Anything, "source-id", float64(100)).Return(nil)
+		walletService.On("AddBalance", mock.Anything, "target-id", float64(100)).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		outgoing, incoming, err := service.CreateTransfer(context.TODO(), params)
+		assert.Nil(t, err)
+		assert.Equal(t, transaction.TYPE_TRANSFER_OUT, outgoing.Type)
+		assert.Equal(t, "target-id", outgoing.CounterpartyWalletId)
+		assert.Equal(t, transaction.TYPE_TRANSFER_IN, incoming.Type)
+		assert.Equal(t, "source-id", incoming.CounterpartyWalletId)
+		assert.Equal(t, float64(100), incoming.Amount)
+	})
+
+	t.Run("should validate the transfer again against the locked wallets", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Lock", mock.Anything, "source-id", "target-id").Return(nil)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(sourceWallet, nil).Once()
+		walletService.On("GetWallet", mock.Anything, "test", "source-id").Return(&wallet.Wallet{Id: "source-id", Status: wallet.STATUS_ENABLED, Balance: 50, Currency: "IDR"}, nil).Once()
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "IDR"}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), params)
+		assert.Equal(t, wallet.ErrInsufficientBalance, err)
+	})
+
+	t.Run("should push the activity of a transfer made within a transaction once it is committed", func(t *testing.T) {
+		for _, rollback := range []bool{false, true} {
+			repository := transaction_mock.NewTransactionRepository(t)
+			repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+			repository.On("Insert", mock.Anything, mock.Anything).Return(nil).Twice()
+			walletService := wallet_mock.NewWalletIService(t)
+			walletService.On("Lock", mock.Anything, "source-id", "target-id").Return(nil)
+			walletService.On("GetWallet", mock.Anything, "test", mock.Anything).Return(sourceWallet, nil)
+			walletService.On("ValidateWallet", mock.Anything).Return(nil)
+			walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "IDR"}, nil)
+			walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+			walletService.On("DeductBalance", mock.Anything, "source-id", float64(100)).Return(nil)
+			walletService.On("AddBalance", mock.Anything, "target-id", float64(100)).Return(nil)
+			storageManager := &manager.MockStorageManager{}
+			broker := activity.NewBroker(activity.HISTORY_SIZE)
+			subscription := broker.Subscribe("source-id", "")
+			service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), storageManager, transaction.NewSettlementWorker(time.Hour), broker)
+
+			err := storageManager.RunInTransaction(context.TODO(), func(ctx context.Context) error {
+				_, _, err := service.CreateTransfer(ctx, params)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, len(subscription.Events))
+				if rollback {
+					return mockedErr
+				}
+				return nil
+			})
+			if rollback {
+				assert.Equal(t, mockedErr, err)
+				assert.Equal(t, 0, len(subscription.Events))
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(subscription.Events))
+			}
+		}
+	})
+
+	t.Run("should convert the amount with the quote if currencies differ", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil).Twice()
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Lock", mock.Anything, "source-id", "target-id").Return(nil)
+		walletService.On("GetWallet", mock.Anything, "test", mock.Anything).Return(&wallet.Wallet{Id: "source-id", Balance: 500, Currency: "USD"}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "IDR"}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		walletService.On("DeductBalance", mock.Anything, "source-id", float64(100)).Return(nil)
+		walletService.On("AddBalance", mock.Anything, "target-id", float64(1500000)).Return(nil)
+		fxService := fx_mock.NewFxIService(t)
+		fxService.On("UseQuote", mock.Anything, &fx.UseQuoteParams{QuoteId: "quote-id", ClientXid: "test", From: "USD", To: "IDR", Amount: 100}).
+			Return(&fx.Quote{Id: "quote-id", TargetAmount: 1500000}, nil)
+		service := transaction.NewTransactionService(repository, walletService, fxService, newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		outgoing, incoming, err := service.CreateTransfer(context.TODO(), &transaction.CreateTransferParams{
+			CustomerXid:    "test",
+			TargetWalletId: "target-id",
+			ReferenceId:    "ref",
+			Amount:         100,
+			QuoteId:        "quote-id",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "USD", outgoing.Currency)
+		assert.Equal(t, "IDR", incoming.Currency)
+		assert.Equal(t, float64(1500000), incoming.Amount)
+	})
+
+	t.Run("should return error if quote can't be used", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("FindByReferenceId", mock.Anything, "ref", transaction.TYPE_TRANSFER_OUT).Return(nil, nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("Lock", mock.Anything, "source-id", "target-id").Return(nil)
+		walletService.On("GetWallet", mock.Anything, "test", mock.Anything).Return(&wallet.Wallet{Id: "source-id", Balance: 500, Currency: "USD"}, nil)
+		walletService.On("ValidateWallet", mock.Anything).Return(nil)
+		walletService.On("GetWalletById", mock.Anything, "target-id").Return(&wallet.Wallet{Id: "target-id", Currency: "IDR"}, nil)
+		walletService.On("ValidateDeposit", mock.Anything).Return(nil)
+		fxService := fx_mock.NewFxIService(t)
+		fxService.On("UseQuote", mock.Anything, mock.Anything).Return(nil, fx.ErrQuoteExpired)
+		service := transaction.NewTransactionService(repository, walletService, fxService, newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		_, _, err := service.CreateTransfer(context.TODO(), &transaction.CreateTransferParams{
+			CustomerXid:    "test",
+			TargetWalletId: "target-id",
+			ReferenceId:    "ref",
+			Amount:         100,
+			QuoteId:        "quote-id",
+		})
+		assert.Equal(t, fx.ErrQuoteExpired, err)
+	})
+}
+
+func TestTransactionService_ExportTransactions(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	params := &transaction.ExportTransactionsParams{CustomerXid: "test", From: from, To: to}
+
+	t.Run("should return error if period is empty", func(t *testing.T) {
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ExportTransactions(context.TODO(), &transaction.ExportTransactionsParams{CustomerXid: "test", From: to, To: from}, transaction_mock.NewExportWriter(t))
+		assert.Equal(t, transaction.ErrInvalidPeriod, err)
+	})
+
+	t.Run("should return error if wallet is disabled", func(t *testing.T) {
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_DISABLED, Currency: "IDR"}, nil)
+		service := transaction.NewTransactionService(transaction_mock.NewTransactionRepository(t), walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ExportTransactions(context.TODO(), params, transaction_mock.NewExportWriter(t))
+		assert.Equal(t, wallet.ErrWalletDisabled, err)
+	})
+
+	t.Run("should default the period to the days before now", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("SumBalanceChange", mock.Anything, "wallet-id", time.Time{}, mock.Anything).Return(float64(0), nil)
+		repository.On("StreamSuccessTransactions", mock.Anything, "wallet-id", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_ENABLED, Currency: "IDR"}, nil)
+		writer := transaction_mock.NewExportWriter(t)
+		writer.On("WriteOpening", mock.Anything).Run(func(args mock.Arguments) {
+			period := args.Get(0).(*transaction.ExportPeriod)
+			assert.WithinDuration(t, time.Now(), period.To, time.Second)
+			assert.Equal(t, transaction.DEFAULT_EXPORT_PERIOD, period.To.Sub(period.From))
+		}).Return(nil)
+		writer.On("WriteClosing", mock.Anything).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ExportTransactions(context.TODO(), &transaction.ExportTransactionsParams{CustomerXid: "test"}, writer)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should write the running balance between the opening and closing balance", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("SumBalanceChange", mock.Anything, "wallet-id", time.Time{}, from).Return(float64(1000), nil)
+		repository.On("StreamSuccessTransactions", mock.Anything, "wallet-id", from, to, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(4).(func(*transaction.Transaction) error)
+			assert.Nil(t, fn(&transaction.Transaction{Id: "trx-1", Type: transaction.TYPE_DEPOSIT, Amount: 0.1}))
+			assert.Nil(t, fn(&transaction.Transaction{Id: "trx-2", Type: transaction.TYPE_TRANSFER_OUT, Amount: 0.2}))
+		}).Return(nil)
+		walletService := wallet_mock.NewWalletIService(t)
+		walletService.On("GetWallet", mock.Anything, "test", "").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_ENABLED, Currency: "IDR"}, nil)
+		writer := transaction_mock.NewExportWriter(t)
+		writer.On("WriteOpening", mock.Anything).Run(func(args mock.Arguments) {
+			period := args.Get(0).(*transaction.ExportPeriod)
+			assert.Equal(t, float64(1000), period.OpeningBalance)
+		}).Return(nil)
+		writer.On("WriteTransaction", mock.MatchedBy(func(trx *transaction.Transaction) bool { return trx.Id == "trx-1" }), 1000.1).Return(nil)
+		writer.On("WriteTransaction", mock.MatchedBy(func(trx *transaction.Transaction) bool { return trx.Id == "trx-2" }), 999.9).Return(nil)
+		writer.On("WriteClosing", mock.Anything).Run(func(args mock.Arguments) {
+			period := args.Get(0).(*transaction.ExportPeriod)
+			assert.Equal(t, 999.9, period.ClosingBalance)
+		}).Return(nil)
+		service := transaction.NewTransactionService(repository, walletService, fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		err := service.ExportTransactions(context.TODO(), params, writer)
+		assert.Nil(t, err)
+	})
+}
+
+func TestTransactionService_SummarizePeriod(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should split the totals into credits, debits and fees", func(t *testing.T) {
+		repository := transaction_mock.NewTransactionRepository(t)
+		repository.On("SumBalanceChange", mock.Anything, "wallet-id", time.Time{}, from).Return(float64(1000), nil)
+		repository.On("SumAmountsByType", mock.Anything, "wallet-id", from, to).Return([]*transaction.TypeTotal{
+			{Type: transaction.TYPE_DEPOSIT, Amount: 300, Count: 2},
+			{Type: transaction.TYPE_TRANSFER_IN, Amount: 50, Count: 1},
+			{Type: transaction.TYPE_WITHDRAWAL, Amount: 200, Count: 1},
+			{Type: transaction.TYPE_FEE, Amount: 5, Count: 1},
+		}, nil)
+		service := transaction.NewTransactionService(repository, wallet_mock.NewWalletIService(t), fx_mock.NewFxIService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{}, transaction.NewSettlementWorker(time.Hour), activity.NewBroker(activity.HISTORY_SIZE))
+
+		summary, err := service.SummarizePeriod(context.TODO(), "wallet-id", from, to)
+		assert.Nil(t, err)
+		assert.Equal(t, &transaction.PeriodSummary{
+			OpeningBalance:   1000,
+			TotalCredits:     350,
+			TotalDebits:      200,
+			TotalFees:        5,
+			ClosingBalance:   1145,
+			TransactionCount: 5,
+		}, summary)
+	})
 }
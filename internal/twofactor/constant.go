@@ -0,0 +1,17 @@
+package twofactor
+
+import "time"
+
+const (
+	TOTP_SECRET_BYTES = 20
+	TOTP_DIGITS       = 6
+	TOTP_PERIOD       = 30 * time.Second
+	// Number of periods before and after the current one accepted to tolerate clock drift
+	TOTP_SKEW_STEPS = 1
+	TOTP_ISSUER     = "mini-wallet"
+)
+
+const (
+	MAX_FAILED_ATTEMPTS = 5
+	LOCKOUT_DURATION    = 15 * time.Minute
+)
@@ -0,0 +1,11 @@
+package twofactor
+
+import (
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrEmptyCode = errors.NewValidationError("otp is required")
+var ErrInvalidCode = errors.NewUnauthorizedError("otp invalid")
+var ErrNotEnrolled = errors.NewValidationError("totp is not enrolled")
+var ErrAlreadyEnrolled = errors.NewValidationError("totp already enrolled")
+var ErrLocked = errors.NewForbiddenError("too many failed otp attempts, try again later")
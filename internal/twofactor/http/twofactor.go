@@ -0,0 +1,98 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+)
+
+type EnrollmentResponse struct {
+	Secret          string    `json:"secret"`
+	ProvisioningUri string    `json:"provisioning_uri"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CodeRequest struct {
+	Otp string `json:"otp"`
+}
+
+func HandleEnroll(service twofactor.TwoFactorIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		enrollment, err := service.Enroll(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"totp": &EnrollmentResponse{
+				Secret:          enrollment.Secret,
+				ProvisioningUri: twofactor.ProvisioningUri(currentClient.Xid, enrollment.Secret),
+				CreatedAt:       enrollment.CreatedAt,
+			},
+		})
+	}
+}
+
+func HandleActivate(service twofactor.TwoFactorIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CodeRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		err = service.Activate(r.Context(), currentClient.Xid, requestBody.Otp)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, nil)
+	}
+}
+
+func HandleDisable(service twofactor.TwoFactorIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CodeRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		err = service.Disable(r.Context(), currentClient.Xid, requestBody.Otp)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, nil)
+	}
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	twofactor "github.com/defryheryanto/mini-wallet/internal/twofactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EnrollmentRepository is an autogenerated mock type for the EnrollmentRepository type
+type EnrollmentRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, xid
+func (_m *EnrollmentRepository) Delete(ctx context.Context, xid string) error {
+	ret := _m.Called(ctx, xid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByClientXid provides a mock function with given fields: ctx, xid
+func (_m *EnrollmentRepository) FindByClientXid(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *twofactor.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*twofactor.Enrollment, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *twofactor.Enrollment); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*twofactor.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByClientXidForUpdate provides a mock function with given fields: ctx, xid
+func (_m *EnrollmentRepository) FindByClientXidForUpdate(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *twofactor.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*twofactor.Enrollment, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *twofactor.Enrollment); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*twofactor.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *EnrollmentRepository) Insert(ctx context.Context, data *twofactor.Enrollment) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *twofactor.Enrollment) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, data
+func (_m *EnrollmentRepository) Update(ctx context.Context, data *twofactor.Enrollment) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *twofactor.Enrollment) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewEnrollmentRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEnrollmentRepository creates a new instance of EnrollmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEnrollmentRepository(t mockConstructorTestingTNewEnrollmentRepository) *EnrollmentRepository {
+	mock := &EnrollmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
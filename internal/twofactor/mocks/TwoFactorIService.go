@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	twofactor "github.com/defryheryanto/mini-wallet/internal/twofactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorIService is an autogenerated mock type for the TwoFactorIService type
+type TwoFactorIService struct {
+	mock.Mock
+}
+
+// Activate provides a mock function with given fields: ctx, xid, code
+func (_m *TwoFactorIService) Activate(ctx context.Context, xid string, code string) error {
+	ret := _m.Called(ctx, xid, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, xid, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Disable provides a mock function with given fields: ctx, xid, code
+func (_m *TwoFactorIService) Disable(ctx context.Context, xid string, code string) error {
+	ret := _m.Called(ctx, xid, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, xid, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: ctx, xid
+func (_m *TwoFactorIService) Enroll(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *twofactor.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*twofactor.Enrollment, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *twofactor.Enrollment); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*twofactor.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEnrolled provides a mock function with given fields: ctx, xid
+func (_m *TwoFactorIService) IsEnrolled(ctx context.Context, xid string) (bool, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, xid, code
+func (_m *TwoFactorIService) Verify(ctx context.Context, xid string, code string) error {
+	ret := _m.Called(ctx, xid, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, xid, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTwoFactorIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTwoFactorIService creates a new instance of TwoFactorIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTwoFactorIService(t mockConstructorTestingTNewTwoFactorIService) *TwoFactorIService {
+	mock := &TwoFactorIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"context"
+
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EnrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) *EnrollmentRepository {
+	return &EnrollmentRepository{db}
+}
+
+func (r *EnrollmentRepository) Insert(ctx context.Context, data *twofactor.Enrollment) error {
+	payload := Enrollment{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EnrollmentRepository) FindByClientXid(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	result := &Enrollment{}
+
+	err := r.db.WithContext(ctx).Where("client_xid = ?", xid).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *EnrollmentRepository) FindByClientXidForUpdate(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	result := &Enrollment{}
+
+	err := r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("client_xid = ?", xid).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *EnrollmentRepository) Update(ctx context.Context, data *twofactor.Enrollment) error {
+	payload := Enrollment{}.FromServiceModel(data)
+
+	db := r.getGormClient(ctx)
+	err := db.Where("client_xid = ?", payload.ClientXid).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EnrollmentRepository) Delete(ctx context.Context, xid string) error {
+	db := r.getGormClient(ctx)
+	err := db.Where("client_xid = ?", xid).Delete(&Enrollment{}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EnrollmentRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
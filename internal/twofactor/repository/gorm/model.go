@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+)
+
+type Enrollment struct {
+	ClientXid      string     `gorm:"primaryKey;column:client_xid"`
+	Secret         string     `gorm:"column:secret"`
+	ActivatedAt    *time.Time `gorm:"column:activated_at"`
+	FailedAttempts int        `gorm:"column:failed_attempts"`
+	LockedUntil    *time.Time `gorm:"column:locked_until"`
+	LastUsedStep   int64      `gorm:"column:last_used_step"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (Enrollment) TableName() string {
+	return "totp_enrollments"
+}
+
+func (Enrollment) FromServiceModel(data *twofactor.Enrollment) *Enrollment {
+	if data == nil {
+		return nil
+	}
+
+	return &Enrollment{
+		ClientXid:      data.ClientXid,
+		Secret:         data.Secret,
+		ActivatedAt:    data.ActivatedAt,
+		FailedAttempts: data.FailedAttempts,
+		LockedUntil:    data.LockedUntil,
+		LastUsedStep:   data.LastUsedStep,
+		CreatedAt:      data.CreatedAt,
+	}
+}
+
+func (e *Enrollment) ToServiceModel() *twofactor.Enrollment {
+	return &twofactor.Enrollment{
+		ClientXid:      e.ClientXid,
+		Secret:         e.Secret,
+		ActivatedAt:    e.ActivatedAt,
+		FailedAttempts: e.FailedAttempts,
+		LockedUntil:    e.LockedUntil,
+		LastUsedStep:   e.LastUsedStep,
+		CreatedAt:      e.CreatedAt,
+	}
+}
@@ -0,0 +1,87 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate a random base32 encoded TOTP secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, TOTP_SECRET_BYTES)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return secretEncoding.EncodeToString(b), nil
+}
+
+// Return the TOTP time step of the given time
+func Step(at time.Time) int64 {
+	return at.Unix() / int64(TOTP_PERIOD/time.Second)
+}
+
+// Return the RFC 6238 code of the base32 encoded secret at the given time step,
+// using HMAC-SHA1 as RFC 4226
+func GenerateCode(secret string, step int64) (string, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	code := truncated % uint32(math.Pow10(TOTP_DIGITS))
+
+	return fmt.Sprintf("%0*d", TOTP_DIGITS, code), nil
+}
+
+// Return the time step matching the code within the allowed clock drift around the given time.
+//
+// Return false if no step matches
+func MatchCode(secret, code string, at time.Time) (int64, bool, error) {
+	current := Step(at)
+	for step := current - TOTP_SKEW_STEPS; step <= current+TOTP_SKEW_STEPS; step++ {
+		expected, err := GenerateCode(secret, step)
+		if err != nil {
+			return 0, false, err
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// Return the otpauth URI of the secret to be imported into authenticator apps, usually as QR code
+func ProvisioningUri(accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", TOTP_ISSUER)
+	query.Set("digits", fmt.Sprint(TOTP_DIGITS))
+	query.Set("period", fmt.Sprint(int64(TOTP_PERIOD/time.Second)))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + TOTP_ISSUER + ":" + accountName,
+		RawQuery: query.Encode(),
+	}).String()
+}
@@ -0,0 +1,64 @@
+package twofactor_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	"github.com/stretchr/testify/assert"
+)
+
+// Base32 of the RFC 6238 SHA1 test secret "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	t.Run("should match the RFC 6238 test vectors truncated to 6 digits", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		for unix, expected := range vectors {
+			code, err := twofactor.GenerateCode(rfcSecret, twofactor.Step(time.Unix(unix, 0)))
+			assert.Nil(t, err)
+			assert.Equal(t, expected, code, "at %d", unix)
+		}
+	})
+	t.Run("should return error if secret is not base32", func(t *testing.T) {
+		_, err := twofactor.GenerateCode("not base32!", 1)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestMatchCode(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+
+	t.Run("should match code of adjacent step", func(t *testing.T) {
+		code, _ := twofactor.GenerateCode(rfcSecret, twofactor.Step(at)-1)
+
+		step, ok, err := twofactor.MatchCode(rfcSecret, code, at)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, twofactor.Step(at)-1, step)
+	})
+	t.Run("should not match code outside the allowed drift", func(t *testing.T) {
+		code, _ := twofactor.GenerateCode(rfcSecret, twofactor.Step(at)-2)
+
+		_, ok, err := twofactor.MatchCode(rfcSecret, code, at)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestProvisioningUri(t *testing.T) {
+	t.Run("should return otpauth uri holding the secret", func(t *testing.T) {
+		uri := twofactor.ProvisioningUri("client-xid", rfcSecret)
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/mini-wallet:client-xid?"))
+		assert.Contains(t, uri, "secret="+rfcSecret)
+	})
+}
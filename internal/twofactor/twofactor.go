@@ -0,0 +1,219 @@
+package twofactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+)
+
+// Enrollment holds the TOTP secret of a client.
+// The enrollment is pending until activated with a valid code
+type Enrollment struct {
+	ClientXid      string     `json:"client_xid"`
+	Secret         string     `json:"-"`
+	ActivatedAt    *time.Time `json:"activated_at"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LockedUntil    *time.Time `json:"locked_until"`
+	// Time step of the last accepted code, so a code can't be used twice
+	LastUsedStep int64     `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type EnrollmentRepository interface {
+	Insert(ctx context.Context, data *Enrollment) error
+	FindByClientXid(ctx context.Context, xid string) (*Enrollment, error)
+	// Find the enrollment and lock it until the end of the database transaction of the context
+	FindByClientXidForUpdate(ctx context.Context, xid string) (*Enrollment, error)
+	Update(ctx context.Context, data *Enrollment) error
+	Delete(ctx context.Context, xid string) error
+}
+
+type TwoFactorIService interface {
+	Enroll(ctx context.Context, xid string) (*Enrollment, error)
+	Activate(ctx context.Context, xid, code string) error
+	Disable(ctx context.Context, xid, code string) error
+	IsEnrolled(ctx context.Context, xid string) (bool, error)
+	Verify(ctx context.Context, xid, code string) error
+}
+
+type TwoFactorService struct {
+	repository     EnrollmentRepository
+	storageManager manager.StorageManager
+}
+
+func NewTwoFactorService(repository EnrollmentRepository, storageManager manager.StorageManager) *TwoFactorService {
+	return &TwoFactorService{repository, storageManager}
+}
+
+func (e *Enrollment) IsActive() bool {
+	return e.ActivatedAt != nil
+}
+
+func (e *Enrollment) IsLocked(at time.Time) bool {
+	return e.LockedUntil != nil && at.Before(*e.LockedUntil)
+}
+
+// Generate a new TOTP secret for the client, replacing the pending one if any.
+// The returned enrollment holds the secret, it has to be activated before it is enforced
+func (s *TwoFactorService) Enroll(ctx context.Context, xid string) (*Enrollment, error) {
+	existingEnrollment, err := s.repository.FindByClientXid(ctx, xid)
+	if err != nil {
+		return nil, err
+	}
+	if existingEnrollment != nil && existingEnrollment.IsActive() {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment := &Enrollment{
+		ClientXid: xid,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+	if existingEnrollment != nil {
+		err = s.repository.Update(ctx, enrollment)
+	} else {
+		err = s.repository.Insert(ctx, enrollment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// Activate the pending enrollment of the client with a code generated from its secret
+func (s *TwoFactorService) Activate(ctx context.Context, xid, code string) error {
+	return s.verifyLocked(ctx, xid, code, func(enrollment *Enrollment) error {
+		if enrollment.IsActive() {
+			return ErrAlreadyEnrolled
+		}
+		return nil
+	}, func(ctx context.Context, enrollment *Enrollment) error {
+		now := time.Now()
+		enrollment.ActivatedAt = &now
+		return s.repository.Update(ctx, enrollment)
+	})
+}
+
+// Remove the active enrollment of the client, the code is required so a leaked token alone can't disable it
+func (s *TwoFactorService) Disable(ctx context.Context, xid, code string) error {
+	return s.verifyLocked(ctx, xid, code, requireActive, func(ctx context.Context, enrollment *Enrollment) error {
+		return s.repository.Delete(ctx, xid)
+	})
+}
+
+// Return true if the client has an active enrollment
+func (s *TwoFactorService) IsEnrolled(ctx context.Context, xid string) (bool, error) {
+	enrollment, err := s.repository.FindByClientXid(ctx, xid)
+	if err != nil {
+		return false, err
+	}
+
+	return enrollment != nil && enrollment.IsActive(), nil
+}
+
+// Verify the code against the active enrollment of the client
+func (s *TwoFactorService) Verify(ctx context.Context, xid, code string) error {
+	return s.verifyLocked(ctx, xid, code, requireActive, func(ctx context.Context, enrollment *Enrollment) error {
+		return nil
+	})
+}
+
+// Verify the code against the enrollment of the client locked in a database transaction,
+// so concurrent attempts can't lose a failed attempt or accept the same code twice.
+// The enrollment is checked by check before the code, and changed by onVerified once the code is accepted.
+// A rejected code is returned after the transaction is committed, so its failed attempt is kept
+func (s *TwoFactorService) verifyLocked(
+	ctx context.Context,
+	xid, code string,
+	check func(enrollment *Enrollment) error,
+	onVerified func(ctx context.Context, enrollment *Enrollment) error,
+) error {
+	var rejectedErr error
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		enrollment, err := s.repository.FindByClientXidForUpdate(ctx, xid)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil {
+			return ErrNotEnrolled
+		}
+		err = check(enrollment)
+		if err != nil {
+			return err
+		}
+
+		err = s.verifyCode(ctx, enrollment, code)
+		if err == ErrInvalidCode {
+			rejectedErr = err
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return onVerified(ctx, enrollment)
+	})
+	if err != nil {
+		return err
+	}
+
+	return rejectedErr
+}
+
+func requireActive(enrollment *Enrollment) error {
+	if !enrollment.IsActive() {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+// Verify the code against the secret of the enrollment.
+// Every failed attempt is counted, and the enrollment is locked once the attempts reach MAX_FAILED_ATTEMPTS.
+// Codes of a time step not after the last accepted one are rejected to prevent replays
+func (s *TwoFactorService) verifyCode(ctx context.Context, enrollment *Enrollment, code string) error {
+	if code == "" {
+		return ErrEmptyCode
+	}
+
+	now := time.Now()
+	if enrollment.IsLocked(now) {
+		return ErrLocked
+	}
+
+	step, ok, err := MatchCode(enrollment.Secret, code, now)
+	if err != nil {
+		return err
+	}
+	if !ok || step <= enrollment.LastUsedStep {
+		enrollment.FailedAttempts++
+		if enrollment.FailedAttempts >= MAX_FAILED_ATTEMPTS {
+			lockedUntil := now.Add(LOCKOUT_DURATION)
+			enrollment.LockedUntil = &lockedUntil
+			enrollment.FailedAttempts = 0
+		}
+
+		err = s.repository.Update(ctx, enrollment)
+		if err != nil {
+			return err
+		}
+
+		return ErrInvalidCode
+	}
+
+	enrollment.FailedAttempts = 0
+	enrollment.LockedUntil = nil
+	enrollment.LastUsedStep = step
+	err = s.repository.Update(ctx, enrollment)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
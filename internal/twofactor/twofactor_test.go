@@ -0,0 +1,310 @@
+package twofactor_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/twofactor"
+	twofactor_mock "github.com/defryheryanto/mini-wallet/internal/twofactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func currentCode(secret string) string {
+	code, _ := twofactor.GenerateCode(secret, twofactor.Step(time.Now()))
+	return code
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	now := time.Now()
+
+	t.Run("should return error if failed to find enrollment", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXid", mock.Anything, xid).Return(nil, mockedErr)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		res, err := service.Enroll(context.TODO(), xid)
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should return error if already enrolled", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXid", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, ActivatedAt: &now}, nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		res, err := service.Enroll(context.TODO(), xid)
+		assert.Equal(t, twofactor.ErrAlreadyEnrolled, err)
+		assert.Nil(t, res)
+	})
+	t.Run("should replace secret of pending enrollment", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXid", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		res, err := service.Enroll(context.TODO(), xid)
+		assert.Nil(t, err)
+		assert.NotEqual(t, rfcSecret, res.Secret)
+		assert.False(t, res.IsActive())
+	})
+	t.Run("should insert pending enrollment", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXid", mock.Anything, xid).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		res, err := service.Enroll(context.TODO(), xid)
+		assert.Nil(t, err)
+		assert.Equal(t, xid, res.ClientXid)
+		assert.NotEmpty(t, res.Secret)
+		assert.False(t, res.IsActive())
+	})
+}
+
+func TestTwoFactorService_Activate(t *testing.T) {
+	xid := "xid"
+
+	t.Run("should return error if not enrolled", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(nil, nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Activate(context.TODO(), xid, "123456")
+		assert.Equal(t, twofactor.ErrNotEnrolled, err)
+	})
+	t.Run("should activate enrollment with valid code", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Activate(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Nil(t, err)
+		updateParams := repository.Calls[len(repository.Calls)-1].Arguments.Get(1).(*twofactor.Enrollment)
+		assert.True(t, updateParams.IsActive())
+	})
+}
+
+func TestTwoFactorService_Verify(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+	xid := "xid"
+	now := time.Now()
+
+	t.Run("should return error if enrollment not activated", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret}, nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Equal(t, twofactor.ErrNotEnrolled, err)
+	})
+	t.Run("should return error if code empty", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}, nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, "")
+		assert.Equal(t, twofactor.ErrEmptyCode, err)
+	})
+	t.Run("should return error if locked", func(t *testing.T) {
+		lockedUntil := now.Add(time.Minute)
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now, LockedUntil: &lockedUntil}, nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Equal(t, twofactor.ErrLocked, err)
+	})
+	t.Run("should count failed attempt if code invalid", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now, FailedAttempts: 1}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*twofactor.Enrollment)
+			assert.True(t, ok, "params should be *Enrollment")
+			assert.Equal(t, 2, updateParams.FailedAttempts)
+			assert.Nil(t, updateParams.LockedUntil)
+		}).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, "000000x")
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+	t.Run("should lock enrollment once failed attempts reach the limit", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now, FailedAttempts: twofactor.MAX_FAILED_ATTEMPTS - 1}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*twofactor.Enrollment)
+			assert.True(t, ok, "params should be *Enrollment")
+			assert.True(t, updateParams.IsLocked(time.Now()))
+		}).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, "000000x")
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+	t.Run("should reject code already used", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now, LastUsedStep: twofactor.Step(time.Now()) + 1}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+	t.Run("should return error if failed to update enrollment", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Equal(t, mockedErr, err)
+	})
+	t.Run("should reset failed attempts if code valid", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now, FailedAttempts: 3}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*twofactor.Enrollment)
+			assert.True(t, ok, "params should be *Enrollment")
+			assert.Equal(t, 0, updateParams.FailedAttempts)
+			assert.NotZero(t, updateParams.LastUsedStep)
+		}).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Verify(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Nil(t, err)
+	})
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	xid := "xid"
+	now := time.Now()
+
+	t.Run("should return error if code invalid", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Disable(context.TODO(), xid, "000000x")
+		assert.Equal(t, twofactor.ErrInvalidCode, err)
+	})
+	t.Run("should delete enrollment if code valid", func(t *testing.T) {
+		repository := twofactor_mock.NewEnrollmentRepository(t)
+		repository.On("FindByClientXidForUpdate", mock.Anything, xid).Return(&twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		repository.On("Delete", mock.Anything, xid).Return(nil)
+		service := twofactor.NewTwoFactorService(repository, &manager.MockStorageManager{})
+
+		err := service.Disable(context.TODO(), xid, currentCode(rfcSecret))
+		assert.Nil(t, err)
+	})
+}
+
+type transactionLocksKey struct{}
+
+// In-memory enrollment store acting as its own storage manager.
+// FindByClientXidForUpdate holds the lock of the enrollment until the end of the transaction, like SELECT ... FOR UPDATE
+type lockingEnrollmentStore struct {
+	mu         sync.Mutex
+	rowLock    sync.Mutex
+	enrollment twofactor.Enrollment
+}
+
+func (s *lockingEnrollmentStore) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	locked := false
+	err := fn(context.WithValue(ctx, transactionLocksKey{}, &locked))
+	if locked {
+		s.rowLock.Unlock()
+	}
+	return err
+}
+
+func (s *lockingEnrollmentStore) AfterCommit(ctx context.Context, fn func()) {
+	fn()
+}
+
+func (s *lockingEnrollmentStore) Insert(ctx context.Context, data *twofactor.Enrollment) error {
+	return s.Update(ctx, data)
+}
+
+func (s *lockingEnrollmentStore) FindByClientXid(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	s.mu.Lock()
+	enrollment := s.enrollment
+	s.mu.Unlock()
+
+	// Widen the window between the read and the write of a concurrent verification
+	time.Sleep(time.Millisecond)
+	return &enrollment, nil
+}
+
+func (s *lockingEnrollmentStore) FindByClientXidForUpdate(ctx context.Context, xid string) (*twofactor.Enrollment, error) {
+	s.rowLock.Lock()
+	*ctx.Value(transactionLocksKey{}).(*bool) = true
+
+	return s.FindByClientXid(ctx, xid)
+}
+
+func (s *lockingEnrollmentStore) Update(ctx context.Context, data *twofactor.Enrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enrollment = *data
+	return nil
+}
+
+func (s *lockingEnrollmentStore) Delete(ctx context.Context, xid string) error {
+	return nil
+}
+
+func TestTwoFactorService_VerifyConcurrently(t *testing.T) {
+	xid := "xid"
+	now := time.Now()
+
+	verifyConcurrently := func(service *twofactor.TwoFactorService, code string, attempts int) []error {
+		errs := make([]error, attempts)
+		wg := sync.WaitGroup{}
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = service.Verify(context.TODO(), xid, code)
+			}(i)
+		}
+		wg.Wait()
+
+		return errs
+	}
+
+	t.Run("should count every concurrent failed attempt", func(t *testing.T) {
+		store := &lockingEnrollmentStore{enrollment: twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}}
+		service := twofactor.NewTwoFactorService(store, store)
+
+		errs := verifyConcurrently(service, "000000x", twofactor.MAX_FAILED_ATTEMPTS)
+		for _, err := range errs {
+			assert.Equal(t, twofactor.ErrInvalidCode, err)
+		}
+		assert.True(t, store.enrollment.IsLocked(time.Now()))
+	})
+	t.Run("should accept a code once when verified concurrently", func(t *testing.T) {
+		store := &lockingEnrollmentStore{enrollment: twofactor.Enrollment{ClientXid: xid, Secret: rfcSecret, ActivatedAt: &now}}
+		service := twofactor.NewTwoFactorService(store, store)
+
+		errs := verifyConcurrently(service, currentCode(rfcSecret), 4)
+		accepted := 0
+		for _, err := range errs {
+			if err == nil {
+				accepted++
+				continue
+			}
+			assert.Equal(t, twofactor.ErrInvalidCode, err)
+		}
+		assert.Equal(t, 1, accepted)
+	})
+}
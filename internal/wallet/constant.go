@@ -3,4 +3,30 @@ package wallet
 const (
 	STATUS_DISABLED = "disabled"
 	STATUS_ENABLED  = "enabled"
+	// Frozen by an operator, the wallet still receives deposits but its balance can't be withdrawn
+	// and the client can't enable or disable it until an operator unfreezes it
+	STATUS_FROZEN = "frozen"
+	// Closed for good with a zero balance, no transition leaves it
+	STATUS_CLOSED = "closed"
+)
+
+const (
+	// Name of the wallets created without a name, e.g. the wallet created along with the client
+	DEFAULT_NAME           = "main"
+	MAX_NAME_LENGTH        = 50
+	MAX_WALLETS_PER_CLIENT = 10
+)
+
+const (
+	DEFAULT_SEARCH_LIMIT = 50
+	MAX_SEARCH_LIMIT     = 500
+)
+
+const (
+	AUDIT_ACTION_CREATED          = "wallet.created"
+	AUDIT_ACTION_STATUS_UPDATED   = "wallet.status_updated"
+	AUDIT_ACTION_BALANCE_ADDED    = "wallet.balance_added"
+	AUDIT_ACTION_BALANCE_DEDUCTED = "wallet.balance_deducted"
+	AUDIT_ACTION_RENAMED          = "wallet.renamed"
+	AUDIT_ACTION_DEFAULT_CHANGED  = "wallet.default_changed"
 )
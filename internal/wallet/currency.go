@@ -0,0 +1,22 @@
+package wallet
+
+import "github.com/defryheryanto/mini-wallet/internal/currency"
+
+// Return error if the amount can't be moved in or out of the wallet,
+// e.g. the amount isn't positive, the given currency differs from the currency of the wallet or the amount has too many decimals.
+// The currency of the wallet is assumed if the given currency is empty
+func (w *Wallet) ValidateAmount(amount float64, currencyCode string) error {
+	if amount <= 0 {
+		return ErrNonPositiveAmount
+	}
+	if currencyCode != "" && currencyCode != w.Currency {
+		return ErrCurrencyMismatch
+	}
+
+	c, err := currency.Find(w.Currency)
+	if err != nil {
+		return err
+	}
+
+	return c.ValidateAmount(amount)
+}
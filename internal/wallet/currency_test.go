@@ -0,0 +1,31 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet_ValidateAmount(t *testing.T) {
+	w := &wallet.Wallet{Currency: "JPY"}
+
+	t.Run("should assume the wallet currency if currency is empty", func(t *testing.T) {
+		assert.Nil(t, w.ValidateAmount(100, ""))
+	})
+
+	t.Run("should return error if amount is not positive", func(t *testing.T) {
+		assert.Equal(t, wallet.ErrNonPositiveAmount, w.ValidateAmount(0, ""))
+		assert.Equal(t, wallet.ErrNonPositiveAmount, w.ValidateAmount(-100, "JPY"))
+	})
+
+	t.Run("should return error if currency differs from the wallet", func(t *testing.T) {
+		assert.Equal(t, wallet.ErrCurrencyMismatch, w.ValidateAmount(100, "USD"))
+	})
+
+	t.Run("should return error if amount has more decimals than the currency", func(t *testing.T) {
+		c, _ := currency.Find("JPY")
+		assert.Equal(t, currency.ErrTooManyDecimals(c), w.ValidateAmount(100.5, "JPY"))
+	})
+}
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"fmt"
+
 	"github.com/defryheryanto/mini-wallet/internal/errors"
 )
 
@@ -10,3 +12,19 @@ var ErrWalletAlreadyEnabled = errors.NewValidationError("Already enabled")
 var ErrWalletAlreadyDisabled = errors.NewValidationError("Already disabled")
 var ErrWalletDisabled = errors.NewNotFoundError("Wallet disabled")
 var ErrInsufficientBalance = errors.NewValidationError("balance insufficient")
+var ErrWalletFrozen = errors.NewForbiddenError("Wallet frozen")
+var ErrInvalidStatus = errors.NewValidationError("wallet status invalid")
+var ErrWalletAlreadyInStatus = errors.NewValidationError("wallet already in the given status")
+var ErrWalletClosed = errors.NewNotFoundError("Wallet closed")
+var ErrEmptyReason = errors.NewValidationError("reason is required")
+var ErrWalletBalanceNotZero = errors.NewValidationError("wallet balance has to be zero or paid out to close the wallet")
+var ErrNonPositiveAmount = errors.NewValidationError("amount must be greater than zero")
+var ErrCurrencyMismatch = errors.NewValidationError("currency doesn't match the currency of the wallet")
+var ErrEmptyWalletName = errors.NewValidationError("wallet name is required")
+var ErrWalletNameTooLong = errors.NewValidationError(fmt.Sprintf("wallet name can't be longer than %d characters", MAX_NAME_LENGTH))
+var ErrWalletNameTaken = errors.NewValidationError("wallet name already used by another wallet of the client")
+var ErrWalletLimitReached = errors.NewValidationError(fmt.Sprintf("client can't own more than %d wallets", MAX_WALLETS_PER_CLIENT))
+
+func ErrTransitionNotAllowed(from, to string) errors.HandledError {
+	return errors.NewValidationError(fmt.Sprintf("wallet can't move from %s to %s", from, to))
+}
@@ -10,11 +10,15 @@ import (
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
 	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/go-chi/chi/v5"
 )
 
 type EnabledWalletResponse struct {
 	Id        string    `json:"id"`
 	OwnedBy   string    `json:"owned_by"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	Currency  string    `json:"currency"`
 	Status    string    `json:"status"`
 	EnabledAt time.Time `json:"enabled_at"`
 	Balance   float64   `json:"balance"`
@@ -22,11 +26,49 @@ type EnabledWalletResponse struct {
 type DisabledWalletResponse struct {
 	Id         string    `json:"id"`
 	OwnedBy    string    `json:"owned_by"`
+	Name       string    `json:"name"`
+	IsDefault  bool      `json:"is_default"`
+	Currency   string    `json:"currency"`
 	Status     string    `json:"status"`
 	DisabledAt time.Time `json:"disabled_at"`
 	Balance    float64   `json:"balance"`
 }
 
+type FrozenWalletResponse struct {
+	Id        string    `json:"id"`
+	OwnedBy   string    `json:"owned_by"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	FrozenAt  time.Time `json:"frozen_at"`
+	Balance   float64   `json:"balance"`
+}
+
+// WalletResponse describes the wallet in any status
+type WalletResponse struct {
+	Id         string     `json:"id"`
+	OwnedBy    string     `json:"owned_by"`
+	Name       string     `json:"name"`
+	IsDefault  bool       `json:"is_default"`
+	Currency   string     `json:"currency"`
+	Status     string     `json:"status"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	EnabledAt  *time.Time `json:"enabled_at"`
+	FrozenAt   *time.Time `json:"frozen_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	Balance    float64    `json:"balance"`
+}
+
+type CreateWalletRequest struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+type RenameWalletRequest struct {
+	Name string `json:"name"`
+}
+
 type UpdateWalletStatusRequest struct {
 	IsDisabled bool `json:"is_disabled"`
 }
@@ -39,7 +81,7 @@ func HandleEnableWallet(service wallet.WalletIService) http.HandlerFunc {
 			return
 		}
 
-		targetWallet, err := service.UpdateStatus(r.Context(), currentClient.Xid, true)
+		targetWallet, err := service.UpdateStatus(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), true)
 		if err != nil {
 			response.Failed(w, err)
 			return
@@ -49,6 +91,9 @@ func HandleEnableWallet(service wallet.WalletIService) http.HandlerFunc {
 			"wallet": &EnabledWalletResponse{
 				Id:        targetWallet.Id,
 				OwnedBy:   targetWallet.OwnedBy,
+				Name:      targetWallet.Name,
+				IsDefault: targetWallet.IsDefault,
+				Currency:  targetWallet.Currency,
 				EnabledAt: *targetWallet.EnabledAt,
 				Status:    targetWallet.Status,
 				Balance:   targetWallet.Balance,
@@ -65,16 +110,35 @@ func HandleViewWallet(service wallet.WalletIService) http.HandlerFunc {
 			return
 		}
 
-		targetWallet, err := service.GetWalletByXid(r.Context(), currentClient.Xid)
+		targetWallet, err := service.GetWallet(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
 		if err != nil {
 			response.Failed(w, err)
 			return
 		}
 
+		if targetWallet.Status == wallet.STATUS_FROZEN {
+			response.Success(w, http.StatusOK, map[string]interface{}{
+				"wallet": &FrozenWalletResponse{
+					Id:        targetWallet.Id,
+					OwnedBy:   targetWallet.OwnedBy,
+					Name:      targetWallet.Name,
+					IsDefault: targetWallet.IsDefault,
+					Currency:  targetWallet.Currency,
+					FrozenAt:  *targetWallet.FrozenAt,
+					Status:    targetWallet.Status,
+					Balance:   targetWallet.Balance,
+				},
+			})
+			return
+		}
+
 		response.Success(w, http.StatusOK, map[string]interface{}{
 			"wallet": &EnabledWalletResponse{
 				Id:        targetWallet.Id,
 				OwnedBy:   targetWallet.OwnedBy,
+				Name:      targetWallet.Name,
+				IsDefault: targetWallet.IsDefault,
+				Currency:  targetWallet.Currency,
 				EnabledAt: *targetWallet.EnabledAt,
 				Status:    targetWallet.Status,
 				Balance:   targetWallet.Balance,
@@ -107,7 +171,7 @@ func HandleUpdateWalletStatus(service wallet.WalletIService) http.HandlerFunc {
 			return
 		}
 
-		targetWallet, err := service.UpdateStatus(r.Context(), currentClient.Xid, !requestBody.IsDisabled)
+		targetWallet, err := service.UpdateStatus(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), !requestBody.IsDisabled)
 		if err != nil {
 			response.Failed(w, err)
 			return
@@ -118,6 +182,9 @@ func HandleUpdateWalletStatus(service wallet.WalletIService) http.HandlerFunc {
 				"wallet": &DisabledWalletResponse{
 					Id:         targetWallet.Id,
 					OwnedBy:    targetWallet.OwnedBy,
+					Name:       targetWallet.Name,
+					IsDefault:  targetWallet.IsDefault,
+					Currency:   targetWallet.Currency,
 					DisabledAt: *targetWallet.DisabledAt,
 					Status:     targetWallet.Status,
 					Balance:    targetWallet.Balance,
@@ -128,6 +195,9 @@ func HandleUpdateWalletStatus(service wallet.WalletIService) http.HandlerFunc {
 				"wallet": &EnabledWalletResponse{
 					Id:        targetWallet.Id,
 					OwnedBy:   targetWallet.OwnedBy,
+					Name:      targetWallet.Name,
+					IsDefault: targetWallet.IsDefault,
+					Currency:  targetWallet.Currency,
 					EnabledAt: *targetWallet.EnabledAt,
 					Status:    targetWallet.Status,
 					Balance:   targetWallet.Balance,
@@ -137,3 +207,132 @@ func HandleUpdateWalletStatus(service wallet.WalletIService) http.HandlerFunc {
 
 	}
 }
+
+func HandleListWallets(service wallet.WalletIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		wallets, err := service.ListWallets(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		result := []*WalletResponse{}
+		for _, targetWallet := range wallets {
+			result = append(result, toWalletResponse(targetWallet))
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallets": result,
+		})
+	}
+}
+
+func HandleCreateWallet(service wallet.WalletIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateWalletRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		createdWallet, err := service.Create(r.Context(), &wallet.CreateWalletParams{
+			OwnedBy:  currentClient.Xid,
+			Name:     requestBody.Name,
+			Currency: requestBody.Currency,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"wallet": toWalletResponse(createdWallet),
+		})
+	}
+}
+
+func HandleRenameWallet(service wallet.WalletIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &RenameWalletRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil {
+			if err == io.EOF {
+				response.Failed(w, errors.NewValidationError(map[string]interface{}{
+					"name": []string{
+						"Missing data for required field.",
+					},
+				}))
+				return
+			}
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		targetWallet, err := service.Rename(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"), requestBody.Name)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallet": toWalletResponse(targetWallet),
+		})
+	}
+}
+
+func HandleSetDefaultWallet(service wallet.WalletIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		targetWallet, err := service.SetDefault(r.Context(), currentClient.Xid, chi.URLParam(r, "wallet_id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"wallet": toWalletResponse(targetWallet),
+		})
+	}
+}
+
+func toWalletResponse(target *wallet.Wallet) *WalletResponse {
+	return &WalletResponse{
+		Id:         target.Id,
+		OwnedBy:    target.OwnedBy,
+		Name:       target.Name,
+		IsDefault:  target.IsDefault,
+		Currency:   target.Currency,
+		Status:     target.Status,
+		DisabledAt: target.DisabledAt,
+		EnabledAt:  target.EnabledAt,
+		FrozenAt:   target.FrozenAt,
+		ClosedAt:   target.ClosedAt,
+		Balance:    target.Balance,
+	}
+}
@@ -28,18 +28,63 @@ func (_m *WalletIService) AddBalance(ctx context.Context, walletId string, amoun
 	return r0
 }
 
+// Close provides a mock function with given fields: ctx, params
+func (_m *WalletIService) Close(ctx context.Context, params *wallet.CloseWalletParams) (*wallet.Wallet, float64, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *wallet.Wallet
+	var r1 float64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CloseWalletParams) (*wallet.Wallet, float64, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CloseWalletParams) *wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.CloseWalletParams) float64); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Get(1).(float64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *wallet.CloseWalletParams) error); ok {
+		r2 = rf(ctx, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Create provides a mock function with given fields: ctx, params
-func (_m *WalletIService) Create(ctx context.Context, params *wallet.CreateWalletParams) error {
+func (_m *WalletIService) Create(ctx context.Context, params *wallet.CreateWalletParams) (*wallet.Wallet, error) {
 	ret := _m.Called(ctx, params)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CreateWalletParams) error); ok {
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CreateWalletParams) (*wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CreateWalletParams) *wallet.Wallet); ok {
 		r0 = rf(ctx, params)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.CreateWalletParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // DeductBalance provides a mock function with given fields: ctx, walletId, amount
@@ -56,6 +101,84 @@ func (_m *WalletIService) DeductBalance(ctx context.Context, walletId string, am
 	return r0
 }
 
+// GetStatistics provides a mock function with given fields: ctx
+func (_m *WalletIService) GetStatistics(ctx context.Context) ([]*wallet.Statistics, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*wallet.Statistics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*wallet.Statistics, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*wallet.Statistics); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Statistics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWallet provides a mock function with given fields: ctx, customerXid, walletId
+func (_m *WalletIService) GetWallet(ctx context.Context, customerXid string, walletId string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, customerXid, walletId)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, customerXid, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, customerXid, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWalletById provides a mock function with given fields: ctx, walletId
+func (_m *WalletIService) GetWalletById(ctx context.Context, walletId string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, walletId)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, walletId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, walletId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWalletByXid provides a mock function with given fields: ctx, customerXid
 func (_m *WalletIService) GetWalletByXid(ctx context.Context, customerXid string) (*wallet.Wallet, error) {
 	ret := _m.Called(ctx, customerXid)
@@ -82,25 +205,124 @@ func (_m *WalletIService) GetWalletByXid(ctx context.Context, customerXid string
 	return r0, r1
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, customerXid, isEnabled
-func (_m *WalletIService) UpdateStatus(ctx context.Context, customerXid string, isEnabled bool) (*wallet.Wallet, error) {
-	ret := _m.Called(ctx, customerXid, isEnabled)
+// ListWallets provides a mock function with given fields: ctx, customerXid
+func (_m *WalletIService) ListWallets(ctx context.Context, customerXid string) ([]*wallet.Wallet, error) {
+	ret := _m.Called(ctx, customerXid)
+
+	var r0 []*wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*wallet.Wallet, error)); ok {
+		return rf(ctx, customerXid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*wallet.Wallet); ok {
+		r0 = rf(ctx, customerXid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, customerXid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: ctx, walletIds
+func (_m *WalletIService) Lock(ctx context.Context, walletIds ...string) error {
+	_va := make([]interface{}, len(walletIds))
+	for _i := range walletIds {
+		_va[_i] = walletIds[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, walletIds...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rename provides a mock function with given fields: ctx, customerXid, walletId, name
+func (_m *WalletIService) Rename(ctx context.Context, customerXid string, walletId string, name string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, customerXid, walletId, name)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, customerXid, walletId, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, customerXid, walletId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchWallets provides a mock function with given fields: ctx, params
+func (_m *WalletIService) SearchWallets(ctx context.Context, params *wallet.SearchWalletsParams) ([]*wallet.Wallet, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.SearchWalletsParams) ([]*wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.SearchWalletsParams) []*wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.SearchWalletsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDefault provides a mock function with given fields: ctx, customerXid, walletId
+func (_m *WalletIService) SetDefault(ctx context.Context, customerXid string, walletId string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, customerXid, walletId)
 
 	var r0 *wallet.Wallet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*wallet.Wallet, error)); ok {
-		return rf(ctx, customerXid, isEnabled)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, customerXid, walletId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *wallet.Wallet); ok {
-		r0 = rf(ctx, customerXid, isEnabled)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, customerXid, walletId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.Wallet)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
-		r1 = rf(ctx, customerXid, isEnabled)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, customerXid, walletId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,6 +330,72 @@ func (_m *WalletIService) UpdateStatus(ctx context.Context, customerXid string,
 	return r0, r1
 }
 
+// Transition provides a mock function with given fields: ctx, params
+func (_m *WalletIService) Transition(ctx context.Context, params *wallet.TransitionParams) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.TransitionParams) (*wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.TransitionParams) *wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.TransitionParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, customerXid, walletId, isEnabled
+func (_m *WalletIService) UpdateStatus(ctx context.Context, customerXid string, walletId string, isEnabled bool) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, customerXid, walletId, isEnabled)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (*wallet.Wallet, error)); ok {
+		return rf(ctx, customerXid, walletId, isEnabled)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) *wallet.Wallet); ok {
+		r0 = rf(ctx, customerXid, walletId, isEnabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, customerXid, walletId, isEnabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateDeposit provides a mock function with given fields: target
+func (_m *WalletIService) ValidateDeposit(target *wallet.Wallet) error {
+	ret := _m.Called(target)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*wallet.Wallet) error); ok {
+		r0 = rf(target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateWallet provides a mock function with given fields: target
 func (_m *WalletIService) ValidateWallet(target *wallet.Wallet) error {
 	ret := _m.Called(target)
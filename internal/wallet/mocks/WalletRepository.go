@@ -14,20 +14,20 @@ type WalletRepository struct {
 	mock.Mock
 }
 
-// FindByCustomerXid provides a mock function with given fields: ctx, xid
-func (_m *WalletRepository) FindByCustomerXid(ctx context.Context, xid string) (*wallet.Wallet, error) {
+// FindAllByCustomerXid provides a mock function with given fields: ctx, xid
+func (_m *WalletRepository) FindAllByCustomerXid(ctx context.Context, xid string) ([]*wallet.Wallet, error) {
 	ret := _m.Called(ctx, xid)
 
-	var r0 *wallet.Wallet
+	var r0 []*wallet.Wallet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*wallet.Wallet, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*wallet.Wallet, error)); ok {
 		return rf(ctx, xid)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *wallet.Wallet); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*wallet.Wallet); ok {
 		r0 = rf(ctx, xid)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*wallet.Wallet)
+			r0 = ret.Get(0).([]*wallet.Wallet)
 		}
 	}
 
@@ -66,6 +66,84 @@ func (_m *WalletRepository) FindById(ctx context.Context, id string) (*wallet.Wa
 	return r0, r1
 }
 
+// FindByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *WalletRepository) FindByIdForUpdate(ctx context.Context, id string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDefaultByCustomerXid provides a mock function with given fields: ctx, xid
+func (_m *WalletRepository) FindDefaultByCustomerXid(ctx context.Context, xid string) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 *wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*wallet.Wallet, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *wallet.Wallet); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatistics provides a mock function with given fields: ctx
+func (_m *WalletRepository) GetStatistics(ctx context.Context) ([]*wallet.Statistics, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*wallet.Statistics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*wallet.Statistics, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*wallet.Statistics); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Statistics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: ctx, data
 func (_m *WalletRepository) Insert(ctx context.Context, data *wallet.Wallet) error {
 	ret := _m.Called(ctx, data)
@@ -80,6 +158,32 @@ func (_m *WalletRepository) Insert(ctx context.Context, data *wallet.Wallet) err
 	return r0
 }
 
+// Search provides a mock function with given fields: ctx, params
+func (_m *WalletRepository) Search(ctx context.Context, params *wallet.SearchWalletsParams) ([]*wallet.Wallet, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 []*wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.SearchWalletsParams) ([]*wallet.Wallet, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.SearchWalletsParams) []*wallet.Wallet); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.SearchWalletsParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, data
 func (_m *WalletRepository) Update(ctx context.Context, data *wallet.Wallet) error {
 	ret := _m.Called(ctx, data)
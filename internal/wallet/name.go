@@ -0,0 +1,30 @@
+package wallet
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Trim the name and fall back to DEFAULT_NAME if it's empty
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return DEFAULT_NAME, nil
+	}
+	if utf8.RuneCountInString(name) > MAX_NAME_LENGTH {
+		return "", ErrWalletNameTooLong
+	}
+
+	return name, nil
+}
+
+// Names are compared case insensitively, so "Savings" and "savings" can't be owned by the same client
+func isNameTaken(wallets []*Wallet, name, exceptId string) bool {
+	for _, w := range wallets {
+		if w.Id != exceptId && strings.EqualFold(w.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,4 +2,29 @@ package wallet
 
 type CreateWalletParams struct {
 	OwnedBy string `json:"owned_by"`
+	// Defaults to DEFAULT_NAME
+	Name string `json:"name"`
+	// Defaults to currency.DEFAULT_CODE
+	Currency string `json:"currency"`
+}
+
+type SearchWalletsParams struct {
+	// Match the id or the owner of the wallet by prefix
+	Query  string `json:"query"`
+	Status string `json:"status"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type TransitionParams struct {
+	WalletId string `json:"wallet_id"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+}
+
+type CloseWalletParams struct {
+	WalletId string `json:"wallet_id"`
+	Reason   string `json:"reason"`
+	// Empty the remaining balance instead of rejecting to close a wallet with balance
+	PayoutRemainder bool `json:"payout_remainder"`
 }
@@ -9,10 +9,18 @@ import (
 type Wallet struct {
 	Id         string     `gorm:"primaryKey;column:id"`
 	OwnedBy    string     `gorm:"column:owned_by"`
+	Name       string     `gorm:"column:name"`
+	IsDefault  bool       `gorm:"column:is_default"`
+	Currency   string     `gorm:"column:currency"`
 	Status     string     `gorm:"column:status"`
 	DisabledAt *time.Time `gorm:"column:disabled_at"`
 	EnabledAt  *time.Time `gorm:"column:enabled_at"`
+	FrozenAt   *time.Time `gorm:"column:frozen_at"`
+	ClosedAt   *time.Time `gorm:"column:closed_at"`
 	Balance    float64    `gorm:"column:balance"`
+
+	StatusReason    string     `gorm:"column:status_reason"`
+	StatusChangedAt *time.Time `gorm:"column:status_changed_at"`
 }
 
 func (Wallet) TableName() string {
@@ -27,10 +35,18 @@ func (Wallet) FromServiceModel(data *wallet.Wallet) *Wallet {
 	return &Wallet{
 		Id:         data.Id,
 		OwnedBy:    data.OwnedBy,
+		Name:       data.Name,
+		IsDefault:  data.IsDefault,
+		Currency:   data.Currency,
 		Status:     data.Status,
 		DisabledAt: data.DisabledAt,
 		EnabledAt:  data.EnabledAt,
+		FrozenAt:   data.FrozenAt,
+		ClosedAt:   data.ClosedAt,
 		Balance:    data.Balance,
+
+		StatusReason:    data.StatusReason,
+		StatusChangedAt: data.StatusChangedAt,
 	}
 }
 
@@ -38,9 +54,30 @@ func (w *Wallet) ToServiceModel() *wallet.Wallet {
 	return &wallet.Wallet{
 		Id:         w.Id,
 		OwnedBy:    w.OwnedBy,
+		Name:       w.Name,
+		IsDefault:  w.IsDefault,
+		Currency:   w.Currency,
 		Status:     w.Status,
 		DisabledAt: w.DisabledAt,
 		EnabledAt:  w.EnabledAt,
+		FrozenAt:   w.FrozenAt,
+		ClosedAt:   w.ClosedAt,
 		Balance:    w.Balance,
+
+		StatusReason:    w.StatusReason,
+		StatusChangedAt: w.StatusChangedAt,
 	}
 }
+
+func SliceToServiceModel(data []*Wallet) []*wallet.Wallet {
+	if data == nil {
+		return nil
+	}
+
+	wallets := []*wallet.Wallet{}
+	for _, w := range data {
+		wallets = append(wallets, w.ToServiceModel())
+	}
+
+	return wallets
+}
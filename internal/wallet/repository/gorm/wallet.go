@@ -2,12 +2,16 @@ package gorm
 
 import (
 	"context"
+	"strings"
 
 	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type WalletRepository struct {
 	db *gorm.DB
 }
@@ -31,7 +35,7 @@ func (r *WalletRepository) Insert(ctx context.Context, data *wallet.Wallet) erro
 func (r *WalletRepository) FindById(ctx context.Context, id string) (*wallet.Wallet, error) {
 	result := &Wallet{}
 
-	err := r.db.Where("id = ?", id).First(&result).Error
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&result).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -42,10 +46,10 @@ func (r *WalletRepository) FindById(ctx context.Context, id string) (*wallet.Wal
 	return result.ToServiceModel(), nil
 }
 
-func (r *WalletRepository) FindByCustomerXid(ctx context.Context, xid string) (*wallet.Wallet, error) {
+func (r *WalletRepository) FindByIdForUpdate(ctx context.Context, id string) (*wallet.Wallet, error) {
 	result := &Wallet{}
 
-	err := r.db.Where("owned_by = ?", xid).First(&result).Error
+	err := r.getGormClient(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&result).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -56,6 +60,31 @@ func (r *WalletRepository) FindByCustomerXid(ctx context.Context, xid string) (*
 	return result.ToServiceModel(), nil
 }
 
+func (r *WalletRepository) FindDefaultByCustomerXid(ctx context.Context, xid string) (*wallet.Wallet, error) {
+	result := &Wallet{}
+
+	err := r.getGormClient(ctx).Where("owned_by = ? AND is_default", xid).First(&result).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result.ToServiceModel(), nil
+}
+
+func (r *WalletRepository) FindAllByCustomerXid(ctx context.Context, xid string) ([]*wallet.Wallet, error) {
+	wallets := []*Wallet{}
+
+	err := r.getGormClient(ctx).Where("owned_by = ?", xid).Order("is_default DESC, name").Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(wallets), nil
+}
+
 func (r *WalletRepository) Update(ctx context.Context, data *wallet.Wallet) error {
 	result := Wallet{}.FromServiceModel(data)
 
@@ -71,11 +100,46 @@ func (r *WalletRepository) Update(ctx context.Context, data *wallet.Wallet) erro
 	return nil
 }
 
+func (r *WalletRepository) GetStatistics(ctx context.Context) ([]*wallet.Statistics, error) {
+	statistics := []*wallet.Statistics{}
+
+	err := r.db.WithContext(ctx).
+		Model(&Wallet{}).
+		Select("status, currency, COUNT(*) AS count, COALESCE(SUM(balance), 0) AS total_balance").
+		Group("status, currency").
+		Scan(&statistics).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+func (r *WalletRepository) Search(ctx context.Context, params *wallet.SearchWalletsParams) ([]*wallet.Wallet, error) {
+	wallets := []*Wallet{}
+
+	query := r.db.WithContext(ctx)
+	if params.Query != "" {
+		prefix := likeEscaper.Replace(params.Query) + "%"
+		query = query.Where("id LIKE ? OR owned_by LIKE ?", prefix, prefix)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	err := query.Order("owned_by, id").Limit(params.Limit).Offset(params.Offset).Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceToServiceModel(wallets), nil
+}
+
 func (r *WalletRepository) getGormClient(ctx context.Context) *gorm.DB {
 	db, err := gorm_manager.ExtractClientFromContext(ctx)
 	if err != nil {
-		return r.db
+		return r.db.WithContext(ctx)
 	}
 
-	return db
+	return db.WithContext(ctx)
 }
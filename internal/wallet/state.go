@@ -0,0 +1,77 @@
+package wallet
+
+import "time"
+
+// Statuses the wallet can move to from each status.
+// The client can only move the wallet between enabled and disabled, the other transitions are made by operators
+var transitions = map[string][]string{
+	STATUS_DISABLED: {STATUS_ENABLED, STATUS_CLOSED},
+	STATUS_ENABLED:  {STATUS_DISABLED, STATUS_FROZEN, STATUS_CLOSED},
+	STATUS_FROZEN:   {STATUS_ENABLED, STATUS_DISABLED, STATUS_CLOSED},
+	STATUS_CLOSED:   {},
+}
+
+// Return whether the wallet can move from the given status to the other
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// Return error if the wallet can't receive balance, only enabled and frozen wallets can
+func (w *Wallet) CanDeposit() error {
+	switch w.Status {
+	case STATUS_ENABLED, STATUS_FROZEN:
+		return nil
+	case STATUS_CLOSED:
+		return ErrWalletClosed
+	default:
+		return ErrWalletDisabled
+	}
+}
+
+// Return error if the balance of the wallet can't be withdrawn, only enabled wallets can
+func (w *Wallet) CanWithdraw() error {
+	switch w.Status {
+	case STATUS_ENABLED:
+		return nil
+	case STATUS_FROZEN:
+		return ErrWalletFrozen
+	case STATUS_CLOSED:
+		return ErrWalletClosed
+	default:
+		return ErrWalletDisabled
+	}
+}
+
+// Move the wallet to the status and stamp the time of the transition.
+// The transition has to be checked with CanTransition first
+func (w *Wallet) transition(to, reason string, now time.Time) {
+	switch to {
+	case STATUS_ENABLED:
+		w.EnabledAt = &now
+		w.DisabledAt = nil
+		w.FrozenAt = nil
+	case STATUS_DISABLED:
+		w.DisabledAt = &now
+		w.EnabledAt = nil
+		w.FrozenAt = nil
+	case STATUS_FROZEN:
+		w.FrozenAt = &now
+	case STATUS_CLOSED:
+		w.ClosedAt = &now
+	}
+
+	w.Status = to
+	w.StatusReason = reason
+	w.StatusChangedAt = &now
+}
@@ -0,0 +1,35 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	t.Run("should allow freezing enabled wallet only", func(t *testing.T) {
+		assert.True(t, wallet.CanTransition(wallet.STATUS_ENABLED, wallet.STATUS_FROZEN))
+		assert.False(t, wallet.CanTransition(wallet.STATUS_DISABLED, wallet.STATUS_FROZEN))
+	})
+
+	t.Run("should not allow leaving closed", func(t *testing.T) {
+		for _, status := range []string{wallet.STATUS_ENABLED, wallet.STATUS_DISABLED, wallet.STATUS_FROZEN} {
+			assert.False(t, wallet.CanTransition(wallet.STATUS_CLOSED, status))
+		}
+	})
+}
+
+func TestWallet_CanDeposit(t *testing.T) {
+	assert.Nil(t, (&wallet.Wallet{Status: wallet.STATUS_ENABLED}).CanDeposit())
+	assert.Nil(t, (&wallet.Wallet{Status: wallet.STATUS_FROZEN}).CanDeposit())
+	assert.Equal(t, wallet.ErrWalletDisabled, (&wallet.Wallet{Status: wallet.STATUS_DISABLED}).CanDeposit())
+	assert.Equal(t, wallet.ErrWalletClosed, (&wallet.Wallet{Status: wallet.STATUS_CLOSED}).CanDeposit())
+}
+
+func TestWallet_CanWithdraw(t *testing.T) {
+	assert.Nil(t, (&wallet.Wallet{Status: wallet.STATUS_ENABLED}).CanWithdraw())
+	assert.Equal(t, wallet.ErrWalletFrozen, (&wallet.Wallet{Status: wallet.STATUS_FROZEN}).CanWithdraw())
+	assert.Equal(t, wallet.ErrWalletDisabled, (&wallet.Wallet{Status: wallet.STATUS_DISABLED}).CanWithdraw())
+	assert.Equal(t, wallet.ErrWalletClosed, (&wallet.Wallet{Status: wallet.STATUS_CLOSED}).CanWithdraw())
+}
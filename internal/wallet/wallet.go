@@ -2,52 +2,126 @@ package wallet
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	"github.com/defryheryanto/mini-wallet/internal/currency"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
 	"github.com/google/uuid"
 )
 
 type Wallet struct {
-	Id         string     `json:"id"`
-	OwnedBy    string     `json:"owned_by"`
+	Id      string `json:"id"`
+	OwnedBy string `json:"owned_by"`
+	Name    string `json:"name"`
+	// The wallet used by the routes not addressing a wallet id, each client has exactly one
+	IsDefault bool `json:"is_default"`
+	// ISO 4217 code of the currency of the balance, fixed when the wallet is created
+	Currency   string     `json:"currency"`
 	Status     string     `json:"status"`
 	DisabledAt *time.Time `json:"disabled_at"`
 	EnabledAt  *time.Time `json:"enabled_at"`
+	FrozenAt   *time.Time `json:"frozen_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
 	Balance    float64    `json:"balance"`
+	// Reason of the last status transition made by an operator
+	StatusReason    string     `json:"status_reason"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+}
+
+// Statistics summarizes the wallets having the same status and currency
+type Statistics struct {
+	Status       string  `json:"status"`
+	Currency     string  `json:"currency"`
+	Count        int64   `json:"count"`
+	TotalBalance float64 `json:"total_balance"`
 }
 
 type WalletRepository interface {
 	Insert(ctx context.Context, data *Wallet) error
 	FindById(ctx context.Context, id string) (*Wallet, error)
-	FindByCustomerXid(ctx context.Context, xid string) (*Wallet, error)
+	// Find the wallet and lock it until the end of the database transaction of the context
+	FindByIdForUpdate(ctx context.Context, id string) (*Wallet, error)
+	FindDefaultByCustomerXid(ctx context.Context, xid string) (*Wallet, error)
+	FindAllByCustomerXid(ctx context.Context, xid string) ([]*Wallet, error)
 	Update(ctx context.Context, data *Wallet) error
+	GetStatistics(ctx context.Context) ([]*Statistics, error)
+	Search(ctx context.Context, params *SearchWalletsParams) ([]*Wallet, error)
 }
 
 type WalletIService interface {
-	Create(ctx context.Context, params *CreateWalletParams) error
-	UpdateStatus(ctx context.Context, customerXid string, isEnabled bool) (*Wallet, error)
+	Create(ctx context.Context, params *CreateWalletParams) (*Wallet, error)
+	UpdateStatus(ctx context.Context, customerXid, walletId string, isEnabled bool) (*Wallet, error)
 	GetWalletByXid(ctx context.Context, customerXid string) (*Wallet, error)
+	GetWallet(ctx context.Context, customerXid, walletId string) (*Wallet, error)
+	ListWallets(ctx context.Context, customerXid string) ([]*Wallet, error)
+	Rename(ctx context.Context, customerXid, walletId, name string) (*Wallet, error)
+	SetDefault(ctx context.Context, customerXid, walletId string) (*Wallet, error)
 	AddBalance(ctx context.Context, walletId string, amount float64) error
 	ValidateWallet(target *Wallet) error
+	ValidateDeposit(target *Wallet) error
 	DeductBalance(ctx context.Context, walletId string, amount float64) error
+	GetStatistics(ctx context.Context) ([]*Statistics, error)
+	GetWalletById(ctx context.Context, walletId string) (*Wallet, error)
+	SearchWallets(ctx context.Context, params *SearchWalletsParams) ([]*Wallet, error)
+	Transition(ctx context.Context, params *TransitionParams) (*Wallet, error)
+	Close(ctx context.Context, params *CloseWalletParams) (*Wallet, float64, error)
+	Lock(ctx context.Context, walletIds ...string) error
 }
 
 type WalletService struct {
-	repository WalletRepository
+	repository     WalletRepository
+	auditService   audit.AuditIService
+	webhookService webhook.WebhookIService
+	eventService   events.EventIService
+	storageManager manager.StorageManager
 }
 
-func NewWalletService(repository WalletRepository) *WalletService {
-	return &WalletService{repository}
+func NewWalletService(
+	repository WalletRepository,
+	auditService audit.AuditIService,
+	webhookService webhook.WebhookIService,
+	eventService events.EventIService,
+	storageManager manager.StorageManager,
+) *WalletService {
+	return &WalletService{repository, auditService, webhookService, eventService, storageManager}
 }
 
-func (s *WalletService) Create(ctx context.Context, params *CreateWalletParams) error {
+// Create a wallet for the client. The first wallet of the client becomes its default wallet
+func (s *WalletService) Create(ctx context.Context, params *CreateWalletParams) (*Wallet, error) {
 	if params.OwnedBy == "" {
-		return ErrOwnedByRequired
+		return nil, ErrOwnedByRequired
+	}
+	name, err := normalizeName(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	currencyCode := params.Currency
+	if currencyCode == "" {
+		currencyCode = currency.DEFAULT_CODE
+	}
+	if !currency.IsSupported(currencyCode) {
+		return nil, currency.ErrUnsupportedCurrency
+	}
+
+	ownedWallets, err := s.repository.FindAllByCustomerXid(ctx, params.OwnedBy)
+	if err != nil {
+		return nil, err
+	}
+	if len(ownedWallets) >= MAX_WALLETS_PER_CLIENT {
+		return nil, ErrWalletLimitReached
+	}
+	if isNameTaken(ownedWallets, name, "") {
+		return nil, ErrWalletNameTaken
 	}
 
 	uuidRandom, err := uuid.NewRandom()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var randomId string
@@ -55,60 +129,90 @@ func (s *WalletService) Create(ctx context.Context, params *CreateWalletParams)
 		randomId = uuidRandom.String()
 		existingWallet, err := s.repository.FindById(ctx, randomId)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if existingWallet == nil {
 			break
 		}
 	}
 
-	err = s.repository.Insert(ctx, &Wallet{
+	createdWallet := &Wallet{
 		Id:         randomId,
 		OwnedBy:    params.OwnedBy,
+		Name:       name,
+		IsDefault:  len(ownedWallets) == 0,
+		Currency:   currencyCode,
 		Status:     STATUS_DISABLED,
 		DisabledAt: nil,
 		EnabledAt:  nil,
 		Balance:    0,
+	}
+
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := s.repository.Insert(ctx, createdWallet)
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, &audit.Event{
+			Action:     AUDIT_ACTION_CREATED,
+			TargetType: audit.TARGET_WALLET,
+			TargetId:   createdWallet.Id,
+			After:      audit.Snapshot(createdWallet),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.eventService.Record(ctx, events.WalletCreated{
+			WalletId:  createdWallet.Id,
+			OwnedBy:   createdWallet.OwnedBy,
+			Name:      createdWallet.Name,
+			Currency:  createdWallet.Currency,
+			IsDefault: createdWallet.IsDefault,
+		})
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return createdWallet, nil
 }
 
-func (s *WalletService) UpdateStatus(ctx context.Context, customerXid string, isEnabled bool) (*Wallet, error) {
-	currentWallet, err := s.repository.FindByCustomerXid(ctx, customerXid)
+// Enable or disable the given wallet of the client, or its default wallet if the wallet id is empty.
+// Frozen and closed wallets can only be moved by operators
+func (s *WalletService) UpdateStatus(ctx context.Context, customerXid, walletId string, isEnabled bool) (*Wallet, error) {
+	currentWallet, err := s.findOwned(ctx, customerXid, walletId)
 	if err != nil {
 		return nil, err
 	}
-	if currentWallet == nil {
-		return nil, ErrWalletNotFound
-	}
 
-	now := time.Now()
+	status := STATUS_DISABLED
 	if isEnabled {
-		if currentWallet.Status == STATUS_ENABLED {
-			return nil, ErrWalletAlreadyEnabled
+		status = STATUS_ENABLED
+	}
+	_, err = s.modify(ctx, currentWallet.Id, AUDIT_ACTION_STATUS_UPDATED, "", func(target *Wallet) error {
+		if target.Status == STATUS_FROZEN {
+			return ErrWalletFrozen
 		}
-		currentWallet.DisabledAt = nil
-		currentWallet.EnabledAt = &now
-		currentWallet.Status = STATUS_ENABLED
-	} else {
-		if currentWallet.Status == STATUS_DISABLED {
-			return nil, ErrWalletAlreadyDisabled
+		if target.Status == STATUS_CLOSED {
+			return ErrWalletClosed
+		}
+		if target.Status == status {
+			if isEnabled {
+				return ErrWalletAlreadyEnabled
+			}
+			return ErrWalletAlreadyDisabled
 		}
-		currentWallet.DisabledAt = &now
-		currentWallet.EnabledAt = nil
-		currentWallet.Status = STATUS_DISABLED
-	}
 
-	err = s.repository.Update(ctx, currentWallet)
+		target.transition(status, "", time.Now())
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	currentWallet, err = s.repository.FindByCustomerXid(ctx, customerXid)
+	currentWallet, err = s.repository.FindById(ctx, currentWallet.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -116,73 +220,420 @@ func (s *WalletService) UpdateStatus(ctx context.Context, customerXid string, is
 	return currentWallet, nil
 }
 
+// Return the default wallet of the client
 func (s *WalletService) GetWalletByXid(ctx context.Context, customerXid string) (*Wallet, error) {
-	currentWallet, err := s.repository.FindByCustomerXid(ctx, customerXid)
+	return s.GetWallet(ctx, customerXid, "")
+}
+
+// Return the given wallet of the client, or its default wallet if the wallet id is empty.
+//
+// Return error if the wallet is disabled or closed
+func (s *WalletService) GetWallet(ctx context.Context, customerXid, walletId string) (*Wallet, error) {
+	currentWallet, err := s.findOwned(ctx, customerXid, walletId)
 	if err != nil {
 		return nil, err
 	}
-	if currentWallet == nil {
-		return nil, ErrWalletNotFound
+	if currentWallet.Status == STATUS_CLOSED {
+		return nil, ErrWalletClosed
 	}
-	if currentWallet.Status != STATUS_ENABLED {
+	if currentWallet.Status != STATUS_ENABLED && currentWallet.Status != STATUS_FROZEN {
 		return nil, ErrWalletDisabled
 	}
 
 	return currentWallet, nil
 }
 
-func (s *WalletService) AddBalance(ctx context.Context, walletId string, amount float64) error {
-	// TODO: This process is sensitive to racing condition
-	// TODO: Implement redis lock here to avoid it
-	targetWallet, err := s.repository.FindById(ctx, walletId)
+// Return every wallet of the client regardless of its status, the default wallet first
+func (s *WalletService) ListWallets(ctx context.Context, customerXid string) ([]*Wallet, error) {
+	wallets, err := s.repository.FindAllByCustomerXid(ctx, customerXid)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err = s.ValidateWallet(targetWallet); err != nil {
-		return err
+
+	return wallets, nil
+}
+
+func (s *WalletService) Rename(ctx context.Context, customerXid, walletId, name string) (*Wallet, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptyWalletName
+	}
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	targetWallet.Balance += amount
+	targetWallet, err := s.findOwned(ctx, customerXid, walletId)
+	if err != nil {
+		return nil, err
+	}
+	if targetWallet.Status == STATUS_CLOSED {
+		return nil, ErrWalletClosed
+	}
+	if targetWallet.Name == name {
+		return targetWallet, nil
+	}
+
+	ownedWallets, err := s.repository.FindAllByCustomerXid(ctx, customerXid)
+	if err != nil {
+		return nil, err
+	}
+	if isNameTaken(ownedWallets, name, targetWallet.Id) {
+		return nil, ErrWalletNameTaken
+	}
+
+	return s.modify(ctx, targetWallet.Id, AUDIT_ACTION_RENAMED, "", func(target *Wallet) error {
+		if target.Status == STATUS_CLOSED {
+			return ErrWalletClosed
+		}
+
+		target.Name = name
+		return nil
+	})
+}
 
-	err = s.repository.Update(ctx, targetWallet)
+// Make the wallet the default wallet of the client in place of the current one
+func (s *WalletService) SetDefault(ctx context.Context, customerXid, walletId string) (*Wallet, error) {
+	targetWallet, err := s.findOwned(ctx, customerXid, walletId)
 	if err != nil {
+		return nil, err
+	}
+	if targetWallet.Status == STATUS_CLOSED {
+		return nil, ErrWalletClosed
+	}
+	if targetWallet.IsDefault {
+		return targetWallet, nil
+	}
+
+	currentDefault, err := s.repository.FindDefaultByCustomerXid(ctx, customerXid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		// The previous default is unset first, a client can't have two default wallets
+		if currentDefault != nil {
+			_, err := s.modify(ctx, currentDefault.Id, AUDIT_ACTION_DEFAULT_CHANGED, "", func(target *Wallet) error {
+				target.IsDefault = false
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		targetWallet, err = s.modify(ctx, targetWallet.Id, AUDIT_ACTION_DEFAULT_CHANGED, "", func(target *Wallet) error {
+			if target.Status == STATUS_CLOSED {
+				return ErrWalletClosed
+			}
+
+			target.IsDefault = true
+			return nil
+		})
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return targetWallet, nil
 }
 
+// Add the amount to the balance of the wallet locked until the end of the database transaction of the context
+func (s *WalletService) AddBalance(ctx context.Context, walletId string, amount float64) error {
+	_, err := s.modify(ctx, walletId, AUDIT_ACTION_BALANCE_ADDED, "", func(target *Wallet) error {
+		if err := s.ValidateDeposit(target); err != nil {
+			return err
+		}
+
+		target.Balance += amount
+		return nil
+	})
+
+	return err
+}
+
+// Return error if the balance of the wallet can't be withdrawn
 func (s *WalletService) ValidateWallet(target *Wallet) error {
 	if target == nil {
 		return ErrWalletNotFound
 	}
-	if target.Status == STATUS_DISABLED {
-		return ErrWalletDisabled
+
+	return target.CanWithdraw()
+}
+
+// Return error if the wallet can't receive balance
+func (s *WalletService) ValidateDeposit(target *Wallet) error {
+	if target == nil {
+		return ErrWalletNotFound
 	}
 
-	return nil
+	return target.CanDeposit()
 }
 
+// Deduct the amount from the balance of the wallet locked until the end of the database transaction of the context,
+// so concurrent debits are checked against the balance left by each other
 func (s *WalletService) DeductBalance(ctx context.Context, walletId string, amount float64) error {
-	// TODO: This process is sensitive to racing condition
-	// TODO: Implement redis lock here to avoid it
+	_, err := s.modify(ctx, walletId, AUDIT_ACTION_BALANCE_DEDUCTED, "", func(target *Wallet) error {
+		if err := s.ValidateWallet(target); err != nil {
+			return err
+		}
+		if target.Balance < amount {
+			return ErrInsufficientBalance
+		}
+
+		target.Balance -= amount
+		return nil
+	})
+
+	return err
+}
+
+func (s *WalletService) GetStatistics(ctx context.Context) ([]*Statistics, error) {
+	statistics, err := s.repository.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+// Return the wallet regardless of its status
+func (s *WalletService) GetWalletById(ctx context.Context, walletId string) (*Wallet, error) {
 	targetWallet, err := s.repository.FindById(ctx, walletId)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := s.ValidateWallet(targetWallet); err != nil {
-		return err
+	if targetWallet == nil {
+		return nil, ErrWalletNotFound
 	}
 
-	if targetWallet.Balance < amount {
-		return ErrInsufficientBalance
+	return targetWallet, nil
+}
+
+func (s *WalletService) SearchWallets(ctx context.Context, params *SearchWalletsParams) ([]*Wallet, error) {
+	if params.Limit <= 0 {
+		params.Limit = DEFAULT_SEARCH_LIMIT
+	}
+	if params.Limit > MAX_SEARCH_LIMIT {
+		params.Limit = MAX_SEARCH_LIMIT
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
 	}
 
-	targetWallet.Balance -= amount
-	err = s.repository.Update(ctx, targetWallet)
+	wallets, err := s.repository.Search(ctx, params)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// Move the wallet to the given status if the transition is allowed, used by operators.
+// Closing the wallet requires a zero balance, use Close to pay out the remainder
+func (s *WalletService) Transition(ctx context.Context, params *TransitionParams) (*Wallet, error) {
+	if params.Status == STATUS_CLOSED {
+		targetWallet, _, err := s.Close(ctx, &CloseWalletParams{
+			WalletId: params.WalletId,
+			Reason:   params.Reason,
+		})
+		return targetWallet, err
+	}
+
+	err := validateTransitionParams(params.Status, params.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modify(ctx, params.WalletId, AUDIT_ACTION_STATUS_UPDATED, params.Reason, func(target *Wallet) error {
+		err := validateTransition(target, params.Status)
+		if err != nil {
+			return err
+		}
+
+		target.transition(params.Status, params.Reason, time.Now())
+		return nil
+	})
+}
+
+// Close the wallet for good. The balance has to be zero unless the remainder is paid out,
+// in which case the balance is emptied and the paid out amount is returned so the payout can be recorded
+func (s *WalletService) Close(ctx context.Context, params *CloseWalletParams) (*Wallet, float64, error) {
+	err := validateTransitionParams(STATUS_CLOSED, params.Reason)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var payout float64
+	targetWallet, err := s.modify(ctx, params.WalletId, AUDIT_ACTION_STATUS_UPDATED, params.Reason, func(target *Wallet) error {
+		err := validateTransition(target, STATUS_CLOSED)
+		if err != nil {
+			return err
+		}
+		if target.Balance < 0 || (target.Balance > 0 && !params.PayoutRemainder) {
+			return ErrWalletBalanceNotZero
+		}
+
+		payout = target.Balance
+		target.Balance = 0
+		target.transition(STATUS_CLOSED, params.Reason, time.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return targetWallet, payout, nil
+}
+
+func validateTransitionParams(status, reason string) error {
+	if !IsValidStatus(status) {
+		return ErrInvalidStatus
+	}
+	if reason == "" {
+		return ErrEmptyReason
+	}
+
+	return nil
+}
+
+func validateTransition(target *Wallet, status string) error {
+	if target.Status == status {
+		return ErrWalletAlreadyInStatus
+	}
+	if !CanTransition(target.Status, status) {
+		return ErrTransitionNotAllowed(target.Status, status)
+	}
+
+	return nil
+}
+
+// Return the given wallet if it is owned by the client, or the default wallet of the client if the wallet id is empty
+func (s *WalletService) findOwned(ctx context.Context, customerXid, walletId string) (*Wallet, error) {
+	var targetWallet *Wallet
+	var err error
+	if walletId == "" {
+		targetWallet, err = s.repository.FindDefaultByCustomerXid(ctx, customerXid)
+	} else {
+		targetWallet, err = s.repository.FindById(ctx, walletId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if targetWallet == nil || targetWallet.OwnedBy != customerXid {
+		return nil, ErrWalletNotFound
+	}
+
+	return targetWallet, nil
+}
+
+// Lock the wallets until the end of the database transaction of the context, so the caller can validate
+// and change them without a concurrent change in between. The wallets are locked in the order of their ids,
+// so two transactions locking the same wallets can't deadlock
+func (s *WalletService) Lock(ctx context.Context, walletIds ...string) error {
+	ids := append([]string{}, walletIds...)
+	sort.Strings(ids)
+	for _, id := range ids {
+		target, err := s.repository.FindByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrWalletNotFound
+		}
 	}
 
 	return nil
 }
+
+// Lock the wallet until the end of the database transaction, apply the change to the locked row and store it.
+// Every change of a wallet goes through here, so concurrent changes are applied one after another
+// instead of overwriting each other with a stale row
+func (s *WalletService) modify(ctx context.Context, walletId, action, reason string, change func(target *Wallet) error) (*Wallet, error) {
+	var targetWallet *Wallet
+	err := s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		targetWallet, err = s.repository.FindByIdForUpdate(ctx, walletId)
+		if err != nil {
+			return err
+		}
+		if targetWallet == nil {
+			return ErrWalletNotFound
+		}
+
+		before := *targetWallet
+		err = change(targetWallet)
+		if err != nil {
+			return err
+		}
+
+		return s.update(ctx, &before, targetWallet, action, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return targetWallet, nil
+}
+
+// Update the wallet and record the change to the audit log in one database transaction.
+// A status change is also published to the webhooks of the owner
+func (s *WalletService) update(ctx context.Context, before, after *Wallet, action, reason string) error {
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := s.repository.Update(ctx, after)
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, &audit.Event{
+			Action:     action,
+			TargetType: audit.TARGET_WALLET,
+			TargetId:   after.Id,
+			Reason:     reason,
+			Before:     audit.Snapshot(before),
+			After:      audit.Snapshot(after),
+		})
+		if err != nil {
+			return err
+		}
+		if before.Status == after.Status {
+			return nil
+		}
+
+		err = s.webhookService.Publish(ctx, &webhook.PublishParams{
+			ClientXid: after.OwnedBy,
+			Type:      webhook.EVENT_WALLET_STATUS_CHANGED,
+			Data: map[string]interface{}{
+				"wallet":          after,
+				"previous_status": before.Status,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.eventService.Record(ctx, statusEvent(before, after, reason))
+	})
+}
+
+// Return the domain event of the status transition of the wallet
+func statusEvent(before, after *Wallet, reason string) events.Event {
+	status := events.WalletStatus{
+		WalletId:       after.Id,
+		OwnedBy:        after.OwnedBy,
+		PreviousStatus: before.Status,
+		Reason:         reason,
+	}
+
+	switch after.Status {
+	case STATUS_ENABLED:
+		return events.WalletEnabled{WalletStatus: status}
+	case STATUS_FROZEN:
+		return events.WalletFrozen{WalletStatus: status}
+	case STATUS_CLOSED:
+		return events.WalletClosed{WalletStatus: status, Payout: before.Balance}
+	default:
+		return events.WalletDisabled{WalletStatus: status}
+	}
+}
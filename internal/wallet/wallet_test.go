@@ -3,31 +3,112 @@ package wallet_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/defryheryanto/mini-wallet/internal/audit"
+	audit_mock "github.com/defryheryanto/mini-wallet/internal/audit/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/events"
+	event_mock "github.com/defryheryanto/mini-wallet/internal/events/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
 	"github.com/defryheryanto/mini-wallet/internal/wallet"
 	"github.com/defryheryanto/mini-wallet/internal/wallet/mocks"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	webhook_mock "github.com/defryheryanto/mini-wallet/internal/webhook/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// Return the audit service accepting any event, for the tests not asserting the recorded events
+func newAuditService(t *testing.T) *audit_mock.AuditIService {
+	auditService := audit_mock.NewAuditIService(t)
+	auditService.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return auditService
+}
+
+// Return the webhook service accepting any event, for the tests not asserting the published events
+func newWebhookService(t *testing.T) *webhook_mock.WebhookIService {
+	webhookService := webhook_mock.NewWebhookIService(t)
+	webhookService.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return webhookService
+}
+
+// Return the event service accepting any event, for the tests not asserting the recorded events
+func newEventService(t *testing.T) *event_mock.EventIService {
+	eventService := event_mock.NewEventIService(t)
+	eventService.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return eventService
+}
+
 func TestWalletService_Create(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 
 	t.Run("should return error when params invalid", func(t *testing.T) {
-		service := wallet.NewWalletService(mocks.NewWalletRepository(t))
+		service := wallet.NewWalletService(mocks.NewWalletRepository(t), newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
-		err := service.Create(context.TODO(), &wallet.CreateWalletParams{})
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{})
 		assert.Equal(t, wallet.ErrOwnedByRequired, err)
 	})
 
+	t.Run("should return error when name is too long", func(t *testing.T) {
+		service := wallet.NewWalletService(mocks.NewWalletRepository(t), newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+			OwnedBy: "test",
+			Name:    strings.Repeat("a", wallet.MAX_NAME_LENGTH+1),
+		})
+		assert.Equal(t, wallet.ErrWalletNameTooLong, err)
+	})
+
+	t.Run("should return error when failed to find the wallets of the owner", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return(nil, mockedErr)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+			OwnedBy: "test",
+		})
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should return error when the owner reached the wallet limit", func(t *testing.T) {
+		ownedWallets := []*wallet.Wallet{}
+		for i := 0; i < wallet.MAX_WALLETS_PER_CLIENT; i++ {
+			ownedWallets = append(ownedWallets, &wallet.Wallet{Id: fmt.Sprint(i), Name: fmt.Sprint(i)})
+		}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return(ownedWallets, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+			OwnedBy: "test",
+		})
+		assert.Equal(t, wallet.ErrWalletLimitReached, err)
+	})
+
+	t.Run("should return error when the name is used by another wallet of the owner", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return([]*wallet.Wallet{{Id: "main-id", Name: "Savings"}}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+			OwnedBy: "test",
+			Name:    " savings ",
+		})
+		assert.Equal(t, wallet.ErrWalletNameTaken, err)
+	})
+
 	t.Run("should return error when failed to get wallet by id", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return([]*wallet.Wallet{}, nil)
 		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, mockedErr)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
-		err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
 			OwnedBy: "test",
 		})
 		assert.Equal(t, mockedErr, err)
@@ -35,30 +116,51 @@ func TestWalletService_Create(t *testing.T) {
 
 	t.Run("should return error when failed to insert", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return([]*wallet.Wallet{}, nil)
 		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, nil)
 		repository.On("Insert", mock.Anything, mock.Anything).Return(mockedErr)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
-		err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+		_, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
 			OwnedBy: "test",
 		})
 		assert.Equal(t, mockedErr, err)
 	})
 
-	t.Run("should not return error if operation success", func(t *testing.T) {
+	t.Run("should create the first wallet of the owner as default wallet", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return([]*wallet.Wallet{}, nil)
 		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, nil)
 		repository.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 			insertParams, ok := args.Get(1).(*wallet.Wallet)
 			assert.True(t, ok, "second argument of insert should be *Wallet")
 			assert.Equal(t, "test", insertParams.OwnedBy)
+			assert.Equal(t, wallet.DEFAULT_NAME, insertParams.Name)
+			assert.True(t, insertParams.IsDefault)
 		}).Return(nil)
 
-		service := wallet.NewWalletService(repository)
-		err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		createdWallet, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
 			OwnedBy: "test",
 		})
 		assert.Nil(t, err)
+		assert.Equal(t, wallet.STATUS_DISABLED, createdWallet.Status)
+	})
+
+	t.Run("should not make the other wallets of the owner default", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindAllByCustomerXid", mock.Anything, "test").Return([]*wallet.Wallet{{Id: "main-id", Name: wallet.DEFAULT_NAME, IsDefault: true}}, nil)
+		repository.On("FindById", mock.Anything, mock.Anything).Return(nil, nil)
+		repository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		createdWallet, err := service.Create(context.TODO(), &wallet.CreateWalletParams{
+			OwnedBy: "test",
+			Name:    "savings",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "savings", createdWallet.Name)
+		assert.False(t, createdWallet.IsDefault)
 	})
 }
 
@@ -67,62 +169,83 @@ func TestWalletService_UpdateStatus(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 	t.Run("should return error if failed to find wallet by customer xid", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(nil, mockedErr)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(nil, mockedErr)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, true)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", true)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return error if wallet not found", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(nil, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(nil, nil)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, true)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", true)
 		assert.Equal(t, wallet.ErrWalletNotFound, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return error if isEnabled true and wallet already enabled", func(t *testing.T) {
+		currentWallet := &wallet.Wallet{
+			OwnedBy: customerXid,
+			Status:  wallet.STATUS_ENABLED,
+		}
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
-			Status: wallet.STATUS_ENABLED,
-		}, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(currentWallet, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Return(currentWallet, nil)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, true)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", true)
 		assert.Equal(t, wallet.ErrWalletAlreadyEnabled, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return error if isEnabled false and wallet already disabled", func(t *testing.T) {
+		currentWallet := &wallet.Wallet{
+			OwnedBy: customerXid,
+			Status:  wallet.STATUS_DISABLED,
+		}
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
-			Status: wallet.STATUS_DISABLED,
-		}, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(currentWallet, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Return(currentWallet, nil)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, false)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", false)
 		assert.Equal(t, wallet.ErrWalletAlreadyDisabled, err)
 		assert.Nil(t, result)
 	})
+	t.Run("should check the status of the locked wallet", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Status: wallet.STATUS_ENABLED}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Status: wallet.STATUS_FROZEN}, nil)
+
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", false)
+		assert.Equal(t, wallet.ErrWalletFrozen, err)
+		assert.Nil(t, result)
+	})
 	t.Run("should return error if update wallet failed", func(t *testing.T) {
+		currentWallet := &wallet.Wallet{OwnedBy: customerXid}
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{}, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(currentWallet, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Return(currentWallet, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, true)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", true)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return wallet data if enable wallet success", func(t *testing.T) {
 		now := time.Now()
-		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
+		currentWallet := &wallet.Wallet{
+			OwnedBy:    customerXid,
 			Status:     wallet.STATUS_DISABLED,
 			DisabledAt: &now,
 			EnabledAt:  nil,
-		}, nil)
+		}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(currentWallet, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Return(currentWallet, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 			updateParams, ok := args.Get(1).(*wallet.Wallet)
 			assert.True(t, ok, "second argument of update should be *Wallet")
@@ -130,20 +253,24 @@ func TestWalletService_UpdateStatus(t *testing.T) {
 			assert.NotNil(t, updateParams.EnabledAt)
 			assert.Equal(t, wallet.STATUS_ENABLED, updateParams.Status)
 		}).Return(nil)
+		repository.On("FindById", mock.Anything, mock.Anything).Return(&wallet.Wallet{OwnedBy: customerXid}, nil)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, true)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", true)
 		assert.NotNil(t, result)
 		assert.Nil(t, err)
 	})
 	t.Run("should return wallet data if disable wallet success", func(t *testing.T) {
 		now := time.Now()
-		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
+		currentWallet := &wallet.Wallet{
+			OwnedBy:    customerXid,
 			Status:     wallet.STATUS_ENABLED,
 			DisabledAt: nil,
 			EnabledAt:  &now,
-		}, nil)
+		}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(currentWallet, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Return(currentWallet, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 			updateParams, ok := args.Get(1).(*wallet.Wallet)
 			assert.True(t, ok, "second argument of update should be *Wallet")
@@ -151,9 +278,10 @@ func TestWalletService_UpdateStatus(t *testing.T) {
 			assert.NotNil(t, updateParams.DisabledAt)
 			assert.Equal(t, wallet.STATUS_DISABLED, updateParams.Status)
 		}).Return(nil)
+		repository.On("FindById", mock.Anything, mock.Anything).Return(&wallet.Wallet{OwnedBy: customerXid}, nil)
 
-		service := wallet.NewWalletService(repository)
-		result, err := service.UpdateStatus(context.TODO(), customerXid, false)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.UpdateStatus(context.TODO(), customerXid, "", false)
 		assert.NotNil(t, result)
 		assert.Nil(t, err)
 	})
@@ -164,29 +292,30 @@ func TestWalletService_GetWalletByXid(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 	t.Run("should return error if failed to find wallet by customer xid", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(nil, mockedErr)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(nil, mockedErr)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		result, err := service.GetWalletByXid(context.TODO(), customerXid)
 		assert.Equal(t, mockedErr, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return error if wallet not found", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(nil, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(nil, nil)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		result, err := service.GetWalletByXid(context.TODO(), customerXid)
 		assert.Equal(t, wallet.ErrWalletNotFound, err)
 		assert.Nil(t, result)
 	})
 	t.Run("should return error if wallet is disabled", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
-			Status: wallet.STATUS_DISABLED,
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{
+			OwnedBy: customerXid,
+			Status:  wallet.STATUS_DISABLED,
 		}, nil)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		result, err := service.GetWalletByXid(context.TODO(), customerXid)
 		assert.Equal(t, wallet.ErrWalletDisabled, err)
 		assert.Nil(t, result)
@@ -195,15 +324,15 @@ func TestWalletService_GetWalletByXid(t *testing.T) {
 		now := time.Now()
 		targetWallet := &wallet.Wallet{
 			Id:        "test",
-			OwnedBy:   "test-owned",
+			OwnedBy:   customerXid,
 			Status:    wallet.STATUS_ENABLED,
 			EnabledAt: &now,
 			Balance:   0,
 		}
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindByCustomerXid", mock.Anything, customerXid).Return(targetWallet, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(targetWallet, nil)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		result, err := service.GetWalletByXid(context.TODO(), customerXid)
 		assert.Nil(t, err)
 		assert.Equal(t, targetWallet.Id, result.Id)
@@ -214,6 +343,130 @@ func TestWalletService_GetWalletByXid(t *testing.T) {
 	})
 }
 
+func TestWalletService_GetWallet(t *testing.T) {
+	customerXid := "test"
+
+	t.Run("should return error if the wallet is owned by another client", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(&wallet.Wallet{
+			Id:      "wallet-id",
+			OwnedBy: "another",
+			Status:  wallet.STATUS_ENABLED,
+		}, nil)
+
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.GetWallet(context.TODO(), customerXid, "wallet-id")
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return the given wallet of the client", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(&wallet.Wallet{
+			Id:      "wallet-id",
+			OwnedBy: customerXid,
+			Status:  wallet.STATUS_ENABLED,
+		}, nil)
+
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		result, err := service.GetWallet(context.TODO(), customerXid, "wallet-id")
+		assert.Nil(t, err)
+		assert.Equal(t, "wallet-id", result.Id)
+	})
+}
+
+func TestWalletService_Rename(t *testing.T) {
+	customerXid := "test"
+
+	t.Run("should return error if the name is empty", func(t *testing.T) {
+		service := wallet.NewWalletService(mocks.NewWalletRepository(t), newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Rename(context.TODO(), customerXid, "wallet-id", "  ")
+		assert.Equal(t, wallet.ErrEmptyWalletName, err)
+	})
+
+	t.Run("should return error if the wallet is closed", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(&wallet.Wallet{
+			Id:      "wallet-id",
+			OwnedBy: customerXid,
+			Status:  wallet.STATUS_CLOSED,
+		}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Rename(context.TODO(), customerXid, "wallet-id", "savings")
+		assert.Equal(t, wallet.ErrWalletClosed, err)
+	})
+
+	t.Run("should return error if the name is used by another wallet of the client", func(t *testing.T) {
+		targetWallet := &wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Name: "spending", Status: wallet.STATUS_ENABLED}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(targetWallet, nil)
+		repository.On("FindAllByCustomerXid", mock.Anything, customerXid).Return([]*wallet.Wallet{
+			targetWallet,
+			{Id: "other-id", OwnedBy: customerXid, Name: "savings"},
+		}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Rename(context.TODO(), customerXid, "wallet-id", "Savings")
+		assert.Equal(t, wallet.ErrWalletNameTaken, err)
+	})
+
+	t.Run("should rename the wallet", func(t *testing.T) {
+		targetWallet := &wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Name: "spending", Status: wallet.STATUS_ENABLED}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(targetWallet, nil)
+		repository.On("FindAllByCustomerXid", mock.Anything, customerXid).Return([]*wallet.Wallet{targetWallet}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(targetWallet, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*wallet.Wallet)
+			assert.True(t, ok, "second argument of update should be *Wallet")
+			assert.Equal(t, "Spending", updateParams.Name)
+		}).Return(nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		result, err := service.Rename(context.TODO(), customerXid, "wallet-id", "Spending")
+		assert.Nil(t, err)
+		assert.Equal(t, "Spending", result.Name)
+	})
+}
+
+func TestWalletService_SetDefault(t *testing.T) {
+	customerXid := "test"
+
+	t.Run("should return error if the wallet is owned by another client", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: "another"}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.SetDefault(context.TODO(), customerXid, "wallet-id")
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+	})
+
+	t.Run("should unset the current default wallet before setting the new one", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindById", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Status: wallet.STATUS_ENABLED}, nil)
+		repository.On("FindDefaultByCustomerXid", mock.Anything, customerXid).Return(&wallet.Wallet{Id: "main-id", OwnedBy: customerXid, IsDefault: true}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, "main-id").Return(&wallet.Wallet{Id: "main-id", OwnedBy: customerXid, IsDefault: true}, nil)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: customerXid, Status: wallet.STATUS_ENABLED}, nil)
+
+		updated := []*wallet.Wallet{}
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = append(updated, args.Get(1).(*wallet.Wallet))
+		}).Return(nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		result, err := service.SetDefault(context.TODO(), customerXid, "wallet-id")
+		assert.Nil(t, err)
+		assert.True(t, result.IsDefault)
+		assert.Len(t, updated, 2)
+		assert.Equal(t, "main-id", updated[0].Id)
+		assert.False(t, updated[0].IsDefault)
+		assert.Equal(t, "wallet-id", updated[1].Id)
+		assert.True(t, updated[1].IsDefault)
+	})
+}
+
 func TestWalletService_AddBalance(t *testing.T) {
 	mockedErr := fmt.Errorf("mocked")
 	walletId := "test-wallet-id"
@@ -221,39 +474,39 @@ func TestWalletService_AddBalance(t *testing.T) {
 
 	t.Run("should return error if failed to get wallet", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(nil, mockedErr)
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(nil, mockedErr)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		err := service.AddBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, mockedErr, err)
 	})
 
 	t.Run("should return error if wallet not active", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status: wallet.STATUS_DISABLED,
 		}, nil)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		err := service.AddBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, wallet.ErrWalletDisabled, err)
 	})
 
 	t.Run("should return error if failed to update balance", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status: wallet.STATUS_ENABLED,
 		}, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		err := service.AddBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, mockedErr, err)
 	})
 
 	t.Run("should update balance if operations success", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status:  wallet.STATUS_ENABLED,
 			Balance: 100_000,
 		}, nil)
@@ -263,7 +516,31 @@ func TestWalletService_AddBalance(t *testing.T) {
 			assert.Equal(t, float64(110_000), updateParams.Balance)
 		}).Return(nil)
 
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+		err := service.AddBalance(context.TODO(), walletId, amount)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should record the balance change with before and after snapshots", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
+			Id:      walletId,
+			Status:  wallet.STATUS_ENABLED,
+			Balance: 100_000,
+		}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		auditService := audit_mock.NewAuditIService(t)
+		auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event, ok := args.Get(1).(*audit.Event)
+			assert.True(t, ok, "params should be *Event")
+			assert.Equal(t, wallet.AUDIT_ACTION_BALANCE_ADDED, event.Action)
+			assert.Equal(t, walletId, event.TargetId)
+			assert.Contains(t, string(event.Before), `"balance":100000`)
+			assert.Contains(t, string(event.After), `"balance":110000`)
+		}).Return(nil)
+
+		service := wallet.NewWalletService(repository, auditService, newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 		err := service.AddBalance(context.TODO(), walletId, amount)
 		assert.Nil(t, err)
 	})
@@ -276,48 +553,48 @@ func TestWalletService_DeductBalance(t *testing.T) {
 
 	t.Run("should return error if failed to get wallet", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(nil, mockedErr)
-		service := wallet.NewWalletService(repository)
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(nil, mockedErr)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
 		err := service.DeductBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, mockedErr, err)
 	})
 	t.Run("should return error if wallet is not active", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status: wallet.STATUS_DISABLED,
 		}, nil)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
 		err := service.DeductBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, wallet.ErrWalletDisabled, err)
 	})
 	t.Run("should return error if balance insufficient", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status:  wallet.STATUS_ENABLED,
 			Balance: 14_999,
 		}, nil)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
 		err := service.DeductBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, wallet.ErrInsufficientBalance, err)
 	})
 	t.Run("should return error if failed to update wallet", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status:  wallet.STATUS_ENABLED,
 			Balance: 15_000,
 		}, nil)
 		repository.On("Update", mock.Anything, mock.Anything).Return(mockedErr)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
 		err := service.DeductBalance(context.TODO(), walletId, amount)
 		assert.Equal(t, mockedErr, err)
 	})
 	t.Run("should deduct wallet balance if operations success", func(t *testing.T) {
 		repository := mocks.NewWalletRepository(t)
-		repository.On("FindById", mock.Anything, walletId).Return(&wallet.Wallet{
+		repository.On("FindByIdForUpdate", mock.Anything, walletId).Return(&wallet.Wallet{
 			Status:  wallet.STATUS_ENABLED,
 			Balance: 15_000,
 		}, nil)
@@ -326,9 +603,198 @@ func TestWalletService_DeductBalance(t *testing.T) {
 			assert.True(t, ok, "params should be *Wallet")
 			assert.Equal(t, float64(0), updateParams.Balance)
 		}).Return(nil)
-		service := wallet.NewWalletService(repository)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
 
 		err := service.DeductBalance(context.TODO(), walletId, amount)
 		assert.Nil(t, err)
 	})
 }
+
+func TestWalletService_GetStatistics(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+
+	t.Run("should return error if failed to get statistics", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("GetStatistics", mock.Anything).Return(nil, mockedErr)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		result, err := service.GetStatistics(context.TODO())
+		assert.Equal(t, mockedErr, err)
+		assert.Nil(t, result)
+	})
+	t.Run("should return statistics if operation success", func(t *testing.T) {
+		statistics := []*wallet.Statistics{
+			{Status: wallet.STATUS_ENABLED, Count: 2, TotalBalance: 10_000},
+		}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("GetStatistics", mock.Anything).Return(statistics, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		result, err := service.GetStatistics(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, statistics, result)
+	})
+}
+
+func TestWalletService_Transition(t *testing.T) {
+	mockedErr := fmt.Errorf("mocked")
+
+	t.Run("should return error if status invalid", func(t *testing.T) {
+		service := wallet.NewWalletService(mocks.NewWalletRepository(t), newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: "unknown", Reason: "test"})
+		assert.Equal(t, wallet.ErrInvalidStatus, err)
+	})
+
+	t.Run("should return error if reason is empty", func(t *testing.T) {
+		service := wallet.NewWalletService(mocks.NewWalletRepository(t), newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN})
+		assert.Equal(t, wallet.ErrEmptyReason, err)
+	})
+
+	t.Run("should return error if failed to find wallet", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(nil, mockedErr)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "test"})
+		assert.Equal(t, mockedErr, err)
+	})
+
+	t.Run("should return error if wallet not found", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(nil, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "test"})
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+	})
+
+	t.Run("should return error if wallet already in the status", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_FROZEN}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "test"})
+		assert.Equal(t, wallet.ErrWalletAlreadyInStatus, err)
+	})
+
+	t.Run("should return error if transition not allowed", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_CLOSED}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_ENABLED, Reason: "test"})
+		assert.Equal(t, wallet.ErrTransitionNotAllowed(wallet.STATUS_CLOSED, wallet.STATUS_ENABLED), err)
+	})
+
+	t.Run("should freeze the wallet with the reason and time of the transition", func(t *testing.T) {
+		enabledAt := time.Now().Add(-time.Hour)
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", OwnedBy: "owner-xid", Status: wallet.STATUS_ENABLED, EnabledAt: &enabledAt}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams, ok := args.Get(1).(*wallet.Wallet)
+			assert.True(t, ok, "second argument of update should be *Wallet")
+			assert.Equal(t, wallet.STATUS_FROZEN, updateParams.Status)
+			assert.Equal(t, "fraud report", updateParams.StatusReason)
+			assert.NotNil(t, updateParams.FrozenAt)
+			assert.NotNil(t, updateParams.StatusChangedAt)
+			assert.Equal(t, &enabledAt, updateParams.EnabledAt)
+		}).Return(nil)
+
+		auditService := audit_mock.NewAuditIService(t)
+		auditService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*audit.Event)
+			assert.Equal(t, wallet.AUDIT_ACTION_STATUS_UPDATED, event.Action)
+			assert.Equal(t, "fraud report", event.Reason)
+		}).Return(nil)
+		webhookService := webhook_mock.NewWebhookIService(t)
+		webhookService.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			params := args.Get(1).(*webhook.PublishParams)
+			assert.Equal(t, "owner-xid", params.ClientXid)
+			assert.Equal(t, webhook.EVENT_WALLET_STATUS_CHANGED, params.Type)
+		}).Return(nil)
+		eventService := event_mock.NewEventIService(t)
+		eventService.On("Record", mock.Anything, events.WalletFrozen{WalletStatus: events.WalletStatus{
+			WalletId:       "wallet-id",
+			OwnedBy:        "owner-xid",
+			PreviousStatus: wallet.STATUS_ENABLED,
+			Reason:         "fraud report",
+		}}).Return(nil)
+		service := wallet.NewWalletService(repository, auditService, webhookService, eventService, &manager.MockStorageManager{})
+
+		updatedWallet, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_FROZEN, Reason: "fraud report"})
+		assert.Nil(t, err)
+		assert.Equal(t, wallet.STATUS_FROZEN, updatedWallet.Status)
+	})
+}
+
+func TestWalletService_Close(t *testing.T) {
+	t.Run("should return error if balance is not zero", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_ENABLED, Balance: 100}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		_, _, err := service.Close(context.TODO(), &wallet.CloseWalletParams{WalletId: "wallet-id", Reason: "requested"})
+		assert.Equal(t, wallet.ErrWalletBalanceNotZero, err)
+	})
+
+	t.Run("should empty the balance if remainder is paid out", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_FROZEN, Balance: 100}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updateParams := args.Get(1).(*wallet.Wallet)
+			assert.Equal(t, wallet.STATUS_CLOSED, updateParams.Status)
+			assert.Equal(t, float64(0), updateParams.Balance)
+			assert.NotNil(t, updateParams.ClosedAt)
+		}).Return(nil)
+		eventService := event_mock.NewEventIService(t)
+		eventService.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(events.WalletClosed)
+			assert.Equal(t, wallet.STATUS_FROZEN, event.PreviousStatus)
+			assert.Equal(t, float64(100), event.Payout)
+		}).Return(nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), eventService, &manager.MockStorageManager{})
+
+		closedWallet, payout, err := service.Close(context.TODO(), &wallet.CloseWalletParams{WalletId: "wallet-id", Reason: "requested", PayoutRemainder: true})
+		assert.Nil(t, err)
+		assert.Equal(t, float64(100), payout)
+		assert.Equal(t, wallet.STATUS_CLOSED, closedWallet.Status)
+	})
+
+	t.Run("should close the wallet by transition if balance is zero", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(&wallet.Wallet{Id: "wallet-id", Status: wallet.STATUS_DISABLED}, nil)
+		repository.On("Update", mock.Anything, mock.Anything).Return(nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		closedWallet, err := service.Transition(context.TODO(), &wallet.TransitionParams{WalletId: "wallet-id", Status: wallet.STATUS_CLOSED, Reason: "requested"})
+		assert.Nil(t, err)
+		assert.Equal(t, wallet.STATUS_CLOSED, closedWallet.Status)
+	})
+}
+
+func TestWalletService_Lock(t *testing.T) {
+	t.Run("should return error if a wallet is not found", func(t *testing.T) {
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, "wallet-id").Return(nil, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		err := service.Lock(context.TODO(), "wallet-id")
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+	})
+
+	t.Run("should lock the wallets in the order of their ids", func(t *testing.T) {
+		locked := []string{}
+		repository := mocks.NewWalletRepository(t)
+		repository.On("FindByIdForUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			locked = append(locked, args.String(1))
+		}).Return(&wallet.Wallet{}, nil)
+		service := wallet.NewWalletService(repository, newAuditService(t), newWebhookService(t), newEventService(t), &manager.MockStorageManager{})
+
+		err := service.Lock(context.TODO(), "b-id", "a-id")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a-id", "b-id"}, locked)
+	})
+}
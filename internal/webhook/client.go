@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Returned when a delivery would connect to an address of the internal network
+var errForbiddenAddress = goerrors.New("address of the webhook is not allowed")
+
+// Return the client delivering the events to the URLs registered by the clients.
+// It refuses to connect to loopback, private, link-local and unspecified addresses whatever the host resolves to,
+// doesn't follow redirects and ignores the proxy of the environment, so a webhook can't reach the internal network
+func NewDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: DELIVERY_TIMEOUT,
+		Control: rejectInternalAddress,
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: DELIVERY_TIMEOUT,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Check the resolved address right before connecting to it
+func rejectInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errForbiddenAddress
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || isInternalAddress(ip) {
+		return errForbiddenAddress
+	}
+
+	return nil
+}
+
+func isInternalAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified()
+}
+
+// Reject the URLs naming an internal host outright, the other hosts are checked once resolved
+func isInternalHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	return isInternalAddress(ip)
+}
+
+// Describe the failed delivery without the details of the network of the server,
+// as the delivery log is shown to the client
+func deliveryError(status int, err error) string {
+	var netErr net.Error
+	switch {
+	case status != 0:
+		return fmt.Sprintf("webhook responded with status %d", status)
+	case goerrors.Is(err, errForbiddenAddress):
+		return errForbiddenAddress.Error()
+	case goerrors.Is(err, context.DeadlineExceeded), goerrors.As(err, &netErr) && netErr.Timeout():
+		return "webhook timed out"
+	default:
+		return "webhook unreachable"
+	}
+}
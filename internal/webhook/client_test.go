@@ -0,0 +1,33 @@
+package webhook_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeliveryClient(t *testing.T) {
+	t.Run("should refuse to connect to a loopback address", func(t *testing.T) {
+		called := false
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer receiver.Close()
+
+		_, err := webhook.NewDeliveryClient().Post(receiver.URL, "application/json", strings.NewReader("{}"))
+		assert.NotNil(t, err)
+		assert.True(t, strings.Contains(err.Error(), "address of the webhook is not allowed"))
+		assert.False(t, called)
+	})
+
+	t.Run("should not follow redirects", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "https://example.com/hook", nil)
+
+		err := webhook.NewDeliveryClient().CheckRedirect(req, []*http.Request{req})
+		assert.Equal(t, http.ErrUseLastResponse, err)
+	})
+}
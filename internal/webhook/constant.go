@@ -0,0 +1,45 @@
+package webhook
+
+import "time"
+
+const (
+	EVENT_TRANSACTION_SUCCEEDED = "transaction.succeeded"
+	EVENT_TRANSACTION_FAILED    = "transaction.failed"
+	EVENT_WALLET_STATUS_CHANGED = "wallet.status_changed"
+)
+
+const (
+	DELIVERY_STATUS_PENDING   = "pending"
+	DELIVERY_STATUS_SUCCEEDED = "succeeded"
+	DELIVERY_STATUS_FAILED    = "failed"
+)
+
+const (
+	MAX_ENDPOINTS      = 10
+	MIN_SECRET_LENGTH  = 16
+	DELIVERY_LOG_LIMIT = 100
+)
+
+const (
+	// Attempts made to deliver an event before the delivery is marked as failed
+	MAX_ATTEMPTS = 8
+	// Delay before the first retry, doubled on every following retry
+	RETRY_BASE_DELAY = 30 * time.Second
+	RETRY_MAX_DELAY  = 6 * time.Hour
+	DELIVERY_TIMEOUT = 10 * time.Second
+	// Time a claimed delivery is hidden from the other dispatchers,
+	// so a delivery interrupted by a crash is attempted again afterwards
+	DELIVERY_LEASE = time.Minute
+)
+
+const (
+	DISPATCH_BATCH_SIZE = 50
+	DISPATCH_INTERVAL   = time.Second
+)
+
+const (
+	HEADER_ID        = "X-Webhook-Id"
+	HEADER_EVENT     = "X-Webhook-Event"
+	HEADER_TIMESTAMP = "X-Webhook-Timestamp"
+	HEADER_SIGNATURE = "X-Webhook-Signature"
+)
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/lifecycle"
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+)
+
+// Dispatcher attempts the due webhook deliveries in the background.
+// Deliveries are claimed before they are attempted, so every instance can run its own dispatcher.
+// Shutdown waits for the deliveries being attempted, pending deliveries are attempted on the next start
+type Dispatcher struct {
+	*lifecycle.Loop
+	service WebhookIService
+}
+
+func NewDispatcher(service WebhookIService) *Dispatcher {
+	d := &Dispatcher{service: service}
+	d.Loop = lifecycle.NewLoop("webhook_dispatcher", d.dispatch)
+	return d
+}
+
+// Attempt a batch of due deliveries, the next batch follows right away unless none is left.
+// The attempts are not interrupted by shutdown, they are bounded by the delivery timeout
+func (d *Dispatcher) dispatch(ctx context.Context) time.Duration {
+	dispatched, err := d.service.Dispatch(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error dispatching webhooks", logging.KEY_ERROR, err)
+		return DISPATCH_INTERVAL
+	}
+	if dispatched < DISPATCH_BATCH_SIZE {
+		return DISPATCH_INTERVAL
+	}
+
+	return 0
+}
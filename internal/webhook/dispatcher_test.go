@@ -0,0 +1,32 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	webhook_mock "github.com/defryheryanto/mini-wallet/internal/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDispatcher(t *testing.T) {
+	t.Run("should dispatch the next batch right away while the batches are full", func(t *testing.T) {
+		service := webhook_mock.NewWebhookIService(t)
+		service.On("Dispatch", mock.Anything).Return(webhook.DISPATCH_BATCH_SIZE, nil).Once()
+		called := make(chan struct{})
+		service.On("Dispatch", mock.Anything).Run(func(args mock.Arguments) {
+			close(called)
+		}).Return(0, nil).Once()
+
+		dispatcher := webhook.NewDispatcher(service)
+		dispatcher.Start(context.TODO())
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("next batch not dispatched")
+		}
+		assert.Nil(t, dispatcher.Shutdown(context.TODO()))
+	})
+}
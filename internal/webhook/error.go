@@ -0,0 +1,15 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/defryheryanto/mini-wallet/internal/errors"
+)
+
+var ErrInvalidUrl = errors.NewValidationError("url must be an absolute http or https url")
+var ErrInternalUrl = errors.NewValidationError("url must not point to a loopback, private or link-local address")
+var ErrSecretTooShort = errors.NewValidationError(fmt.Sprintf("secret must be at least %d characters", MIN_SECRET_LENGTH))
+var ErrTooManyEndpoints = errors.NewValidationError(fmt.Sprintf("a client can register up to %d webhooks", MAX_ENDPOINTS))
+var ErrEmptyEventType = errors.NewValidationError("event type is required")
+var ErrEndpointNotFound = errors.NewNotFoundError("webhook not found")
+var ErrDeliveryNotFound = errors.NewNotFoundError("webhook delivery not found")
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/defryheryanto/mini-wallet/internal/client"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/request"
+	"github.com/defryheryanto/mini-wallet/internal/httpserver/response"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateEndpointRequest struct {
+	Url    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+func HandleCreateEndpoint(service webhook.WebhookIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateEndpointRequest{}
+
+		err := request.DecodeBody(r, &requestBody)
+		if err != nil && err != io.EOF {
+			response.Failed(w, err)
+			return
+		}
+
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		endpoint, err := service.CreateEndpoint(r.Context(), &webhook.CreateEndpointParams{
+			ClientXid: currentClient.Xid,
+			Url:       requestBody.Url,
+			Secret:    requestBody.Secret,
+		})
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusCreated, map[string]interface{}{
+			"webhook": endpoint,
+		})
+	}
+}
+
+func HandleGetEndpoints(service webhook.WebhookIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		endpoints, err := service.GetEndpoints(r.Context(), currentClient.Xid)
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"webhooks": endpoints,
+		})
+	}
+}
+
+func HandleDeleteEndpoint(service webhook.WebhookIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		err = service.DeleteEndpoint(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, nil)
+	}
+}
+
+func HandleGetDeliveries(service webhook.WebhookIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		deliveries, err := service.GetDeliveries(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"deliveries": deliveries,
+		})
+	}
+}
+
+func HandleRedeliver(service webhook.WebhookIService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentClient, err := client.FromContext(r.Context())
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		delivery, err := service.Redeliver(r.Context(), currentClient.Xid, chi.URLParam(r, "id"))
+		if err != nil {
+			response.Failed(w, err)
+			return
+		}
+
+		response.Success(w, http.StatusAccepted, map[string]interface{}{
+			"delivery": delivery,
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	webhook "github.com/defryheryanto/mini-wallet/internal/webhook"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EndpointRepository is an autogenerated mock type for the EndpointRepository type
+type EndpointRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *EndpointRepository) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByClientXid provides a mock function with given fields: ctx, xid
+func (_m *EndpointRepository) FindAllByClientXid(ctx context.Context, xid string) ([]*webhook.Endpoint, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 []*webhook.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*webhook.Endpoint, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*webhook.Endpoint); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindById provides a mock function with given fields: ctx, id
+func (_m *EndpointRepository) FindById(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *webhook.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*webhook.Endpoint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *webhook.Endpoint); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, data
+func (_m *EndpointRepository) Insert(ctx context.Context, data *webhook.Endpoint) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Endpoint) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewEndpointRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEndpointRepository creates a new instance of EndpointRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEndpointRepository(t mockConstructorTestingTNewEndpointRepository) *EndpointRepository {
+	mock := &EndpointRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,178 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webhook "github.com/defryheryanto/mini-wallet/internal/webhook"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// ClaimDueDeliveries provides a mock function with given fields: ctx, now, leaseUntil, limit
+func (_m *OutboxRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	ret := _m.Called(ctx, now, leaseUntil, limit)
+
+	var r0 []*webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]*webhook.Delivery, error)); ok {
+		return rf(ctx, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []*webhook.Delivery); ok {
+		r0 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeliveriesByEndpointId provides a mock function with given fields: ctx, endpointId, limit
+func (_m *OutboxRepository) FindDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) ([]*webhook.Delivery, error) {
+	ret := _m.Called(ctx, endpointId, limit)
+
+	var r0 []*webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*webhook.Delivery, error)); ok {
+		return rf(ctx, endpointId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*webhook.Delivery); ok {
+		r0 = rf(ctx, endpointId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, endpointId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeliveryById provides a mock function with given fields: ctx, id
+func (_m *OutboxRepository) FindDeliveryById(ctx context.Context, id string) (*webhook.Delivery, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*webhook.Delivery, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *webhook.Delivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEventById provides a mock function with given fields: ctx, id
+func (_m *OutboxRepository) FindEventById(ctx context.Context, id string) (*webhook.Event, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *webhook.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*webhook.Event, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *webhook.Event); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertDelivery provides a mock function with given fields: ctx, data
+func (_m *OutboxRepository) InsertDelivery(ctx context.Context, data *webhook.Delivery) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Delivery) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertEvent provides a mock function with given fields: ctx, data
+func (_m *OutboxRepository) InsertEvent(ctx context.Context, data *webhook.Event) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Event) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDelivery provides a mock function with given fields: ctx, data
+func (_m *OutboxRepository) UpdateDelivery(ctx context.Context, data *webhook.Delivery) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Delivery) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOutboxRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOutboxRepository(t mockConstructorTestingTNewOutboxRepository) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,186 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	webhook "github.com/defryheryanto/mini-wallet/internal/webhook"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookIService is an autogenerated mock type for the WebhookIService type
+type WebhookIService struct {
+	mock.Mock
+}
+
+// CreateEndpoint provides a mock function with given fields: ctx, params
+func (_m *WebhookIService) CreateEndpoint(ctx context.Context, params *webhook.CreateEndpointParams) (*webhook.Endpoint, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *webhook.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.CreateEndpointParams) (*webhook.Endpoint, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.CreateEndpointParams) *webhook.Endpoint); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *webhook.CreateEndpointParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEndpoint provides a mock function with given fields: ctx, xid, id
+func (_m *WebhookIService) DeleteEndpoint(ctx context.Context, xid string, id string) error {
+	ret := _m.Called(ctx, xid, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, xid, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Dispatch provides a mock function with given fields: ctx
+func (_m *WebhookIService) Dispatch(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeliveries provides a mock function with given fields: ctx, xid, endpointId
+func (_m *WebhookIService) GetDeliveries(ctx context.Context, xid string, endpointId string) ([]*webhook.Delivery, error) {
+	ret := _m.Called(ctx, xid, endpointId)
+
+	var r0 []*webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*webhook.Delivery, error)); ok {
+		return rf(ctx, xid, endpointId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*webhook.Delivery); ok {
+		r0 = rf(ctx, xid, endpointId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, xid, endpointId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpoints provides a mock function with given fields: ctx, xid
+func (_m *WebhookIService) GetEndpoints(ctx context.Context, xid string) ([]*webhook.Endpoint, error) {
+	ret := _m.Called(ctx, xid)
+
+	var r0 []*webhook.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*webhook.Endpoint, error)); ok {
+		return rf(ctx, xid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*webhook.Endpoint); ok {
+		r0 = rf(ctx, xid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, xid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, params
+func (_m *WebhookIService) Publish(ctx context.Context, params *webhook.PublishParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.PublishParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Redeliver provides a mock function with given fields: ctx, xid, deliveryId
+func (_m *WebhookIService) Redeliver(ctx context.Context, xid string, deliveryId string) (*webhook.Delivery, error) {
+	ret := _m.Called(ctx, xid, deliveryId)
+
+	var r0 *webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*webhook.Delivery, error)); ok {
+		return rf(ctx, xid, deliveryId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *webhook.Delivery); ok {
+		r0 = rf(ctx, xid, deliveryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, xid, deliveryId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebhookIService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookIService creates a new instance of WebhookIService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookIService(t mockConstructorTestingTNewWebhookIService) *WebhookIService {
+	mock := &WebhookIService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
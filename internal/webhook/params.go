@@ -0,0 +1,15 @@
+package webhook
+
+type CreateEndpointParams struct {
+	ClientXid string `json:"client_xid"`
+	Url       string `json:"url"`
+	// Key of the signature of the deliveries, never returned afterwards
+	Secret string `json:"secret"`
+}
+
+type PublishParams struct {
+	ClientXid string
+	Type      string
+	// Marshalled to JSON as the data of the event
+	Data any
+}
@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"context"
+
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	"gorm.io/gorm"
+)
+
+type EndpointRepository struct {
+	db *gorm.DB
+}
+
+func NewEndpointRepository(db *gorm.DB) *EndpointRepository {
+	return &EndpointRepository{db}
+}
+
+func (r *EndpointRepository) Insert(ctx context.Context, data *webhook.Endpoint) error {
+	payload := Endpoint{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *EndpointRepository) FindById(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	e := &Endpoint{}
+
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&e).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return e.ToServiceModel(), nil
+}
+
+func (r *EndpointRepository) FindAllByClientXid(ctx context.Context, xid string) ([]*webhook.Endpoint, error) {
+	endpoints := []*Endpoint{}
+
+	err := r.getGormClient(ctx).Where("client_xid = ?", xid).Order("created_at").Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return EndpointsToServiceModel(endpoints), nil
+}
+
+func (r *EndpointRepository) Delete(ctx context.Context, id string) error {
+	return r.getGormClient(ctx).Where("id = ?", id).Delete(&Endpoint{}).Error
+}
+
+func (r *EndpointRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+)
+
+type Endpoint struct {
+	Id        string    `gorm:"primaryKey;column:id"`
+	ClientXid string    `gorm:"column:client_xid"`
+	Url       string    `gorm:"column:url"`
+	Secret    string    `gorm:"column:secret"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+func (Endpoint) FromServiceModel(data *webhook.Endpoint) *Endpoint {
+	if data == nil {
+		return nil
+	}
+
+	return &Endpoint{
+		Id:        data.Id,
+		ClientXid: data.ClientXid,
+		Url:       data.Url,
+		Secret:    data.Secret,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func (e *Endpoint) ToServiceModel() *webhook.Endpoint {
+	return &webhook.Endpoint{
+		Id:        e.Id,
+		ClientXid: e.ClientXid,
+		Url:       e.Url,
+		Secret:    e.Secret,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func EndpointsToServiceModel(data []*Endpoint) []*webhook.Endpoint {
+	if data == nil {
+		return nil
+	}
+
+	endpoints := []*webhook.Endpoint{}
+	for _, e := range data {
+		endpoints = append(endpoints, e.ToServiceModel())
+	}
+
+	return endpoints
+}
+
+type Event struct {
+	Id        string    `gorm:"primaryKey;column:id"`
+	ClientXid string    `gorm:"column:client_xid"`
+	Type      string    `gorm:"column:type"`
+	Data      []byte    `gorm:"column:data"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (Event) TableName() string {
+	return "webhook_events"
+}
+
+func (Event) FromServiceModel(data *webhook.Event) *Event {
+	if data == nil {
+		return nil
+	}
+
+	return &Event{
+		Id:        data.Id,
+		ClientXid: data.ClientXid,
+		Type:      data.Type,
+		Data:      data.Data,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func (e *Event) ToServiceModel() *webhook.Event {
+	return &webhook.Event{
+		Id:        e.Id,
+		ClientXid: e.ClientXid,
+		Type:      e.Type,
+		Data:      json.RawMessage(e.Data),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+type Delivery struct {
+	Id             string     `gorm:"primaryKey;column:id"`
+	EventId        string     `gorm:"column:event_id"`
+	EventType      string     `gorm:"column:event_type"`
+	EndpointId     string     `gorm:"column:endpoint_id"`
+	Status         string     `gorm:"column:status"`
+	Attempts       int        `gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	LastAttemptAt  *time.Time `gorm:"column:last_attempt_at"`
+	ResponseStatus int        `gorm:"column:response_status"`
+	LastError      string     `gorm:"column:last_error"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (Delivery) FromServiceModel(data *webhook.Delivery) *Delivery {
+	if data == nil {
+		return nil
+	}
+
+	return &Delivery{
+		Id:             data.Id,
+		EventId:        data.EventId,
+		EventType:      data.EventType,
+		EndpointId:     data.EndpointId,
+		Status:         data.Status,
+		Attempts:       data.Attempts,
+		NextAttemptAt:  data.NextAttemptAt,
+		LastAttemptAt:  data.LastAttemptAt,
+		ResponseStatus: data.ResponseStatus,
+		LastError:      data.LastError,
+		DeliveredAt:    data.DeliveredAt,
+		CreatedAt:      data.CreatedAt,
+	}
+}
+
+func (d *Delivery) ToServiceModel() *webhook.Delivery {
+	return &webhook.Delivery{
+		Id:             d.Id,
+		EventId:        d.EventId,
+		EventType:      d.EventType,
+		EndpointId:     d.EndpointId,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+func DeliveriesToServiceModel(data []*Delivery) []*webhook.Delivery {
+	if data == nil {
+		return nil
+	}
+
+	deliveries := []*webhook.Delivery{}
+	for _, d := range data {
+		deliveries = append(deliveries, d.ToServiceModel())
+	}
+
+	return deliveries
+}
@@ -0,0 +1,125 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	gorm_manager "github.com/defryheryanto/mini-wallet/internal/storage/manager/gorm"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db}
+}
+
+func (r *OutboxRepository) InsertEvent(ctx context.Context, data *webhook.Event) error {
+	payload := Event{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) FindEventById(ctx context.Context, id string) (*webhook.Event, error) {
+	e := &Event{}
+
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&e).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return e.ToServiceModel(), nil
+}
+
+func (r *OutboxRepository) InsertDelivery(ctx context.Context, data *webhook.Delivery) error {
+	payload := Delivery{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Create(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) FindDeliveryById(ctx context.Context, id string) (*webhook.Delivery, error) {
+	d := &Delivery{}
+
+	err := r.getGormClient(ctx).Where("id = ?", id).First(&d).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return d.ToServiceModel(), nil
+}
+
+func (r *OutboxRepository) FindDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) ([]*webhook.Delivery, error) {
+	deliveries := []*Delivery{}
+
+	err := r.getGormClient(ctx).
+		Where("endpoint_id = ?", endpointId).
+		Order("created_at DESC, id").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return DeliveriesToServiceModel(deliveries), nil
+}
+
+func (r *OutboxRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	deliveries := []*Delivery{}
+
+	err := r.getGormClient(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		leaseUntil, webhook.DELIVERY_STATUS_PENDING, now, limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return DeliveriesToServiceModel(deliveries), nil
+}
+
+func (r *OutboxRepository) UpdateDelivery(ctx context.Context, data *webhook.Delivery) error {
+	payload := Delivery{}.FromServiceModel(data)
+
+	err := r.getGormClient(ctx).Where("id = ?", payload.Id).Select("*").Updates(&payload).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) getGormClient(ctx context.Context) *gorm.DB {
+	db, err := gorm_manager.ExtractClientFromContext(ctx)
+	if err != nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,16 @@
+package webhook
+
+import "time"
+
+// Return the delay before the next attempt of a delivery attempted the given number of times
+func RetryDelay(attempts int) time.Duration {
+	delay := RETRY_BASE_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= RETRY_MAX_DELAY {
+			return RETRY_MAX_DELAY
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,390 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/logging"
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/google/uuid"
+)
+
+// Endpoint is a URL registered by a client to receive its events
+type Endpoint struct {
+	Id        string `json:"id"`
+	ClientXid string `json:"client_xid"`
+	Url       string `json:"url"`
+	// Key of the signature of the deliveries
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event is written to the outbox within the database transaction of the change it describes,
+// so an event is only delivered if the change is committed
+type Event struct {
+	Id        string          `json:"id"`
+	ClientXid string          `json:"-"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Delivery tracks the delivery of an event to an endpoint of the client
+type Delivery struct {
+	Id            string     `json:"id"`
+	EventId       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	EndpointId    string     `json:"endpoint_id"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastAttemptAt *time.Time `json:"last_attempt_at"`
+	// HTTP status of the last response, zero if the endpoint didn't respond
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type EndpointRepository interface {
+	Insert(ctx context.Context, data *Endpoint) error
+	FindById(ctx context.Context, id string) (*Endpoint, error)
+	FindAllByClientXid(ctx context.Context, xid string) ([]*Endpoint, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type OutboxRepository interface {
+	InsertEvent(ctx context.Context, data *Event) error
+	FindEventById(ctx context.Context, id string) (*Event, error)
+	InsertDelivery(ctx context.Context, data *Delivery) error
+	FindDeliveryById(ctx context.Context, id string) (*Delivery, error)
+	// Return up to limit deliveries to the endpoint, the latest first
+	FindDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) ([]*Delivery, error)
+	// Return up to limit pending deliveries due at the given time and postpone them to leaseUntil,
+	// so they are not claimed by another dispatcher while being attempted
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Delivery, error)
+	UpdateDelivery(ctx context.Context, data *Delivery) error
+}
+
+type WebhookIService interface {
+	CreateEndpoint(ctx context.Context, params *CreateEndpointParams) (*Endpoint, error)
+	GetEndpoints(ctx context.Context, xid string) ([]*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, xid, id string) error
+	GetDeliveries(ctx context.Context, xid, endpointId string) ([]*Delivery, error)
+	Redeliver(ctx context.Context, xid, deliveryId string) (*Delivery, error)
+	Publish(ctx context.Context, params *PublishParams) error
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	endpointRepository EndpointRepository
+	outboxRepository   OutboxRepository
+	storageManager     manager.StorageManager
+	httpClient         *http.Client
+}
+
+func NewWebhookService(
+	endpointRepository EndpointRepository,
+	outboxRepository OutboxRepository,
+	storageManager manager.StorageManager,
+	httpClient *http.Client,
+) *WebhookService {
+	return &WebhookService{endpointRepository, outboxRepository, storageManager, httpClient}
+}
+
+// Register the URL receiving the events of the client.
+// The URLs naming an internal host are rejected, the addresses they resolve to are checked on every delivery
+func (s *WebhookService) CreateEndpoint(ctx context.Context, params *CreateEndpointParams) (*Endpoint, error) {
+	target, err := url.Parse(params.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrInvalidUrl
+	}
+	if isInternalHost(target.Hostname()) {
+		return nil, ErrInternalUrl
+	}
+	if len(params.Secret) < MIN_SECRET_LENGTH {
+		return nil, ErrSecretTooShort
+	}
+
+	endpoints, err := s.endpointRepository.FindAllByClientXid(ctx, params.ClientXid)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) >= MAX_ENDPOINTS {
+		return nil, ErrTooManyEndpoints
+	}
+
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &Endpoint{
+		Id:        uuidRandom.String(),
+		ClientXid: params.ClientXid,
+		Url:       target.String(),
+		Secret:    params.Secret,
+		CreatedAt: time.Now(),
+	}
+	err = s.endpointRepository.Insert(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context, xid string) ([]*Endpoint, error) {
+	return s.endpointRepository.FindAllByClientXid(ctx, xid)
+}
+
+// Remove the endpoint of the client. Its pending deliveries are failed when they are due
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, xid, id string) error {
+	_, err := s.findOwned(ctx, xid, id)
+	if err != nil {
+		return err
+	}
+
+	return s.endpointRepository.Delete(ctx, id)
+}
+
+// Return the latest deliveries to the endpoint of the client
+func (s *WebhookService) GetDeliveries(ctx context.Context, xid, endpointId string) ([]*Delivery, error) {
+	_, err := s.findOwned(ctx, xid, endpointId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.outboxRepository.FindDeliveriesByEndpointId(ctx, endpointId, DELIVERY_LOG_LIMIT)
+}
+
+// Deliver the event of the given delivery to its endpoint again as a new delivery, attempted right away
+func (s *WebhookService) Redeliver(ctx context.Context, xid, deliveryId string) (*Delivery, error) {
+	delivery, err := s.outboxRepository.FindDeliveryById(ctx, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrDeliveryNotFound
+	}
+
+	_, err = s.findOwned(ctx, xid, delivery.EndpointId)
+	if err == ErrEndpointNotFound {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery, err := newDelivery(delivery.EventId, delivery.EventType, delivery.EndpointId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.outboxRepository.InsertDelivery(ctx, redelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return redelivery, nil
+}
+
+// Write the event to the outbox with a delivery to every endpoint of the client.
+// Publish within the database transaction of the change so the event is only delivered if the change is committed.
+// Nothing is written if the client has no endpoint
+func (s *WebhookService) Publish(ctx context.Context, params *PublishParams) error {
+	if params.Type == "" {
+		return ErrEmptyEventType
+	}
+
+	endpoints, err := s.endpointRepository.FindAllByClientXid(ctx, params.ClientXid)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(params.Data)
+	if err != nil {
+		return err
+	}
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	event := &Event{
+		Id:        uuidRandom.String(),
+		ClientXid: params.ClientXid,
+		Type:      params.Type,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+
+	return s.storageManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := s.outboxRepository.InsertEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+
+		for _, endpoint := range endpoints {
+			delivery, err := newDelivery(event.Id, event.Type, endpoint.Id)
+			if err != nil {
+				return err
+			}
+
+			err = s.outboxRepository.InsertDelivery(ctx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Attempt the due deliveries concurrently, each one at most once.
+// A failed attempt is retried with exponential backoff until MAX_ATTEMPTS is reached.
+//
+// Return the number of attempted deliveries
+func (s *WebhookService) Dispatch(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.outboxRepository.ClaimDueDeliveries(ctx, now, now.Add(DELIVERY_LEASE), DISPATCH_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := logging.FromContext(ctx)
+	wg := sync.WaitGroup{}
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *Delivery) {
+			defer wg.Done()
+
+			err := s.deliver(ctx, delivery)
+			if err != nil {
+				logger.Error("error delivering webhook", "delivery_id", delivery.Id, logging.KEY_ERROR, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// Attempt the delivery and record the outcome
+func (s *WebhookService) deliver(ctx context.Context, delivery *Delivery) error {
+	event, err := s.outboxRepository.FindEventById(ctx, delivery.EventId)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.endpointRepository.FindById(ctx, delivery.EndpointId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	switch {
+	case event == nil:
+		delivery.Status = DELIVERY_STATUS_FAILED
+		delivery.LastError = "event not found"
+	case endpoint == nil:
+		delivery.Status = DELIVERY_STATUS_FAILED
+		delivery.LastError = "webhook deleted"
+	default:
+		delivery.ResponseStatus, err = s.send(ctx, endpoint, event, now)
+		if err == nil {
+			delivery.Status = DELIVERY_STATUS_SUCCEEDED
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			break
+		}
+
+		delivery.LastError = deliveryError(delivery.ResponseStatus, err)
+		if delivery.Attempts >= MAX_ATTEMPTS {
+			delivery.Status = DELIVERY_STATUS_FAILED
+		} else {
+			delivery.NextAttemptAt = now.Add(RetryDelay(delivery.Attempts))
+		}
+	}
+
+	return s.outboxRepository.UpdateDelivery(ctx, delivery)
+}
+
+// POST the signed event to the endpoint.
+//
+// Return the status of the response, and error unless the status is 2xx
+func (s *WebhookService) send(ctx context.Context, endpoint *Endpoint, event *Event, now time.Time) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DELIVERY_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_ID, event.Id)
+	req.Header.Set(HEADER_EVENT, event.Type)
+	req.Header.Set(HEADER_TIMESTAMP, timestamp)
+	req.Header.Set(HEADER_SIGNATURE, Sign(endpoint.Secret, timestamp, body))
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+func (s *WebhookService) findOwned(ctx context.Context, xid, id string) (*Endpoint, error) {
+	endpoint, err := s.endpointRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil || endpoint.ClientXid != xid {
+		return nil, ErrEndpointNotFound
+	}
+
+	return endpoint, nil
+}
+
+func newDelivery(eventId, eventType, endpointId string) (*Delivery, error) {
+	uuidRandom, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Delivery{
+		Id:            uuidRandom.String(),
+		EventId:       eventId,
+		EventType:     eventType,
+		EndpointId:    endpointId,
+		Status:        DELIVERY_STATUS_PENDING,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
@@ -0,0 +1,308 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defryheryanto/mini-wallet/internal/storage/manager"
+	"github.com/defryheryanto/mini-wallet/internal/webhook"
+	webhook_mock "github.com/defryheryanto/mini-wallet/internal/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSecret = "0123456789abcdef"
+
+type serviceMocks struct {
+	endpointRepository *webhook_mock.EndpointRepository
+	outboxRepository   *webhook_mock.OutboxRepository
+}
+
+func newService(t *testing.T) (*webhook.WebhookService, *serviceMocks) {
+	mocks := &serviceMocks{
+		endpointRepository: webhook_mock.NewEndpointRepository(t),
+		outboxRepository:   webhook_mock.NewOutboxRepository(t),
+	}
+
+	return webhook.NewWebhookService(mocks.endpointRepository, mocks.outboxRepository, &manager.MockStorageManager{}, &http.Client{}), mocks
+}
+
+func TestWebhookService_CreateEndpoint(t *testing.T) {
+	t.Run("should return error if url is invalid", func(t *testing.T) {
+		service, _ := newService(t)
+
+		for _, url := range []string{"", "example.com/hook", "ftp://example.com/hook", "https://"} {
+			_, err := service.CreateEndpoint(context.TODO(), &webhook.CreateEndpointParams{ClientXid: "test", Url: url, Secret: testSecret})
+			assert.Equal(t, webhook.ErrInvalidUrl, err, url)
+		}
+	})
+
+	t.Run("should return error if url points to an internal address", func(t *testing.T) {
+		service, _ := newService(t)
+
+		for _, url := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://10.0.0.1/hook", "http://169.254.169.254/latest", "http://[::1]/hook", "http://0.0.0.0/hook"} {
+			_, err := service.CreateEndpoint(context.TODO(), &webhook.CreateEndpointParams{ClientXid: "test", Url: url, Secret: testSecret})
+			assert.Equal(t, webhook.ErrInternalUrl, err, url)
+		}
+	})
+
+	t.Run("should return error if secret is too short", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.CreateEndpoint(context.TODO(), &webhook.CreateEndpointParams{ClientXid: "test", Url: "https://example.com/hook", Secret: "short"})
+		assert.Equal(t, webhook.ErrSecretTooShort, err)
+	})
+
+	t.Run("should return error if client has too many endpoints", func(t *testing.T) {
+		endpoints := []*webhook.Endpoint{}
+		for i := 0; i < webhook.MAX_ENDPOINTS; i++ {
+			endpoints = append(endpoints, &webhook.Endpoint{Id: fmt.Sprint(i)})
+		}
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindAllByClientXid", mock.Anything, "test").Return(endpoints, nil)
+
+		_, err := service.CreateEndpoint(context.TODO(), &webhook.CreateEndpointParams{ClientXid: "test", Url: "https://example.com/hook", Secret: testSecret})
+		assert.Equal(t, webhook.ErrTooManyEndpoints, err)
+	})
+
+	t.Run("should register the endpoint of the client", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindAllByClientXid", mock.Anything, "test").Return(nil, nil)
+		mocks.endpointRepository.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+		endpoint, err := service.CreateEndpoint(context.TODO(), &webhook.CreateEndpointParams{ClientXid: "test", Url: "https://example.com/hook", Secret: testSecret})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, endpoint.Id)
+		assert.Equal(t, "test", endpoint.ClientXid)
+		assert.Equal(t, testSecret, endpoint.Secret)
+	})
+}
+
+func TestWebhookService_DeleteEndpoint(t *testing.T) {
+	t.Run("should return error if endpoint is owned by another client", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(&webhook.Endpoint{Id: "endpoint-id", ClientXid: "other"}, nil)
+
+		err := service.DeleteEndpoint(context.TODO(), "test", "endpoint-id")
+		assert.Equal(t, webhook.ErrEndpointNotFound, err)
+	})
+
+	t.Run("should delete the endpoint of the client", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(&webhook.Endpoint{Id: "endpoint-id", ClientXid: "test"}, nil)
+		mocks.endpointRepository.On("Delete", mock.Anything, "endpoint-id").Return(nil)
+
+		err := service.DeleteEndpoint(context.TODO(), "test", "endpoint-id")
+		assert.Nil(t, err)
+	})
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	t.Run("should return error if event type is empty", func(t *testing.T) {
+		service, _ := newService(t)
+
+		err := service.Publish(context.TODO(), &webhook.PublishParams{ClientXid: "test"})
+		assert.Equal(t, webhook.ErrEmptyEventType, err)
+	})
+
+	t.Run("should write nothing if client has no endpoint", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindAllByClientXid", mock.Anything, "test").Return([]*webhook.Endpoint{}, nil)
+
+		err := service.Publish(context.TODO(), &webhook.PublishParams{ClientXid: "test", Type: webhook.EVENT_TRANSACTION_SUCCEEDED})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should write the event with a delivery to every endpoint", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.endpointRepository.On("FindAllByClientXid", mock.Anything, "test").Return([]*webhook.Endpoint{{Id: "first"}, {Id: "second"}}, nil)
+
+		var eventId string
+		mocks.outboxRepository.On("InsertEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event := args.Get(1).(*webhook.Event)
+			eventId = event.Id
+			assert.Equal(t, "test", event.ClientXid)
+			assert.Equal(t, webhook.EVENT_TRANSACTION_SUCCEEDED, event.Type)
+			assert.JSONEq(t, `{"id":"trx-id"}`, string(event.Data))
+		}).Return(nil)
+		endpointIds := []string{}
+		mocks.outboxRepository.On("InsertDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			delivery := args.Get(1).(*webhook.Delivery)
+			endpointIds = append(endpointIds, delivery.EndpointId)
+			assert.Equal(t, eventId, delivery.EventId)
+			assert.Equal(t, webhook.DELIVERY_STATUS_PENDING, delivery.Status)
+		}).Return(nil)
+
+		err := service.Publish(context.TODO(), &webhook.PublishParams{
+			ClientXid: "test",
+			Type:      webhook.EVENT_TRANSACTION_SUCCEEDED,
+			Data:      map[string]string{"id": "trx-id"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"first", "second"}, endpointIds)
+	})
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	t.Run("should return error if delivery not found", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.outboxRepository.On("FindDeliveryById", mock.Anything, "delivery-id").Return(nil, nil)
+
+		_, err := service.Redeliver(context.TODO(), "test", "delivery-id")
+		assert.Equal(t, webhook.ErrDeliveryNotFound, err)
+	})
+
+	t.Run("should return error if delivery is made to the endpoint of another client", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.outboxRepository.On("FindDeliveryById", mock.Anything, "delivery-id").Return(&webhook.Delivery{Id: "delivery-id", EndpointId: "endpoint-id"}, nil)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(&webhook.Endpoint{Id: "endpoint-id", ClientXid: "other"}, nil)
+
+		_, err := service.Redeliver(context.TODO(), "test", "delivery-id")
+		assert.Equal(t, webhook.ErrDeliveryNotFound, err)
+	})
+
+	t.Run("should queue a new delivery of the event", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.outboxRepository.On("FindDeliveryById", mock.Anything, "delivery-id").Return(&webhook.Delivery{
+			Id:         "delivery-id",
+			EventId:    "event-id",
+			EventType:  webhook.EVENT_TRANSACTION_FAILED,
+			EndpointId: "endpoint-id",
+			Status:     webhook.DELIVERY_STATUS_FAILED,
+			Attempts:   webhook.MAX_ATTEMPTS,
+		}, nil)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(&webhook.Endpoint{Id: "endpoint-id", ClientXid: "test"}, nil)
+		mocks.outboxRepository.On("InsertDelivery", mock.Anything, mock.Anything).Return(nil)
+
+		redelivery, err := service.Redeliver(context.TODO(), "test", "delivery-id")
+		assert.Nil(t, err)
+		assert.NotEqual(t, "delivery-id", redelivery.Id)
+		assert.Equal(t, "event-id", redelivery.EventId)
+		assert.Equal(t, webhook.EVENT_TRANSACTION_FAILED, redelivery.EventType)
+		assert.Equal(t, webhook.DELIVERY_STATUS_PENDING, redelivery.Status)
+		assert.Equal(t, 0, redelivery.Attempts)
+	})
+}
+
+func TestWebhookService_Dispatch(t *testing.T) {
+	event := &webhook.Event{
+		Id:        "event-id",
+		ClientXid: "test",
+		Type:      webhook.EVENT_TRANSACTION_SUCCEEDED,
+		Data:      json.RawMessage(`{"id":"trx-id"}`),
+		CreatedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	dispatch := func(t *testing.T, url string, delivery *webhook.Delivery) *webhook.Delivery {
+		service, mocks := newService(t)
+		mocks.outboxRepository.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, webhook.DISPATCH_BATCH_SIZE).Return([]*webhook.Delivery{delivery}, nil)
+		mocks.outboxRepository.On("FindEventById", mock.Anything, "event-id").Return(event, nil)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(&webhook.Endpoint{Id: "endpoint-id", Url: url, Secret: testSecret}, nil)
+
+		var updated *webhook.Delivery
+		mocks.outboxRepository.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*webhook.Delivery)
+		}).Return(nil)
+
+		dispatched, err := service.Dispatch(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, dispatched)
+		return updated
+	}
+
+	t.Run("should post the signed event to the endpoint", func(t *testing.T) {
+		received := make(chan *http.Request, 1)
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			received <- r
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		delivery := dispatch(t, receiver.URL, &webhook.Delivery{Id: "delivery-id", EventId: "event-id", EndpointId: "endpoint-id", Status: webhook.DELIVERY_STATUS_PENDING})
+
+		r := <-received
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "event-id", r.Header.Get(webhook.HEADER_ID))
+		assert.Equal(t, webhook.EVENT_TRANSACTION_SUCCEEDED, r.Header.Get(webhook.HEADER_EVENT))
+		assert.Equal(t, webhook.Sign(testSecret, r.Header.Get(webhook.HEADER_TIMESTAMP), body), r.Header.Get(webhook.HEADER_SIGNATURE))
+		assert.JSONEq(t, `{"id":"event-id","type":"transaction.succeeded","data":{"id":"trx-id"},"created_at":"2024-01-03T00:00:00Z"}`, string(body))
+
+		assert.Equal(t, webhook.DELIVERY_STATUS_SUCCEEDED, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+		assert.NotNil(t, delivery.DeliveredAt)
+	})
+
+	t.Run("should retry with backoff if endpoint responded with an error", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		before := time.Now()
+		delivery := dispatch(t, receiver.URL, &webhook.Delivery{Id: "delivery-id", EventId: "event-id", EndpointId: "endpoint-id", Status: webhook.DELIVERY_STATUS_PENDING, Attempts: 2})
+
+		assert.Equal(t, webhook.DELIVERY_STATUS_PENDING, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+		assert.True(t, strings.Contains(delivery.LastError, "500"))
+		assert.False(t, delivery.NextAttemptAt.Before(before.Add(webhook.RetryDelay(3))))
+		assert.Nil(t, delivery.DeliveredAt)
+	})
+
+	t.Run("should record the unreachable endpoint without the details of the network", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		receiver.Close()
+
+		delivery := dispatch(t, receiver.URL, &webhook.Delivery{Id: "delivery-id", EventId: "event-id", EndpointId: "endpoint-id", Status: webhook.DELIVERY_STATUS_PENDING})
+
+		assert.Equal(t, webhook.DELIVERY_STATUS_PENDING, delivery.Status)
+		assert.Equal(t, "webhook unreachable", delivery.LastError)
+	})
+
+	t.Run("should fail the delivery after the last attempt", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer receiver.Close()
+
+		delivery := dispatch(t, receiver.URL, &webhook.Delivery{Id: "delivery-id", EventId: "event-id", EndpointId: "endpoint-id", Status: webhook.DELIVERY_STATUS_PENDING, Attempts: webhook.MAX_ATTEMPTS - 1})
+
+		assert.Equal(t, webhook.DELIVERY_STATUS_FAILED, delivery.Status)
+		assert.Equal(t, webhook.MAX_ATTEMPTS, delivery.Attempts)
+	})
+
+	t.Run("should fail the delivery if endpoint is deleted", func(t *testing.T) {
+		service, mocks := newService(t)
+		mocks.outboxRepository.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, webhook.DISPATCH_BATCH_SIZE).Return([]*webhook.Delivery{
+			{Id: "delivery-id", EventId: "event-id", EndpointId: "endpoint-id", Status: webhook.DELIVERY_STATUS_PENDING},
+		}, nil)
+		mocks.outboxRepository.On("FindEventById", mock.Anything, "event-id").Return(event, nil)
+		mocks.endpointRepository.On("FindById", mock.Anything, "endpoint-id").Return(nil, nil)
+		mocks.outboxRepository.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			delivery := args.Get(1).(*webhook.Delivery)
+			assert.Equal(t, webhook.DELIVERY_STATUS_FAILED, delivery.Status)
+		}).Return(nil)
+
+		_, err := service.Dispatch(context.TODO())
+		assert.Nil(t, err)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Run("should double the delay after every attempt up to the maximum delay", func(t *testing.T) {
+		assert.Equal(t, webhook.RETRY_BASE_DELAY, webhook.RetryDelay(1))
+		assert.Equal(t, 2*webhook.RETRY_BASE_DELAY, webhook.RetryDelay(2))
+		assert.Equal(t, 8*webhook.RETRY_BASE_DELAY, webhook.RetryDelay(4))
+		assert.Equal(t, webhook.RETRY_MAX_DELAY, webhook.RetryDelay(100))
+	})
+}